@@ -0,0 +1,111 @@
+// Command relay-expr evaluates an expression tree against a metadata API sample
+// configuration and prints the result, along with a trace of how each
+// reference, function invocation, and query was evaluated.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/puppetlabs/relay-core/pkg/expr/evaluate"
+	"github.com/puppetlabs/relay-core/pkg/expr/model"
+	"github.com/puppetlabs/relay-core/pkg/expr/parse"
+	"github.com/puppetlabs/relay-core/pkg/metadataapi/opt"
+	"github.com/puppetlabs/relay-core/pkg/metadataapi/sample"
+)
+
+type stringsFlag []string
+
+func (sf *stringsFlag) String() string {
+	return strings.Join(*sf, ",")
+}
+
+func (sf *stringsFlag) Set(value string) error {
+	*sf = append(*sf, value)
+	return nil
+}
+
+var (
+	sampleConfigFiles stringsFlag
+
+	runID      = flag.String("run", "", "ID of the run in the sample configuration")
+	stepName   = flag.String("step", "", "Name of the step in the sample configuration")
+	treeFile   = flag.String("tree", "", "JSON or YAML file containing the tree to evaluate instead of the spec of the step")
+	conditions = flag.Bool("conditions", false, "Evaluate the conditions of the step instead of its spec")
+	query      = flag.String("q", "", "Query to evaluate against the tree")
+	lang       = flag.String("lang", evaluate.LanguagePath.String(), "Language of the query")
+)
+
+func init() {
+	flag.Var(&sampleConfigFiles, "sample-config", "Sample configuration file to load (may be repeated)")
+}
+
+func main() {
+	flag.Parse()
+
+	if len(sampleConfigFiles) == 0 || *runID == "" || *stepName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	l, ok := evaluate.ParseLanguage(*lang)
+	if !ok {
+		log.Fatalf("Unsupported query language %q", *lang)
+	}
+
+	sc, err := (&opt.Config{SampleConfigFiles: sampleConfigFiles}).SampleConfig()
+	if err != nil {
+		log.Fatalf("Failed to load sample configuration: %v", err)
+	}
+
+	opts, err := sample.EvaluatorOptions(sc, *runID, *stepName)
+	if err != nil {
+		log.Fatalf("Failed to configure evaluator: %v", err)
+	}
+
+	var tree parse.Tree
+	switch step := sc.Runs[*runID].Steps[*stepName]; {
+	case *treeFile != "":
+		tree, err = parse.ParseFile(*treeFile)
+		if err != nil {
+			log.Fatalf("Failed to parse tree: %v", err)
+		}
+	case *conditions:
+		tree = step.Conditions.Tree
+	default:
+		tree = step.Spec.Interface()
+	}
+
+	trace := model.NewTrace()
+	ev := evaluate.NewEvaluator(append(opts, evaluate.WithLanguage(l), evaluate.WithTrace(trace))...)
+
+	var r *model.Result
+	var rerr error
+	if *query != "" {
+		r, rerr = ev.EvaluateQuery(context.Background(), tree, *query)
+	} else {
+		r, rerr = ev.EvaluateAll(context.Background(), tree)
+	}
+
+	// The result is printed even if evaluation fails, as the trace shows
+	// where the error occurred.
+	env := &model.JSONResultEnvelope{Trace: model.NewJSONTraceEnvelope(trace.Nodes())}
+	if rerr == nil {
+		env = model.NewJSONResultEnvelope(r)
+		env.Trace = model.NewJSONTraceEnvelope(trace.Nodes())
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(env); err != nil {
+		log.Fatalf("Failed to write result: %v", err)
+	}
+
+	if rerr != nil {
+		log.Fatalf("Failed to evaluate tree: %v", rerr)
+	}
+}
@@ -7,18 +7,28 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"time"
 
 	"github.com/inconshreveable/log15"
 	"github.com/puppetlabs/errawr-go/v2/pkg/errawr"
 	"github.com/puppetlabs/horsehead/v2/instrumentation/alerts"
+	"github.com/puppetlabs/horsehead/v2/instrumentation/metrics"
+	"github.com/puppetlabs/horsehead/v2/instrumentation/metrics/delegates"
+	metricsserver "github.com/puppetlabs/horsehead/v2/instrumentation/metrics/server"
 	"github.com/puppetlabs/horsehead/v2/logging"
 	"github.com/puppetlabs/horsehead/v2/mainutil"
+	"github.com/puppetlabs/relay-core/pkg/authenticate"
+	"github.com/puppetlabs/relay-core/pkg/manager/cache"
 	"github.com/puppetlabs/relay-core/pkg/metadataapi/opt"
 	"github.com/puppetlabs/relay-core/pkg/metadataapi/sample"
 	"github.com/puppetlabs/relay-core/pkg/metadataapi/server"
 	"github.com/puppetlabs/relay-core/pkg/metadataapi/server/middleware"
 	"github.com/puppetlabs/relay-core/pkg/util/lifecycleutil"
 	"github.com/puppetlabs/relay-core/pkg/workflow/validation"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
 )
 
 func main() {
@@ -30,7 +40,37 @@ func main() {
 
 	var servers []mainutil.CancelableFunc
 
-	// TODO: Add metrics server.
+	mets, err := metrics.NewNamespace("metadata_api", metrics.Options{
+		DelegateType:  delegates.PrometheusDelegate,
+		ErrorBehavior: metrics.ErrorBehaviorLog,
+	})
+	if err != nil {
+		log().Crit("failed to set up metrics", "error", err)
+		os.Exit(1)
+	}
+
+	exporter, err := cfg.TracingExporter()
+	if err != nil {
+		log().Crit("failed to set up trace exporter", "error", err)
+		os.Exit(1)
+	}
+
+	var spans *sdktrace.BatchSpanProcessor
+	if exporter != nil {
+		spans = sdktrace.NewBatchSpanProcessor(exporter)
+
+		global.SetTracerProvider(sdktrace.NewTracerProvider(
+			sdktrace.WithSpanProcessor(spans),
+			sdktrace.WithResource(resource.New(semconv.ServiceNameKey.String("relay-metadata-api"))),
+		))
+	}
+
+	if cfg.MetricsEnabled {
+		servers = append(servers, metricsserver.New(mets, metricsserver.Options{
+			BindAddr: cfg.MetricsBindAddr,
+			Path:     "/metrics",
+		}).Run)
+	}
 
 	servers = append(servers, func(ctx context.Context) error {
 		var auth middleware.Authenticator
@@ -58,7 +98,16 @@ func main() {
 			// Print the JWTs so users can pick them off for requests.
 			_ = tg.GenerateAll(ctx, sc)
 
-			auth = sample.NewAuthenticator(sc, tg.Key())
+			var authOpts []sample.AuthenticatorOption
+			if dir := cfg.SampleDataDirectory; dir != "" {
+				if err := sample.SeedDataDirectory(ctx, sc, dir); err != nil {
+					return fmt.Errorf("failed to seed sample data directory: %+v", err)
+				}
+
+				authOpts = append(authOpts, sample.AuthenticatorWithDataDirectory(dir))
+			}
+
+			auth = sample.NewAuthenticator(sc, tg.Key(), authOpts...)
 		} else {
 			// Set up the server for real traffic.
 			kc, err := cfg.KubernetesClient()
@@ -76,20 +125,78 @@ func main() {
 				return err
 			}
 
-			auth = middleware.NewKubernetesAuthenticator(
-				cfg.KubernetesClientFactory,
+			podCache := authenticate.NewKubernetesPodCache(kc.Interface, authenticate.KubernetesPodCacheWithMetrics(mets))
+			go podCache.Run(ctx)
+
+			authOpts := []middleware.KubernetesAuthenticatorOption{
 				middleware.KubernetesAuthenticatorWithKubernetesIntermediary(kc),
+				middleware.KubernetesAuthenticatorWithKubernetesPodCache(podCache),
 				middleware.KubernetesAuthenticatorWithLogServiceIntermediary(lc),
 				middleware.KubernetesAuthenticatorWithChainToVaultTransitIntermediary(vc, cfg.VaultTransitPath, cfg.VaultTransitKey),
 				middleware.KubernetesAuthenticatorWithVaultResolver(cfg.VaultAuthURL, cfg.VaultAuthPath, cfg.VaultAuthRole),
-			)
+			}
+
+			if kss, err := cfg.JWTKeySets(kc.Interface); err != nil {
+				return err
+			} else {
+				for _, ks := range kss {
+					authOpts = append(authOpts, middleware.KubernetesAuthenticatorWithKeySetResolver(ks))
+				}
+			}
+
+			if sink, err := cfg.AuditSink(); err != nil {
+				return fmt.Errorf("failed to open audit log: %+v", err)
+			} else if sink != nil {
+				authOpts = append(authOpts, middleware.KubernetesAuthenticatorWithAuditSink(sink))
+			}
+
+			if cfg.SecretCacheTTL > 0 || cfg.SecretCacheNegativeTTL > 0 {
+				authOpts = append(authOpts, middleware.KubernetesAuthenticatorWithSecretCache(cache.NewCache(
+					cache.CacheWithTTL(cfg.SecretCacheTTL),
+					cache.CacheWithNegativeTTL(cfg.SecretCacheNegativeTTL),
+					cache.CacheWithMetrics(mets),
+				)))
+			}
+
+			if cts, err := cfg.ConnectionTypes(); err != nil {
+				return fmt.Errorf("failed to load connection types: %+v", err)
+			} else if len(cts) > 0 {
+				authOpts = append(authOpts, middleware.KubernetesAuthenticatorWithConnectionRefresher(cts.Refresher))
+			}
+
+			auth = middleware.NewKubernetesAuthenticator(cfg.KubernetesClientFactory, authOpts...)
 		}
 
-		var serverOpts []server.Option
+		serverOpts := []server.Option{
+			server.WithMetrics(mets),
+			server.WithRateLimiter(middleware.NewRateLimiter(
+				middleware.RateLimiterWithMetrics(mets),
+				middleware.RateLimiterWithLimit(middleware.RateLimitBudgetSecrets, middleware.RateLimit{
+					PerSecond: cfg.RateLimitSecretsPerSecond,
+					Burst:     cfg.RateLimitSecretsBurst,
+				}),
+				middleware.RateLimiterWithLimit(middleware.RateLimitBudgetOutputs, middleware.RateLimit{
+					PerSecond: cfg.RateLimitOutputsPerSecond,
+					Burst:     cfg.RateLimitOutputsBurst,
+				}),
+				middleware.RateLimiterWithLimit(middleware.RateLimitBudgetLogs, middleware.RateLimit{
+					PerSecond: cfg.RateLimitLogsPerSecond,
+					Burst:     cfg.RateLimitLogsBurst,
+				}),
+				middleware.RateLimiterWithLimit(middleware.RateLimitBudgetDefault, middleware.RateLimit{
+					PerSecond: cfg.RateLimitDefaultPerSecond,
+					Burst:     cfg.RateLimitDefaultBurst,
+				}),
+			)),
+		}
 		if cfg.Debug {
 			serverOpts = append(serverOpts, server.WithErrorSensitivity(errawr.ErrorSensitivityAll))
 		}
 
+		if cfg.EvaluationTrace {
+			serverOpts = append(serverOpts, server.WithEvaluationTrace(true))
+		}
+
 		if cfg.SentryDSN != "" {
 			delegate, err := alerts.DelegateToSentry(cfg.SentryDSN)
 			if err != nil {
@@ -136,5 +243,18 @@ func main() {
 		return lifecycleutil.ListenWaitHTTP(ctx, s, listenOpts...)
 	})
 
-	os.Exit(mainutil.TrapAndWait(context.Background(), servers...))
+	code := mainutil.TrapAndWait(context.Background(), servers...)
+
+	if spans != nil {
+		// Flush any remaining spans before disconnecting from the collector.
+		spans.Shutdown()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := exporter.Shutdown(ctx); err != nil {
+			log().Warn("failed to shut down trace exporter", "error", err)
+		}
+		cancel()
+	}
+
+	os.Exit(code)
 }
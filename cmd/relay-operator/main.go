@@ -5,6 +5,7 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"flag"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"net/url"
@@ -18,6 +19,8 @@ import (
 	"github.com/puppetlabs/horsehead/v2/storage"
 	_ "github.com/puppetlabs/horsehead/v2/storage/file"
 	_ "github.com/puppetlabs/horsehead/v2/storage/gcs"
+	relayv1beta1 "github.com/puppetlabs/relay-core/pkg/apis/relay.sh/v1beta1"
+	"github.com/puppetlabs/relay-core/pkg/authenticate"
 	"github.com/puppetlabs/relay-core/pkg/model"
 	"github.com/puppetlabs/relay-core/pkg/operator/admission"
 	"github.com/puppetlabs/relay-core/pkg/operator/config"
@@ -48,7 +51,8 @@ func main() {
 	numWorkers := fs.Int("num-workers", 2, "the number of worker threads to spawn that process Workflow resources")
 	metricsEnabled := fs.Bool("metrics-enabled", false, "enables the metrics collection and server")
 	metricsServerBindAddr := fs.String("metrics-server-bind-addr", "localhost:3050", "the host:port to bind the metrics server to")
-	jwtSigningKeyFile := fs.String("jwt-signing-key-file", "", "path to a PEM-encoded RSA JWT key to use for signing step tokens")
+	jwtSigningKeyFile := fs.String("jwt-signing-key-file", "", "path to a PEM-encoded RSA or ECDSA JWT key to use for signing step tokens")
+	jwtSigningAlgorithmStr := fs.String("jwt-signing-algorithm", "", "the JWT signing algorithm to use, like RS256 or ES256 (defaults to RS512 for RSA keys and the algorithm matching the curve for ECDSA keys)")
 	vaultTransitPath := fs.String("vault-transit-path", "transit", "path to the Vault secrets engine to use for encrypting step tokens")
 	vaultTransitKey := fs.String("vault-transit-key", "metadata-api", "the Vault transit key to use")
 	metadataAPIURLStr := fs.String("metadata-api-url", "", "URL to the metadata API")
@@ -59,6 +63,7 @@ func main() {
 	sentryDSN := fs.String("sentry-dsn", "", "the Sentry DSN to use for error reporting")
 	dynamicRBACBinding := fs.Bool("dynamic-rbac-binding", false, "enable if RBAC rules are set up dynamically for the operator to reduce unhelpful reported errors")
 	toolInjectionImage := fs.String("tool-injection-image", model.DefaultToolInjectionImage, "tool injection image to use")
+	defaultSecretStoreBackendStr := fs.String("default-secret-store-backend", string(relayv1beta1.TenantSecretStoreBackendVault), "the backend to read secrets and connections from for tenants that do not specify one (Vault or Kubernetes)")
 
 	fs.Parse(os.Args[1:])
 
@@ -80,6 +85,13 @@ func main() {
 		log.Fatal("Error initializing the storage client from the -storage-addr", err)
 	}
 
+	defaultSecretStoreBackend := relayv1beta1.TenantSecretStoreBackend(*defaultSecretStoreBackendStr)
+	switch defaultSecretStoreBackend {
+	case relayv1beta1.TenantSecretStoreBackendVault, relayv1beta1.TenantSecretStoreBackendKubernetes:
+	default:
+		log.Fatal("Unknown secret store backend for -default-secret-store-backend", *defaultSecretStoreBackendStr)
+	}
+
 	if *webhookServerKeyDir == "" {
 		log.Fatal("The webhook server key directory -webhook-server-key-dir must be specified")
 	}
@@ -125,19 +137,20 @@ func main() {
 		log.Fatal("Error reading JWT signing key file", err)
 	}
 
-	jwtSigningKeyBlock, _ := pem.Decode(jwtSigningKeyBytes)
-	if jwtSigningKeyBlock == nil {
-		log.Fatal("Error parsing PEM")
-	} else if jwtSigningKeyBlock.Type != "RSA PRIVATE KEY" {
-		log.Fatal("PEM file does not contain an RSA private key")
+	jwtSigningKey, err := parseJWTSigningKey(jwtSigningKeyBytes)
+	if err != nil {
+		log.Fatal("Error parsing JWT signing key", err)
 	}
 
-	jwtSigningKey, err := x509.ParsePKCS1PrivateKey(jwtSigningKeyBlock.Bytes)
-	if err != nil {
-		log.Fatal("Error parsing RSA private key", err)
+	jwtSigningAlgorithm := jose.SignatureAlgorithm(*jwtSigningAlgorithmStr)
+	if jwtSigningAlgorithm == "" {
+		jwtSigningAlgorithm, err = authenticate.SignatureAlgorithmForKey(jwtSigningKey)
+		if err != nil {
+			log.Fatal("Error determining JWT signing algorithm", err)
+		}
 	}
 
-	jwtSigner, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS512, Key: jwtSigningKey}, &jose.SignerOptions{})
+	jwtSigner, err := authenticate.NewKeyIDSigner(jwtSigningAlgorithm, jwtSigningKey)
 	if err != nil {
 		log.Fatal("Error creating signer for JWTs", err)
 	}
@@ -152,19 +165,20 @@ func main() {
 	}
 
 	cfg := &config.WorkflowControllerConfig{
-		Environment:             *environment,
-		Standalone:              *standalone,
-		Namespace:               *kubeNamespace,
-		ImagePullSecret:         *imagePullSecret,
-		MaxConcurrentReconciles: *numWorkers,
-		MetadataAPIURL:          metadataAPIURL,
-		VaultTransitPath:        *vaultTransitPath,
-		VaultTransitKey:         *vaultTransitKey,
-		WebhookServerPort:       *webhookServerPort,
-		WebhookServerKeyDir:     *webhookServerKeyDir,
-		AlertsDelegate:          alertsDelegate,
-		DynamicRBACBinding:      *dynamicRBACBinding,
-		ToolInjectionImage:      *toolInjectionImage,
+		Environment:               *environment,
+		Standalone:                *standalone,
+		Namespace:                 *kubeNamespace,
+		ImagePullSecret:           *imagePullSecret,
+		MaxConcurrentReconciles:   *numWorkers,
+		MetadataAPIURL:            metadataAPIURL,
+		VaultTransitPath:          *vaultTransitPath,
+		VaultTransitKey:           *vaultTransitKey,
+		WebhookServerPort:         *webhookServerPort,
+		WebhookServerKeyDir:       *webhookServerKeyDir,
+		AlertsDelegate:            alertsDelegate,
+		DynamicRBACBinding:        *dynamicRBACBinding,
+		ToolInjectionImage:        *toolInjectionImage,
+		DefaultSecretStoreBackend: defaultSecretStoreBackend,
 	}
 
 	dm, err := dependency.NewDependencyManager(cfg, kcc, vc, jwtSigner, blobStore, mets)
@@ -204,3 +218,21 @@ func main() {
 		log.Fatal("Manager exited non-zero", err)
 	}
 }
+
+func parseJWTSigningKey(b []byte) (interface{}, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("PEM block of type %q does not contain a private key", block.Type)
+	}
+}
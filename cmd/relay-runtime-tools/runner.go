@@ -15,6 +15,7 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -149,6 +150,22 @@ func scan(mu *url.URL, scanner *bufio.Scanner, out *os.File, log *plspb.LogCreat
 	done <- true
 }
 
+// doMetadataAPIRequest sends a request to the metadata API. If the step has a
+// projected service account token, it is read on every request because the
+// kubelet rotates it periodically.
+func doMetadataAPIRequest(req *http.Request) (*http.Response, error) {
+	if tf := os.Getenv("METADATA_API_TOKEN_FILE"); tf != "" {
+		tok, err := ioutil.ReadFile(tf)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(tok)))
+	}
+
+	return http.DefaultClient.Do(req)
+}
+
 func getEnvironmentVariables(mu *url.URL) error {
 	ee := &url.URL{Path: "/environment"}
 
@@ -157,7 +174,7 @@ func getEnvironmentVariables(mu *url.URL) error {
 		return err
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := doMetadataAPIRequest(req)
 	if err != nil {
 		return err
 	}
@@ -194,7 +211,7 @@ func postLog(mu *url.URL, request *plspb.LogCreateRequest) (*plspb.LogCreateResp
 
 	req.Header.Set("Content-Type", "application/octet-stream")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := doMetadataAPIRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -228,7 +245,7 @@ func postLogMessage(mu *url.URL, request *plspb.LogMessageAppendRequest) (*plspb
 
 	req.Header.Set("Content-Type", "application/octet-stream")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := doMetadataAPIRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -268,7 +285,7 @@ func validateSchemas(mu *url.URL) error {
 
 	// We are ignoring the response for now because this endpoint just sends
 	// all validation errors to the error capturing system.
-	if _, err = http.DefaultClient.Do(req); err != nil {
+	if _, err = doMetadataAPIRequest(req); err != nil {
 		return err
 	}
 
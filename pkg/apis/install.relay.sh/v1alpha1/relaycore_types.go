@@ -63,6 +63,12 @@ type RelayCoreSpec struct {
 	// +optional
 	Vault *VaultConfig `json:"vault,omitempty"`
 
+	// SecretStore is the default configuration for storing the secrets and
+	// connections of tenants. Tenants may choose a different backend.
+	//
+	// +optional
+	SecretStore *SecretStoreConfig `json:"secretStore,omitempty"`
+
 	// SentryDSNSecretName is the secret that holds the DSN address for Sentry
 	// error and stacktrace collection. The secret object MUST have a data
 	// field called "dsn".
@@ -99,6 +105,13 @@ type OperatorConfig struct {
 	// +optional
 	JWTSigningKeySecretName *string `json:"jwtSigningKeySecretName,omitempty"`
 
+	// JWTSigningKeyRotation configures periodic rotation of the generated JWT
+	// signing key. This field is ignored unless GenerateJWTSigningKey is
+	// true.
+	//
+	// +optional
+	JWTSigningKeyRotation *JWTSigningKeyRotationConfig `json:"jwtSigningKeyRotation,omitempty"`
+
 	// MetricsEnabled enables the metrics server for the operator deployment
 	// and creates a service that can be used to scrape those metrics.
 	//
@@ -167,6 +180,24 @@ type OperatorConfig struct {
 	VaultAgentRole *string `json:"vaultAgentRole,omitempty"`
 }
 
+// JWTSigningKeyRotationConfig is the configuration for rotating the
+// generated JWT signing key.
+type JWTSigningKeyRotationConfig struct {
+	// Interval is how long a signing key is used before it is replaced.
+	//
+	// +kubebuilder:default="720h"
+	// +optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+
+	// Overlap is how long tokens signed by a replaced key continue to be
+	// accepted. It should be longer than the lifetime of a step token, which
+	// is a little over an hour.
+	//
+	// +kubebuilder:default="2h"
+	// +optional
+	Overlap metav1.Duration `json:"overlap,omitempty"`
+}
+
 type AdmissionWebhookServerConfig struct {
 	// TLSSecretName is the name of the secret that holds the tls cert
 	// files for webhooks. The secret object MUST have two data fields called
@@ -262,6 +293,18 @@ type VaultConfig struct {
 	Sidecar *VaultSidecar `json:"sidecar"`
 }
 
+type SecretStoreConfig struct {
+	// Backend is the storage system for the secrets and connections of
+	// tenants that do not specify one. Vault stores them at the paths given
+	// by annotations on workflow runs and webhook triggers. Kubernetes stores
+	// them in labelled secrets in the namespace of each tenant.
+	//
+	// +kubebuilder:default="Vault"
+	// +kubebuilder:validation:Enum=Vault;Kubernetes
+	// +optional
+	Backend string `json:"backend,omitempty"`
+}
+
 type VaultSidecar struct {
 	// +kubebuilder:default="vault:latest"
 	// +optional
@@ -29,6 +29,23 @@ func (in *AdmissionWebhookServerConfig) DeepCopy() *AdmissionWebhookServerConfig
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JWTSigningKeyRotationConfig) DeepCopyInto(out *JWTSigningKeyRotationConfig) {
+	*out = *in
+	out.Interval = in.Interval
+	out.Overlap = in.Overlap
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JWTSigningKeyRotationConfig.
+func (in *JWTSigningKeyRotationConfig) DeepCopy() *JWTSigningKeyRotationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(JWTSigningKeyRotationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetadataAPIConfig) DeepCopyInto(out *MetadataAPIConfig) {
 	*out = *in
@@ -93,6 +110,11 @@ func (in *OperatorConfig) DeepCopyInto(out *OperatorConfig) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.JWTSigningKeyRotation != nil {
+		in, out := &in.JWTSigningKeyRotation, &out.JWTSigningKeyRotation
+		*out = new(JWTSigningKeyRotationConfig)
+		**out = **in
+	}
 	if in.TenantSandboxingRuntimeClassName != nil {
 		in, out := &in.TenantSandboxingRuntimeClassName, &out.TenantSandboxingRuntimeClassName
 		*out = new(string)
@@ -224,6 +246,11 @@ func (in *RelayCoreSpec) DeepCopyInto(out *RelayCoreSpec) {
 		*out = new(VaultConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SecretStore != nil {
+		in, out := &in.SecretStore, &out.SecretStore
+		*out = new(SecretStoreConfig)
+		**out = **in
+	}
 	if in.SentryDSNSecretName != nil {
 		in, out := &in.SentryDSNSecretName, &out.SentryDSNSecretName
 		*out = new(string)
@@ -257,6 +284,21 @@ func (in *RelayCoreStatus) DeepCopy() *RelayCoreStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreConfig) DeepCopyInto(out *SecretStoreConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreConfig.
+func (in *SecretStoreConfig) DeepCopy() *SecretStoreConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ToolInjectionConfig) DeepCopyInto(out *ToolInjectionConfig) {
 	*out = *in
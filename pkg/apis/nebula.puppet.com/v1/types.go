@@ -70,6 +70,13 @@ type WorkflowStep struct {
 
 	// +optional
 	DependsOn []string `json:"depends_on,omitempty"`
+
+	// DynamicAccess allows the step to retrieve any secret, connection, or
+	// output available to the run instead of only those referenced in its
+	// spec, environment, and conditions.
+	//
+	// +optional
+	DynamicAccess bool `json:"dynamic_access,omitempty"`
 }
 
 type WorkflowRunStatusSummary struct {
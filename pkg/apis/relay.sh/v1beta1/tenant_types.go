@@ -36,6 +36,18 @@ type TenantSpec struct {
 	//
 	// +optional
 	TriggerEventSink TriggerEventSink `json:"triggerEventSink,omitempty"`
+
+	// Authentication configures how actions run for this tenant identify
+	// themselves to the metadata API.
+	//
+	// +optional
+	Authentication TenantAuthentication `json:"authentication,omitempty"`
+
+	// SecretStore configures where the secrets and connections used by
+	// actions run for this tenant are stored.
+	//
+	// +optional
+	SecretStore TenantSecretStore `json:"secretStore,omitempty"`
 }
 
 type NamespaceTemplate struct {
@@ -51,6 +63,82 @@ type NamespaceTemplate struct {
 	Metadata metav1.ObjectMeta `json:"metadata,omitempty"`
 }
 
+type TenantAuthenticationMethod string
+
+const (
+	// TenantAuthenticationMethodPodIP looks up the requesting pod by the
+	// source IP address of its requests to the metadata API. This is the
+	// default.
+	TenantAuthenticationMethodPodIP TenantAuthenticationMethod = "PodIP"
+
+	// TenantAuthenticationMethodServiceAccountToken projects a bound service
+	// account token into each step pod. The metadata API reviews the token
+	// with the Kubernetes API server to determine the requesting pod.
+	TenantAuthenticationMethodServiceAccountToken TenantAuthenticationMethod = "ServiceAccountToken"
+)
+
+type TenantAuthentication struct {
+	// Method is the mechanism used to identify pods to the metadata API.
+	//
+	// Webhook trigger and condition pods cannot mount a projected service
+	// account token, so they always use PodIP.
+	//
+	// +optional
+	// +kubebuilder:validation:Enum=PodIP;ServiceAccountToken
+	Method TenantAuthenticationMethod `json:"method,omitempty"`
+}
+
+type TenantSecretStoreBackend string
+
+const (
+	// TenantSecretStoreBackendVault reads secrets and connections from Vault
+	// at the paths given by the annotations on the workflow run or webhook
+	// trigger.
+	TenantSecretStoreBackendVault TenantSecretStoreBackend = "Vault"
+
+	// TenantSecretStoreBackendKubernetes reads secrets and connections from
+	// labelled Kubernetes secrets in the namespace the tenant's actions run
+	// in.
+	TenantSecretStoreBackendKubernetes TenantSecretStoreBackend = "Kubernetes"
+)
+
+type TenantSecretStore struct {
+	// Backend is the storage system for secrets and connections. If not
+	// specified, the operator's default backend is used.
+	//
+	// +optional
+	// +kubebuilder:validation:Enum=Vault;Kubernetes
+	Backend TenantSecretStoreBackend `json:"backend,omitempty"`
+
+	// Vault configures access to the tenant's secrets and connections when
+	// they are stored in Vault.
+	//
+	// +optional
+	Vault TenantSecretStoreVault `json:"vault,omitempty"`
+}
+
+type TenantSecretStoreVaultKVVersion string
+
+const (
+	TenantSecretStoreVaultKVVersion1 TenantSecretStoreVaultKVVersion = "v1"
+	TenantSecretStoreVaultKVVersion2 TenantSecretStoreVaultKVVersion = "v2"
+)
+
+type TenantSecretStoreVault struct {
+	// Namespace is the Vault Enterprise namespace that contains the KV engine
+	// for this tenant. If not specified, the root namespace is used.
+	//
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// KVVersion is the version of the KV engine that stores the tenant's
+	// secrets and connections. If not specified, v2 is assumed.
+	//
+	// +optional
+	// +kubebuilder:validation:Enum=v1;v2
+	KVVersion TenantSecretStoreVaultKVVersion `json:"kvVersion,omitempty"`
+}
+
 type ToolInjection struct {
 	// VolumeClaimTemplate is an optional definition of the PVC that will be
 	// populated and attached to every tenant container.
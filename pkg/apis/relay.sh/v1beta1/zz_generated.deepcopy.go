@@ -124,6 +124,21 @@ func (in *Tenant) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantAuthentication) DeepCopyInto(out *TenantAuthentication) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantAuthentication.
+func (in *TenantAuthentication) DeepCopy() *TenantAuthentication {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantAuthentication)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TenantCondition) DeepCopyInto(out *TenantCondition) {
 	*out = *in
@@ -172,12 +187,45 @@ func (in *TenantList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantSecretStore) DeepCopyInto(out *TenantSecretStore) {
+	*out = *in
+	out.Vault = in.Vault
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantSecretStore.
+func (in *TenantSecretStore) DeepCopy() *TenantSecretStore {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantSecretStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantSecretStoreVault) DeepCopyInto(out *TenantSecretStoreVault) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantSecretStoreVault.
+func (in *TenantSecretStoreVault) DeepCopy() *TenantSecretStoreVault {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantSecretStoreVault)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TenantSpec) DeepCopyInto(out *TenantSpec) {
 	*out = *in
 	in.NamespaceTemplate.DeepCopyInto(&out.NamespaceTemplate)
 	in.ToolInjection.DeepCopyInto(&out.ToolInjection)
 	in.TriggerEventSink.DeepCopyInto(&out.TriggerEventSink)
+	out.Authentication = in.Authentication
+	out.SecretStore = in.SecretStore
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantSpec.
@@ -39,13 +39,16 @@ type Authenticator struct {
 	injectors    []Injector
 }
 
-func (a *Authenticator) Authenticate(ctx context.Context) (bool, error) {
+func (a *Authenticator) Authenticate(ctx context.Context) (authenticated bool, err error) {
+	ctx, span := startSpan(ctx, "authenticate")
+	defer func() { endSpan(ctx, span, err) }()
+
 	validators := append([]Validator{}, a.validators...)
 	injectors := append([]Injector{}, a.injectors...)
 
 	state := NewInitializedAuthentication(&validators, &injectors)
 
-	raw, err := a.intermediary.Next(ctx, state)
+	raw, err := traceIntermediary(ctx, a.intermediary, state)
 	if _, ok := err.(*NotFoundError); ok {
 		log(ctx).Warn("authentication failed in intermediary", "error", err)
 		return false, nil
@@ -53,7 +56,7 @@ func (a *Authenticator) Authenticate(ctx context.Context) (bool, error) {
 		return false, err
 	}
 
-	claims, err := a.resolver.Resolve(ctx, state, raw)
+	claims, err := traceResolver(ctx, a.resolver, state, raw)
 	if _, ok := err.(*NotFoundError); ok {
 		log(ctx).Warn("authentication failed in resolver", "error", err)
 		return false, nil
@@ -15,6 +15,11 @@ type Claims struct {
 	KubernetesNamespaceUID        string `json:"k8s.io/namespace-uid,omitempty"`
 	KubernetesServiceAccountToken string `json:"k8s.io/service-account-token,omitempty"`
 
+	// KubernetesAuthenticationMethod is the way the pod holding these claims
+	// must identify itself to the metadata API. If not set, the pod is found
+	// by its IP address.
+	KubernetesAuthenticationMethod KubernetesAuthenticationMethod `json:"k8s.io/authentication-method,omitempty"`
+
 	// RelayDomainID represents a holder of tenants with high-level
 	// configuration like connections. In our SaaS service, domains correspond
 	// to accounts.
@@ -30,12 +35,31 @@ type Claims struct {
 	RelayKubernetesImmutableConfigMapName string `json:"relay.sh/k8s/immutable-config-map-name,omitempty"`
 	RelayKubernetesMutableConfigMapName   string `json:"relay.sh/k8s/mutable-config-map-name,omitempty"`
 
+	// RelayKubernetesSecretStoreNamespace is the namespace containing the
+	// Kubernetes secrets that hold the tenant's secrets and connections. If
+	// set, it takes precedence over the Vault paths.
+	RelayKubernetesSecretStoreNamespace string `json:"relay.sh/k8s/secret-store-namespace,omitempty"`
+
 	RelayVaultEnginePath     string `json:"relay.sh/vault/engine-path,omitempty"`
 	RelayVaultSecretPath     string `json:"relay.sh/vault/secret-path,omitempty"`
 	RelayVaultConnectionPath string `json:"relay.sh/vault/connection-path,omitempty"`
 
+	// RelayVaultNamespace is the Vault Enterprise namespace containing the
+	// engine at RelayVaultEnginePath.
+	RelayVaultNamespace string `json:"relay.sh/vault/namespace,omitempty"`
+
+	// RelayVaultEngineVersion is the version of the KV engine at
+	// RelayVaultEnginePath, either "v1" or "v2". If not set, the engine is
+	// assumed to be a KV V2 engine.
+	RelayVaultEngineVersion string `json:"relay.sh/vault/engine-version,omitempty"`
+
 	RelayEventAPIURL   *jsonutil.URL `json:"relay.sh/event/api/url,omitempty"`
 	RelayEventAPIToken string        `json:"relay.sh/event/api/token,omitempty"`
+
+	// RelayPermissions restricts the secrets, connections, and outputs the
+	// action may access. If not set, the action may access any of them in
+	// its tenant.
+	RelayPermissions *model.ActionPermissions `json:"relay.sh/permissions,omitempty"`
 }
 
 func (c *Claims) Action() model.Action {
@@ -32,6 +32,12 @@ func NewHTTPAuthorizationHeaderIntermediary(r *http.Request) *HTTPAuthorizationH
 	}
 }
 
+// HTTPBearerToken returns the token from the Authorization header of the
+// request if it uses the Bearer scheme.
+func HTTPBearerToken(r *http.Request) (string, bool) {
+	return parseBearerAuth(r.Header.Get("authorization"))
+}
+
 func parseBearerAuth(auth string) (token string, ok bool) {
 	const prefix = "Bearer "
 	if len(auth) < len(prefix) || !strings.EqualFold(auth[:len(prefix)], prefix) {
@@ -34,7 +34,7 @@ type ChainIntermediary struct {
 var _ Intermediary = &ChainIntermediary{}
 
 func (ci *ChainIntermediary) Next(ctx context.Context, state *Authentication) (Raw, error) {
-	raw, err := ci.initial.Next(ctx, state)
+	raw, err := traceIntermediary(ctx, ci.initial, state)
 	if err != nil {
 		return nil, err
 	}
@@ -45,7 +45,7 @@ func (ci *ChainIntermediary) Next(ctx context.Context, state *Authentication) (R
 			return nil, err
 		}
 
-		raw, err = next.Next(ctx, state)
+		raw, err = traceIntermediary(ctx, next, state)
 		if err != nil {
 			return nil, err
 		}
@@ -2,6 +2,12 @@ package authenticate
 
 import (
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
 
 	jose "gopkg.in/square/go-jose.v2"
 	"gopkg.in/square/go-jose.v2/jwt"
@@ -84,3 +90,79 @@ func NewHS256KeySignerIssuer(key []byte) (*KeySignerIssuer, error) {
 
 	return NewKeySignerIssuer(signer), nil
 }
+
+// KeyID computes a stable identifier for the given asymmetric key using its
+// RFC 7638 thumbprint. The private and public halves of a key pair have the
+// same ID.
+func KeyID(key interface{}) (string, error) {
+	jwk := jose.JSONWebKey{Key: key}
+	if !jwk.IsPublic() {
+		jwk = jwk.Public()
+	}
+
+	if jwk.Key == nil {
+		return "", fmt.Errorf("authenticate: cannot compute key ID for key of type %T", key)
+	}
+
+	tp, err := jwk.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(tp), nil
+}
+
+// NewKeyIDSigner creates a signer that includes the key ID of the given
+// asymmetric private key in the header of every token it signs. Resolvers
+// that use a key set can then select the correct key without trying each
+// one.
+func NewKeyIDSigner(alg jose.SignatureAlgorithm, key interface{}) (jose.Signer, error) {
+	kid, err := KeyID(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return jose.NewSigner(jose.SigningKey{
+		Algorithm: alg,
+		Key:       jose.JSONWebKey{Key: key, KeyID: kid, Algorithm: string(alg)},
+	}, &jose.SignerOptions{})
+}
+
+// SignatureAlgorithmForKey returns the signature algorithm Relay uses for the
+// given asymmetric private key. RSA keys use RS512 for compatibility with
+// existing deployments; ECDSA keys use the algorithm that matches their curve.
+func SignatureAlgorithmForKey(key interface{}) (jose.SignatureAlgorithm, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return jose.RS512, nil
+	case *ecdsa.PrivateKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return jose.ES256, nil
+		case elliptic.P384():
+			return jose.ES384, nil
+		case elliptic.P521():
+			return jose.ES512, nil
+		}
+
+		return "", fmt.Errorf("authenticate: unsupported elliptic curve %s", k.Curve.Params().Name)
+	default:
+		return "", fmt.Errorf("authenticate: unsupported signing key of type %T", key)
+	}
+}
+
+// NewAsymmetricKeySignerIssuer creates an issuer that signs tokens with the
+// given RSA or ECDSA private key and identifies the key in each token.
+func NewAsymmetricKeySignerIssuer(key interface{}) (*KeySignerIssuer, error) {
+	alg, err := SignatureAlgorithmForKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := NewKeyIDSigner(alg, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeySignerIssuer(signer), nil
+}
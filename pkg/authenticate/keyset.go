@@ -0,0 +1,332 @@
+package authenticate
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// DefaultKeySetRefreshInterval is how often a key set that loads its keys
+	// from an external source checks for new keys.
+	DefaultKeySetRefreshInterval = 1 * time.Minute
+
+	// KeySetJWKSKey is the name of the data key in a Kubernetes secret that
+	// holds a JSON Web Key Set.
+	KeySetJWKSKey = "jwks.json"
+)
+
+// KeySet provides the keys that may have been used to sign a token. During a
+// key rotation, a key set contains both the current and previous keys.
+type KeySet interface {
+	Keys(ctx context.Context) (*jose.JSONWebKeySet, error)
+}
+
+// StaticKeySet is a key set that never changes.
+type StaticKeySet struct {
+	keys *jose.JSONWebKeySet
+}
+
+var _ KeySet = &StaticKeySet{}
+
+func (sks *StaticKeySet) Keys(ctx context.Context) (*jose.JSONWebKeySet, error) {
+	return sks.keys, nil
+}
+
+func NewStaticKeySet(keys ...jose.JSONWebKey) *StaticKeySet {
+	return &StaticKeySet{
+		keys: &jose.JSONWebKeySet{Keys: keys},
+	}
+}
+
+// KeySetLoaderFunc retrieves the keys for a RefreshingKeySet.
+type KeySetLoaderFunc func(ctx context.Context) (*jose.JSONWebKeySet, error)
+
+// RefreshingKeySet periodically reloads its keys. If a reload fails, it
+// continues to use the keys it last loaded successfully.
+type RefreshingKeySet struct {
+	loader   KeySetLoaderFunc
+	interval time.Duration
+	now      func() time.Time
+
+	mut      sync.Mutex
+	keys     *jose.JSONWebKeySet
+	loadedAt time.Time
+}
+
+var _ KeySet = &RefreshingKeySet{}
+
+func (rks *RefreshingKeySet) Keys(ctx context.Context) (*jose.JSONWebKeySet, error) {
+	rks.mut.Lock()
+	defer rks.mut.Unlock()
+
+	now := rks.now()
+	if rks.keys != nil && now.Before(rks.loadedAt.Add(rks.interval)) {
+		return rks.keys, nil
+	}
+
+	keys, err := rks.loader(ctx)
+	if err != nil {
+		if rks.keys == nil {
+			return nil, err
+		}
+
+		log(ctx).Warn("failed to refresh key set; using previously loaded keys", "error", err)
+		return rks.keys, nil
+	}
+
+	rks.keys = keys
+	rks.loadedAt = now
+
+	return keys, nil
+}
+
+type RefreshingKeySetOption func(rks *RefreshingKeySet)
+
+func RefreshingKeySetWithInterval(interval time.Duration) RefreshingKeySetOption {
+	return func(rks *RefreshingKeySet) {
+		rks.interval = interval
+	}
+}
+
+func RefreshingKeySetWithClock(now func() time.Time) RefreshingKeySetOption {
+	return func(rks *RefreshingKeySet) {
+		rks.now = now
+	}
+}
+
+func NewRefreshingKeySet(loader KeySetLoaderFunc, opts ...RefreshingKeySetOption) *RefreshingKeySet {
+	rks := &RefreshingKeySet{
+		loader:   loader,
+		interval: DefaultKeySetRefreshInterval,
+		now:      time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(rks)
+	}
+
+	return rks
+}
+
+// NewJWKSFileKeySet creates a key set from a file containing a JSON Web Key
+// Set, such as one projected into a pod from a Kubernetes secret.
+func NewJWKSFileKeySet(path string, opts ...RefreshingKeySetOption) *RefreshingKeySet {
+	return NewRefreshingKeySet(func(ctx context.Context) (*jose.JSONWebKeySet, error) {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		return parseJWKS(b)
+	}, opts...)
+}
+
+// NewJWKSURLKeySet creates a key set by retrieving a JSON Web Key Set from
+// the given URL.
+func NewJWKSURLKeySet(client *http.Client, u string, opts ...RefreshingKeySetOption) *RefreshingKeySet {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return NewRefreshingKeySet(func(ctx context.Context) (*jose.JSONWebKeySet, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("authenticate: unexpected status %d retrieving key set from %s", resp.StatusCode, u)
+		}
+
+		b, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return parseJWKS(b)
+	}, opts...)
+}
+
+// NewKubernetesSecretKeySet creates a key set from the data in a Kubernetes
+// secret. The secret may contain a JSON Web Key Set under the key
+// "jwks.json", any number of PEM-encoded public keys, or both.
+func NewKubernetesSecretKeySet(client kubernetes.Interface, namespace, name string, opts ...RefreshingKeySetOption) *RefreshingKeySet {
+	return NewRefreshingKeySet(func(ctx context.Context) (*jose.JSONWebKeySet, error) {
+		secret, err := client.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		keys := &jose.JSONWebKeySet{}
+
+		// Sort the data keys so the key set is stable across reloads.
+		dataKeys := make([]string, 0, len(secret.Data))
+		for dataKey := range secret.Data {
+			dataKeys = append(dataKeys, dataKey)
+		}
+		sort.Strings(dataKeys)
+
+		for _, dataKey := range dataKeys {
+			data := secret.Data[dataKey]
+
+			if dataKey == KeySetJWKSKey {
+				jwks, err := parseJWKS(data)
+				if err != nil {
+					return nil, fmt.Errorf("authenticate: secret %s/%s: key %q: %+v", namespace, name, dataKey, err)
+				}
+
+				keys.Keys = append(keys.Keys, jwks.Keys...)
+				continue
+			}
+
+			jwk, ok, err := parsePublicKeyPEM(data)
+			if err != nil {
+				return nil, fmt.Errorf("authenticate: secret %s/%s: key %q: %+v", namespace, name, dataKey, err)
+			} else if !ok {
+				continue
+			}
+
+			keys.Keys = append(keys.Keys, jwk)
+		}
+
+		return keys, nil
+	}, opts...)
+}
+
+func parseJWKS(b []byte) (*jose.JSONWebKeySet, error) {
+	jwks := &jose.JSONWebKeySet{}
+	if err := json.Unmarshal(b, jwks); err != nil {
+		return nil, err
+	}
+
+	// Never verify with private key material, even if it was (mistakenly)
+	// published.
+	for i, key := range jwks.Keys {
+		if !key.IsPublic() {
+			jwks.Keys[i] = key.Public()
+		}
+	}
+
+	return jwks, nil
+}
+
+// parsePublicKeyPEM reads a PKIX-encoded public key. Data that does not
+// contain a public key, like a private key or certificate, is ignored.
+func parsePublicKeyPEM(b []byte) (jose.JSONWebKey, bool, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return jose.JSONWebKey{}, false, nil
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY", "RSA PUBLIC KEY", "EC PUBLIC KEY":
+	default:
+		return jose.JSONWebKey{}, false, nil
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return jose.JSONWebKey{}, false, err
+	}
+
+	kid, err := KeyID(key)
+	if err != nil {
+		return jose.JSONWebKey{}, false, err
+	}
+
+	return jose.JSONWebKey{Key: key, KeyID: kid, Use: "sig"}, true, nil
+}
+
+// KeySetResolver validates tokens using any of the keys in a key set. If a
+// token identifies its signing key, only keys with that ID are considered.
+type KeySetResolver struct {
+	keys        KeySet
+	expectation jwt.Expected
+}
+
+var _ Resolver = &KeySetResolver{}
+
+func (ksr *KeySetResolver) Resolve(ctx context.Context, state *Authentication, raw Raw) (*Claims, error) {
+	tok, err := jwt.ParseSigned(string(raw))
+	if err != nil {
+		return nil, &NotFoundError{Reason: "key set: JWT parse error", Causes: []error{err}}
+	}
+
+	keys, err := ksr.keys.Keys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var kid string
+	for _, header := range tok.Headers {
+		if header.KeyID != "" {
+			kid = header.KeyID
+			break
+		}
+	}
+
+	candidates := keys.Keys
+	if kid != "" {
+		candidates = keys.Key(kid)
+	}
+
+	if len(candidates) == 0 {
+		return nil, &NotFoundError{Reason: fmt.Sprintf("key set: no key found to validate JWT with key ID %q", kid)}
+	}
+
+	var causes []error
+	for _, candidate := range candidates {
+		claims := &Claims{}
+		if err := tok.Claims(candidate.Key, claims); err != nil {
+			causes = append(causes, err)
+			continue
+		}
+
+		if err := claims.Validate(ksr.expectation); err != nil {
+			return nil, &NotFoundError{Reason: "key set: could not validate JWT claims", Causes: []error{err}}
+		}
+
+		return claims, nil
+	}
+
+	return nil, &NotFoundError{Reason: "key set: could not validate JWT signature", Causes: causes}
+}
+
+type KeySetResolverOption func(ksr *KeySetResolver)
+
+func KeySetResolverWithExpectation(e jwt.Expected) KeySetResolverOption {
+	return func(ksr *KeySetResolver) {
+		ksr.expectation = e
+	}
+}
+
+func NewKeySetResolver(keys KeySet, opts ...KeySetResolverOption) *KeySetResolver {
+	ksr := &KeySetResolver{
+		keys: keys,
+	}
+
+	for _, opt := range opts {
+		opt(ksr)
+	}
+
+	return ksr
+}
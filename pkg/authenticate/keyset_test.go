@@ -0,0 +1,226 @@
+package authenticate_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/puppetlabs/relay-core/pkg/authenticate"
+	"github.com/puppetlabs/relay-core/pkg/util/testutil"
+	"github.com/stretchr/testify/require"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func publicJSONWebKey(t *testing.T, key crypto.Signer) jose.JSONWebKey {
+	kid, err := authenticate.KeyID(key)
+	require.NoError(t, err)
+
+	return jose.JSONWebKey{Key: key.Public(), KeyID: kid, Use: "sig"}
+}
+
+func TestKeySetResolverWithKeyIDs(t *testing.T) {
+	ctx := context.Background()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	keys := authenticate.NewStaticKeySet(publicJSONWebKey(t, rsaKey), publicJSONWebKey(t, ecKey))
+
+	tests := []struct {
+		Name          string
+		Algorithm     jose.SignatureAlgorithm
+		Key           interface{}
+		ExpectedValid bool
+	}{
+		{Name: "RS256", Algorithm: jose.RS256, Key: rsaKey, ExpectedValid: true},
+		{Name: "RS512", Algorithm: jose.RS512, Key: rsaKey, ExpectedValid: true},
+		{Name: "ES256", Algorithm: jose.ES256, Key: ecKey, ExpectedValid: true},
+		{Name: "Unknown", Algorithm: jose.ES256, Key: otherKey},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			signer, err := authenticate.NewKeyIDSigner(test.Algorithm, test.Key)
+			require.NoError(t, err)
+
+			raw, err := authenticate.NewKeySignerIssuer(signer).Issue(ctx, &authenticate.Claims{Claims: &jwt.Claims{Subject: "foo"}})
+			require.NoError(t, err)
+
+			tok, err := jwt.ParseSigned(string(raw))
+			require.NoError(t, err)
+			require.Len(t, tok.Headers, 1)
+
+			kid, err := authenticate.KeyID(test.Key)
+			require.NoError(t, err)
+			require.Equal(t, kid, tok.Headers[0].KeyID)
+
+			claims, err := authenticate.NewKeySetResolver(keys).Resolve(ctx, authenticate.NewAuthentication(), raw)
+			if test.ExpectedValid {
+				require.NoError(t, err)
+				require.Equal(t, "foo", claims.Subject)
+			} else {
+				require.IsType(t, &authenticate.NotFoundError{}, err)
+			}
+		})
+	}
+}
+
+func TestKeySetResolverWithoutKeyID(t *testing.T) {
+	ctx := context.Background()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	// Tokens issued before key IDs were introduced should still validate
+	// against any key in the set.
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS512, Key: key}, &jose.SignerOptions{})
+	require.NoError(t, err)
+
+	raw, err := authenticate.NewKeySignerIssuer(signer).Issue(ctx, &authenticate.Claims{Claims: &jwt.Claims{Subject: "foo"}})
+	require.NoError(t, err)
+
+	keys := authenticate.NewStaticKeySet(publicJSONWebKey(t, otherKey), publicJSONWebKey(t, key))
+
+	claims, err := authenticate.NewKeySetResolver(keys).Resolve(ctx, authenticate.NewAuthentication(), raw)
+	require.NoError(t, err)
+	require.Equal(t, "foo", claims.Subject)
+}
+
+func TestJWKSFileKeySetRefresh(t *testing.T) {
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "relay-keyset-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	oldKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	write := func(keys ...jose.JSONWebKey) {
+		b, err := json.Marshal(&jose.JSONWebKeySet{Keys: keys})
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "jwks.json"), b, 0600))
+	}
+
+	now := time.Now()
+	clock := func() time.Time { return now }
+
+	write(publicJSONWebKey(t, oldKey))
+
+	ks := authenticate.NewJWKSFileKeySet(
+		filepath.Join(dir, "jwks.json"),
+		authenticate.RefreshingKeySetWithInterval(time.Minute),
+		authenticate.RefreshingKeySetWithClock(clock),
+	)
+
+	issue := func(key *ecdsa.PrivateKey) authenticate.Raw {
+		issuer, err := authenticate.NewAsymmetricKeySignerIssuer(key)
+		require.NoError(t, err)
+
+		raw, err := issuer.Issue(ctx, &authenticate.Claims{Claims: &jwt.Claims{Subject: "foo"}})
+		require.NoError(t, err)
+
+		return raw
+	}
+
+	resolver := authenticate.NewKeySetResolver(ks)
+
+	_, err = resolver.Resolve(ctx, authenticate.NewAuthentication(), issue(oldKey))
+	require.NoError(t, err)
+
+	_, err = resolver.Resolve(ctx, authenticate.NewAuthentication(), issue(newKey))
+	require.IsType(t, &authenticate.NotFoundError{}, err)
+
+	// Rotate the keys on disk. The old key set stays in use until the refresh
+	// interval passes.
+	write(publicJSONWebKey(t, newKey), publicJSONWebKey(t, oldKey))
+
+	_, err = resolver.Resolve(ctx, authenticate.NewAuthentication(), issue(newKey))
+	require.IsType(t, &authenticate.NotFoundError{}, err)
+
+	now = now.Add(2 * time.Minute)
+
+	_, err = resolver.Resolve(ctx, authenticate.NewAuthentication(), issue(newKey))
+	require.NoError(t, err)
+
+	_, err = resolver.Resolve(ctx, authenticate.NewAuthentication(), issue(oldKey))
+	require.NoError(t, err)
+
+	// A broken file should not discard the keys we already know about.
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "jwks.json"), []byte("garbage"), 0600))
+	now = now.Add(2 * time.Minute)
+
+	_, err = resolver.Resolve(ctx, authenticate.NewAuthentication(), issue(newKey))
+	require.NoError(t, err)
+}
+
+func TestKubernetesSecretKeySet(t *testing.T) {
+	ctx := context.Background()
+
+	pemKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwksKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	pub, err := x509.MarshalPKIXPublicKey(pemKey.Public())
+	require.NoError(t, err)
+
+	jwks, err := json.Marshal(&jose.JSONWebKeySet{Keys: []jose.JSONWebKey{publicJSONWebKey(t, jwksKey)}})
+	require.NoError(t, err)
+
+	client := testutil.NewMockKubernetesClient(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "relay-system",
+			Name:      "signing-keys",
+		},
+		Data: map[string][]byte{
+			"public-key.pem":  pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub}),
+			"private-key.pem": pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(pemKey)}),
+			"jwks.json":       jwks,
+		},
+	})
+
+	ks := authenticate.NewKubernetesSecretKeySet(client, "relay-system", "signing-keys")
+
+	keys, err := ks.Keys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys.Keys, 2)
+
+	for _, key := range []interface{}{pemKey, jwksKey} {
+		issuer, err := authenticate.NewAsymmetricKeySignerIssuer(key)
+		require.NoError(t, err)
+
+		raw, err := issuer.Issue(ctx, &authenticate.Claims{Claims: &jwt.Claims{Subject: "foo"}})
+		require.NoError(t, err)
+
+		claims, err := authenticate.NewKeySetResolver(ks).Resolve(ctx, authenticate.NewAuthentication(), raw)
+		require.NoError(t, err)
+		require.Equal(t, "foo", claims.Subject)
+	}
+}
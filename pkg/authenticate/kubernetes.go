@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strings"
 	"time"
 
 	"github.com/puppetlabs/relay-core/pkg/model"
 	"github.com/puppetlabs/relay-core/pkg/util/retry"
 	tekton "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -21,6 +23,23 @@ import (
 const (
 	KubernetesTokenAnnotation   = "relay.sh/token"
 	KubernetesSubjectAnnotation = "relay.sh/subject"
+
+	KubernetesServiceAccountPodNameExtra = "authentication.kubernetes.io/pod-name"
+	KubernetesServiceAccountPodUIDExtra  = "authentication.kubernetes.io/pod-uid"
+)
+
+// KubernetesAuthenticationMethod is the way the metadata API finds the pod
+// making a request.
+type KubernetesAuthenticationMethod string
+
+const (
+	// KubernetesAuthenticationMethodPodIP finds the pod by the source IP
+	// address of the request.
+	KubernetesAuthenticationMethodPodIP KubernetesAuthenticationMethod = "PodIP"
+
+	// KubernetesAuthenticationMethodServiceAccountToken finds the pod by
+	// reviewing the service account token bound to it.
+	KubernetesAuthenticationMethodServiceAccountToken KubernetesAuthenticationMethod = "ServiceAccountToken"
 )
 
 type KubernetesIntermediaryMetadata struct {
@@ -59,47 +78,173 @@ func NewKubernetesInterfaceForConfig(cfg *rest.Config) (*KubernetesInterface, er
 	}, nil
 }
 
-// KubernetesIntermediary looks up a pod by IP and reads the value of an
-// annotation as the authentication credential.
+// KubernetesIntermediary looks up a pod and reads the value of an annotation
+// as the authentication credential. The pod is found either by the IP address
+// of the request or by reviewing a service account token bound to it.
 type KubernetesIntermediary struct {
-	client *KubernetesInterface
-	ip     net.IP
+	client   *KubernetesInterface
+	podCache *KubernetesPodCache
+	method   KubernetesAuthenticationMethod
+	lookup   kubernetesPodLookupFunc
 }
 
 var _ Intermediary = &KubernetesIntermediary{}
 
-func (ki *KubernetesIntermediary) next(ctx context.Context, state *Authentication) (Raw, *KubernetesIntermediaryMetadata, error) {
-	if len(ki.ip) == 0 || ki.ip.IsUnspecified() {
-		return nil, nil, &NotFoundError{Reason: "kubernetes: no IP address to look up"}
+type kubernetesPodLookupFunc func(ctx context.Context, client *KubernetesInterface, podCache *KubernetesPodCache) (*corev1.Pod, error)
+
+func kubernetesPodLookupByIP(ip net.IP) kubernetesPodLookupFunc {
+	return func(ctx context.Context, client *KubernetesInterface, podCache *KubernetesPodCache) (*corev1.Pod, error) {
+		if len(ip) == 0 || ip.IsUnspecified() {
+			return nil, &NotFoundError{Reason: "kubernetes: no IP address to look up"}
+		}
+
+		if podCache != nil {
+			if cached, ok := podCache.PodByIP(ip); ok {
+				// The cache only watches Relay pods, so if it hasn't seen a
+				// pod terminate yet, the IP address may already belong to a
+				// pod it doesn't know about. Make sure the pod is still the
+				// one using the IP address before trusting it.
+				pod, err := client.CoreV1().Pods(cached.GetNamespace()).Get(cached.GetName(), metav1.GetOptions{})
+				if err != nil && !errors.IsNotFound(err) {
+					return nil, err
+				} else if err == nil && kubernetesPodHasIP(pod, cached.GetUID(), ip) {
+					return pod, nil
+				}
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, PodValidationTimeout)
+		defer cancel()
+
+		var pod corev1.Pod
+		err := retry.Retry(ctx, PodValidationBackoffFrequency, func() *retry.RetryError {
+			pods, err := client.CoreV1().Pods("").List(metav1.ListOptions{
+				FieldSelector: fields.Set{
+					"status.podIP": ip.String(),
+					"status.phase": string(corev1.PodRunning),
+				}.String(),
+			})
+			if err != nil {
+				return retry.RetryPermanent(err)
+			}
+
+			switch len(pods.Items) {
+			case 0:
+				return retry.RetryTransient(&NotFoundError{Reason: fmt.Sprintf("kubernetes: no pod found with IP %s", ip)})
+			case 1:
+				pod = pods.Items[0]
+				return retry.RetryPermanent(nil)
+			default:
+				// Multiple pods with the same IP? This is just nonsense and we'll
+				// throw out the request.
+				return retry.RetryPermanent(&NotFoundError{Reason: fmt.Sprintf("kubernetes: multiple pods found with IP %s (bug?)", ip)})
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &pod, nil
 	}
+}
 
-	ctx, cancel := context.WithTimeout(ctx, PodValidationTimeout)
-	defer cancel()
+// kubernetesPodHasIP returns true if the given pod has the expected UID and is
+// running with the given IP address.
+func kubernetesPodHasIP(pod *corev1.Pod, uid types.UID, ip net.IP) bool {
+	return pod.GetUID() == uid &&
+		pod.GetDeletionTimestamp() == nil &&
+		pod.Status.Phase == corev1.PodRunning &&
+		pod.Status.PodIP == ip.String()
+}
+
+func kubernetesPodLookupByServiceAccountToken(token string) kubernetesPodLookupFunc {
+	return func(ctx context.Context, client *KubernetesInterface, podCache *KubernetesPodCache) (*corev1.Pod, error) {
+		if token == "" {
+			return nil, &NotFoundError{Reason: "kubernetes: no service account token to review"}
+		}
 
-	var pod corev1.Pod
-	err := retry.Retry(ctx, PodValidationBackoffFrequency, func() *retry.RetryError {
-		pods, err := ki.client.CoreV1().Pods("").List(metav1.ListOptions{
-			FieldSelector: fields.Set{
-				"status.podIP": ki.ip.String(),
-				"status.phase": string(corev1.PodRunning),
-			}.String(),
+		tr, err := client.AuthenticationV1().TokenReviews().CreateContext(ctx, &authenticationv1.TokenReview{
+			Spec: authenticationv1.TokenReviewSpec{
+				Token:     token,
+				Audiences: []string{MetadataAPIAudienceV1},
+			},
 		})
 		if err != nil {
-			return retry.RetryPermanent(err)
+			return nil, err
 		}
 
-		switch len(pods.Items) {
-		case 0:
-			return retry.RetryTransient(&NotFoundError{Reason: fmt.Sprintf("kubernetes: no pod found with IP %s", ki.ip)})
-		case 1:
-			pod = pods.Items[0]
-			return retry.RetryPermanent(nil)
-		default:
-			// Multiple pods with the same IP? This is just nonsense and we'll throw
-			// out the request.
-			return retry.RetryPermanent(&NotFoundError{Reason: fmt.Sprintf("kubernetes: multiple pods found with IP %s (bug?)", ki.ip)})
+		if !tr.Status.Authenticated {
+			reason := "kubernetes: service account token is not valid"
+			if tr.Status.Error != "" {
+				reason += ": " + tr.Status.Error
+			}
+
+			return nil, &NotFoundError{Reason: reason}
 		}
-	})
+
+		// If the API server's authenticator is not audience-aware, it will not
+		// return any audiences and we have to assume the token was issued for
+		// something else.
+		var audienceOK bool
+		for _, aud := range tr.Status.Audiences {
+			if aud == MetadataAPIAudienceV1 {
+				audienceOK = true
+				break
+			}
+		}
+		if !audienceOK {
+			return nil, &NotFoundError{Reason: "kubernetes: service account token is not intended for the metadata API"}
+		}
+
+		namespace, _, ok := splitServiceAccountUsername(tr.Status.User.Username)
+		if !ok {
+			return nil, &NotFoundError{Reason: fmt.Sprintf("kubernetes: token review user %q is not a service account", tr.Status.User.Username)}
+		}
+
+		podName := tr.Status.User.Extra[KubernetesServiceAccountPodNameExtra]
+		podUID := tr.Status.User.Extra[KubernetesServiceAccountPodUIDExtra]
+		if len(podName) != 1 || len(podUID) != 1 {
+			return nil, &NotFoundError{Reason: "kubernetes: service account token is not bound to a pod"}
+		}
+
+		if podCache != nil {
+			if pod, ok := podCache.PodByUID(namespace, podName[0], types.UID(podUID[0])); ok {
+				return pod, nil
+			}
+		}
+
+		pod, err := client.CoreV1().Pods(namespace).Get(podName[0], metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return nil, &NotFoundError{Reason: "kubernetes: pod bound to service account token no longer exists"}
+		} else if err != nil {
+			return nil, err
+		}
+
+		// Make sure we didn't get a new pod with the same name.
+		if pod.GetUID() != types.UID(podUID[0]) {
+			return nil, &NotFoundError{Reason: "kubernetes: pod bound to service account token no longer exists"}
+		}
+
+		return pod, nil
+	}
+}
+
+func splitServiceAccountUsername(username string) (namespace, name string, ok bool) {
+	const prefix = "system:serviceaccount:"
+	if !strings.HasPrefix(username, prefix) {
+		return
+	}
+
+	parts := strings.Split(username[len(prefix):], ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return
+	}
+
+	return parts[0], parts[1], true
+}
+
+func (ki *KubernetesIntermediary) next(ctx context.Context, state *Authentication) (Raw, *KubernetesIntermediaryMetadata, error) {
+	pod, err := ki.lookup(ctx, ki.client, ki.podCache)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -184,6 +329,23 @@ func (ki *KubernetesIntermediary) next(ctx context.Context, state *Authenticatio
 		return r, nil
 	}))
 
+	// Authentication method validation. A pod must identify itself the way
+	// its tenant is configured to, so that a tenant that requires service
+	// account tokens can't be reached by IP address and vice versa.
+	state.AddValidator(ValidatorFunc(func(ctx context.Context, claims *Claims) (bool, error) {
+		method := claims.KubernetesAuthenticationMethod
+		if method == "" {
+			method = KubernetesAuthenticationMethodPodIP
+		}
+
+		r := method == ki.method
+		if !r {
+			log(ctx).Warn("kubernetes: authentication method of claim does not match authentication method of request", "claim-authentication-method", method, "request-authentication-method", ki.method)
+		}
+
+		return r, nil
+	}))
+
 	return Raw(tok), md, nil
 }
 
@@ -208,9 +370,39 @@ func (ki *KubernetesIntermediary) Next(ctx context.Context, state *Authenticatio
 	return raw, err
 }
 
-func NewKubernetesIntermediary(client *KubernetesInterface, ip net.IP) *KubernetesIntermediary {
-	return &KubernetesIntermediary{
+type KubernetesIntermediaryOption func(ki *KubernetesIntermediary)
+
+// KubernetesIntermediaryWithPodCache consults the given cache before querying
+// the API server for the requesting pod.
+func KubernetesIntermediaryWithPodCache(podCache *KubernetesPodCache) KubernetesIntermediaryOption {
+	return func(ki *KubernetesIntermediary) {
+		ki.podCache = podCache
+	}
+}
+
+func newKubernetesIntermediary(client *KubernetesInterface, method KubernetesAuthenticationMethod, lookup kubernetesPodLookupFunc, opts []KubernetesIntermediaryOption) *KubernetesIntermediary {
+	ki := &KubernetesIntermediary{
 		client: client,
-		ip:     ip,
+		method: method,
+		lookup: lookup,
+	}
+
+	for _, opt := range opts {
+		opt(ki)
 	}
+
+	return ki
+}
+
+// NewKubernetesIntermediary creates an intermediary that finds the pod with
+// the given IP address.
+func NewKubernetesIntermediary(client *KubernetesInterface, ip net.IP, opts ...KubernetesIntermediaryOption) *KubernetesIntermediary {
+	return newKubernetesIntermediary(client, KubernetesAuthenticationMethodPodIP, kubernetesPodLookupByIP(ip), opts)
+}
+
+// NewKubernetesServiceAccountTokenIntermediary creates an intermediary that
+// finds the pod a projected service account token is bound to. The token must
+// have the metadata API as its audience.
+func NewKubernetesServiceAccountTokenIntermediary(client *KubernetesInterface, token string, opts ...KubernetesIntermediaryOption) *KubernetesIntermediary {
+	return newKubernetesIntermediary(client, KubernetesAuthenticationMethodServiceAccountToken, kubernetesPodLookupByServiceAccountToken(token), opts)
 }
@@ -13,9 +13,12 @@ import (
 	"github.com/stretchr/testify/require"
 	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
 	"gopkg.in/square/go-jose.v2/jwt"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 func TestKubernetesIntermediary(t *testing.T) {
@@ -255,3 +258,246 @@ func TestKubernetesIntermediaryChainToVault(t *testing.T) {
 		require.Equal(t, authenticate.Raw("my-auth-token"), raw)
 	})
 }
+
+func TestKubernetesServiceAccountTokenIntermediary(t *testing.T) {
+	ctx := context.Background()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "my-test-namespace",
+			Name:      "pod-a",
+			Annotations: map[string]string{
+				authenticate.KubernetesTokenAnnotation:   "my-auth-token",
+				authenticate.KubernetesSubjectAnnotation: "my-test-subject",
+			},
+		},
+		Status: corev1.PodStatus{
+			PodIP: "10.20.30.40",
+			Phase: corev1.PodRunning,
+		},
+	}
+
+	objs := []runtime.Object{
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-test-namespace",
+			},
+		},
+		pod,
+	}
+
+	kc := &authenticate.KubernetesInterface{
+		Interface:       testutil.NewMockKubernetesClient(objs...),
+		TektonInterface: testutil.NewMockTektonKubernetesClient(),
+	}
+
+	reviews := map[string]authenticationv1.TokenReviewStatus{
+		"bound-token": {
+			Authenticated: true,
+			Audiences:     []string{authenticate.MetadataAPIAudienceV1},
+			User: authenticationv1.UserInfo{
+				Username: "system:serviceaccount:my-test-namespace:untrusted",
+				Extra: map[string]authenticationv1.ExtraValue{
+					authenticate.KubernetesServiceAccountPodNameExtra: {"pod-a"},
+					authenticate.KubernetesServiceAccountPodUIDExtra:  {string(pod.GetUID())},
+				},
+			},
+		},
+		"stale-token": {
+			Authenticated: true,
+			Audiences:     []string{authenticate.MetadataAPIAudienceV1},
+			User: authenticationv1.UserInfo{
+				Username: "system:serviceaccount:my-test-namespace:untrusted",
+				Extra: map[string]authenticationv1.ExtraValue{
+					authenticate.KubernetesServiceAccountPodNameExtra: {"pod-a"},
+					authenticate.KubernetesServiceAccountPodUIDExtra:  {"some-other-uid"},
+				},
+			},
+		},
+		"unbound-token": {
+			Authenticated: true,
+			Audiences:     []string{authenticate.MetadataAPIAudienceV1},
+			User: authenticationv1.UserInfo{
+				Username: "system:serviceaccount:my-test-namespace:untrusted",
+			},
+		},
+		"wrong-audience-token": {
+			Authenticated: true,
+			Audiences:     []string{"https://kubernetes.default.svc"},
+		},
+	}
+
+	kc.Interface.(*fake.Clientset).PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		tr := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		tr.Status = reviews[tr.Spec.Token]
+		return true, tr, nil
+	})
+
+	tests := []struct {
+		Token         string
+		ExpectedRaw   authenticate.Raw
+		ExpectedError error
+	}{
+		{
+			Token:       "bound-token",
+			ExpectedRaw: authenticate.Raw("my-auth-token"),
+		},
+		{
+			Token:         "stale-token",
+			ExpectedError: &authenticate.NotFoundError{Reason: "kubernetes: pod bound to service account token no longer exists"},
+		},
+		{
+			Token:         "unbound-token",
+			ExpectedError: &authenticate.NotFoundError{Reason: "kubernetes: service account token is not bound to a pod"},
+		},
+		{
+			Token:         "wrong-audience-token",
+			ExpectedError: &authenticate.NotFoundError{Reason: "kubernetes: service account token is not intended for the metadata API"},
+		},
+		{
+			Token:         "invalid-token",
+			ExpectedError: &authenticate.NotFoundError{Reason: "kubernetes: service account token is not valid"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Token, func(t *testing.T) {
+			im := authenticate.NewKubernetesServiceAccountTokenIntermediary(kc, test.Token)
+			raw, err := im.Next(ctx, authenticate.NewAuthentication())
+			require.Equal(t, test.ExpectedError, err)
+			require.Equal(t, test.ExpectedRaw, raw)
+		})
+	}
+}
+
+func TestKubernetesIntermediaryAuthenticationMethod(t *testing.T) {
+	ctx := context.Background()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "my-test-namespace",
+			Name:      "pod-a",
+			Annotations: map[string]string{
+				authenticate.KubernetesTokenAnnotation:   "my-auth-token",
+				authenticate.KubernetesSubjectAnnotation: "my-test-subject",
+			},
+		},
+		Status: corev1.PodStatus{
+			PodIP: "10.20.30.40",
+			Phase: corev1.PodRunning,
+		},
+	}
+
+	objs := []runtime.Object{
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-test-namespace",
+			},
+		},
+		pod,
+	}
+
+	kc := &authenticate.KubernetesInterface{
+		Interface:       testutil.NewMockKubernetesClient(objs...),
+		TektonInterface: testutil.NewMockTektonKubernetesClient(),
+	}
+
+	kc.Interface.(*fake.Clientset).PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		tr := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		tr.Status = authenticationv1.TokenReviewStatus{
+			Authenticated: true,
+			Audiences:     []string{authenticate.MetadataAPIAudienceV1},
+			User: authenticationv1.UserInfo{
+				Username: "system:serviceaccount:my-test-namespace:untrusted",
+				Extra: map[string]authenticationv1.ExtraValue{
+					authenticate.KubernetesServiceAccountPodNameExtra: {"pod-a"},
+					authenticate.KubernetesServiceAccountPodUIDExtra:  {string(pod.GetUID())},
+				},
+			},
+		}
+		return true, tr, nil
+	})
+
+	intermediaries := map[authenticate.KubernetesAuthenticationMethod]func() *authenticate.KubernetesIntermediary{
+		authenticate.KubernetesAuthenticationMethodPodIP: func() *authenticate.KubernetesIntermediary {
+			return authenticate.NewKubernetesIntermediary(kc, net.ParseIP("10.20.30.40"))
+		},
+		authenticate.KubernetesAuthenticationMethodServiceAccountToken: func() *authenticate.KubernetesIntermediary {
+			return authenticate.NewKubernetesServiceAccountTokenIntermediary(kc, "bound-token")
+		},
+	}
+
+	tests := []struct {
+		Name          string
+		RequestMethod authenticate.KubernetesAuthenticationMethod
+		ClaimMethod   authenticate.KubernetesAuthenticationMethod
+		Expected      bool
+	}{
+		{
+			Name:          "pod IP for default",
+			RequestMethod: authenticate.KubernetesAuthenticationMethodPodIP,
+			Expected:      true,
+		},
+		{
+			Name:          "pod IP for pod IP",
+			RequestMethod: authenticate.KubernetesAuthenticationMethodPodIP,
+			ClaimMethod:   authenticate.KubernetesAuthenticationMethodPodIP,
+			Expected:      true,
+		},
+		{
+			Name:          "pod IP for service account token",
+			RequestMethod: authenticate.KubernetesAuthenticationMethodPodIP,
+			ClaimMethod:   authenticate.KubernetesAuthenticationMethodServiceAccountToken,
+			Expected:      false,
+		},
+		{
+			Name:          "service account token for service account token",
+			RequestMethod: authenticate.KubernetesAuthenticationMethodServiceAccountToken,
+			ClaimMethod:   authenticate.KubernetesAuthenticationMethodServiceAccountToken,
+			Expected:      true,
+		},
+		{
+			Name:          "service account token for default",
+			RequestMethod: authenticate.KubernetesAuthenticationMethodServiceAccountToken,
+			Expected:      false,
+		},
+		{
+			Name:          "service account token for pod IP",
+			RequestMethod: authenticate.KubernetesAuthenticationMethodServiceAccountToken,
+			ClaimMethod:   authenticate.KubernetesAuthenticationMethodPodIP,
+			Expected:      false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var validators []authenticate.Validator
+			state := authenticate.NewInitializedAuthentication(&validators, &[]authenticate.Injector{})
+
+			var namespaceUID string
+			im := intermediaries[test.RequestMethod]().Chain(func(ctx context.Context, raw authenticate.Raw, md *authenticate.KubernetesIntermediaryMetadata) (authenticate.Intermediary, error) {
+				namespaceUID = string(md.NamespaceUID)
+				return raw, nil
+			})
+			raw, err := im.Next(ctx, state)
+			require.NoError(t, err)
+			require.Equal(t, authenticate.Raw("my-auth-token"), raw)
+
+			claims := &authenticate.Claims{
+				Claims: &jwt.Claims{
+					Subject: "my-test-subject",
+				},
+				KubernetesNamespaceUID:         namespaceUID,
+				KubernetesAuthenticationMethod: test.ClaimMethod,
+			}
+
+			valid := true
+			for i, validator := range validators {
+				outcome, err := validator.Validate(ctx, claims)
+				require.NoError(t, err, "validator %d", i)
+
+				valid = valid && outcome
+			}
+
+			require.Equal(t, test.Expected, valid)
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package authenticate
+
+import (
+	"context"
+	"net"
+
+	"github.com/puppetlabs/horsehead/v2/instrumentation/metrics"
+	"github.com/puppetlabs/horsehead/v2/instrumentation/metrics/collectors"
+	"github.com/puppetlabs/relay-core/pkg/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	kubernetesPodCacheIPIndex = "podIP"
+
+	metricKubernetesPodCacheLookups = "kubernetes_pod_cache_lookups"
+)
+
+// KubernetesPodCacheLabels are the labels that identify pods that may make
+// requests to the metadata API. A pod must have at least one of them to be
+// cached.
+var KubernetesPodCacheLabels = []string{
+	model.RelayControllerWorkflowRunIDLabel,
+	model.RelayControllerWebhookTriggerIDLabel,
+}
+
+type kubernetesPodCacheResult string
+
+const (
+	kubernetesPodCacheResultHit      kubernetesPodCacheResult = "hit"
+	kubernetesPodCacheResultMiss     kubernetesPodCacheResult = "miss"
+	kubernetesPodCacheResultNotReady kubernetesPodCacheResult = "not_ready"
+)
+
+// KubernetesPodCache watches Relay pods and indexes them by IP address so
+// that the Kubernetes intermediary does not need to query the API server for
+// every request.
+//
+// The cache is only consulted as an optimization. Until it has synced, or if
+// it cannot find exactly one matching pod, callers fall back to the API
+// server. Because only Relay pods are watched, a pod found by IP address may
+// have terminated and given its IP address to another pod, so callers must
+// confirm the pod with the API server before trusting it.
+type KubernetesPodCache struct {
+	informers []cache.SharedIndexInformer
+	metrics   *metrics.Metrics
+}
+
+// Run starts watching pods and blocks until the context is canceled.
+func (kpc *KubernetesPodCache) Run(ctx context.Context) error {
+	for _, informer := range kpc.informers {
+		go informer.Run(ctx.Done())
+	}
+
+	<-ctx.Done()
+	return nil
+}
+
+// HasSynced returns true if the cache has received an initial list of pods.
+func (kpc *KubernetesPodCache) HasSynced() bool {
+	for _, informer := range kpc.informers {
+		if !informer.HasSynced() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// PodByIP returns the running pod with the given IP address. If the cache has
+// not synced or does not contain exactly one running pod with the IP address,
+// this method returns false.
+func (kpc *KubernetesPodCache) PodByIP(ip net.IP) (*corev1.Pod, bool) {
+	if !kpc.HasSynced() {
+		kpc.observe(kubernetesPodCacheResultNotReady)
+		return nil, false
+	}
+
+	// The same pod may appear in more than one informer if it has multiple
+	// labels, so deduplicate by UID.
+	var pods []*corev1.Pod
+	seen := make(map[types.UID]struct{})
+	for _, informer := range kpc.informers {
+		objs, err := informer.GetIndexer().ByIndex(kubernetesPodCacheIPIndex, ip.String())
+		if err != nil {
+			kpc.observe(kubernetesPodCacheResultMiss)
+			return nil, false
+		}
+
+		for _, obj := range objs {
+			pod := obj.(*corev1.Pod)
+			if _, found := seen[pod.GetUID()]; found {
+				continue
+			}
+
+			seen[pod.GetUID()] = struct{}{}
+			pods = append(pods, pod)
+		}
+	}
+
+	// If an IP address was reused and we haven't yet seen the previous pod
+	// terminate, we may have multiple matches. We can't tell which one is
+	// correct, so let the API server decide.
+	if len(pods) != 1 {
+		kpc.observe(kubernetesPodCacheResultMiss)
+		return nil, false
+	}
+
+	kpc.observe(kubernetesPodCacheResultHit)
+	return pods[0].DeepCopy(), true
+}
+
+// PodByUID returns the pod with the given namespace, name, and UID. If the
+// cache has not synced or does not contain the pod, this method returns false.
+func (kpc *KubernetesPodCache) PodByUID(namespace, name string, uid types.UID) (*corev1.Pod, bool) {
+	if !kpc.HasSynced() {
+		kpc.observe(kubernetesPodCacheResultNotReady)
+		return nil, false
+	}
+
+	key := namespace + "/" + name
+	for _, informer := range kpc.informers {
+		obj, exists, err := informer.GetIndexer().GetByKey(key)
+		if err != nil || !exists {
+			continue
+		}
+
+		if pod := obj.(*corev1.Pod); pod.GetUID() == uid {
+			kpc.observe(kubernetesPodCacheResultHit)
+			return pod.DeepCopy(), true
+		}
+	}
+
+	kpc.observe(kubernetesPodCacheResultMiss)
+	return nil, false
+}
+
+func (kpc *KubernetesPodCache) observe(result kubernetesPodCacheResult) {
+	if kpc.metrics == nil {
+		return
+	}
+
+	kpc.metrics.MustCounter(metricKubernetesPodCacheLookups, metrics.NewLabel("result", string(result))).Inc()
+}
+
+// kubernetesPodCacheIndexByIP indexes only pods that are running and not being
+// deleted. Once a pod stops running, it is removed from the index so its IP
+// address can be reassigned to another pod.
+func kubernetesPodCacheIndexByIP(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil, nil
+	}
+
+	if pod.Status.PodIP == "" || pod.Status.Phase != corev1.PodRunning || pod.GetDeletionTimestamp() != nil {
+		return nil, nil
+	}
+
+	return []string{pod.Status.PodIP}, nil
+}
+
+type KubernetesPodCacheOption func(kpc *KubernetesPodCache)
+
+// KubernetesPodCacheWithMetrics reports the outcome of each cache lookup to
+// the given metrics collector.
+func KubernetesPodCacheWithMetrics(mets *metrics.Metrics) KubernetesPodCacheOption {
+	return func(kpc *KubernetesPodCache) {
+		kpc.metrics = mets
+	}
+}
+
+func NewKubernetesPodCache(client kubernetes.Interface, opts ...KubernetesPodCacheOption) *KubernetesPodCache {
+	kpc := &KubernetesPodCache{}
+
+	// Label selectors can't express a disjunction, so we need one informer
+	// for each label.
+	for _, label := range KubernetesPodCacheLabels {
+		label := label
+
+		kpc.informers = append(kpc.informers, coreinformers.NewFilteredPodInformer(
+			client,
+			metav1.NamespaceAll,
+			0,
+			cache.Indexers{kubernetesPodCacheIPIndex: kubernetesPodCacheIndexByIP},
+			func(opts *metav1.ListOptions) {
+				opts.LabelSelector = label
+			},
+		))
+	}
+
+	for _, opt := range opts {
+		opt(kpc)
+	}
+
+	if kpc.metrics != nil {
+		kpc.metrics.MustRegisterCounter(metricKubernetesPodCacheLookups, collectors.CounterOptions{
+			Description: "number of pod lookups by the metadata API partitioned by whether the pod cache could answer them",
+			Labels:      []string{"result"},
+		})
+	}
+
+	return kpc
+}
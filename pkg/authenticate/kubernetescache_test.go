@@ -0,0 +1,222 @@
+package authenticate_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/puppetlabs/horsehead/v2/instrumentation/metrics"
+	"github.com/puppetlabs/horsehead/v2/instrumentation/metrics/delegates"
+	"github.com/puppetlabs/relay-core/pkg/authenticate"
+	"github.com/puppetlabs/relay-core/pkg/model"
+	"github.com/puppetlabs/relay-core/pkg/util/testutil"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestKubernetesPodCache(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	objs := []runtime.Object{
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-test-namespace",
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "my-test-namespace",
+				Name:      "step-pod",
+				Labels: map[string]string{
+					model.RelayControllerWorkflowRunIDLabel: "my-run",
+				},
+				Annotations: map[string]string{
+					authenticate.KubernetesTokenAnnotation:   "my-auth-token",
+					authenticate.KubernetesSubjectAnnotation: "my-test-subject",
+				},
+			},
+			Status: corev1.PodStatus{
+				PodIP: "10.20.30.40",
+				Phase: corev1.PodRunning,
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "my-test-namespace",
+				Name:      "completed-step-pod",
+				Labels: map[string]string{
+					model.RelayControllerWorkflowRunIDLabel: "my-run",
+				},
+			},
+			Status: corev1.PodStatus{
+				PodIP: "10.20.30.41",
+				Phase: corev1.PodSucceeded,
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "my-test-namespace",
+				Name:      "unrelated-pod",
+			},
+			Status: corev1.PodStatus{
+				PodIP: "10.20.30.42",
+				Phase: corev1.PodRunning,
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "my-test-namespace",
+				Name:      "old-trigger-pod",
+				Labels: map[string]string{
+					model.RelayControllerWebhookTriggerIDLabel: "my-trigger",
+				},
+			},
+			Status: corev1.PodStatus{
+				PodIP: "10.20.30.43",
+				Phase: corev1.PodRunning,
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "my-test-namespace",
+				Name:      "new-step-pod",
+				Labels: map[string]string{
+					model.RelayControllerWorkflowRunIDLabel: "my-run",
+				},
+			},
+			Status: corev1.PodStatus{
+				PodIP: "10.20.30.43",
+				Phase: corev1.PodRunning,
+			},
+		},
+	}
+
+	kc := &authenticate.KubernetesInterface{
+		Interface:       testutil.NewMockKubernetesClient(objs...),
+		TektonInterface: testutil.NewMockTektonKubernetesClient(),
+	}
+
+	mets, err := metrics.NewNamespace("authenticate_test", metrics.Options{
+		DelegateType:  delegates.PrometheusDelegate,
+		ErrorBehavior: metrics.ErrorBehaviorPanic,
+	})
+	require.NoError(t, err)
+
+	pc := authenticate.NewKubernetesPodCache(kc.Interface, authenticate.KubernetesPodCacheWithMetrics(mets))
+
+	// Before the cache syncs, it should never answer.
+	_, found := pc.PodByIP(net.ParseIP("10.20.30.40"))
+	require.False(t, found)
+
+	go pc.Run(ctx)
+	require.Eventually(t, pc.HasSynced, 10*time.Second, 10*time.Millisecond)
+
+	tests := []struct {
+		IP          string
+		ExpectedPod string
+	}{
+		{IP: "10.20.30.40", ExpectedPod: "step-pod"},
+		{IP: "10.20.30.41"},
+		{IP: "10.20.30.42"},
+		{IP: "10.20.30.43"},
+		{IP: "10.20.30.44"},
+	}
+	for _, test := range tests {
+		t.Run(test.IP, func(t *testing.T) {
+			pod, found := pc.PodByIP(net.ParseIP(test.IP))
+			if test.ExpectedPod == "" {
+				require.False(t, found)
+			} else {
+				require.True(t, found)
+				require.Equal(t, test.ExpectedPod, pod.GetName())
+			}
+		})
+	}
+
+	// The intermediary should be able to authenticate using the cache.
+	im := authenticate.NewKubernetesIntermediary(kc, net.ParseIP("10.20.30.40"), authenticate.KubernetesIntermediaryWithPodCache(pc))
+	raw, err := im.Next(ctx, authenticate.NewAuthentication())
+	require.NoError(t, err)
+	require.Equal(t, authenticate.Raw("my-auth-token"), raw)
+
+	req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	require.NoError(t, err)
+
+	resp := httptest.NewRecorder()
+	mets.Handler().ServeHTTP(resp, req)
+	require.Contains(t, resp.Body.String(), `authenticate_test_kubernetes_pod_cache_lookups{result="hit"} 2`)
+	require.Contains(t, resp.Body.String(), `authenticate_test_kubernetes_pod_cache_lookups{result="miss"} 4`)
+	require.Contains(t, resp.Body.String(), `authenticate_test_kubernetes_pod_cache_lookups{result="not_ready"} 1`)
+}
+
+func TestKubernetesPodCacheIPReusedByUnlabeledPod(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-test-namespace",
+		},
+	}
+
+	// The cache still has the step pod, because it hasn't seen it terminate.
+	pc := authenticate.NewKubernetesPodCache(testutil.NewMockKubernetesClient(
+		namespace,
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "my-test-namespace",
+				Name:      "step-pod",
+				UID:       "step-pod-uid",
+				Labels: map[string]string{
+					model.RelayControllerWorkflowRunIDLabel: "my-run",
+				},
+				Annotations: map[string]string{
+					authenticate.KubernetesTokenAnnotation:   "my-auth-token",
+					authenticate.KubernetesSubjectAnnotation: "my-test-subject",
+				},
+			},
+			Status: corev1.PodStatus{
+				PodIP: "10.20.30.40",
+				Phase: corev1.PodRunning,
+			},
+		},
+	))
+
+	go pc.Run(ctx)
+	require.Eventually(t, pc.HasSynced, 10*time.Second, 10*time.Millisecond)
+
+	pod, found := pc.PodByIP(net.ParseIP("10.20.30.40"))
+	require.True(t, found)
+	require.Equal(t, "step-pod", pod.GetName())
+
+	// Meanwhile, the API server has given its IP address to a pod that isn't
+	// managed by Relay.
+	kc := &authenticate.KubernetesInterface{
+		Interface: testutil.NewMockKubernetesClient(
+			namespace,
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "my-test-namespace",
+					Name:      "unrelated-pod",
+					UID:       "unrelated-pod-uid",
+				},
+				Status: corev1.PodStatus{
+					PodIP: "10.20.30.40",
+					Phase: corev1.PodRunning,
+				},
+			},
+		),
+		TektonInterface: testutil.NewMockTektonKubernetesClient(),
+	}
+
+	im := authenticate.NewKubernetesIntermediary(kc, net.ParseIP("10.20.30.40"), authenticate.KubernetesIntermediaryWithPodCache(pc))
+	raw, err := im.Next(ctx, authenticate.NewAuthentication())
+	require.Error(t, err)
+	require.NotEqual(t, authenticate.Raw("my-auth-token"), raw)
+}
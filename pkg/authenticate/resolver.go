@@ -30,7 +30,7 @@ var _ Resolver = &AnyResolver{}
 func (ar *AnyResolver) Resolve(ctx context.Context, state *Authentication, raw Raw) (*Claims, error) {
 	// Fast case for one delegate.
 	if len(ar.delegates) == 1 {
-		return ar.delegates[0].Resolve(ctx, state, raw)
+		return traceResolver(ctx, ar.delegates[0], state, raw)
 	}
 
 	var causes []error
@@ -41,7 +41,7 @@ func (ar *AnyResolver) Resolve(ctx context.Context, state *Authentication, raw R
 		var injectors []Injector
 		ts := NewInitializedAuthentication(&validators, &injectors)
 
-		claims, err := delegate.Resolve(ctx, ts, raw)
+		claims, err := traceResolver(ctx, delegate, ts, raw)
 		if _, ok := err.(*NotFoundError); ok {
 			causes = append(causes, err)
 			continue
@@ -0,0 +1,41 @@
+package authenticate
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/label"
+)
+
+const (
+	tracerName = "github.com/puppetlabs/relay-core/pkg/authenticate"
+)
+
+func startSpan(ctx context.Context, name string, attrs ...label.KeyValue) (context.Context, trace.Span) {
+	return global.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+func endSpan(ctx context.Context, span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(ctx, err, trace.WithErrorStatus(codes.Error))
+	}
+
+	span.End()
+}
+
+func traceIntermediary(ctx context.Context, i Intermediary, state *Authentication) (raw Raw, err error) {
+	ctx, span := startSpan(ctx, "authenticate.intermediary", label.String("relay.authenticate.type", fmt.Sprintf("%T", i)))
+	defer func() { endSpan(ctx, span, err) }()
+
+	return i.Next(ctx, state)
+}
+
+func traceResolver(ctx context.Context, r Resolver, state *Authentication, raw Raw) (claims *Claims, err error) {
+	ctx, span := startSpan(ctx, "authenticate.resolver", label.String("relay.authenticate.type", fmt.Sprintf("%T", r)))
+	defer func() { endSpan(ctx, span, err) }()
+
+	return r.Resolve(ctx, state, raw)
+}
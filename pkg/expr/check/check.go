@@ -0,0 +1,401 @@
+// Package check verifies expression trees without evaluating them, so that
+// mistakes like calling a function with the wrong arguments or referring to a
+// parameter that doesn't exist can be reported before a workflow runs.
+package check
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/puppetlabs/relay-core/pkg/expr/evaluate"
+	"github.com/puppetlabs/relay-core/pkg/expr/fn"
+	"github.com/puppetlabs/relay-core/pkg/expr/fnlib"
+	"github.com/puppetlabs/relay-core/pkg/expr/parse"
+)
+
+type Checker struct {
+	fns        fn.Map
+	parameters map[string]bool
+	steps      map[string]bool
+}
+
+type Option func(c *Checker)
+
+// WithFunctionMap sets the functions that invocations are checked against.
+// The core function library is used by default.
+func WithFunctionMap(m fn.Map) Option {
+	return func(c *Checker) {
+		c.fns = m
+	}
+}
+
+// WithParameters restricts parameter references to the given names. Without
+// this option, any parameter may be referenced.
+func WithParameters(names []string) Option {
+	return func(c *Checker) {
+		c.parameters = set(names)
+	}
+}
+
+// WithSteps restricts output references to steps with the given names.
+// Without this option, outputs of any step may be referenced.
+func WithSteps(names []string) Option {
+	return func(c *Checker) {
+		c.steps = set(names)
+	}
+}
+
+func set(names []string) map[string]bool {
+	m := make(map[string]bool, len(names))
+	for _, name := range names {
+		m[name] = true
+	}
+	return m
+}
+
+func NewChecker(opts ...Option) *Checker {
+	c := &Checker{
+		fns: fnlib.Library(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Check walks the given tree and returns every problem it finds, ordered by
+// path. Parts of the tree that depend on runtime values, like the results of
+// parameters and outputs, are assumed to be of any type.
+func (c *Checker) Check(tree parse.Tree) []*Error {
+	return c.CheckType(tree, fn.TypeAny)
+}
+
+// CheckType is like Check, but additionally reports an error if the tree
+// cannot evaluate to any of the wanted types.
+func (c *Checker) CheckType(tree parse.Tree, wanted fn.Type) []*Error {
+	s := &state{Checker: c}
+	if got := s.check(nil, tree, nil); !wanted.Accepts(got) {
+		s.report(nil, &TypeError{Wanted: wanted, Got: got})
+	}
+
+	sort.SliceStable(s.errs, func(i, j int) bool {
+		return strings.Join(s.errs[i].Path, ".") < strings.Join(s.errs[j].Path, ".")
+	})
+	return s.errs
+}
+
+type state struct {
+	*Checker
+	errs []*Error
+}
+
+func (s *state) report(path []string, err error) {
+	s.errs = append(s.errs, &Error{Path: path, Cause: err})
+}
+
+func appendPath(path []string, elem string) []string {
+	return append(append([]string{}, path...), elem)
+}
+
+// check reports any problems with the given value and returns the types it
+// could evaluate to. Bound is the set of names available to bindings at this
+// point in the tree.
+func (s *state) check(path []string, v interface{}, bound map[string]bool) fn.Type {
+	switch vt := v.(type) {
+	case nil:
+		return fn.TypeNull
+	case bool:
+		return fn.TypeBoolean
+	case int, int64, float64:
+		return fn.TypeNumber
+	case string:
+		return fn.TypeString
+	case time.Time:
+		return fn.TypeTime
+	case []interface{}:
+		for i, v := range vt {
+			s.check(appendPath(path, strconv.Itoa(i)), v, bound)
+		}
+
+		return fn.TypeArray
+	case map[string]interface{}:
+		if _, ok := vt["$type"]; ok {
+			return s.checkType(path, vt, bound)
+		} else if _, ok := vt["$encoding"]; ok {
+			return s.checkEncoding(path, vt, bound)
+		} else if len(vt) == 1 {
+			var first string
+			for first = range vt {
+			}
+
+			if strings.HasPrefix(first, "$fn.") {
+				return s.checkInvocation(path, vt, bound)
+			}
+		}
+
+		for k, v := range vt {
+			s.check(appendPath(path, k), v, bound)
+		}
+
+		return fn.TypeObject
+	default:
+		return fn.TypeAny
+	}
+}
+
+func (s *state) checkType(path []string, tm map[string]interface{}, bound map[string]bool) fn.Type {
+	ty, _ := tm["$type"].(string)
+
+	field := func(names ...string) (string, bool) {
+		for _, name := range names {
+			if v, ok := tm[name].(string); ok {
+				return v, true
+			}
+		}
+
+		s.report(path, &evaluate.InvalidTypeError{Type: ty, Cause: &evaluate.FieldNotFoundError{Name: names[0]}})
+		return "", false
+	}
+
+	switch ty {
+	case "Data":
+		field("query")
+	case "Secret":
+		field("name")
+		return fn.TypeString
+	case "Connection":
+		_, tok := field("type")
+		_, nok := field("name")
+		if tok && nok {
+			return fn.TypeObject
+		}
+	case "Output":
+		// The taskName field is the old name of the from field.
+		from, ok := field("from", "taskName")
+		if ok && s.steps != nil && !s.steps[from] {
+			s.report(path, &evaluate.InvalidTypeError{Type: ty, Cause: &UndeclaredStepError{Name: from}})
+		}
+
+		field("name")
+	case "Parameter":
+		name, ok := field("name")
+		if ok && s.parameters != nil && !s.parameters[name] {
+			s.report(path, &evaluate.InvalidTypeError{Type: ty, Cause: &UndeclaredParameterError{Name: name}})
+		}
+	case "Binding":
+		name, ok := field("name")
+		if ok && !bound[name] {
+			s.report(path, &evaluate.InvalidTypeError{Type: ty, Cause: &evaluate.BindingNotFoundError{Name: name}})
+		}
+	case "Answer":
+		field("askRef")
+		field("name")
+	default:
+		return fn.TypeObject
+	}
+
+	return fn.TypeAny
+}
+
+func (s *state) checkEncoding(path []string, em map[string]interface{}, bound map[string]bool) fn.Type {
+	ty, ok := em["$encoding"].(string)
+	if !ok {
+		return fn.TypeObject
+	}
+
+	if got := s.check(appendPath(path, "data"), em["data"], bound); !fn.TypeString.Accepts(got) {
+		s.report(path, &evaluate.InvalidEncodingError{
+			Type:  ty,
+			Cause: &TypeError{Wanted: fn.TypeString, Got: got},
+		})
+	}
+
+	return fn.TypeString
+}
+
+func (s *state) checkInvocation(path []string, im map[string]interface{}, bound map[string]bool) fn.Type {
+	var key string
+	var value interface{}
+	for key, value = range im {
+	}
+
+	name := strings.TrimPrefix(key, "$fn.")
+	path = appendPath(path, key)
+
+	var sig *fn.Signature
+	if desc, err := s.fns.Descriptor(name); err != nil {
+		s.report(path, &evaluate.InvalidInvocationError{Name: name, Cause: err})
+	} else {
+		sig = desc.Signature()
+	}
+
+	// Arguments are passed by keyword only when they are given as a literal
+	// object. Anything else that evaluates to an object is passed as a single
+	// positional argument.
+	switch vt := value.(type) {
+	case []interface{}:
+		return s.checkPositional(path, name, sig, vt, bound)
+	case map[string]interface{}:
+		if _, ok := vt["$type"]; ok {
+			break
+		} else if _, ok := vt["$encoding"]; ok {
+			break
+		} else if len(vt) == 1 {
+			var first string
+			for first = range vt {
+			}
+
+			if strings.HasPrefix(first, "$fn.") {
+				break
+			}
+		}
+
+		return s.checkKeyword(path, name, sig, vt, bound)
+	}
+
+	// A single positional argument, which is checked at the same path as the
+	// invocation itself.
+	got := s.check(path, value, bound)
+	if sig == nil {
+		return fn.TypeAny
+	} else if (fn.TypeArray | fn.TypeObject).Accepts(got) {
+		// The value may turn out to be a list of positional arguments or a
+		// map of keyword arguments, so we can't know how the function will
+		// be invoked.
+		return sig.Returns
+	} else if sig.Positional == nil {
+		return s.invalidStyle(path, name, sig, fn.ErrPositionalArgsNotAccepted)
+	}
+
+	s.checkArity(path, name, sig.Positional, 1)
+	if param, ok := sig.Positional.ParamAt(0); ok && !param.Type.Accepts(got) {
+		s.report(path, &evaluate.InvocationError{
+			Name:  name,
+			Cause: &fn.PositionalArgError{Arg: 1, Cause: &TypeError{Wanted: param.Type, Got: got}},
+		})
+	}
+
+	return sig.Returns
+}
+
+// invalidStyle reports that a function can't be invoked in a particular way,
+// unless the function has no signature and so can't be checked.
+func (s *state) invalidStyle(path []string, name string, sig *fn.Signature, err error) fn.Type {
+	if sig == nil {
+		return fn.TypeAny
+	}
+
+	s.report(path, &evaluate.InvalidInvocationError{Name: name, Cause: err})
+	return sig.Returns
+}
+
+func (s *state) checkArity(path []string, name string, args *fn.Arguments, got int) {
+	if got >= args.Required && (got <= len(args.Params) || args.Variadic) {
+		return
+	}
+
+	err := &fn.ArityError{Got: got}
+	if args.Variadic {
+		err.Wanted = []int{args.Required}
+		err.Variadic = true
+	} else {
+		for n := args.Required; n <= len(args.Params); n++ {
+			err.Wanted = append(err.Wanted, n)
+		}
+	}
+
+	s.report(path, &evaluate.InvalidInvocationError{Name: name, Cause: err})
+}
+
+func (s *state) checkPositional(path []string, name string, sig *fn.Signature, args []interface{}, bound map[string]bool) fn.Type {
+	if sig == nil || sig.Positional == nil {
+		for i, arg := range args {
+			s.check(appendPath(path, strconv.Itoa(i)), arg, bound)
+		}
+
+		return s.invalidStyle(path, name, sig, fn.ErrPositionalArgsNotAccepted)
+	}
+
+	s.checkArity(path, name, sig.Positional, len(args))
+
+	for i, arg := range args {
+		argPath := appendPath(path, strconv.Itoa(i))
+
+		param, ok := sig.Positional.ParamAt(i)
+		if !ok {
+			s.check(argPath, arg, bound)
+			continue
+		}
+
+		if got := s.check(argPath, arg, s.bind(bound, param)); !param.Type.Accepts(got) {
+			s.report(argPath, &evaluate.InvocationError{
+				Name:  name,
+				Cause: &fn.PositionalArgError{Arg: i + 1, Cause: &TypeError{Wanted: param.Type, Got: got}},
+			})
+		}
+	}
+
+	return sig.Returns
+}
+
+func (s *state) checkKeyword(path []string, name string, sig *fn.Signature, args map[string]interface{}, bound map[string]bool) fn.Type {
+	if sig == nil || sig.Keyword == nil {
+		for key, arg := range args {
+			s.check(appendPath(path, key), arg, bound)
+		}
+
+		return s.invalidStyle(path, name, sig, fn.ErrKeywordArgsNotAccepted)
+	}
+
+	for _, param := range sig.Keyword.Params[:sig.Keyword.Required] {
+		if _, found := args[param.Name]; !found {
+			s.report(path, &evaluate.InvalidInvocationError{
+				Name:  name,
+				Cause: &fn.KeywordArgError{Arg: param.Name, Cause: fn.ErrArgNotFound},
+			})
+		}
+	}
+
+	for key, arg := range args {
+		argPath := appendPath(path, key)
+
+		param, ok := sig.Keyword.Param(key)
+		if !ok {
+			s.check(argPath, arg, bound)
+			s.report(argPath, &evaluate.InvalidInvocationError{
+				Name:  name,
+				Cause: &fn.KeywordArgError{Arg: key, Cause: ErrUnknownArg},
+			})
+			continue
+		}
+
+		if got := s.check(argPath, arg, s.bind(bound, param)); !param.Type.Accepts(got) {
+			s.report(argPath, &evaluate.InvocationError{
+				Name:  name,
+				Cause: &fn.KeywordArgError{Arg: key, Cause: &TypeError{Wanted: param.Type, Got: got}},
+			})
+		}
+	}
+
+	return sig.Returns
+}
+
+// bind returns the names available to bindings within the given argument.
+func (s *state) bind(bound map[string]bool, param fn.Param) map[string]bool {
+	if !param.Lambda {
+		return bound
+	}
+
+	nb := map[string]bool{
+		fn.ItemBinding:  true,
+		fn.IndexBinding: true,
+	}
+	for name := range bound {
+		nb[name] = true
+	}
+	return nb
+}
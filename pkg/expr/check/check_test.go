@@ -0,0 +1,242 @@
+package check_test
+
+import (
+	"testing"
+
+	"github.com/puppetlabs/horsehead/v2/encoding/transfer"
+	"github.com/puppetlabs/relay-core/pkg/expr/check"
+	"github.com/puppetlabs/relay-core/pkg/expr/evaluate"
+	"github.com/puppetlabs/relay-core/pkg/expr/fn"
+	"github.com/puppetlabs/relay-core/pkg/expr/parse"
+	"github.com/puppetlabs/relay-core/pkg/expr/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	opts := []check.Option{
+		check.WithParameters([]string{"message", "items"}),
+		check.WithSteps([]string{"build"}),
+	}
+
+	tests := []struct {
+		Name           string
+		Tree           parse.Tree
+		Wanted         fn.Type
+		ExpectedErrors []*check.Error
+	}{
+		{
+			Name: "valid",
+			Tree: map[string]interface{}{
+				"greeting": testutil.JSONInvocation("concat", []interface{}{
+					"Hello, ",
+					testutil.JSONParameter("message"),
+					testutil.JSONOutput("build", "version"),
+				}),
+				"numbers": testutil.JSONInvocation("range", map[string]interface{}{
+					"start": 1,
+					"end":   testutil.JSONInvocation("length", testutil.JSONParameter("items")),
+				}),
+				"secret": testutil.JSONSecret("password"),
+			},
+		},
+		{
+			Name: "unknown function",
+			Tree: testutil.JSONInvocation("nope", []interface{}{"a"}),
+			ExpectedErrors: []*check.Error{
+				{
+					Path:  []string{"$fn.nope"},
+					Cause: &evaluate.InvalidInvocationError{Name: "nope", Cause: fn.ErrFunctionNotFound},
+				},
+			},
+		},
+		{
+			Name: "too few positional arguments",
+			Tree: testutil.JSONInvocation("upper", []interface{}{}),
+			ExpectedErrors: []*check.Error{
+				{
+					Path:  []string{"$fn.upper"},
+					Cause: &evaluate.InvalidInvocationError{Name: "upper", Cause: &fn.ArityError{Wanted: []int{1}, Got: 0}},
+				},
+			},
+		},
+		{
+			Name: "too few variadic arguments",
+			Tree: testutil.JSONInvocation("append", []interface{}{}),
+			ExpectedErrors: []*check.Error{
+				{
+					Path:  []string{"$fn.append"},
+					Cause: &evaluate.InvalidInvocationError{Name: "append", Cause: &fn.ArityError{Wanted: []int{2}, Variadic: true, Got: 0}},
+				},
+			},
+		},
+		{
+			Name: "positional argument of the wrong type",
+			Tree: testutil.JSONInvocation("upper", []interface{}{[]interface{}{"a"}}),
+			ExpectedErrors: []*check.Error{
+				{
+					Path: []string{"$fn.upper", "0"},
+					Cause: &evaluate.InvocationError{
+						Name:  "upper",
+						Cause: &fn.PositionalArgError{Arg: 1, Cause: &check.TypeError{Wanted: fn.TypeScalar, Got: fn.TypeArray}},
+					},
+				},
+			},
+		},
+		{
+			Name: "single argument of the wrong type",
+			Tree: testutil.JSONInvocation("not", "true"),
+			ExpectedErrors: []*check.Error{
+				{
+					Path: []string{"$fn.not"},
+					Cause: &evaluate.InvocationError{
+						Name:  "not",
+						Cause: &fn.PositionalArgError{Arg: 1, Cause: &check.TypeError{Wanted: fn.TypeBoolean, Got: fn.TypeString}},
+					},
+				},
+			},
+		},
+		{
+			Name: "single argument resolved at runtime",
+			Tree: testutil.JSONInvocation("split", testutil.JSONParameter("items")),
+		},
+		{
+			Name: "nested invocation of the wrong type",
+			Tree: testutil.JSONInvocation("join", []interface{}{
+				testutil.JSONInvocation("upper", "a"),
+				",",
+			}),
+			ExpectedErrors: []*check.Error{
+				{
+					Path: []string{"$fn.join", "0"},
+					Cause: &evaluate.InvocationError{
+						Name:  "join",
+						Cause: &fn.PositionalArgError{Arg: 1, Cause: &check.TypeError{Wanted: fn.TypeArray, Got: fn.TypeString}},
+					},
+				},
+			},
+		},
+		{
+			Name: "keyword arguments",
+			Tree: testutil.JSONInvocation("substring", map[string]interface{}{
+				"string": "hello",
+				"begin":  1,
+				"end":    "2",
+			}),
+			ExpectedErrors: []*check.Error{
+				{
+					Path:  []string{"$fn.substring"},
+					Cause: &evaluate.InvalidInvocationError{Name: "substring", Cause: &fn.KeywordArgError{Arg: "start", Cause: fn.ErrArgNotFound}},
+				},
+				{
+					Path:  []string{"$fn.substring", "begin"},
+					Cause: &evaluate.InvalidInvocationError{Name: "substring", Cause: &fn.KeywordArgError{Arg: "begin", Cause: check.ErrUnknownArg}},
+				},
+				{
+					Path: []string{"$fn.substring", "end"},
+					Cause: &evaluate.InvocationError{
+						Name:  "substring",
+						Cause: &fn.KeywordArgError{Arg: "end", Cause: &check.TypeError{Wanted: fn.TypeNumber, Got: fn.TypeString}},
+					},
+				},
+			},
+		},
+		{
+			Name: "keyword arguments not accepted",
+			Tree: testutil.JSONInvocation("and", map[string]interface{}{"value": true}),
+			ExpectedErrors: []*check.Error{
+				{
+					Path:  []string{"$fn.and"},
+					Cause: &evaluate.InvalidInvocationError{Name: "and", Cause: fn.ErrKeywordArgsNotAccepted},
+				},
+			},
+		},
+		{
+			Name: "undeclared references",
+			Tree: []interface{}{
+				testutil.JSONParameter("nope"),
+				testutil.JSONOutput("deploy", "url"),
+			},
+			ExpectedErrors: []*check.Error{
+				{
+					Path:  []string{"0"},
+					Cause: &evaluate.InvalidTypeError{Type: "Parameter", Cause: &check.UndeclaredParameterError{Name: "nope"}},
+				},
+				{
+					Path:  []string{"1"},
+					Cause: &evaluate.InvalidTypeError{Type: "Output", Cause: &check.UndeclaredStepError{Name: "deploy"}},
+				},
+			},
+		},
+		{
+			Name: "missing field",
+			Tree: map[string]interface{}{
+				"token": map[string]interface{}{"$type": "Secret"},
+			},
+			ExpectedErrors: []*check.Error{
+				{
+					Path:  []string{"token"},
+					Cause: &evaluate.InvalidTypeError{Type: "Secret", Cause: &evaluate.FieldNotFoundError{Name: "name"}},
+				},
+			},
+		},
+		{
+			Name: "binding in lambda",
+			Tree: testutil.JSONInvocation("map", []interface{}{
+				testutil.JSONParameter("items"),
+				testutil.JSONInvocation("filter", []interface{}{
+					testutil.JSONBinding("$item"),
+					testutil.JSONInvocation("equals", []interface{}{
+						testutil.JSONBinding("$index"),
+						0,
+					}),
+				}),
+			}),
+		},
+		{
+			Name: "binding outside lambda",
+			Tree: testutil.JSONInvocation("upper", testutil.JSONBinding("$item")),
+			ExpectedErrors: []*check.Error{
+				{
+					Path:  []string{"$fn.upper"},
+					Cause: &evaluate.InvalidTypeError{Type: "Binding", Cause: &evaluate.BindingNotFoundError{Name: "$item"}},
+				},
+			},
+		},
+		{
+			Name: "encoding of the wrong type",
+			Tree: testutil.JSONEncoding(transfer.Base64EncodingType, 42),
+			ExpectedErrors: []*check.Error{
+				{
+					Cause: &evaluate.InvalidEncodingError{
+						Type:  string(transfer.Base64EncodingType),
+						Cause: &check.TypeError{Wanted: fn.TypeString, Got: fn.TypeNumber},
+					},
+				},
+			},
+		},
+		{
+			Name:   "wanted type",
+			Tree:   testutil.JSONInvocation("equals", []interface{}{testutil.JSONParameter("message"), "hi"}),
+			Wanted: fn.TypeBoolean,
+		},
+		{
+			Name:   "wanted type mismatch",
+			Tree:   testutil.JSONInvocation("concat", []interface{}{"a", "b"}),
+			Wanted: fn.TypeBoolean,
+			ExpectedErrors: []*check.Error{
+				{Cause: &check.TypeError{Wanted: fn.TypeBoolean, Got: fn.TypeString}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			wanted := test.Wanted
+			if wanted == 0 {
+				wanted = fn.TypeAny
+			}
+
+			errs := check.NewChecker(opts...).CheckType(test.Tree, wanted)
+			require.Equal(t, test.ExpectedErrors, errs)
+		})
+	}
+}
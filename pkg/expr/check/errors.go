@@ -0,0 +1,54 @@
+package check
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/puppetlabs/relay-core/pkg/expr/fn"
+)
+
+var (
+	ErrUnknownArg = errors.New("check: argument is not accepted by this function")
+)
+
+// Error is a problem found at a particular path in an expression tree. Paths
+// are the dot-separated keys and array indices leading to the offending
+// value.
+type Error struct {
+	Path  []string
+	Cause error
+}
+
+func (e *Error) Error() string {
+	if len(e.Path) == 0 {
+		return e.Cause.Error()
+	}
+
+	return fmt.Sprintf("path %q: %+v", strings.Join(e.Path, "."), e.Cause)
+}
+
+type TypeError struct {
+	Wanted fn.Type
+	Got    fn.Type
+}
+
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("check: unexpected type %s (wanted %s)", e.Got, e.Wanted)
+}
+
+type UndeclaredParameterError struct {
+	Name string
+}
+
+func (e *UndeclaredParameterError) Error() string {
+	return fmt.Sprintf("check: parameter %q is not declared", e.Name)
+}
+
+type UndeclaredStepError struct {
+	Name string
+}
+
+func (e *UndeclaredStepError) Error() string {
+	return fmt.Sprintf("check: step %q is not declared", e.Name)
+}
@@ -46,6 +46,23 @@ func (e *FieldNotFoundError) Error() string {
 	return fmt.Sprintf("the required field %q could not be found", e.Name)
 }
 
+type InvalidFieldValueError struct {
+	Name  string
+	Value interface{}
+}
+
+func (e *InvalidFieldValueError) Error() string {
+	return fmt.Sprintf("the field %q has an invalid value %v", e.Name, e.Value)
+}
+
+type BindingNotFoundError struct {
+	Name string
+}
+
+func (e *BindingNotFoundError) Error() string {
+	return fmt.Sprintf("the name %q is not bound", e.Name)
+}
+
 type InvalidEncodingError struct {
 	Type  string
 	Cause error
@@ -2,6 +2,7 @@ package evaluate
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
@@ -16,6 +17,7 @@ import (
 	"github.com/puppetlabs/relay-core/pkg/expr/parse"
 	"github.com/puppetlabs/relay-core/pkg/expr/resolve"
 	"github.com/puppetlabs/relay-core/pkg/util/jsonpath"
+	"go.opentelemetry.io/otel/label"
 )
 
 type Language int
@@ -24,10 +26,61 @@ const (
 	LanguagePath Language = 1 + iota
 	LanguageJSONPath
 	LanguageJSONPathTemplate
+	LanguageJQ
+	LanguageCEL
 )
 
+func (l Language) String() string {
+	switch l {
+	case LanguagePath:
+		return "path"
+	case LanguageJSONPath:
+		return "jsonpath"
+	case LanguageJSONPathTemplate:
+		return "jsonpath-template"
+	case LanguageJQ:
+		return "jq"
+	case LanguageCEL:
+		return "cel"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseLanguage returns the language with the given name, as returned by
+// Language.String.
+func ParseLanguage(name string) (Language, bool) {
+	for _, lang := range []Language{LanguagePath, LanguageJSONPath, LanguageJSONPathTemplate, LanguageJQ, LanguageCEL} {
+		if lang.String() == name {
+			return lang, true
+		}
+	}
+
+	return 0, false
+}
+
 type InvokeFunc func(ctx context.Context, i fn.Invoker) (*model.Result, error)
 
+// secretVersion converts the version field of a Secret type to a positive
+// integer. JSON documents represent numbers as floats, and YAML documents
+// represent them as ints, so both are accepted.
+func secretVersion(v interface{}) (int, bool) {
+	switch vt := v.(type) {
+	case int:
+		return vt, vt > 0
+	case int64:
+		return int(vt), vt > 0
+	case float64:
+		if vt != float64(int(vt)) {
+			return 0, false
+		}
+
+		return int(vt), vt > 0
+	default:
+		return 0, false
+	}
+}
+
 type Evaluator struct {
 	lang                   Language
 	invoke                 InvokeFunc
@@ -39,12 +92,26 @@ type Evaluator struct {
 	parameterTypeResolver  resolve.ParameterTypeResolver
 	answerTypeResolver     resolve.AnswerTypeResolver
 	invocationResolver     resolve.InvocationResolver
+	bindings               map[string]interface{}
+	inert                  bool
+	trace                  *model.Trace
+
+	maxConcurrentResolutions int
+	resolutionScope          uint64
 }
 
 func (e *Evaluator) ScopeTo(tree parse.Tree) *ScopedEvaluator {
 	return &ScopedEvaluator{parent: e, tree: tree}
 }
 
+// scopeToArg scopes the evaluator to an argument of a function invocation at
+// the given path relative to the invocation.
+func (e *Evaluator) scopeToArg(tree parse.Tree, path ...string) *ScopedEvaluator {
+	se := e.ScopeTo(tree).Copy(WithLanguage(LanguagePath))
+	se.path = path
+	return se
+}
+
 func (e *Evaluator) Copy(opts ...Option) *Evaluator {
 	if len(opts) == 0 {
 		return e
@@ -60,7 +127,13 @@ func (e *Evaluator) Copy(opts ...Option) *Evaluator {
 	return ne
 }
 
-func (e *Evaluator) Evaluate(ctx context.Context, tree parse.Tree, depth int) (*model.Result, error) {
+func (e *Evaluator) Evaluate(ctx context.Context, tree parse.Tree, depth int) (result *model.Result, err error) {
+	ctx, span := startSpan(ctx, "evaluate", label.Int("relay.evaluate.depth", depth))
+	defer func() { endSpan(ctx, span, err) }()
+
+	ctx = e.withResolutions(ctx)
+	e.prefetch(ctx, tree, depth)
+
 	r, err := e.evaluate(ctx, tree, depth)
 	if err != nil {
 		return nil, err
@@ -73,8 +146,11 @@ func (e *Evaluator) EvaluateAll(ctx context.Context, tree parse.Tree) (*model.Re
 	return e.Evaluate(ctx, tree, -1)
 }
 
-func (e *Evaluator) EvaluateInto(ctx context.Context, tree parse.Tree, target interface{}) (model.Unresolvable, error) {
-	var u model.Unresolvable
+func (e *Evaluator) EvaluateInto(ctx context.Context, tree parse.Tree, target interface{}) (u model.Unresolvable, err error) {
+	ctx, span := startSpan(ctx, "evaluate.into")
+	defer func() { endSpan(ctx, span, err) }()
+
+	ctx = e.withResolutions(ctx)
 
 	d, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
 		DecodeHook: mapstructure.ComposeDecodeHookFunc(
@@ -90,10 +166,29 @@ func (e *Evaluator) EvaluateInto(ctx context.Context, tree parse.Tree, target in
 		return u, err
 	}
 
-	return u, d.Decode(tree)
+	err = d.Decode(tree)
+	return u, err
+}
+
+func (e *Evaluator) EvaluateQuery(ctx context.Context, tree parse.Tree, query string) (result *model.Result, err error) {
+	ctx, span := startSpan(ctx, "evaluate.query", label.String("relay.evaluate.language", e.lang.String()))
+	defer func() { endSpan(ctx, span, err) }()
+
+	ctx = e.withResolutions(ctx)
+
+	return e.traced(ctx, model.TraceNodeKindQuery, query, e.lang.String(), func(ctx context.Context) (*model.Result, error) {
+		return e.evaluateQuery(ctx, tree, query)
+	})
 }
 
-func (e *Evaluator) EvaluateQuery(ctx context.Context, tree parse.Tree, query string) (*model.Result, error) {
+func (e *Evaluator) evaluateQuery(ctx context.Context, tree parse.Tree, query string) (*model.Result, error) {
+	switch e.lang {
+	case LanguageJQ:
+		return e.evaluateJQQuery(ctx, tree, query)
+	case LanguageCEL:
+		return e.evaluateCELQuery(ctx, tree, query)
+	}
+
 	r := &model.Result{}
 
 	var pl gval.Language
@@ -115,9 +210,10 @@ func (e *Evaluator) EvaluateQuery(ctx context.Context, tree parse.Tree, query st
 				rv, err := e.evaluate(ctx, v, -1)
 				if err != nil {
 					return "", err
-				} else if !rv.Complete() {
-					r.Extends(rv)
-				} else {
+				}
+
+				r.Extends(rv)
+				if rv.Complete() {
 					v = rv.Value
 				}
 
@@ -133,7 +229,22 @@ func (e *Evaluator) EvaluateQuery(ctx context.Context, tree parse.Tree, query st
 		return nil, err
 	}
 
-	v, err := path(ctx, tree)
+	// The root of the tree may itself be a reference, like a parameter or a
+	// binding, in which case the query applies to the value it refers to. A
+	// depth of 1 only resolves the root when it is a reference, leaving the
+	// references within an object or array to the variable selector. If the
+	// root can't be resolved, there's nothing to apply the query to, so the
+	// unresolvable root is the result.
+	rr, err := e.evaluate(ctx, tree, 1)
+	if err != nil {
+		return nil, err
+	} else if !rr.Complete() {
+		return e.resultMapper.MapResult(ctx, rr)
+	}
+
+	r.Extends(rr)
+
+	v, err := path(ctx, rr.Value)
 	if err != nil {
 		return nil, err
 	}
@@ -176,19 +287,26 @@ func (e *Evaluator) evaluateType(ctx context.Context, tm map[string]interface{})
 			return nil, &InvalidTypeError{Type: "Secret", Cause: &FieldNotFoundError{Name: "name"}}
 		}
 
-		value, err := e.secretTypeResolver.ResolveSecret(ctx, name)
+		ref := reference{ty: "Secret", name: name}
+		if v, found := tm["version"]; found {
+			if ref.version, ok = secretVersion(v); !ok {
+				return nil, &InvalidTypeError{Type: "Secret", Cause: &InvalidFieldValueError{Name: "version", Value: v}}
+			}
+		}
+
+		value, err := e.resolved(ctx, ref)
 		if serr, ok := err.(*model.SecretNotFoundError); ok {
 			return &model.Result{
 				Value: tm,
 				Unresolvable: model.Unresolvable{Secrets: []model.UnresolvableSecret{
-					{Name: serr.Name},
+					{Name: serr.Name, Version: ref.version},
 				}},
 			}, nil
 		} else if err != nil {
 			return nil, &InvalidTypeError{Type: "Secret", Cause: err}
 		}
 
-		return &model.Result{Value: value}, nil
+		return &model.Result{Value: value, Sensitive: true}, nil
 	case "Connection":
 		connectionType, ok := tm["type"].(string)
 		if !ok {
@@ -200,7 +318,7 @@ func (e *Evaluator) evaluateType(ctx context.Context, tm map[string]interface{})
 			return nil, &InvalidTypeError{Type: "Connection", Cause: &FieldNotFoundError{Name: "name"}}
 		}
 
-		value, err := e.connectionTypeResolver.ResolveConnection(ctx, connectionType, name)
+		value, err := e.resolved(ctx, reference{ty: "Connection", scope: connectionType, name: name})
 		if oerr, ok := err.(*model.ConnectionNotFoundError); ok {
 			return &model.Result{
 				Value: tm,
@@ -212,7 +330,7 @@ func (e *Evaluator) evaluateType(ctx context.Context, tm map[string]interface{})
 			return nil, &InvalidTypeError{Type: "Connection", Cause: err}
 		}
 
-		return &model.Result{Value: value}, nil
+		return &model.Result{Value: value, Sensitive: true}, nil
 	case "Output":
 		from, ok := tm["from"].(string)
 		if !ok {
@@ -230,7 +348,7 @@ func (e *Evaluator) evaluateType(ctx context.Context, tm map[string]interface{})
 			return nil, &InvalidTypeError{Type: "Output", Cause: &FieldNotFoundError{Name: "name"}}
 		}
 
-		value, err := e.outputTypeResolver.ResolveOutput(ctx, from, name)
+		value, err := e.resolved(ctx, reference{ty: "Output", scope: from, name: name})
 		if oerr, ok := err.(*model.OutputNotFoundError); ok {
 			return &model.Result{
 				Value: tm,
@@ -261,6 +379,22 @@ func (e *Evaluator) evaluateType(ctx context.Context, tm map[string]interface{})
 			return nil, &InvalidTypeError{Type: "Parameter", Cause: err}
 		}
 
+		return &model.Result{Value: value}, nil
+	case "Binding":
+		name, ok := tm["name"].(string)
+		if !ok {
+			return nil, &InvalidTypeError{Type: "Binding", Cause: &FieldNotFoundError{Name: "name"}}
+		}
+
+		value, found := e.bindings[name]
+		if !found && e.inert {
+			// Names are only bound while a function applies a lambda, which
+			// never happens here.
+			return &model.Result{Value: tm}, nil
+		} else if !found {
+			return nil, &InvalidTypeError{Type: "Binding", Cause: &BindingNotFoundError{Name: name}}
+		}
+
 		return &model.Result{Value: value}, nil
 	case "Answer":
 		askRef, ok := tm["askRef"].(string)
@@ -297,10 +431,10 @@ func (e *Evaluator) evaluateEncoding(ctx context.Context, em map[string]interfac
 		return &model.Result{Value: em}, nil
 	}
 
-	dr, err := e.evaluate(ctx, em["data"], -1)
+	dr, err := e.evaluate(e.withTracePath(ctx, "data"), em["data"], -1)
 	if err != nil {
 		return nil, &InvalidEncodingError{Type: ty, Cause: err}
-	} else if !dr.Complete() {
+	} else if !dr.Complete() || e.inert {
 		r := &model.Result{
 			Value: map[string]interface{}{
 				"$encoding": ty,
@@ -330,7 +464,7 @@ func (e *Evaluator) evaluateEncoding(ctx context.Context, em map[string]interfac
 		return nil, &InvalidEncodingError{Type: ty, Cause: err}
 	}
 
-	return &model.Result{Value: string(decoded)}, nil
+	return &model.Result{Value: string(decoded), Sensitive: dr.Sensitive}, nil
 }
 
 func (e *Evaluator) evaluateInvocation(ctx context.Context, im map[string]interface{}) (*model.Result, error) {
@@ -341,35 +475,49 @@ func (e *Evaluator) evaluateInvocation(ctx context.Context, im map[string]interf
 
 	name := strings.TrimPrefix(key, "$fn.")
 
+	if e.inert {
+		// We only want to know what the arguments refer to, so we evaluate
+		// all of them, even those the function itself might not, and never
+		// call it.
+		a, err := e.evaluate(e.withTracePath(ctx, key), value, -1)
+		if err != nil {
+			return nil, err
+		}
+
+		r := &model.Result{Value: im}
+		r.Extends(a)
+		return r, nil
+	}
+
 	var invoker fn.Invoker
 
 	// Evaluate one level to determine whether we should do a positional or
 	// keyword invocation.
-	a, err := e.evaluate(ctx, value, 1)
+	a, err := e.evaluate(e.withTracePath(ctx, key), value, 1)
 	if err != nil {
 		return nil, err
 	} else if !a.Complete() {
 		// The top level couldn't be resolved, so we'll pass it in unmodified as
 		// a single-argument parameter.
-		invoker, err = e.invocationResolver.ResolveInvocationPositional(ctx, name, []model.Evaluable{e.ScopeTo(value).Copy(WithLanguage(LanguagePath))})
+		invoker, err = e.invocationResolver.ResolveInvocationPositional(ctx, name, []model.Evaluable{e.scopeToArg(value, key)})
 	} else {
 		switch ra := a.Value.(type) {
 		case []interface{}:
 			args := make([]model.Evaluable, len(ra))
 			for i, value := range ra {
-				args[i] = e.ScopeTo(value).Copy(WithLanguage(LanguagePath))
+				args[i] = e.scopeToArg(value, key, strconv.Itoa(i))
 			}
 
 			invoker, err = e.invocationResolver.ResolveInvocationPositional(ctx, name, args)
 		case map[string]interface{}:
 			args := make(map[string]model.Evaluable, len(ra))
-			for key, value := range ra {
-				args[key] = e.ScopeTo(value).Copy(WithLanguage(LanguagePath))
+			for k, value := range ra {
+				args[k] = e.scopeToArg(value, key, k)
 			}
 
 			invoker, err = e.invocationResolver.ResolveInvocation(ctx, name, args)
 		default:
-			invoker, err = e.invocationResolver.ResolveInvocationPositional(ctx, name, []model.Evaluable{e.ScopeTo(ra).Copy(WithLanguage(LanguagePath))})
+			invoker, err = e.invocationResolver.ResolveInvocationPositional(ctx, name, []model.Evaluable{e.scopeToArg(ra, key)})
 		}
 	}
 	if ierr, ok := err.(*model.FunctionResolutionError); ok {
@@ -413,7 +561,7 @@ func (e *Evaluator) evaluateUnchecked(ctx context.Context, v interface{}, depth
 		r := &model.Result{}
 		l := make([]interface{}, len(vt))
 		for i, v := range vt {
-			nv, err := e.evaluate(ctx, v, depth-1)
+			nv, err := e.evaluate(e.withTracePath(ctx, strconv.Itoa(i)), v, depth-1)
 			if err != nil {
 				return nil, &PathEvaluationError{
 					Path:  strconv.Itoa(i),
@@ -428,17 +576,23 @@ func (e *Evaluator) evaluateUnchecked(ctx context.Context, v interface{}, depth
 		r.Value = l
 		return r, nil
 	case map[string]interface{}:
-		if _, ok := vt["$type"]; ok {
-			return e.evaluateType(ctx, vt)
-		} else if _, ok := vt["$encoding"]; ok {
-			return e.evaluateEncoding(ctx, vt)
+		if ty, ok := vt["$type"]; ok {
+			return e.traced(ctx, model.TraceNodeKindType, fmt.Sprint(ty), e.typeResolverName(ty), func(ctx context.Context) (*model.Result, error) {
+				return e.evaluateType(ctx, vt)
+			})
+		} else if ty, ok := vt["$encoding"]; ok {
+			return e.traced(ctx, model.TraceNodeKindEncoding, fmt.Sprint(ty), "", func(ctx context.Context) (*model.Result, error) {
+				return e.evaluateEncoding(ctx, vt)
+			})
 		} else if len(vt) == 1 {
 			var first string
 			for first = range vt {
 			}
 
 			if strings.HasPrefix(first, "$fn.") {
-				return e.evaluateInvocation(ctx, vt)
+				return e.traced(ctx, model.TraceNodeKindInvocation, strings.TrimPrefix(first, "$fn."), fmt.Sprintf("%T", e.invocationResolver), func(ctx context.Context) (*model.Result, error) {
+					return e.evaluateInvocation(ctx, vt)
+				})
 			}
 		} else if depth == 1 {
 			return &model.Result{Value: v}, nil
@@ -447,7 +601,7 @@ func (e *Evaluator) evaluateUnchecked(ctx context.Context, v interface{}, depth
 		r := &model.Result{}
 		m := make(map[string]interface{}, len(vt))
 		for k, v := range vt {
-			nv, err := e.evaluate(ctx, v, depth-1)
+			nv, err := e.evaluate(e.withTracePath(ctx, k), v, depth-1)
 			if err != nil {
 				return nil, &PathEvaluationError{Path: k, Cause: err}
 			}
@@ -494,6 +648,9 @@ func NewEvaluator(opts ...Option) *Evaluator {
 		parameterTypeResolver:  resolve.NoOpParameterTypeResolver,
 		answerTypeResolver:     resolve.NoOpAnswerTypeResolver,
 		invocationResolver:     resolve.NewDefaultMemoryInvocationResolver(),
+
+		maxConcurrentResolutions: DefaultMaxConcurrentResolutions,
+		resolutionScope:          newResolutionScope(),
 	}
 
 	for _, opt := range opts {
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/puppetlabs/horsehead/v2/encoding/transfer"
 	jsonpath "github.com/puppetlabs/paesslerag-jsonpath"
@@ -20,6 +22,11 @@ import (
 
 type randomOrder []interface{}
 
+type versionedSecretTypeResolver struct {
+	resolve.SecretTypeResolver
+	resolve.SecretVersionTypeResolver
+}
+
 type test struct {
 	Name                 string
 	Data                 string
@@ -148,6 +155,43 @@ func TestEvaluate(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "versioned secret",
+			Data: `{"foo": {"$type": "Secret", "name": "bar", "version": 2}}`,
+			Opts: []evaluate.Option{
+				evaluate.WithSecretTypeResolver(resolve.ChainSecretTypeResolvers(
+					resolve.NewMemorySecretTypeResolver(map[string]string{"bar": "latest"}),
+					versionedSecretTypeResolver{
+						SecretTypeResolver: resolve.NoOpSecretTypeResolver,
+						SecretVersionTypeResolver: resolve.SecretVersionTypeResolverFunc(func(ctx context.Context, name string, version int) (string, error) {
+							if name != "bar" || version != 2 {
+								return "", &model.SecretNotFoundError{Name: name}
+							}
+
+							return "version 2", nil
+						}),
+					},
+				)),
+			},
+			ExpectedValue: map[string]interface{}{
+				"foo": "version 2",
+			},
+		},
+		{
+			Name: "unresolvable versioned secret",
+			Data: `{"foo": {"$type": "Secret", "name": "bar", "version": 2}}`,
+			Opts: []evaluate.Option{
+				evaluate.WithSecretTypeResolver(resolve.NewMemorySecretTypeResolver(map[string]string{"bar": "latest"})),
+			},
+			ExpectedValue: map[string]interface{}{
+				"foo": map[string]interface{}{"$type": "Secret", "name": "bar", "version": float64(2)},
+			},
+			ExpectedUnresolvable: model.Unresolvable{
+				Secrets: []model.UnresolvableSecret{
+					{Name: "bar", Version: 2},
+				},
+			},
+		},
 		{
 			Name: "unresolvable connection",
 			Data: `{"foo": {"$type": "Connection", "type": "blort", "name": "bar"}}`,
@@ -212,6 +256,17 @@ func TestEvaluate(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "invalid secret version",
+			Data: `{"foo": {"$type": "Secret", "name": "bar", "version": 1.5}}`,
+			ExpectedError: &evaluate.PathEvaluationError{
+				Path: "foo",
+				Cause: &evaluate.InvalidTypeError{
+					Type:  "Secret",
+					Cause: &evaluate.InvalidFieldValueError{Name: "version", Value: 1.5},
+				},
+			},
+		},
 		{
 			Name: "invalid connection",
 			Data: `{"foo": [{"$type": "Connection", "name": "foo"}]}`,
@@ -643,6 +698,43 @@ func TestEvaluate(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "inert",
+			Data: `{
+				"a": {"$fn.if": [
+					{"$type": "Parameter", "name": "deploy"},
+					{"$type": "Output", "from": "build", "name": "image"},
+					{"$fn.map": [[1, 2], {"$fn.concat": [{"$type": "Binding", "name": "$item"}, {"$type": "Secret", "name": "suffix"}]}]}
+				]},
+				"b": {"$encoding": "base64", "data": {"$type": "Binding", "name": "$item"}}
+			}`,
+			Opts: []evaluate.Option{
+				evaluate.WithSecretTypeResolver(resolve.NewMemorySecretTypeResolver(map[string]string{"suffix": "!"})),
+				evaluate.WithInert(true),
+			},
+			ExpectedValue: map[string]interface{}{
+				"a": map[string]interface{}{"$fn.if": []interface{}{
+					map[string]interface{}{"$type": "Parameter", "name": "deploy"},
+					map[string]interface{}{"$type": "Output", "from": "build", "name": "image"},
+					map[string]interface{}{"$fn.map": []interface{}{
+						[]interface{}{float64(1), float64(2)},
+						map[string]interface{}{"$fn.concat": []interface{}{
+							map[string]interface{}{"$type": "Binding", "name": "$item"},
+							map[string]interface{}{"$type": "Secret", "name": "suffix"},
+						}},
+					}},
+				}},
+				"b": map[string]interface{}{
+					"$encoding": "base64",
+					"data":      map[string]interface{}{"$type": "Binding", "name": "$item"},
+				},
+			},
+			ExpectedUnresolvable: model.Unresolvable{
+				Secrets:    []model.UnresolvableSecret{{Name: "suffix"}},
+				Outputs:    []model.UnresolvableOutput{{From: "build", Name: "image"}},
+				Parameters: []model.UnresolvableParameter{{Name: "deploy"}},
+			},
+		},
 	}.RunAll(t)
 }
 
@@ -701,6 +793,65 @@ func TestEvaluateQuery(t *testing.T) {
 			},
 			ExpectedValue: "quux",
 		},
+		{
+			Name:  "root parameter",
+			Data:  `{"$type": "Parameter", "name": "bar"}`,
+			Query: "baz.quux",
+			Opts: []evaluate.Option{
+				evaluate.WithParameterTypeResolver(resolve.NewMemoryParameterTypeResolver(
+					map[string]interface{}{
+						"bar": map[string]interface{}{
+							"baz": map[string]interface{}{"quux": "x"},
+						},
+					},
+				)),
+			},
+			ExpectedValue: "x",
+		},
+		{
+			Name:  "JSONPath root parameter with nested references",
+			Data:  `{"$type": "Parameter", "name": "bar"}`,
+			Query: "$.baz",
+			Opts: []evaluate.Option{
+				evaluate.WithLanguage(evaluate.LanguageJSONPath),
+				evaluate.WithParameterTypeResolver(resolve.NewMemoryParameterTypeResolver(
+					map[string]interface{}{
+						"bar": map[string]interface{}{
+							"baz": map[string]interface{}{"$type": "Secret", "name": "foo"},
+						},
+					},
+				)),
+			},
+			ExpectedUnresolvable: model.Unresolvable{
+				Secrets: []model.UnresolvableSecret{
+					{Name: "foo"},
+				},
+			},
+		},
+		{
+			// The query can't be applied to a value we don't have, so the
+			// result is the unresolvable root itself.
+			Name:          "root unresolvable parameter",
+			Data:          `{"$type": "Parameter", "name": "bar"}`,
+			Query:         "baz.quux",
+			ExpectedValue: map[string]interface{}{"$type": "Parameter", "name": "bar"},
+			ExpectedUnresolvable: model.Unresolvable{
+				Parameters: []model.UnresolvableParameter{
+					{Name: "bar"},
+				},
+			},
+		},
+		{
+			// Only the root itself is evaluated before the query is applied,
+			// so references the query doesn't select stay untouched.
+			Name: "root object is not evaluated",
+			Data: `{
+				"a": {"$type": "Parameter", "name": "bar"},
+				"b": {"c": {"$type": "Secret", "name": "foo"}, "d": "x"}
+			}`,
+			Query:         "b.d",
+			ExpectedValue: "x",
+		},
 		{
 			Name: "JSONPath traverses output",
 			Data: `{
@@ -908,6 +1059,149 @@ func TestEvaluateQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "jq traverses parameter",
+			Data: `{
+				"foo": {"$type": "Parameter", "name": "bar"}
+			}`,
+			Query: ".foo.bar.baz",
+			Opts: []evaluate.Option{
+				evaluate.WithLanguage(evaluate.LanguageJQ),
+				evaluate.WithParameterTypeResolver(resolve.NewMemoryParameterTypeResolver(
+					map[string]interface{}{
+						"bar": map[string]interface{}{
+							"bar": map[string]interface{}{"baz": "quux"},
+						},
+					},
+				)),
+			},
+			ExpectedValue: "quux",
+		},
+		{
+			Name: "jq reshapes data",
+			Data: `{
+				"items": [
+					{"name": "a", "enabled": true},
+					{"name": "b", "enabled": false},
+					{"name": "c", "enabled": true}
+				]
+			}`,
+			Query: `{names: [.items[] | select(.enabled) | .name]}`,
+			Opts: []evaluate.Option{
+				evaluate.WithLanguage(evaluate.LanguageJQ),
+			},
+			ExpectedValue: map[string]interface{}{
+				"names": []interface{}{"a", "c"},
+			},
+		},
+		{
+			Name:  "jq multiple outputs",
+			Data:  `{"foo": [1, 2, 3]}`,
+			Query: ".foo[] * 2",
+			Opts: []evaluate.Option{
+				evaluate.WithLanguage(evaluate.LanguageJQ),
+			},
+			ExpectedValue: []interface{}{float64(2), float64(4), float64(6)},
+		},
+		{
+			Name: "jq unresolvable",
+			Data: `{
+				"a": {"name": "aa", "value": {"$type": "Secret", "name": "foo"}},
+				"b": {"name": "bb", "value": "gggggg"}
+			}`,
+			Query: "[.[].value | strings]",
+			Opts: []evaluate.Option{
+				evaluate.WithLanguage(evaluate.LanguageJQ),
+			},
+			ExpectedValue: []interface{}{"gggggg"},
+			ExpectedUnresolvable: model.Unresolvable{
+				Secrets: []model.UnresolvableSecret{
+					{Name: "foo"},
+				},
+			},
+		},
+		{
+			Name: "jq unresolvable used by program",
+			Data: `{
+				"foo": {"$type": "Parameter", "name": "bar"}
+			}`,
+			Query: ".foo + 1",
+			Opts: []evaluate.Option{
+				evaluate.WithLanguage(evaluate.LanguageJQ),
+			},
+			ExpectedUnresolvable: model.Unresolvable{
+				Parameters: []model.UnresolvableParameter{
+					{Name: "bar"},
+				},
+			},
+		},
+		{
+			Name: "jq unresolvable not evaluated because not selected",
+			Data: `{
+				"a": {"b": "x", "c": {"$type": "Secret", "name": "foo"}},
+				"d": {"$type": "Secret", "name": "bar"},
+				"e": [{"$type": "Parameter", "name": "baz"}, {"f": ["y", {"$type": "Connection", "type": "aws", "name": "qux"}]}]
+			}`,
+			Query: `{b: .a.b, f: .e[1].f[0], n: (.e[1].f | length), g: (.a.g // "z")}`,
+			Opts: []evaluate.Option{
+				evaluate.WithLanguage(evaluate.LanguageJQ),
+			},
+			ExpectedValue: map[string]interface{}{
+				"b": "x",
+				"f": "y",
+				"n": 2,
+				"g": "z",
+			},
+			ExpectedUnresolvable: model.Unresolvable{
+				Connections: []model.UnresolvableConnection{
+					{Type: "aws", Name: "qux"},
+				},
+			},
+		},
+		{
+			Name: "CEL boolean check",
+			Data: `{
+				"foo": {"$type": "Parameter", "name": "bar"},
+				"replicas": 3
+			}`,
+			Query: `foo.enabled && replicas > 2.0`,
+			Opts: []evaluate.Option{
+				evaluate.WithLanguage(evaluate.LanguageCEL),
+				evaluate.WithParameterTypeResolver(resolve.NewMemoryParameterTypeResolver(
+					map[string]interface{}{
+						"bar": map[string]interface{}{"enabled": true},
+					},
+				)),
+			},
+			ExpectedValue: true,
+		},
+		{
+			Name: "CEL unresolvable",
+			Data: `{
+				"foo": {"$type": "Parameter", "name": "bar"}
+			}`,
+			Query: `foo.enabled`,
+			Opts: []evaluate.Option{
+				evaluate.WithLanguage(evaluate.LanguageCEL),
+			},
+			ExpectedUnresolvable: model.Unresolvable{
+				Parameters: []model.UnresolvableParameter{
+					{Name: "bar"},
+				},
+			},
+		},
+		{
+			Name: "CEL unresolvable not evaluated because not referenced",
+			Data: `{
+				"a": {"$type": "Parameter", "name": "bar"},
+				"b": ["x", "y"]
+			}`,
+			Query: `"y" in b`,
+			Opts: []evaluate.Option{
+				evaluate.WithLanguage(evaluate.LanguageCEL),
+			},
+			ExpectedValue: true,
+		},
 	}.RunAll(t)
 }
 
@@ -1081,3 +1375,226 @@ func TestJSON(t *testing.T) {
 		},
 	}.RunAll(t)
 }
+
+func TestEvaluateTrace(t *testing.T) {
+	tree, err := parse.ParseJSONString(`{
+		"greeting": {"$fn.concat": ["Hello, ", {"$type": "Parameter", "name": "name"}]},
+		"token": {"$type": "Secret", "name": "token"},
+		"image": {"$type": "Output", "from": "build", "name": "image"}
+	}`)
+	require.NoError(t, err)
+
+	trace := model.NewTrace()
+	ev := evaluate.NewEvaluator(
+		evaluate.WithParameterTypeResolver(resolve.NewMemoryParameterTypeResolver(
+			map[string]interface{}{"name": "world"},
+		)),
+		evaluate.WithSecretTypeResolver(resolve.NewMemorySecretTypeResolver(
+			map[string]string{"token": "v3ry s3kr3t!"},
+		)),
+		evaluate.WithTrace(trace),
+	)
+
+	_, err = ev.EvaluateAll(context.Background(), tree)
+	require.NoError(t, err)
+
+	nodes := trace.Nodes()
+
+	var clearElapsed func(nodes []*model.TraceNode)
+	clearElapsed = func(nodes []*model.TraceNode) {
+		for _, node := range nodes {
+			node.Elapsed = 0
+			clearElapsed(node.Children)
+		}
+	}
+	clearElapsed(nodes)
+
+	require.Equal(t, []*model.TraceNode{
+		{
+			Path:     "greeting",
+			Kind:     model.TraceNodeKindInvocation,
+			Name:     "concat",
+			Resolver: "*resolve.MemoryInvocationResolver",
+			Value:    "Hello, world",
+			Children: []*model.TraceNode{
+				{
+					Path:     "greeting.$fn.concat.1",
+					Kind:     model.TraceNodeKindType,
+					Name:     "Parameter",
+					Resolver: "*resolve.MemoryParameterTypeResolver",
+					Value:    "world",
+				},
+			},
+		},
+		{
+			Path:     "image",
+			Kind:     model.TraceNodeKindType,
+			Name:     "Output",
+			Resolver: "*resolve.chainOutputTypeResolvers",
+			Value: map[string]interface{}{
+				"$type": "Output",
+				"from":  "build",
+				"name":  "image",
+			},
+			Unresolvable: model.Unresolvable{
+				Outputs: []model.UnresolvableOutput{{From: "build", Name: "image"}},
+			},
+		},
+		{
+			Path:      "token",
+			Kind:      model.TraceNodeKindType,
+			Name:      "Secret",
+			Resolver:  "*resolve.MemorySecretTypeResolver",
+			Value:     model.RedactedValue,
+			Sensitive: true,
+		},
+	}, nodes)
+}
+
+// countingSecretTypeResolver records how many times each secret is resolved
+// and the most secrets it was asked to resolve at the same time. If overlap is
+// set, each resolution waits until that many are in progress at once, so that
+// a test can tell that they overlap.
+type countingSecretTypeResolver struct {
+	overlap int
+
+	mut         sync.Mutex
+	calls       map[string]int
+	active      int
+	peak        int
+	overlapping chan struct{}
+}
+
+func newCountingSecretTypeResolver(overlap int) *countingSecretTypeResolver {
+	return &countingSecretTypeResolver{
+		overlap:     overlap,
+		calls:       make(map[string]int),
+		overlapping: make(chan struct{}),
+	}
+}
+
+func (r *countingSecretTypeResolver) ResolveSecret(ctx context.Context, name string) (string, error) {
+	r.mut.Lock()
+	r.calls[name]++
+	r.active++
+	if r.active > r.peak {
+		r.peak = r.active
+		if r.peak == r.overlap {
+			close(r.overlapping)
+		}
+	}
+	r.mut.Unlock()
+
+	if r.overlap > 1 {
+		select {
+		case <-r.overlapping:
+		case <-time.After(time.Second):
+		}
+	}
+
+	r.mut.Lock()
+	r.active--
+	r.mut.Unlock()
+
+	if name == "missing" {
+		return "", &model.SecretNotFoundError{Name: name}
+	}
+
+	return "value of " + name, nil
+}
+
+func TestEvaluateResolution(t *testing.T) {
+	tree, err := parse.ParseJSONString(`{
+		"a": {"$type": "Secret", "name": "a"},
+		"b": [{"$type": "Secret", "name": "b"}, {"$type": "Secret", "name": "a"}],
+		"c": {"$fn.concat": [{"$type": "Secret", "name": "c"}, {"$type": "Secret", "name": "a"}]},
+		"d": {"$type": "Secret", "name": "d"},
+		"e": {"$type": "Secret", "name": "missing"},
+		"f": {"$fn.if": [true, "yes", {"$type": "Secret", "name": "f"}]},
+		"g": {"$fn.and": [false, {"$type": "Secret", "name": "g"}]},
+		"h": {"$fn.or": [true, {"$type": "Secret", "name": "h"}]}
+	}`)
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"a": "value of a",
+		"b": []interface{}{"value of b", "value of a"},
+		"c": "value of cvalue of a",
+		"d": "value of d",
+		"e": map[string]interface{}{"$type": "Secret", "name": "missing"},
+		"f": "yes",
+		"g": false,
+		"h": true,
+	}
+
+	for _, test := range []struct {
+		Name         string
+		MaxResolving int
+	}{
+		{Name: "sequential", MaxResolving: 1},
+		{Name: "concurrent", MaxResolving: 2},
+		{Name: "concurrent without limit", MaxResolving: evaluate.DefaultMaxConcurrentResolutions},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			// The prefetched secrets are a, b, d, and missing. When they can
+			// be resolved concurrently, each waits for the others up to the
+			// limit.
+			overlap := test.MaxResolving
+			if overlap > 4 {
+				overlap = 4
+			}
+
+			resolver := newCountingSecretTypeResolver(overlap)
+
+			ev := evaluate.NewEvaluator(
+				evaluate.WithSecretTypeResolver(resolver),
+				evaluate.WithMaxConcurrentResolutions(test.MaxResolving),
+			)
+
+			r, err := ev.EvaluateAll(context.Background(), tree)
+			require.NoError(t, err)
+			require.Equal(t, expected, r.Value)
+			require.Equal(t, model.Unresolvable{
+				Secrets: []model.UnresolvableSecret{{Name: "missing"}},
+			}, r.Unresolvable)
+
+			// Each secret is resolved once, and the secrets in the arguments
+			// that the functions don't evaluate are never resolved.
+			require.Equal(t, map[string]int{"a": 1, "b": 1, "c": 1, "d": 1, "missing": 1}, resolver.calls)
+
+			// Resolutions overlap, but never by more than the limit.
+			require.Equal(t, overlap, resolver.peak)
+		})
+	}
+}
+
+func TestEvaluateTemplate(t *testing.T) {
+	ev := evaluate.NewEvaluator(
+		evaluate.WithConnectionTypeResolver(resolve.NewMemoryConnectionTypeResolver(
+			map[resolve.MemoryConnectionKey]interface{}{
+				{Type: "kubernetes", Name: "prod"}: map[string]interface{}{"server": "https://prod.example.com"},
+			},
+		)),
+		evaluate.WithOutputTypeResolver(resolve.NewMemoryOutputTypeResolver(
+			map[resolve.MemoryOutputKey]interface{}{
+				{From: "build", Name: "image"}:  "app:1.2.3",
+				{From: "build", Name: "labels"}: map[string]interface{}{"tier": "web"},
+			},
+		)),
+	)
+
+	tree, err := parse.ParseYAMLString(`!Template 'Deploying ${outputs.build.image} to ${connections.kubernetes.prod.server}'`)
+	require.NoError(t, err)
+
+	r, err := ev.EvaluateAll(context.Background(), tree)
+	require.NoError(t, err)
+	require.True(t, r.Complete())
+	require.Equal(t, "Deploying app:1.2.3 to https://prod.example.com", r.Value)
+
+	// A placeholder that refers to an object can't be interpolated.
+	tree, err = parse.ParseYAMLString(`!Template 'Labels: ${outputs.build.labels}'`)
+	require.NoError(t, err)
+
+	_, err = ev.EvaluateAll(context.Background(), tree)
+	require.IsType(t, &evaluate.InvocationError{}, err)
+}
@@ -0,0 +1,327 @@
+package evaluate
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/itchyny/gojq"
+	"github.com/puppetlabs/relay-core/pkg/expr/model"
+)
+
+// jqPath is a sequence of object keys and array indices from the root of the
+// input to a jq program.
+type jqPath []interface{}
+
+func (p jqPath) child(key interface{}) jqPath {
+	return append(append(jqPath{}, p...), key)
+}
+
+// jqReads finds the parts of its input that a jq program may read. It works
+// on the parsed program without running it, so it can't always tell exactly
+// which parts are used. When it can't, it assumes the program reads the
+// whole of the value in question, so the result is never too narrow.
+//
+// Values are tracked as paths into the input for as long as the program only
+// selects object keys and array indices using constants. Once a value is used
+// in any other way, like being compared, iterated, or passed to a function, it
+// is read in its entirety. Values the program computes are not tracked, since
+// they can only be made from parts of the input that have been read.
+type jqReads struct {
+	paths []jqPath
+}
+
+func (jr *jqReads) read(ps []jqPath) {
+	jr.paths = append(jr.paths, ps...)
+}
+
+func (jr *jqReads) query(q *gojq.Query, in []jqPath) []jqPath {
+	if q == nil {
+		return nil
+	}
+
+	if len(q.FuncDefs) > 0 || len(q.Imports) > 0 {
+		// Functions defined by the program may be called with any input.
+		jr.read(in)
+		return nil
+	}
+
+	switch {
+	case q.Term != nil:
+		return jr.term(q.Term, in)
+	case q.Func != "":
+		return jr.fn(&gojq.Func{Name: q.Func}, in)
+	case q.Left == nil || q.Right == nil:
+		return nil
+	}
+
+	switch q.Op {
+	case gojq.OpPipe:
+		return jr.query(q.Right, jr.query(q.Left, in))
+	case gojq.OpComma, gojq.OpAlt:
+		// The outputs of the alternative operator are outputs of one side or
+		// the other, unchanged. Only their truthiness is checked, and that is
+		// known once the path to them has been walked.
+		return append(jr.query(q.Left, in), jr.query(q.Right, in)...)
+	case gojq.OpAssign, gojq.OpModify, gojq.OpUpdateAdd, gojq.OpUpdateSub, gojq.OpUpdateMul, gojq.OpUpdateDiv, gojq.OpUpdateMod, gojq.OpUpdateAlt:
+		// Assignments output a modified copy of their input.
+		jr.read(in)
+		jr.read(jr.query(q.Right, in))
+		return nil
+	default:
+		jr.read(jr.query(q.Left, in))
+		jr.read(jr.query(q.Right, in))
+		return nil
+	}
+}
+
+func (jr *jqReads) term(t *gojq.Term, in []jqPath) []jqPath {
+	var out []jqPath
+
+	switch t.Type {
+	case gojq.TermTypeIdentity:
+		out = in
+	case gojq.TermTypeIndex:
+		out = jr.index(t.Index, in, in)
+	case gojq.TermTypeFunc:
+		out = jr.fn(t.Func, in)
+	case gojq.TermTypeObject:
+		for _, kv := range t.Object.KeyVals {
+			jr.objectKeyVal(kv, in)
+		}
+	case gojq.TermTypeArray:
+		jr.read(jr.query(t.Array.Query, in))
+	case gojq.TermTypeUnary:
+		jr.read(jr.term(t.Unary.Term, in))
+	case gojq.TermTypeFormat:
+		if t.Str != nil {
+			jr.str(t.Str, in)
+		} else {
+			jr.read(in)
+		}
+	case gojq.TermTypeString:
+		jr.str(t.Str, in)
+	case gojq.TermTypeIf:
+		jr.read(jr.query(t.If.Cond, in))
+		out = jr.query(t.If.Then, in)
+
+		for _, elif := range t.If.Elif {
+			jr.read(jr.query(elif.Cond, in))
+			out = append(out, jr.query(elif.Then, in)...)
+		}
+
+		if t.If.Else != nil {
+			out = append(out, jr.query(t.If.Else, in)...)
+		} else {
+			out = append(out, in...)
+		}
+	case gojq.TermTypeTry:
+		out = jr.query(t.Try.Body, in)
+
+		// The input to the catch clause is the error, not part of our input.
+		jr.read(jr.query(t.Try.Catch, nil))
+	case gojq.TermTypeLabel:
+		out = jr.query(t.Label.Body, in)
+	case gojq.TermTypeQuery:
+		out = jr.query(t.Query, in)
+	case gojq.TermTypeNull, gojq.TermTypeTrue, gojq.TermTypeFalse, gojq.TermTypeNumber, gojq.TermTypeBreak:
+	default:
+		// Recursion, reductions, and anything else we don't know about.
+		jr.read(in)
+	}
+
+	for _, s := range t.SuffixList {
+		switch {
+		case s.Index != nil:
+			out = jr.index(s.Index, out, in)
+		case s.Iter:
+			jr.read(out)
+			out = nil
+		case s.Bind != nil:
+			// The body of a binding receives the same input as the term.
+			jr.read(out)
+			out = jr.query(s.Bind.Body, in)
+		}
+	}
+
+	return out
+}
+
+func (jr *jqReads) index(x *gojq.Index, cur, in []jqPath) []jqPath {
+	var key interface{}
+	switch {
+	case x.Name != "":
+		key = x.Name
+	case x.Str != nil && x.Str.Queries == nil:
+		key = x.Str.Str
+	case x.Start != nil && !x.IsSlice:
+		key = jqConstantKey(x.Start)
+	}
+
+	if key == nil {
+		// Any part of the value could be selected.
+		if x.Str != nil {
+			jr.str(x.Str, in)
+		}
+		jr.read(jr.query(x.Start, in))
+		jr.read(jr.query(x.End, in))
+		jr.read(cur)
+		return nil
+	}
+
+	out := make([]jqPath, len(cur))
+	for i, p := range cur {
+		out[i] = p.child(key)
+	}
+
+	return out
+}
+
+func (jr *jqReads) objectKeyVal(kv *gojq.ObjectKeyVal, in []jqPath) {
+	switch {
+	case strings.HasPrefix(kv.KeyOnly, "$"):
+	case kv.KeyOnly != "":
+		jr.read(jr.index(&gojq.Index{Name: kv.KeyOnly}, in, in))
+	case kv.KeyOnlyString != nil:
+		jr.read(jr.index(&gojq.Index{Str: kv.KeyOnlyString}, in, in))
+	}
+
+	if kv.KeyString != nil {
+		jr.str(kv.KeyString, in)
+	}
+	jr.read(jr.query(kv.KeyQuery, in))
+
+	if kv.Val != nil {
+		vin := in
+		for _, q := range kv.Val.Queries {
+			vin = jr.query(q, vin)
+		}
+
+		jr.read(vin)
+	}
+}
+
+func (jr *jqReads) str(s *gojq.String, in []jqPath) {
+	for _, q := range s.Queries {
+		jr.read(jr.query(q, in))
+	}
+}
+
+func (jr *jqReads) fn(f *gojq.Func, in []jqPath) []jqPath {
+	switch {
+	case strings.HasPrefix(f.Name, "$"), f.Name == "empty":
+		// Variables are computed values, and empty produces nothing.
+		return nil
+	case f.Name == "select" && len(f.Args) == 1:
+		jr.read(jr.query(f.Args[0], in))
+		return in
+	}
+
+	// Any other function may use its whole input. Its arguments are applied
+	// to the same input or to parts of it, so they don't read anything else.
+	jr.read(in)
+	return nil
+}
+
+// jqConstantKey returns the string or non-negative integer a query consists
+// of, or nil if it is anything else.
+func jqConstantKey(q *gojq.Query) interface{} {
+	if q.Term == nil || len(q.Term.SuffixList) > 0 || len(q.FuncDefs) > 0 {
+		return nil
+	}
+
+	switch q.Term.Type {
+	case gojq.TermTypeString:
+		if q.Term.Str.Queries == nil {
+			return q.Term.Str.Str
+		}
+	case gojq.TermTypeNumber:
+		if i, err := strconv.Atoi(q.Term.Number); err == nil && i >= 0 {
+			return i
+		}
+	}
+
+	return nil
+}
+
+// jqInput evaluates the parts of the tree at the given paths, and as much of
+// the tree above them as needed to reach them. Anything else in the tree is
+// left as it is, since the program will never look at it.
+func (e *Evaluator) jqInput(ctx context.Context, v interface{}, paths []jqPath, r *model.Result) (interface{}, error) {
+	for _, p := range paths {
+		if len(p) == 0 {
+			nr, err := e.evaluate(ctx, v, -1)
+			if err != nil {
+				return nil, err
+			}
+
+			r.Extends(nr)
+			return nr.Value, nil
+		}
+	}
+
+	nr, err := e.evaluate(ctx, v, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Extends(nr)
+	if !nr.Complete() {
+		return nr.Value, nil
+	}
+
+	children := make(map[interface{}][]jqPath)
+	for _, p := range paths {
+		children[p[0]] = append(children[p[0]], p[1:])
+	}
+
+	switch vt := nr.Value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(vt))
+		for k, v := range vt {
+			m[k] = v
+		}
+
+		for key, ps := range children {
+			k, ok := key.(string)
+			if !ok {
+				continue
+			}
+
+			cv, found := vt[k]
+			if !found {
+				continue
+			}
+
+			nv, err := e.jqInput(e.withTracePath(ctx, k), cv, ps, r)
+			if err != nil {
+				return nil, &PathEvaluationError{Path: k, Cause: err}
+			}
+
+			m[k] = nv
+		}
+
+		return m, nil
+	case []interface{}:
+		l := make([]interface{}, len(vt))
+		copy(l, vt)
+
+		for key, ps := range children {
+			i, ok := key.(int)
+			if !ok || i >= len(vt) {
+				continue
+			}
+
+			nv, err := e.jqInput(e.withTracePath(ctx, strconv.Itoa(i)), vt[i], ps, r)
+			if err != nil {
+				return nil, &PathEvaluationError{Path: strconv.Itoa(i), Cause: err}
+			}
+
+			l[i] = nv
+		}
+
+		return l, nil
+	default:
+		return nr.Value, nil
+	}
+}
@@ -16,18 +16,21 @@ func WithDataTypeResolver(resolver resolve.DataTypeResolver) Option {
 func WithSecretTypeResolver(resolver resolve.SecretTypeResolver) Option {
 	return func(e *Evaluator) {
 		e.secretTypeResolver = resolver
+		e.resolutionScope = newResolutionScope()
 	}
 }
 
 func WithConnectionTypeResolver(resolver resolve.ConnectionTypeResolver) Option {
 	return func(e *Evaluator) {
 		e.connectionTypeResolver = resolver
+		e.resolutionScope = newResolutionScope()
 	}
 }
 
 func WithOutputTypeResolver(resolver resolve.OutputTypeResolver) Option {
 	return func(e *Evaluator) {
 		e.outputTypeResolver = resolver
+		e.resolutionScope = newResolutionScope()
 	}
 }
 
@@ -49,6 +52,63 @@ func WithInvocationResolver(resolver resolve.InvocationResolver) Option {
 	}
 }
 
+// WithBindings makes the given values available to Binding types by name, in
+// addition to any values already bound.
+func WithBindings(bindings map[string]interface{}) Option {
+	return func(e *Evaluator) {
+		nb := make(map[string]interface{}, len(e.bindings)+len(bindings))
+		for name, value := range e.bindings {
+			nb[name] = value
+		}
+		for name, value := range bindings {
+			nb[name] = value
+		}
+
+		e.bindings = nb
+	}
+}
+
+// WithInert causes the evaluator to resolve no references and invoke no
+// functions, replacing any type resolvers already configured. Evaluating a tree
+// inertly reports every reference it contains as unresolvable, including those
+// in arguments a function would choose not to evaluate, so it can be used to
+// find out what a tree depends on.
+func WithInert(inert bool) Option {
+	return func(e *Evaluator) {
+		e.inert = inert
+		if inert {
+			e.dataTypeResolver = resolve.NoOpDataTypeResolver
+			e.secretTypeResolver = resolve.NoOpSecretTypeResolver
+			e.connectionTypeResolver = resolve.NoOpConnectionTypeResolver
+			e.outputTypeResolver = resolve.NoOpOutputTypeResolver
+			e.parameterTypeResolver = resolve.NoOpParameterTypeResolver
+			e.answerTypeResolver = resolve.NoOpAnswerTypeResolver
+			e.resolutionScope = newResolutionScope()
+		}
+	}
+}
+
+// WithMaxConcurrentResolutions sets the number of secrets, connections, and
+// outputs the evaluator looks up at the same time. Before evaluating a tree,
+// the evaluator resolves each distinct reference the tree is certain to need,
+// so that slow resolvers are waited on in parallel. References in the
+// arguments of a function invocation are only resolved if the function
+// evaluates them. A value of 1 or less disables this. Either way, each
+// reference is resolved at most once per evaluation.
+func WithMaxConcurrentResolutions(n int) Option {
+	return func(e *Evaluator) {
+		e.maxConcurrentResolutions = n
+	}
+}
+
+// WithTrace records the evaluation of each $type, $encoding, and $fn node, and
+// of each query, in the given trace. Sensitive values are redacted.
+func WithTrace(trace *model.Trace) Option {
+	return func(e *Evaluator) {
+		e.trace = trace
+	}
+}
+
 func WithLanguage(lang Language) Option {
 	return func(e *Evaluator) {
 		e.lang = lang
@@ -0,0 +1,186 @@
+package evaluate
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/itchyny/gojq"
+	"github.com/puppetlabs/relay-core/pkg/expr/model"
+	"github.com/puppetlabs/relay-core/pkg/expr/parse"
+	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// evaluateJQQuery runs a jq program against the tree. jq has no way to defer
+// evaluation of a value until the program accesses it, so the parts of the
+// tree the program could read are found ahead of time and only those are
+// evaluated. Any references in them that can't be resolved are left in place
+// and reported with the result.
+//
+// A program that produces no output evaluates to nil, a program that produces
+// exactly one output evaluates to that output, and a program that produces
+// more than one output evaluates to an array of the outputs.
+func (e *Evaluator) evaluateJQQuery(ctx context.Context, tree parse.Tree, query string) (*model.Result, error) {
+	q, err := gojq.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := gojq.Compile(q)
+	if err != nil {
+		return nil, err
+	}
+
+	reads := &jqReads{}
+	reads.read(reads.query(q, []jqPath{{}}))
+
+	r := &model.Result{}
+
+	in, err := e.jqInput(ctx, tree, reads.paths, r)
+	if err != nil {
+		return nil, err
+	}
+
+	var outs []interface{}
+
+	iter := code.RunWithContext(ctx, normalizeQueryValue(in))
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			break
+		} else if err, ok := v.(error); ok {
+			if !r.Complete() {
+				// The program probably tried to use a value that we couldn't
+				// resolve. This is reported the same way as a path traversal
+				// through an unresolvable value.
+				return e.resultMapper.MapResult(ctx, &model.Result{Unresolvable: r.Unresolvable, Sensitive: r.Sensitive})
+			}
+
+			return nil, err
+		}
+
+		outs = append(outs, normalizeQueryValue(v))
+	}
+
+	er := &model.Result{Unresolvable: r.Unresolvable, Sensitive: r.Sensitive}
+	switch len(outs) {
+	case 0:
+	case 1:
+		er.Value = outs[0]
+	default:
+		er.Value = outs
+	}
+
+	return e.resultMapper.MapResult(ctx, er)
+}
+
+// evaluateCELQuery runs a CEL expression against the tree. Each top-level key
+// of the tree is available to the expression as a variable. Variables are
+// evaluated only when the expression first refers to them, so unresolvable
+// references are reported only for the variables actually used.
+func (e *Evaluator) evaluateCELQuery(ctx context.Context, tree parse.Tree, query string) (*model.Result, error) {
+	top, _ := tree.(map[string]interface{})
+
+	var ds []*exprpb.Decl
+	for name := range top {
+		ds = append(ds, decls.NewVar(name, decls.Dyn))
+	}
+
+	env, err := cel.NewEnv(cel.Declarations(ds...))
+	if err != nil {
+		return nil, err
+	}
+
+	ast, iss := env.Compile(query)
+	if iss != nil && iss.Err() != nil {
+		return nil, iss.Err()
+	}
+
+	prg, err := env.Program(ast)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &model.Result{}
+
+	var verr error
+	vars := make(map[string]interface{}, len(top))
+	for name, value := range top {
+		value := value
+
+		vars[name] = func() interface{} {
+			nr, err := e.evaluate(ctx, value, -1)
+			if err != nil {
+				verr = err
+				return nil
+			}
+
+			r.Extends(nr)
+			return normalizeQueryValue(nr.Value)
+		}
+	}
+
+	out, _, err := prg.Eval(vars)
+	if verr != nil {
+		return nil, verr
+	} else if err != nil {
+		if !r.Complete() {
+			return e.resultMapper.MapResult(ctx, r)
+		}
+
+		return nil, err
+	}
+
+	v, err := celValueToNative(out)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Value = v
+
+	return e.resultMapper.MapResult(ctx, r)
+}
+
+// normalizeQueryValue copies a value into the set of types understood by the
+// jq and CEL interpreters.
+func normalizeQueryValue(v interface{}) interface{} {
+	switch vt := v.(type) {
+	case []interface{}:
+		l := make([]interface{}, len(vt))
+		for i, v := range vt {
+			l[i] = normalizeQueryValue(v)
+		}
+
+		return l
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(vt))
+		for k, v := range vt {
+			m[k] = normalizeQueryValue(v)
+		}
+
+		return m
+	case time.Time:
+		return vt.Format(time.RFC3339Nano)
+	case *big.Int:
+		f, _ := new(big.Float).SetInt(vt).Float64()
+		return f
+	default:
+		return v
+	}
+}
+
+var celNativeType = reflect.TypeOf(&structpb.Value{})
+
+func celValueToNative(v ref.Val) (interface{}, error) {
+	nv, err := v.ConvertToNative(celNativeType)
+	if err != nil {
+		return nil, err
+	}
+
+	return nv.(*structpb.Value).AsInterface(), nil
+}
@@ -0,0 +1,255 @@
+package evaluate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/puppetlabs/relay-core/pkg/expr/model"
+	"github.com/puppetlabs/relay-core/pkg/expr/resolve"
+)
+
+// DefaultMaxConcurrentResolutions is the number of references an evaluator
+// resolves at the same time unless configured otherwise.
+const DefaultMaxConcurrentResolutions = 8
+
+// reference identifies a value provided by a type resolver that may be slow
+// to look up, like a secret.
+type reference struct {
+	ty string
+
+	// scope is the type of a connection or the step of an output.
+	scope   string
+	name    string
+	version int
+}
+
+// typeReference returns the reference made by a $type node, if it is one that
+// is resolved ahead of time. A node with invalid fields is left for the
+// evaluator to report.
+func typeReference(tm map[string]interface{}) (reference, bool) {
+	switch tm["$type"] {
+	case "Secret":
+		name, ok := tm["name"].(string)
+		if !ok {
+			return reference{}, false
+		}
+
+		ref := reference{ty: "Secret", name: name}
+		if v, found := tm["version"]; found {
+			if ref.version, ok = secretVersion(v); !ok {
+				return reference{}, false
+			}
+		}
+
+		return ref, true
+	case "Connection":
+		connectionType, ok := tm["type"].(string)
+		if !ok {
+			return reference{}, false
+		}
+
+		name, ok := tm["name"].(string)
+		if !ok {
+			return reference{}, false
+		}
+
+		return reference{ty: "Connection", scope: connectionType, name: name}, true
+	case "Output":
+		from, ok := tm["from"].(string)
+		if !ok {
+			if from, ok = tm["taskName"].(string); !ok {
+				return reference{}, false
+			}
+		}
+
+		name, ok := tm["name"].(string)
+		if !ok {
+			return reference{}, false
+		}
+
+		return reference{ty: "Output", scope: from, name: name}, true
+	default:
+		return reference{}, false
+	}
+}
+
+type resolution struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// resolutions holds the references resolved during a single evaluation, so
+// that each is only resolved once no matter how many times it is used.
+type resolutions struct {
+	mut sync.Mutex
+	m   map[reference]*resolution
+}
+
+func (rs *resolutions) resolve(ctx context.Context, ref reference, f func(ctx context.Context, ref reference) (interface{}, error)) (interface{}, error) {
+	rs.mut.Lock()
+	r, found := rs.m[ref]
+	if !found {
+		r = &resolution{done: make(chan struct{})}
+		rs.m[ref] = r
+	}
+	rs.mut.Unlock()
+
+	if found {
+		<-r.done
+		return r.value, r.err
+	}
+
+	// Other callers wait for the resolution to finish, so it must be marked
+	// done even if the resolver panics.
+	defer close(r.done)
+
+	r.value, r.err = f(ctx, ref)
+	return r.value, r.err
+}
+
+var lastResolutionScope uint64
+
+// newResolutionScope returns an identifier for a set of type resolvers.
+// Evaluators only share resolved references when they have the same
+// identifier.
+func newResolutionScope() uint64 {
+	return atomic.AddUint64(&lastResolutionScope, 1)
+}
+
+type resolutionsContextKey struct {
+	scope uint64
+}
+
+// withResolutions returns a context that remembers the references resolved
+// by this evaluator and any copy of it that uses the same type resolvers.
+func (e *Evaluator) withResolutions(ctx context.Context) context.Context {
+	key := resolutionsContextKey{scope: e.resolutionScope}
+	if _, found := ctx.Value(key).(*resolutions); found {
+		return ctx
+	}
+
+	return context.WithValue(ctx, key, &resolutions{m: make(map[reference]*resolution)})
+}
+
+// resolved returns the value of the given reference, resolving it only if it
+// hasn't already been resolved using the given context.
+func (e *Evaluator) resolved(ctx context.Context, ref reference) (interface{}, error) {
+	rs, found := ctx.Value(resolutionsContextKey{scope: e.resolutionScope}).(*resolutions)
+	if !found {
+		return e.resolveReference(ctx, ref)
+	}
+
+	return rs.resolve(ctx, ref, e.resolveReference)
+}
+
+func (e *Evaluator) resolveReference(ctx context.Context, ref reference) (interface{}, error) {
+	switch ref.ty {
+	case "Secret":
+		if ref.version == 0 {
+			return e.secretTypeResolver.ResolveSecret(ctx, ref.name)
+		}
+
+		vr, ok := e.secretTypeResolver.(resolve.SecretVersionTypeResolver)
+		if !ok {
+			// Without a way to look up the requested version, we can't
+			// provide a value at all.
+			return nil, &model.SecretNotFoundError{Name: ref.name}
+		}
+
+		return vr.ResolveSecretVersion(ctx, ref.name, ref.version)
+	case "Connection":
+		return e.connectionTypeResolver.ResolveConnection(ctx, ref.scope, ref.name)
+	case "Output":
+		return e.outputTypeResolver.ResolveOutput(ctx, ref.scope, ref.name)
+	default:
+		return nil, fmt.Errorf("evaluate: unknown reference type %q", ref.ty)
+	}
+}
+
+// references finds the references in the given tree that evaluating it to the
+// given depth is certain to resolve. References in the arguments of a function
+// invocation are not included because the function may not evaluate them, like
+// the branch of a conditional that isn't taken.
+func references(v interface{}, depth int, refs map[reference]struct{}) {
+	if depth == 0 {
+		return
+	}
+
+	switch vt := v.(type) {
+	case []interface{}:
+		if depth == 1 {
+			return
+		}
+
+		for _, v := range vt {
+			references(v, depth-1, refs)
+		}
+	case map[string]interface{}:
+		if _, ok := vt["$type"]; ok {
+			if ref, ok := typeReference(vt); ok {
+				refs[ref] = struct{}{}
+			}
+
+			return
+		} else if _, ok := vt["$encoding"]; ok {
+			if _, ok := vt["$encoding"].(string); ok {
+				references(vt["data"], -1, refs)
+			}
+
+			return
+		} else if len(vt) == 1 {
+			var first string
+			for first = range vt {
+			}
+
+			if strings.HasPrefix(first, "$fn.") {
+				return
+			}
+		} else if depth == 1 {
+			return
+		}
+
+		for _, v := range vt {
+			references(v, depth-1, refs)
+		}
+	}
+}
+
+// prefetch concurrently resolves the references that evaluating the given
+// tree is certain to need, at most maxConcurrentResolutions at a time. The
+// results are remembered by the context, so the evaluator can substitute them
+// as it walks the tree without waiting for each in turn.
+func (e *Evaluator) prefetch(ctx context.Context, tree interface{}, depth int) {
+	if e.maxConcurrentResolutions <= 1 || e.inert {
+		return
+	}
+
+	refs := make(map[reference]struct{})
+	references(tree, depth, refs)
+	if len(refs) <= 1 {
+		return
+	}
+
+	sem := make(chan struct{}, e.maxConcurrentResolutions)
+
+	var wg sync.WaitGroup
+	for ref := range refs {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(ref reference) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			// Errors are remembered and reported if and when the evaluator
+			// gets to the reference.
+			_, _ = e.resolved(ctx, ref)
+		}(ref)
+	}
+
+	wg.Wait()
+}
@@ -3,6 +3,7 @@ package evaluate
 import (
 	"context"
 
+	"github.com/puppetlabs/relay-core/pkg/expr/fn"
 	"github.com/puppetlabs/relay-core/pkg/expr/model"
 	"github.com/puppetlabs/relay-core/pkg/expr/parse"
 )
@@ -10,26 +11,36 @@ import (
 type ScopedEvaluator struct {
 	parent *Evaluator
 	tree   parse.Tree
+
+	// path is the location of the tree relative to the node being evaluated
+	// when the evaluator was created, used for tracing.
+	path []string
 }
 
+var _ fn.Bindable = &ScopedEvaluator{}
+
 func (se *ScopedEvaluator) Evaluate(ctx context.Context, depth int) (*model.Result, error) {
-	return se.parent.Evaluate(ctx, se.tree, depth)
+	return se.parent.Evaluate(se.parent.withTracePath(ctx, se.path...), se.tree, depth)
 }
 
 func (se *ScopedEvaluator) EvaluateAll(ctx context.Context) (*model.Result, error) {
-	return se.parent.EvaluateAll(ctx, se.tree)
+	return se.parent.EvaluateAll(se.parent.withTracePath(ctx, se.path...), se.tree)
 }
 
 func (se *ScopedEvaluator) EvaluateInto(ctx context.Context, target interface{}) (model.Unresolvable, error) {
-	return se.parent.EvaluateInto(ctx, se.tree, target)
+	return se.parent.EvaluateInto(se.parent.withTracePath(ctx, se.path...), se.tree, target)
 }
 
 func (se *ScopedEvaluator) EvaluateQuery(ctx context.Context, query string) (*model.Result, error) {
-	return se.parent.EvaluateQuery(ctx, se.tree, query)
+	return se.parent.EvaluateQuery(se.parent.withTracePath(ctx, se.path...), se.tree, query)
+}
+
+func (se *ScopedEvaluator) Bind(bindings map[string]interface{}) model.Evaluable {
+	return se.Copy(WithBindings(bindings))
 }
 
 func (se *ScopedEvaluator) Copy(opts ...Option) *ScopedEvaluator {
-	return &ScopedEvaluator{parent: se.parent.Copy(opts...), tree: se.tree}
+	return &ScopedEvaluator{parent: se.parent.Copy(opts...), tree: se.tree, path: se.path}
 }
 
 func NewScopedEvaluator(obj parse.Tree, opts ...Option) *ScopedEvaluator {
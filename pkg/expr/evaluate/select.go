@@ -50,8 +50,10 @@ func variableVisitor(e *Evaluator, r *model.Result) jsonpath.VariableVisitor {
 				nr, err := e.evaluate(ctx, pv.Value, 1)
 				if err != nil {
 					return err
-				} else if !nr.Complete() {
-					r.Extends(nr)
+				}
+
+				r.Extends(nr)
+				if !nr.Complete() {
 					return nil
 				}
 
@@ -0,0 +1,101 @@
+package evaluate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/puppetlabs/relay-core/pkg/expr/model"
+)
+
+type traceContextKey struct{}
+
+// traceContext is the location in the trace of the node currently being
+// evaluated. It is carried by the context so that nodes evaluated by function
+// invocations are recorded under the invocation.
+type traceContext struct {
+	parent *model.TraceNode
+	path   []string
+}
+
+func traceContextFrom(ctx context.Context) *traceContext {
+	tc, ok := ctx.Value(traceContextKey{}).(*traceContext)
+	if !ok {
+		return &traceContext{}
+	}
+
+	return tc
+}
+
+// withTracePath returns a context for evaluating the child of the current node
+// at the given path. It returns the context unmodified if tracing is disabled.
+func (e *Evaluator) withTracePath(ctx context.Context, path ...string) context.Context {
+	if e.trace == nil || len(path) == 0 {
+		return ctx
+	}
+
+	tc := traceContextFrom(ctx)
+	return context.WithValue(ctx, traceContextKey{}, &traceContext{
+		parent: tc.parent,
+		path:   append(append([]string{}, tc.path...), path...),
+	})
+}
+
+// traced records the evaluation performed by the given function in the trace,
+// if tracing is enabled.
+func (e *Evaluator) traced(ctx context.Context, kind model.TraceNodeKind, name, resolver string, f func(ctx context.Context) (*model.Result, error)) (*model.Result, error) {
+	if e.trace == nil {
+		return f(ctx)
+	}
+
+	tc := traceContextFrom(ctx)
+	node := &model.TraceNode{
+		Path:     strings.Join(tc.path, "."),
+		Kind:     kind,
+		Name:     name,
+		Resolver: resolver,
+	}
+
+	start := time.Now()
+	r, err := f(context.WithValue(ctx, traceContextKey{}, &traceContext{parent: node, path: tc.path}))
+	node.Elapsed = time.Since(start)
+
+	if err != nil {
+		node.Error = err
+	} else {
+		node.Value = r.Value
+		node.Unresolvable = r.Unresolvable
+		node.Sensitive = r.Sensitive
+
+		if r.Sensitive {
+			node.Value = model.RedactedValue
+		}
+	}
+
+	e.trace.Add(tc.parent, node)
+	return r, err
+}
+
+// typeResolverName describes the resolver used to evaluate the given $type.
+func (e *Evaluator) typeResolverName(ty interface{}) string {
+	var resolver interface{}
+	switch ty {
+	case "Data":
+		resolver = e.dataTypeResolver
+	case "Secret":
+		resolver = e.secretTypeResolver
+	case "Connection":
+		resolver = e.connectionTypeResolver
+	case "Output":
+		resolver = e.outputTypeResolver
+	case "Parameter":
+		resolver = e.parameterTypeResolver
+	case "Answer":
+		resolver = e.answerTypeResolver
+	default:
+		return ""
+	}
+
+	return fmt.Sprintf("%T", resolver)
+}
@@ -14,6 +14,9 @@ type Descriptor interface {
 	// to the function. Enforcing name and length constraints is up to the author
 	// of the function.
 	KeywordInvoker(args map[string]model.Evaluable) (Invoker, error)
+	// Signature returns the arguments the function accepts and the type of
+	// value it returns, or nil if the function cannot be checked statically.
+	Signature() *Signature
 }
 
 // DescriptorFuncs is an adapter that takes anonymous functions that handle
@@ -23,6 +26,7 @@ type DescriptorFuncs struct {
 	DescriptionFunc       func() string
 	PositionalInvokerFunc func(args []model.Evaluable) (Invoker, error)
 	KeywordInvokerFunc    func(args map[string]model.Evaluable) (Invoker, error)
+	SignatureFunc         func() *Signature
 }
 
 var _ Descriptor = DescriptorFuncs{}
@@ -51,6 +55,14 @@ func (df DescriptorFuncs) KeywordInvoker(args map[string]model.Evaluable) (Invok
 	return df.KeywordInvokerFunc(args)
 }
 
+func (df DescriptorFuncs) Signature() *Signature {
+	if df.SignatureFunc == nil {
+		return nil
+	}
+
+	return df.SignatureFunc()
+}
+
 type Map interface {
 	Descriptor(name string) (Descriptor, error)
 }
@@ -39,7 +39,8 @@ func EvaluatedPositionalInvoker(args []model.Evaluable, fn func(ctx context.Cont
 			return nil, err
 		}
 
-		return &model.Result{Value: rv}, nil
+		// Anything computed from a sensitive argument is sensitive.
+		return &model.Result{Value: rv, Sensitive: r.Sensitive}, nil
 	})
 }
 
@@ -60,6 +61,6 @@ func EvaluatedKeywordInvoker(args map[string]model.Evaluable, fn func(ctx contex
 			return nil, err
 		}
 
-		return &model.Result{Value: rv}, nil
+		return &model.Result{Value: rv, Sensitive: r.Sensitive}, nil
 	})
 }
@@ -0,0 +1,43 @@
+package fn
+
+import (
+	"context"
+
+	"github.com/puppetlabs/relay-core/pkg/expr/model"
+)
+
+const (
+	// ItemBinding is the name bound to the current element when a lambda is
+	// applied to a collection.
+	ItemBinding = "$item"
+
+	// IndexBinding is the name bound to the index of the current element when
+	// a lambda is applied to an array, or to its key when a lambda is applied
+	// to an object.
+	IndexBinding = "$index"
+)
+
+// Bindable is an evaluable that can be re-evaluated with additional named
+// values in scope. Arguments passed to functions by the evaluator are
+// bindable, so functions can treat them as lambdas.
+type Bindable interface {
+	model.Evaluable
+
+	// Bind returns a copy of this evaluable with the given names bound to the
+	// given values. Existing bindings with the same names are shadowed.
+	Bind(bindings map[string]interface{}) model.Evaluable
+}
+
+// ApplyLambda fully evaluates a lambda with ItemBinding and IndexBinding bound
+// to the given values. If the lambda is not bindable, it is evaluated as is,
+// which is the same as a lambda that doesn't refer to its bindings.
+func ApplyLambda(ctx context.Context, lambda model.Evaluable, index, item interface{}) (*model.Result, error) {
+	if b, ok := lambda.(Bindable); ok {
+		lambda = b.Bind(map[string]interface{}{
+			ItemBinding:  item,
+			IndexBinding: index,
+		})
+	}
+
+	return lambda.EvaluateAll(ctx)
+}
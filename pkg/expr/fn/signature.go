@@ -0,0 +1,118 @@
+package fn
+
+import "strings"
+
+// Type is a set of the kinds of values an argument may take or a function may
+// return. It is used to check invocations statically, before any arguments
+// are evaluated.
+type Type uint
+
+const (
+	TypeNull Type = 1 << iota
+	TypeBoolean
+	TypeNumber
+	TypeString
+	TypeTime
+	TypeArray
+	TypeObject
+
+	// TypeScalar is any type that can be converted to a string.
+	TypeScalar = TypeNull | TypeBoolean | TypeNumber | TypeString | TypeTime
+
+	// TypeAny is the set of all types.
+	TypeAny = TypeScalar | TypeArray | TypeObject
+)
+
+var typeNames = []struct {
+	Type Type
+	Name string
+}{
+	{TypeNull, "null"},
+	{TypeBoolean, "boolean"},
+	{TypeNumber, "number"},
+	{TypeString, "string"},
+	{TypeTime, "time"},
+	{TypeArray, "array"},
+	{TypeObject, "object"},
+}
+
+// Accepts returns true if a value of the given type could be of this type.
+func (t Type) Accepts(other Type) bool {
+	return t&other != 0
+}
+
+func (t Type) String() string {
+	switch t {
+	case TypeAny:
+		return "any"
+	case 0:
+		return "none"
+	}
+
+	var names []string
+	for _, tn := range typeNames {
+		if t&tn.Type != 0 {
+			names = append(names, tn.Name)
+		}
+	}
+
+	return strings.Join(names, " or ")
+}
+
+// Param describes a single argument to a function.
+type Param struct {
+	Name string
+	Type Type
+
+	// Lambda indicates that the argument is evaluated once for each element
+	// of a collection with ItemBinding and IndexBinding in scope.
+	Lambda bool
+}
+
+// Arguments describes the arguments a function accepts in one invocation
+// style.
+type Arguments struct {
+	// Params are the arguments to the function, in positional order.
+	Params []Param
+
+	// Required is the number of leading params that must be specified.
+	Required int
+
+	// Variadic indicates that the last param may be repeated any number of
+	// times. It only applies to positional invocations.
+	Variadic bool
+}
+
+// ParamAt returns the param for the positional argument at the given index.
+func (a *Arguments) ParamAt(i int) (Param, bool) {
+	if i < len(a.Params) {
+		return a.Params[i], true
+	} else if a.Variadic && len(a.Params) > 0 {
+		return a.Params[len(a.Params)-1], true
+	}
+
+	return Param{}, false
+}
+
+// Param returns the param with the given name.
+func (a *Arguments) Param(name string) (Param, bool) {
+	for _, p := range a.Params {
+		if p.Name == name {
+			return p, true
+		}
+	}
+
+	return Param{}, false
+}
+
+// Signature describes the arguments a function accepts and the type of value
+// it returns.
+type Signature struct {
+	// Positional and Keyword describe the arguments accepted by each
+	// invocation style. If either is nil, the function cannot be invoked in
+	// that style.
+	Positional *Arguments
+	Keyword    *Arguments
+
+	Returns Type
+}
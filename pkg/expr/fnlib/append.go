@@ -35,4 +35,17 @@ var appendDescriptor = fn.DescriptorFuncs{
 		})
 		return fn, nil
 	},
+	SignatureFunc: func() *fn.Signature {
+		return &fn.Signature{
+			Positional: &fn.Arguments{
+				Params: []fn.Param{
+					{Name: "array", Type: fn.TypeArray},
+					{Name: "value", Type: fn.TypeAny},
+				},
+				Required: 2,
+				Variadic: true,
+			},
+			Returns: fn.TypeArray,
+		}
+	},
 }
@@ -0,0 +1,152 @@
+package fnlib
+
+import (
+	"context"
+	"math"
+	"reflect"
+
+	"github.com/puppetlabs/relay-core/pkg/expr/fn"
+	"github.com/puppetlabs/relay-core/pkg/expr/model"
+)
+
+// argError wraps an error caused by the argument at the given position in the
+// error type appropriate to the invocation style.
+func argError(names []string, keyword bool, i int, cause error) error {
+	if keyword {
+		return &fn.KeywordArgError{Arg: names[i], Cause: cause}
+	}
+
+	return &fn.PositionalArgError{Arg: i + 1, Cause: cause}
+}
+
+// evaluatedArgs holds the evaluated arguments to a function that accepts the
+// same arguments either positionally or by keyword. Arguments are addressed by
+// their positional index so that the function body can be shared between both
+// invocation styles.
+type evaluatedArgs struct {
+	names   []string
+	values  []interface{}
+	present []bool
+	keyword bool
+}
+
+func newPositionalEvaluatedArgs(names []string, values []interface{}) *evaluatedArgs {
+	ea := &evaluatedArgs{
+		names:   names,
+		values:  make([]interface{}, len(names)),
+		present: make([]bool, len(names)),
+	}
+	for i, v := range values {
+		ea.values[i] = v
+		ea.present[i] = true
+	}
+	return ea
+}
+
+func newKeywordEvaluatedArgs(names []string, values map[string]interface{}) *evaluatedArgs {
+	ea := &evaluatedArgs{
+		names:   names,
+		values:  make([]interface{}, len(names)),
+		present: make([]bool, len(names)),
+		keyword: true,
+	}
+	for i, name := range names {
+		ea.values[i], ea.present[i] = values[name]
+	}
+	return ea
+}
+
+func (ea *evaluatedArgs) hasAt(i int) bool {
+	return ea.present[i]
+}
+
+func (ea *evaluatedArgs) errorAt(i int, cause error) error {
+	return argError(ea.names, ea.keyword, i, cause)
+}
+
+func (ea *evaluatedArgs) stringAt(i int) (string, error) {
+	s, err := toString(ea.values[i])
+	if err != nil {
+		return "", ea.errorAt(i, err)
+	}
+
+	return s, nil
+}
+
+func (ea *evaluatedArgs) intAt(i int) (int, error) {
+	switch v := ea.values[i].(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v == math.Trunc(v) {
+			return int(v), nil
+		}
+	}
+
+	return 0, ea.errorAt(i, &fn.UnexpectedTypeError{
+		Wanted: []reflect.Type{
+			reflect.TypeOf(int(0)),
+			reflect.TypeOf(int64(0)),
+			reflect.TypeOf(float64(0)),
+		},
+		Got: reflect.TypeOf(ea.values[i]),
+	})
+}
+
+func (ea *evaluatedArgs) arrayAt(i int) ([]interface{}, error) {
+	a, ok := ea.values[i].([]interface{})
+	if !ok {
+		return nil, ea.errorAt(i, &fn.UnexpectedTypeError{
+			Wanted: []reflect.Type{reflect.TypeOf([]interface{}(nil))},
+			Got:    reflect.TypeOf(ea.values[i]),
+		})
+	}
+
+	return a, nil
+}
+
+// evaluatedDescriptor creates a descriptor for a function with a fixed list of
+// params, the first required of which must be specified. The function may be
+// invoked positionally, in which case the arguments are given in order, or by
+// keyword. All arguments are fully evaluated before the function is called.
+func evaluatedDescriptor(description string, params []fn.Param, required int, returns fn.Type, f func(ctx context.Context, args *evaluatedArgs) (interface{}, error)) fn.Descriptor {
+	names := make([]string, len(params))
+	for i, param := range params {
+		names[i] = param.Name
+	}
+
+	return fn.DescriptorFuncs{
+		DescriptionFunc: func() string { return description },
+		PositionalInvokerFunc: func(args []model.Evaluable) (fn.Invoker, error) {
+			if len(args) < required || len(args) > len(names) {
+				wanted := make([]int, 0, len(names)-required+1)
+				for n := required; n <= len(names); n++ {
+					wanted = append(wanted, n)
+				}
+
+				return nil, &fn.ArityError{Wanted: wanted, Got: len(args)}
+			}
+
+			return fn.EvaluatedPositionalInvoker(args, func(ctx context.Context, args []interface{}) (interface{}, error) {
+				return f(ctx, newPositionalEvaluatedArgs(names, args))
+			}), nil
+		},
+		KeywordInvokerFunc: func(args map[string]model.Evaluable) (fn.Invoker, error) {
+			for _, name := range names[:required] {
+				if _, found := args[name]; !found {
+					return nil, &fn.KeywordArgError{Arg: name, Cause: fn.ErrArgNotFound}
+				}
+			}
+
+			return fn.EvaluatedKeywordInvoker(args, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+				return f(ctx, newKeywordEvaluatedArgs(names, args))
+			}), nil
+		},
+		SignatureFunc: func() *fn.Signature {
+			args := &fn.Arguments{Params: params, Required: required}
+			return &fn.Signature{Positional: args, Keyword: args, Returns: returns}
+		},
+	}
+}
@@ -26,4 +26,15 @@ var coalesceDescriptor = fn.DescriptorFuncs{
 		})
 		return fn, nil
 	},
+	SignatureFunc: func() *fn.Signature {
+		return &fn.Signature{
+			Positional: &fn.Arguments{
+				Params: []fn.Param{
+					{Name: "value", Type: fn.TypeAny},
+				},
+				Variadic: true,
+			},
+			Returns: fn.TypeAny,
+		}
+	},
 }
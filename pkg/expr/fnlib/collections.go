@@ -0,0 +1,478 @@
+package fnlib
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/puppetlabs/relay-core/pkg/expr/fn"
+	"github.com/puppetlabs/relay-core/pkg/expr/model"
+)
+
+// MaxRangeLength is the largest number of elements the range function will
+// produce.
+const MaxRangeLength = 10000
+
+var (
+	ErrRangeStepZero = errors.New("fnlib: range step must not be zero")
+	ErrRangeTooLong  = errors.New("fnlib: range has too many elements")
+)
+
+func (ea *evaluatedArgs) objectAt(i int) (map[string]interface{}, error) {
+	m, ok := ea.values[i].(map[string]interface{})
+	if !ok {
+		return nil, ea.errorAt(i, &fn.UnexpectedTypeError{
+			Wanted: []reflect.Type{reflect.TypeOf(map[string]interface{}(nil))},
+			Got:    reflect.TypeOf(ea.values[i]),
+		})
+	}
+
+	return m, nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// lambdaDescriptor creates a descriptor for a function that takes a collection
+// and a lambda to apply to each of its elements. The collection is evaluated
+// up front, but the lambda is left to the function to evaluate once for each
+// element using fn.ApplyLambda.
+func lambdaDescriptor(description string, f func(ctx context.Context, args *evaluatedArgs, lambda model.Evaluable) (*model.Result, error)) fn.Descriptor {
+	names := []string{"collection", "lambda"}
+
+	invoker := func(collection, lambda model.Evaluable, newArgs func(value interface{}) *evaluatedArgs) fn.Invoker {
+		return fn.InvokerFunc(func(ctx context.Context) (*model.Result, error) {
+			cr, err := collection.EvaluateAll(ctx)
+			if err != nil {
+				return nil, err
+			} else if !cr.Complete() {
+				return cr, nil
+			}
+
+			args := newArgs(cr.Value)
+			switch cr.Value.(type) {
+			case []interface{}, map[string]interface{}:
+			default:
+				return nil, args.errorAt(0, &fn.UnexpectedTypeError{
+					Wanted: []reflect.Type{
+						reflect.TypeOf([]interface{}(nil)),
+						reflect.TypeOf(map[string]interface{}(nil)),
+					},
+					Got: reflect.TypeOf(cr.Value),
+				})
+			}
+
+			r, err := f(ctx, args, lambda)
+			if err != nil {
+				return nil, err
+			}
+
+			// Elements of a sensitive collection are sensitive even if the
+			// lambda only refers to them through bindings.
+			r.Sensitive = r.Sensitive || cr.Sensitive
+			return r, nil
+		})
+	}
+
+	return fn.DescriptorFuncs{
+		DescriptionFunc: func() string { return description },
+		PositionalInvokerFunc: func(args []model.Evaluable) (fn.Invoker, error) {
+			if len(args) != len(names) {
+				return nil, &fn.ArityError{Wanted: []int{len(names)}, Got: len(args)}
+			}
+
+			return invoker(args[0], args[1], func(value interface{}) *evaluatedArgs {
+				return newPositionalEvaluatedArgs(names, []interface{}{value})
+			}), nil
+		},
+		KeywordInvokerFunc: func(args map[string]model.Evaluable) (fn.Invoker, error) {
+			for _, name := range names {
+				if _, found := args[name]; !found {
+					return nil, &fn.KeywordArgError{Arg: name, Cause: fn.ErrArgNotFound}
+				}
+			}
+
+			return invoker(args[names[0]], args[names[1]], func(value interface{}) *evaluatedArgs {
+				return newKeywordEvaluatedArgs(names, map[string]interface{}{names[0]: value})
+			}), nil
+		},
+		SignatureFunc: func() *fn.Signature {
+			args := &fn.Arguments{
+				Params: []fn.Param{
+					{Name: names[0], Type: fn.TypeArray | fn.TypeObject},
+					{Name: names[1], Type: fn.TypeAny, Lambda: true},
+				},
+				Required: 2,
+			}
+			return &fn.Signature{Positional: args, Keyword: args, Returns: fn.TypeArray | fn.TypeObject}
+		},
+	}
+}
+
+var mapDescriptor = lambdaDescriptor(
+	`Applies a lambda to each element of an array or object, returning a new array or object containing the results.
+
+Within the lambda, !Binding $item refers to the current element and !Binding $index to its index in the array or its key in the object.`,
+	func(ctx context.Context, args *evaluatedArgs, lambda model.Evaluable) (*model.Result, error) {
+		r := &model.Result{}
+
+		switch c := args.values[0].(type) {
+		case []interface{}:
+			l := make([]interface{}, len(c))
+			for i, item := range c {
+				lr, err := fn.ApplyLambda(ctx, lambda, i, item)
+				if err != nil {
+					return nil, err
+				}
+
+				r.Extends(lr)
+				l[i] = lr.Value
+			}
+
+			r.Value = l
+		case map[string]interface{}:
+			m := make(map[string]interface{}, len(c))
+			for _, k := range sortedKeys(c) {
+				lr, err := fn.ApplyLambda(ctx, lambda, k, c[k])
+				if err != nil {
+					return nil, err
+				}
+
+				r.Extends(lr)
+				m[k] = lr.Value
+			}
+
+			r.Value = m
+		}
+
+		return r, nil
+	},
+)
+
+var filterDescriptor = lambdaDescriptor(
+	`Returns the elements of an array or object for which a lambda evaluates to true.
+
+Within the lambda, !Binding $item refers to the current element and !Binding $index to its index in the array or its key in the object.`,
+	func(ctx context.Context, args *evaluatedArgs, lambda model.Evaluable) (*model.Result, error) {
+		r := &model.Result{}
+
+		keep := func(index, item interface{}) (bool, error) {
+			lr, err := fn.ApplyLambda(ctx, lambda, index, item)
+			if err != nil {
+				return false, err
+			}
+
+			r.Extends(lr)
+			if !lr.Complete() {
+				return false, nil
+			}
+
+			b, ok := lr.Value.(bool)
+			if !ok {
+				return false, args.errorAt(1, &fn.UnexpectedTypeError{
+					Wanted: []reflect.Type{reflect.TypeOf(false)},
+					Got:    reflect.TypeOf(lr.Value),
+				})
+			}
+
+			return b, nil
+		}
+
+		switch c := args.values[0].(type) {
+		case []interface{}:
+			l := []interface{}{}
+			for i, item := range c {
+				ok, err := keep(i, item)
+				if err != nil {
+					return nil, err
+				} else if ok {
+					l = append(l, item)
+				}
+			}
+
+			r.Value = l
+		case map[string]interface{}:
+			m := make(map[string]interface{})
+			for _, k := range sortedKeys(c) {
+				ok, err := keep(k, c[k])
+				if err != nil {
+					return nil, err
+				} else if ok {
+					m[k] = c[k]
+				}
+			}
+
+			r.Value = m
+		}
+
+		return r, nil
+	},
+)
+
+var keysDescriptor = evaluatedDescriptor(
+	"Returns the keys of an object as a sorted array",
+	[]fn.Param{
+		{Name: "object", Type: fn.TypeObject},
+	}, 1, fn.TypeArray,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		m, err := args.objectAt(0)
+		if err != nil {
+			return nil, err
+		}
+
+		keys := sortedKeys(m)
+
+		r := make([]interface{}, len(keys))
+		for i, k := range keys {
+			r[i] = k
+		}
+		return r, nil
+	},
+)
+
+var valuesDescriptor = evaluatedDescriptor(
+	"Returns the values of an object as an array, ordered by their keys",
+	[]fn.Param{
+		{Name: "object", Type: fn.TypeObject},
+	}, 1, fn.TypeArray,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		m, err := args.objectAt(0)
+		if err != nil {
+			return nil, err
+		}
+
+		keys := sortedKeys(m)
+
+		r := make([]interface{}, len(keys))
+		for i, k := range keys {
+			r[i] = m[k]
+		}
+		return r, nil
+	},
+)
+
+var lengthDescriptor = evaluatedDescriptor(
+	"Returns the number of elements in an array, keys in an object, or characters in a string",
+	[]fn.Param{
+		{Name: "value", Type: fn.TypeString | fn.TypeArray | fn.TypeObject},
+	}, 1, fn.TypeNumber,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		switch v := args.values[0].(type) {
+		case []interface{}:
+			return len(v), nil
+		case map[string]interface{}:
+			return len(v), nil
+		case string:
+			return utf8.RuneCountInString(v), nil
+		default:
+			return nil, args.errorAt(0, &fn.UnexpectedTypeError{
+				Wanted: []reflect.Type{
+					reflect.TypeOf([]interface{}(nil)),
+					reflect.TypeOf(map[string]interface{}(nil)),
+					reflect.TypeOf(""),
+				},
+				Got: reflect.TypeOf(v),
+			})
+		}
+	},
+)
+
+var rangeDescriptor = evaluatedDescriptor(
+	`Returns an array of integers counting from start up to, but not including, end.
+
+The step defaults to 1 and may be negative to count down.`,
+	[]fn.Param{
+		{Name: "start", Type: fn.TypeNumber},
+		{Name: "end", Type: fn.TypeNumber},
+		{Name: "step", Type: fn.TypeNumber},
+	}, 2, fn.TypeArray,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		start, err := args.intAt(0)
+		if err != nil {
+			return nil, err
+		}
+
+		end, err := args.intAt(1)
+		if err != nil {
+			return nil, err
+		}
+
+		step := 1
+		if args.hasAt(2) {
+			step, err = args.intAt(2)
+			if err != nil {
+				return nil, err
+			} else if step == 0 {
+				return nil, args.errorAt(2, ErrRangeStepZero)
+			}
+		}
+
+		var n int
+		if step > 0 && end > start {
+			n = (end - start + step - 1) / step
+		} else if step < 0 && end < start {
+			n = (start - end - step - 1) / -step
+		}
+
+		// A negative length means the computation overflowed.
+		if n < 0 || n > MaxRangeLength {
+			return nil, ErrRangeTooLong
+		}
+
+		r := make([]interface{}, n)
+		for i := range r {
+			r[i] = start + i*step
+		}
+		return r, nil
+	},
+)
+
+var uniqueDescriptor = evaluatedDescriptor(
+	"Returns the elements of an array with duplicates removed, keeping the first occurrence of each",
+	[]fn.Param{
+		{Name: "array", Type: fn.TypeArray},
+	}, 1, fn.TypeArray,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		a, err := args.arrayAt(0)
+		if err != nil {
+			return nil, err
+		}
+
+		r := []interface{}{}
+	next:
+		for _, v := range a {
+			for _, seen := range r {
+				if reflect.DeepEqual(v, seen) {
+					continue next
+				}
+			}
+
+			r = append(r, v)
+		}
+		return r, nil
+	},
+)
+
+// sortLess returns an ordering function for the given values, which must be
+// all numbers, all strings, or all times.
+func sortLess(a []interface{}) (func(i, j int) bool, error) {
+	if len(a) == 0 {
+		return func(i, j int) bool { return false }, nil
+	}
+
+	var wanted []reflect.Type
+	switch a[0].(type) {
+	case int, int64, float64:
+		wanted = []reflect.Type{reflect.TypeOf(int(0)), reflect.TypeOf(int64(0)), reflect.TypeOf(float64(0))}
+		for _, v := range a {
+			if _, ok := toNumber(v); !ok {
+				return nil, &fn.UnexpectedTypeError{Wanted: wanted, Got: reflect.TypeOf(v)}
+			}
+		}
+
+		return func(i, j int) bool {
+			ni, _ := toNumber(a[i])
+			nj, _ := toNumber(a[j])
+			return ni < nj
+		}, nil
+	case string:
+		wanted = []reflect.Type{reflect.TypeOf("")}
+		for _, v := range a {
+			if _, ok := v.(string); !ok {
+				return nil, &fn.UnexpectedTypeError{Wanted: wanted, Got: reflect.TypeOf(v)}
+			}
+		}
+
+		return func(i, j int) bool { return a[i].(string) < a[j].(string) }, nil
+	case time.Time:
+		wanted = []reflect.Type{reflect.TypeOf(time.Time{})}
+		for _, v := range a {
+			if _, ok := v.(time.Time); !ok {
+				return nil, &fn.UnexpectedTypeError{Wanted: wanted, Got: reflect.TypeOf(v)}
+			}
+		}
+
+		return func(i, j int) bool { return a[i].(time.Time).Before(a[j].(time.Time)) }, nil
+	default:
+		return nil, &fn.UnexpectedTypeError{
+			Wanted: []reflect.Type{
+				reflect.TypeOf(int(0)),
+				reflect.TypeOf(int64(0)),
+				reflect.TypeOf(float64(0)),
+				reflect.TypeOf(""),
+				reflect.TypeOf(time.Time{}),
+			},
+			Got: reflect.TypeOf(a[0]),
+		}
+	}
+}
+
+var sortDescriptor = evaluatedDescriptor(
+	"Returns the elements of an array in ascending order. The elements must be all numbers, all strings, or all times",
+	[]fn.Param{
+		{Name: "array", Type: fn.TypeArray},
+	}, 1, fn.TypeArray,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		a, err := args.arrayAt(0)
+		if err != nil {
+			return nil, err
+		}
+
+		r := append([]interface{}{}, a...)
+
+		less, err := sortLess(r)
+		if err != nil {
+			return nil, args.errorAt(0, err)
+		}
+
+		sort.SliceStable(r, less)
+		return r, nil
+	},
+)
+
+func flatten(a []interface{}, depth int) []interface{} {
+	r := []interface{}{}
+	for _, v := range a {
+		if va, ok := v.([]interface{}); ok && depth != 0 {
+			r = append(r, flatten(va, depth-1)...)
+		} else {
+			r = append(r, v)
+		}
+	}
+	return r
+}
+
+var flattenDescriptor = evaluatedDescriptor(
+	`Replaces nested arrays in an array with their elements.
+
+By default, arrays are flattened completely. If a depth is given, only that many levels of nesting are removed.`,
+	[]fn.Param{
+		{Name: "array", Type: fn.TypeArray},
+		{Name: "depth", Type: fn.TypeNumber},
+	}, 1, fn.TypeArray,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		a, err := args.arrayAt(0)
+		if err != nil {
+			return nil, err
+		}
+
+		depth := -1
+		if args.hasAt(1) {
+			depth, err = args.intAt(1)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return flatten(a, depth), nil
+	},
+)
@@ -0,0 +1,328 @@
+package fnlib_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/puppetlabs/relay-core/pkg/expr/evaluate"
+	"github.com/puppetlabs/relay-core/pkg/expr/fn"
+	"github.com/puppetlabs/relay-core/pkg/expr/fnlib"
+	"github.com/puppetlabs/relay-core/pkg/expr/model"
+	"github.com/puppetlabs/relay-core/pkg/expr/resolve"
+	"github.com/puppetlabs/relay-core/pkg/expr/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionFuncs(t *testing.T) {
+	tests := []stringFuncTest{
+		{
+			Name:           "keys",
+			Func:           "keys",
+			PositionalArgs: []interface{}{map[string]interface{}{"b": 1, "a": 2}},
+			KeywordArgs:    map[string]interface{}{"object": map[string]interface{}{"b": 1, "a": 2}},
+			Expected:       []interface{}{"a", "b"},
+		},
+		{
+			Name:           "keys with non-object",
+			Func:           "keys",
+			PositionalArgs: []interface{}{[]interface{}{"a"}},
+			ExpectedPositionalError: &fn.PositionalArgError{
+				Arg: 1,
+				Cause: &fn.UnexpectedTypeError{
+					Wanted: []reflect.Type{reflect.TypeOf(map[string]interface{}(nil))},
+					Got:    reflect.TypeOf([]interface{}(nil)),
+				},
+			},
+		},
+		{
+			Name:           "values",
+			Func:           "values",
+			PositionalArgs: []interface{}{map[string]interface{}{"b": 1, "a": 2}},
+			KeywordArgs:    map[string]interface{}{"object": map[string]interface{}{"b": 1, "a": 2}},
+			Expected:       []interface{}{2, 1},
+		},
+		{
+			Name:           "length of array",
+			Func:           "length",
+			PositionalArgs: []interface{}{[]interface{}{"a", "b", "c"}},
+			KeywordArgs:    map[string]interface{}{"value": []interface{}{"a", "b", "c"}},
+			Expected:       3,
+		},
+		{
+			Name:           "length of object",
+			Func:           "length",
+			PositionalArgs: []interface{}{map[string]interface{}{"a": 1}},
+			Expected:       1,
+		},
+		{
+			Name:           "length of string",
+			Func:           "length",
+			PositionalArgs: []interface{}{"héllo"},
+			Expected:       5,
+		},
+		{
+			Name:           "length of number",
+			Func:           "length",
+			PositionalArgs: []interface{}{float64(42)},
+			ExpectedPositionalError: &fn.PositionalArgError{
+				Arg: 1,
+				Cause: &fn.UnexpectedTypeError{
+					Wanted: []reflect.Type{
+						reflect.TypeOf([]interface{}(nil)),
+						reflect.TypeOf(map[string]interface{}(nil)),
+						reflect.TypeOf(""),
+					},
+					Got: reflect.TypeOf(float64(0)),
+				},
+			},
+		},
+		{
+			Name:           "range",
+			Func:           "range",
+			PositionalArgs: []interface{}{0, 4},
+			KeywordArgs:    map[string]interface{}{"start": 0, "end": 4},
+			Expected:       []interface{}{0, 1, 2, 3},
+		},
+		{
+			Name:           "range with step",
+			Func:           "range",
+			PositionalArgs: []interface{}{float64(1), float64(8), float64(3)},
+			Expected:       []interface{}{1, 4, 7},
+		},
+		{
+			Name:           "range with negative step",
+			Func:           "range",
+			PositionalArgs: []interface{}{5, 0, -2},
+			Expected:       []interface{}{5, 3, 1},
+		},
+		{
+			Name:           "range with end before start",
+			Func:           "range",
+			PositionalArgs: []interface{}{5, 0},
+			Expected:       []interface{}{},
+		},
+		{
+			Name:           "range with zero step",
+			Func:           "range",
+			PositionalArgs: []interface{}{0, 5, 0},
+			KeywordArgs:    map[string]interface{}{"start": 0, "end": 5, "step": 0},
+			ExpectedPositionalError: &fn.PositionalArgError{
+				Arg:   3,
+				Cause: fnlib.ErrRangeStepZero,
+			},
+			ExpectedKeywordError: &fn.KeywordArgError{
+				Arg:   "step",
+				Cause: fnlib.ErrRangeStepZero,
+			},
+		},
+		{
+			Name:                    "range too long",
+			Func:                    "range",
+			PositionalArgs:          []interface{}{0, fnlib.MaxRangeLength + 1},
+			ExpectedPositionalError: fnlib.ErrRangeTooLong,
+		},
+		{
+			Name:           "unique",
+			Func:           "unique",
+			PositionalArgs: []interface{}{[]interface{}{"b", "a", "b", map[string]interface{}{"c": 1}, map[string]interface{}{"c": 1}}},
+			KeywordArgs:    map[string]interface{}{"array": []interface{}{"b", "a", "b", map[string]interface{}{"c": 1}, map[string]interface{}{"c": 1}}},
+			Expected:       []interface{}{"b", "a", map[string]interface{}{"c": 1}},
+		},
+		{
+			Name:           "sort strings",
+			Func:           "sort",
+			PositionalArgs: []interface{}{[]interface{}{"pear", "apple", "fig"}},
+			KeywordArgs:    map[string]interface{}{"array": []interface{}{"pear", "apple", "fig"}},
+			Expected:       []interface{}{"apple", "fig", "pear"},
+		},
+		{
+			Name:           "sort numbers",
+			Func:           "sort",
+			PositionalArgs: []interface{}{[]interface{}{3, float64(1.5), int64(2)}},
+			Expected:       []interface{}{float64(1.5), int64(2), 3},
+		},
+		{
+			Name:           "sort mixed types",
+			Func:           "sort",
+			PositionalArgs: []interface{}{[]interface{}{"a", 1}},
+			ExpectedPositionalError: &fn.PositionalArgError{
+				Arg: 1,
+				Cause: &fn.UnexpectedTypeError{
+					Wanted: []reflect.Type{reflect.TypeOf("")},
+					Got:    reflect.TypeOf(int(0)),
+				},
+			},
+		},
+		{
+			Name:           "flatten",
+			Func:           "flatten",
+			PositionalArgs: []interface{}{[]interface{}{1, []interface{}{2, []interface{}{3}}}},
+			KeywordArgs:    map[string]interface{}{"array": []interface{}{1, []interface{}{2, []interface{}{3}}}},
+			Expected:       []interface{}{1, 2, 3},
+		},
+		{
+			Name:           "flatten with depth",
+			Func:           "flatten",
+			PositionalArgs: []interface{}{[]interface{}{1, []interface{}{2, []interface{}{3}}}, 1},
+			KeywordArgs:    map[string]interface{}{"array": []interface{}{1, []interface{}{2, []interface{}{3}}}, "depth": 1},
+			Expected:       []interface{}{1, 2, []interface{}{3}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, test.Run)
+	}
+}
+
+func TestLambdaFuncs(t *testing.T) {
+	users := []interface{}{
+		map[string]interface{}{"name": "alice", "admin": true},
+		map[string]interface{}{"name": "bob", "admin": false},
+		map[string]interface{}{"name": "carol", "admin": true},
+	}
+
+	ev := evaluate.NewEvaluator(
+		evaluate.WithParameterTypeResolver(resolve.NewMemoryParameterTypeResolver(map[string]interface{}{
+			"users": users,
+		})),
+	)
+
+	tests := []struct {
+		Name                 string
+		Tree                 interface{}
+		Expected             interface{}
+		ExpectedUnresolvable model.Unresolvable
+		ExpectedError        error
+	}{
+		{
+			Name: "map",
+			Tree: testutil.JSONInvocation("map", []interface{}{
+				testutil.JSONParameter("users"),
+				testutil.JSONInvocation("path", []interface{}{testutil.JSONBinding("$item"), "name"}),
+			}),
+			Expected: []interface{}{"alice", "bob", "carol"},
+		},
+		{
+			Name: "map with index",
+			Tree: testutil.JSONInvocation("map", map[string]interface{}{
+				"collection": []interface{}{"a", "b"},
+				"lambda": testutil.JSONInvocation("format", []interface{}{
+					"%v=%v",
+					testutil.JSONBinding("$index"),
+					testutil.JSONBinding("$item"),
+				}),
+			}),
+			Expected: []interface{}{"0=a", "1=b"},
+		},
+		{
+			Name: "map over object",
+			Tree: testutil.JSONInvocation("map", []interface{}{
+				map[string]interface{}{"a": "x", "b": "y"},
+				testutil.JSONInvocation("concat", []interface{}{testutil.JSONBinding("$index"), testutil.JSONBinding("$item")}),
+			}),
+			Expected: map[string]interface{}{"a": "ax", "b": "by"},
+		},
+		{
+			Name: "map with unknown function in lambda",
+			Tree: testutil.JSONInvocation("map", []interface{}{
+				[]interface{}{"a", "b"},
+				testutil.JSONInvocation("map", []interface{}{
+					[]interface{}{1, 2},
+					testutil.JSONInvocation("list", []interface{}{}),
+				}),
+			}),
+			ExpectedUnresolvable: model.Unresolvable{Invocations: []model.UnresolvableInvocation{
+				{Name: "list", Cause: fn.ErrFunctionNotFound},
+			}},
+		},
+		{
+			Name: "filter",
+			Tree: testutil.JSONInvocation("filter", []interface{}{
+				testutil.JSONParameter("users"),
+				testutil.JSONInvocation("path", []interface{}{testutil.JSONBinding("$item"), "admin"}),
+			}),
+			Expected: []interface{}{users[0], users[2]},
+		},
+		{
+			Name: "filter composed with map",
+			Tree: testutil.JSONInvocation("map", []interface{}{
+				testutil.JSONInvocation("filter", []interface{}{
+					testutil.JSONParameter("users"),
+					testutil.JSONInvocation("notEquals", []interface{}{
+						testutil.JSONInvocation("path", []interface{}{testutil.JSONBinding("$item"), "name"}),
+						"bob",
+					}),
+				}),
+				testutil.JSONInvocation("upper", []interface{}{
+					testutil.JSONInvocation("path", []interface{}{testutil.JSONBinding("$item"), "name"}),
+				}),
+			}),
+			Expected: []interface{}{"ALICE", "CAROL"},
+		},
+		{
+			Name: "filter with non-boolean lambda",
+			Tree: testutil.JSONInvocation("filter", []interface{}{
+				[]interface{}{1, 2},
+				testutil.JSONBinding("$item"),
+			}),
+			ExpectedError: &evaluate.InvocationError{
+				Name: "filter",
+				Cause: &fn.PositionalArgError{
+					Arg: 2,
+					Cause: &fn.UnexpectedTypeError{
+						Wanted: []reflect.Type{reflect.TypeOf(false)},
+						Got:    reflect.TypeOf(int(0)),
+					},
+				},
+			},
+		},
+		{
+			Name: "map with unresolvable collection",
+			Tree: testutil.JSONInvocation("map", []interface{}{
+				testutil.JSONParameter("groups"),
+				testutil.JSONBinding("$item"),
+			}),
+			ExpectedUnresolvable: model.Unresolvable{Parameters: []model.UnresolvableParameter{
+				{Name: "groups"},
+			}},
+		},
+		{
+			Name: "map with unresolvable lambda",
+			Tree: testutil.JSONInvocation("map", []interface{}{
+				[]interface{}{"a", "b"},
+				testutil.JSONInvocation("concat", []interface{}{testutil.JSONBinding("$item"), testutil.JSONParameter("suffix")}),
+			}),
+			ExpectedUnresolvable: model.Unresolvable{Parameters: []model.UnresolvableParameter{
+				{Name: "suffix"},
+			}},
+		},
+		{
+			Name: "binding outside lambda",
+			Tree: map[string]interface{}{
+				"foo": testutil.JSONBinding("$item"),
+			},
+			ExpectedError: &evaluate.PathEvaluationError{
+				Path: "foo",
+				Cause: &evaluate.InvalidTypeError{
+					Type:  "Binding",
+					Cause: &evaluate.BindingNotFoundError{Name: "$item"},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			r, err := ev.EvaluateAll(context.Background(), test.Tree)
+			if test.ExpectedError != nil {
+				require.Equal(t, test.ExpectedError, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.ExpectedUnresolvable, r.Unresolvable)
+			if r.Complete() {
+				require.Equal(t, test.Expected, r.Value)
+			}
+		})
+	}
+}
@@ -33,4 +33,15 @@ var concatDescriptor = fn.DescriptorFuncs{
 		})
 		return fn, nil
 	},
+	SignatureFunc: func() *fn.Signature {
+		return &fn.Signature{
+			Positional: &fn.Arguments{
+				Params: []fn.Param{
+					{Name: "string", Type: fn.TypeScalar},
+				},
+				Variadic: true,
+			},
+			Returns: fn.TypeString,
+		}
+	},
 }
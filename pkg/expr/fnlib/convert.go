@@ -84,4 +84,23 @@ var convertMarkdownDescriptor = fn.DescriptorFuncs{
 			}
 		}), nil
 	},
+	SignatureFunc: func() *fn.Signature {
+		return &fn.Signature{
+			Positional: &fn.Arguments{
+				Params: []fn.Param{
+					{Name: "to", Type: fn.TypeString},
+					{Name: "content", Type: fn.TypeString},
+				},
+				Required: 2,
+			},
+			Keyword: &fn.Arguments{
+				Params: []fn.Param{
+					{Name: "to", Type: fn.TypeString},
+					{Name: "content", Type: fn.TypeString},
+				},
+				Required: 2,
+			},
+			Returns: fn.TypeString,
+		}
+	},
 }
@@ -0,0 +1,169 @@
+package fnlib
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/puppetlabs/relay-core/pkg/expr/fn"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Every function in this file produces a result that is as sensitive as its
+// input. The evaluator takes care of marking results computed from secrets as
+// sensitive, so a hash of a secret is reported the same way as the secret
+// itself.
+
+var base64EncodeDescriptor = stringTransformDescriptor(
+	"Encodes a string using standard base64 encoding",
+	[]string{"string"},
+	func(args []string) string { return base64.StdEncoding.EncodeToString([]byte(args[0])) },
+)
+
+var base64DecodeDescriptor = evaluatedDescriptor(
+	"Decodes a string encoded using standard base64 encoding",
+	[]fn.Param{
+		{Name: "string", Type: fn.TypeScalar},
+	}, 1, fn.TypeString,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		s, err := args.stringAt(0)
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, args.errorAt(0, err)
+		}
+
+		return string(b), nil
+	},
+)
+
+var jsonMarshalDescriptor = evaluatedDescriptor(
+	"Marshals a value into a JSON-encoded string",
+	[]fn.Param{
+		{Name: "value", Type: fn.TypeAny},
+	}, 1, fn.TypeString,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		b, err := json.Marshal(args.values[0])
+		if err != nil {
+			return nil, args.errorAt(0, err)
+		}
+
+		return string(b), nil
+	},
+)
+
+var yamlMarshalDescriptor = evaluatedDescriptor(
+	"Marshals a value into a YAML-encoded string",
+	[]fn.Param{
+		{Name: "value", Type: fn.TypeAny},
+	}, 1, fn.TypeString,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		b, err := yaml.Marshal(args.values[0])
+		if err != nil {
+			return nil, args.errorAt(0, err)
+		}
+
+		return string(b), nil
+	},
+)
+
+var yamlUnmarshalDescriptor = evaluatedDescriptor(
+	"Unmarshals a YAML-encoded string into the specification",
+	[]fn.Param{
+		{Name: "string", Type: fn.TypeScalar},
+	}, 1, fn.TypeAny,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		s, err := args.stringAt(0)
+		if err != nil {
+			return nil, err
+		}
+
+		var v interface{}
+		if err := yaml.Unmarshal([]byte(s), &v); err != nil {
+			return nil, args.errorAt(0, err)
+		}
+
+		return normalizeYAMLValue(v), nil
+	},
+)
+
+// normalizeYAMLValue converts mappings with non-string keys, which YAML
+// permits but the specification does not, to mappings with string keys.
+func normalizeYAMLValue(v interface{}) interface{} {
+	switch vt := v.(type) {
+	case []interface{}:
+		for i, v := range vt {
+			vt[i] = normalizeYAMLValue(v)
+		}
+
+		return vt
+	case map[string]interface{}:
+		for k, v := range vt {
+			vt[k] = normalizeYAMLValue(v)
+		}
+
+		return vt
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(vt))
+		for k, v := range vt {
+			m[fmt.Sprint(k)] = normalizeYAMLValue(v)
+		}
+
+		return m
+	default:
+		return v
+	}
+}
+
+var sha1Descriptor = stringTransformDescriptor(
+	"Returns the hex-encoded SHA-1 digest of a string",
+	[]string{"string"},
+	func(args []string) string {
+		sum := sha1.Sum([]byte(args[0]))
+		return hex.EncodeToString(sum[:])
+	},
+)
+
+var sha256Descriptor = stringTransformDescriptor(
+	"Returns the hex-encoded SHA-256 digest of a string",
+	[]string{"string"},
+	func(args []string) string {
+		sum := sha256.Sum256([]byte(args[0]))
+		return hex.EncodeToString(sum[:])
+	},
+)
+
+var urlEncodeDescriptor = stringTransformDescriptor(
+	"Escapes a string so it can be safely placed in a URL query",
+	[]string{"string"},
+	func(args []string) string { return url.QueryEscape(args[0]) },
+)
+
+var urlDecodeDescriptor = evaluatedDescriptor(
+	"Reverses the escaping performed by urlEncode",
+	[]fn.Param{
+		{Name: "string", Type: fn.TypeScalar},
+	}, 1, fn.TypeString,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		s, err := args.stringAt(0)
+		if err != nil {
+			return nil, err
+		}
+
+		s, err = url.QueryUnescape(s)
+		if err != nil {
+			return nil, args.errorAt(0, err)
+		}
+
+		return s, nil
+	},
+)
@@ -0,0 +1,130 @@
+package fnlib_test
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/puppetlabs/relay-core/pkg/expr/evaluate"
+	"github.com/puppetlabs/relay-core/pkg/expr/fn"
+	"github.com/puppetlabs/relay-core/pkg/expr/resolve"
+	"github.com/puppetlabs/relay-core/pkg/expr/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodingFuncs(t *testing.T) {
+	tests := []stringFuncTest{
+		{
+			Name:           "base64Encode",
+			Func:           "base64Encode",
+			PositionalArgs: []interface{}{"hello"},
+			KeywordArgs:    map[string]interface{}{"string": "hello"},
+			Expected:       "aGVsbG8=",
+		},
+		{
+			Name:           "base64Decode",
+			Func:           "base64Decode",
+			PositionalArgs: []interface{}{"aGVsbG8="},
+			KeywordArgs:    map[string]interface{}{"string": "aGVsbG8="},
+			Expected:       "hello",
+		},
+		{
+			Name:           "base64Decode with invalid input",
+			Func:           "base64Decode",
+			PositionalArgs: []interface{}{"!!!"},
+			ExpectedPositionalError: &fn.PositionalArgError{
+				Arg:   1,
+				Cause: base64.CorruptInputError(0),
+			},
+		},
+		{
+			Name:           "jsonMarshal",
+			Func:           "jsonMarshal",
+			PositionalArgs: []interface{}{map[string]interface{}{"foo": []interface{}{"bar", 1}}},
+			KeywordArgs:    map[string]interface{}{"value": map[string]interface{}{"foo": []interface{}{"bar", 1}}},
+			Expected:       `{"foo":["bar",1]}`,
+		},
+		{
+			Name:           "yamlMarshal",
+			Func:           "yamlMarshal",
+			PositionalArgs: []interface{}{map[string]interface{}{"foo": []interface{}{"bar", 1}}},
+			KeywordArgs:    map[string]interface{}{"value": map[string]interface{}{"foo": []interface{}{"bar", 1}}},
+			Expected:       "foo:\n    - bar\n    - 1\n",
+		},
+		{
+			Name:           "yamlUnmarshal",
+			Func:           "yamlUnmarshal",
+			PositionalArgs: []interface{}{"foo:\n  - bar\n  - 1\n"},
+			KeywordArgs:    map[string]interface{}{"string": "foo:\n  - bar\n  - 1\n"},
+			Expected:       map[string]interface{}{"foo": []interface{}{"bar", 1}},
+		},
+		{
+			Name:           "yamlUnmarshal with non-string keys",
+			Func:           "yamlUnmarshal",
+			PositionalArgs: []interface{}{"1: one\ntrue: yes\n"},
+			Expected:       map[string]interface{}{"1": "one", "true": "yes"},
+		},
+		{
+			Name:           "sha1",
+			Func:           "sha1",
+			PositionalArgs: []interface{}{"hello"},
+			KeywordArgs:    map[string]interface{}{"string": "hello"},
+			Expected:       "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d",
+		},
+		{
+			Name:           "sha256",
+			Func:           "sha256",
+			PositionalArgs: []interface{}{"hello"},
+			KeywordArgs:    map[string]interface{}{"string": "hello"},
+			Expected:       "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		},
+		{
+			Name:           "urlEncode",
+			Func:           "urlEncode",
+			PositionalArgs: []interface{}{"a b&c=d/e"},
+			KeywordArgs:    map[string]interface{}{"string": "a b&c=d/e"},
+			Expected:       "a+b%26c%3Dd%2Fe",
+		},
+		{
+			Name:           "urlDecode",
+			Func:           "urlDecode",
+			PositionalArgs: []interface{}{"a+b%26c%3Dd%2Fe"},
+			KeywordArgs:    map[string]interface{}{"string": "a+b%26c%3Dd%2Fe"},
+			Expected:       "a b&c=d/e",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, test.Run)
+	}
+}
+
+func TestEncodingFuncSensitivity(t *testing.T) {
+	ev := evaluate.NewEvaluator(
+		evaluate.WithSecretTypeResolver(resolve.NewMemorySecretTypeResolver(map[string]string{"password": "hunter2"})),
+	)
+
+	r, err := ev.EvaluateAll(context.Background(), testutil.JSONInvocation("sha256", []interface{}{testutil.JSONSecret("password")}))
+	require.NoError(t, err)
+	require.True(t, r.Complete())
+	require.True(t, r.Sensitive)
+	require.Equal(t, "f52fbd32b2b3b86ff88ef6c490628285f482af15ddcb29541f94bcf526a3f6c7", r.Value)
+
+	r, err = ev.EvaluateAll(context.Background(), map[string]interface{}{
+		"digest":  testutil.JSONInvocation("sha256", []interface{}{"hunter2"}),
+		"encoded": testutil.JSONInvocation("base64Encode", []interface{}{"hunter2"}),
+	})
+	require.NoError(t, err)
+	require.True(t, r.Complete())
+	require.False(t, r.Sensitive)
+
+	r, err = ev.EvaluateAll(context.Background(), map[string]interface{}{
+		"plain":  testutil.JSONInvocation("upper", []interface{}{"hello"}),
+		"secret": testutil.JSONInvocation("base64Encode", []interface{}{testutil.JSONSecret("password")}),
+	})
+	require.NoError(t, err)
+	require.True(t, r.Sensitive)
+	require.Equal(t, map[string]interface{}{
+		"plain":  "HELLO",
+		"secret": "aHVudGVyMg==",
+	}, r.Value)
+}
@@ -22,6 +22,18 @@ var (
 
 			return fn, nil
 		},
+		SignatureFunc: func() *fn.Signature {
+			return &fn.Signature{
+				Positional: &fn.Arguments{
+					Params: []fn.Param{
+						{Name: "left", Type: fn.TypeAny},
+						{Name: "right", Type: fn.TypeAny},
+					},
+					Required: 2,
+				},
+				Returns: fn.TypeBoolean,
+			}
+		},
 	}
 
 	notEqualsDescriptor = fn.DescriptorFuncs{
@@ -37,5 +49,17 @@ var (
 
 			return fn, nil
 		},
+		SignatureFunc: func() *fn.Signature {
+			return &fn.Signature{
+				Positional: &fn.Arguments{
+					Params: []fn.Param{
+						{Name: "left", Type: fn.TypeAny},
+						{Name: "right", Type: fn.TypeAny},
+					},
+					Required: 2,
+				},
+				Returns: fn.TypeBoolean,
+			}
+		},
 	}
 )
@@ -4,16 +4,61 @@ import "github.com/puppetlabs/relay-core/pkg/expr/fn"
 
 var (
 	library = map[string]fn.Descriptor{
-		"append":          appendDescriptor,
-		"coalesce":        coalesceDescriptor,
-		"concat":          concatDescriptor,
-		"convertMarkdown": convertMarkdownDescriptor,
-		"equals":          equalsDescriptor,
-		"jsonUnmarshal":   jsonUnmarshalDescriptor,
-		"merge":           mergeDescriptor,
-		"notEquals":       notEqualsDescriptor,
-		"path":            pathDescriptor,
-		"toString":        toStringDescriptor,
+		"addTime":            addTimeDescriptor,
+		"and":                andDescriptor,
+		"append":             appendDescriptor,
+		"base64Decode":       base64DecodeDescriptor,
+		"base64Encode":       base64EncodeDescriptor,
+		"coalesce":           coalesceDescriptor,
+		"concat":             concatDescriptor,
+		"contains":           containsDescriptor,
+		"convertMarkdown":    convertMarkdownDescriptor,
+		"diffTime":           diffTimeDescriptor,
+		"equals":             equalsDescriptor,
+		"filter":             filterDescriptor,
+		"flatten":            flattenDescriptor,
+		"format":             formatDescriptor,
+		"formatTime":         formatTimeDescriptor,
+		"greaterThan":        greaterThanDescriptor,
+		"greaterThanOrEqual": greaterThanOrEqualDescriptor,
+		"if":                 ifDescriptor,
+		"join":               joinDescriptor,
+		"jsonMarshal":        jsonMarshalDescriptor,
+		"jsonUnmarshal":      jsonUnmarshalDescriptor,
+		"keys":               keysDescriptor,
+		"length":             lengthDescriptor,
+		"lessThan":           lessThanDescriptor,
+		"lessThanOrEqual":    lessThanOrEqualDescriptor,
+		"lower":              lowerDescriptor,
+		"map":                mapDescriptor,
+		"merge":              mergeDescriptor,
+		"not":                notDescriptor,
+		"notEquals":          notEqualsDescriptor,
+		"now":                nowDescriptor,
+		"or":                 orDescriptor,
+		"parseTime":          parseTimeDescriptor,
+		"path":               pathDescriptor,
+		"range":              rangeDescriptor,
+		"regexFind":          regexFindDescriptor,
+		"regexMatch":         regexMatchDescriptor,
+		"regexReplace":       regexReplaceDescriptor,
+		"replace":            replaceDescriptor,
+		"sha1":               sha1Descriptor,
+		"sha256":             sha256Descriptor,
+		"sort":               sortDescriptor,
+		"split":              splitDescriptor,
+		"substring":          substringDescriptor,
+		"toString":           toStringDescriptor,
+		"trimPrefix":         trimPrefixDescriptor,
+		"trimSuffix":         trimSuffixDescriptor,
+		"truncateTime":       truncateTimeDescriptor,
+		"unique":             uniqueDescriptor,
+		"upper":              upperDescriptor,
+		"urlDecode":          urlDecodeDescriptor,
+		"urlEncode":          urlEncodeDescriptor,
+		"values":             valuesDescriptor,
+		"yamlMarshal":        yamlMarshalDescriptor,
+		"yamlUnmarshal":      yamlUnmarshalDescriptor,
 	}
 )
 
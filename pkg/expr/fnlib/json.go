@@ -37,4 +37,15 @@ var jsonUnmarshalDescriptor = fn.DescriptorFuncs{
 		})
 		return fn, nil
 	},
+	SignatureFunc: func() *fn.Signature {
+		return &fn.Signature{
+			Positional: &fn.Arguments{
+				Params: []fn.Param{
+					{Name: "string", Type: fn.TypeString},
+				},
+				Required: 1,
+			},
+			Returns: fn.TypeAny,
+		}
+	},
 }
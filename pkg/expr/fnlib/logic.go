@@ -0,0 +1,364 @@
+package fnlib
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/puppetlabs/relay-core/pkg/expr/fn"
+	"github.com/puppetlabs/relay-core/pkg/expr/model"
+)
+
+func (ea *evaluatedArgs) boolAt(i int) (bool, error) {
+	b, ok := ea.values[i].(bool)
+	if !ok {
+		return false, ea.errorAt(i, &fn.UnexpectedTypeError{
+			Wanted: []reflect.Type{reflect.TypeOf(false)},
+			Got:    reflect.TypeOf(ea.values[i]),
+		})
+	}
+
+	return b, nil
+}
+
+// shortCircuitDescriptor creates a descriptor for a logical operator that
+// evaluates its arguments in order, stopping as soon as one of them evaluates
+// to the given value. Arguments after that one are never evaluated, so any
+// references they contain need not be resolvable.
+func shortCircuitDescriptor(description string, stop bool) fn.Descriptor {
+	return fn.DescriptorFuncs{
+		DescriptionFunc: func() string { return description },
+		PositionalInvokerFunc: func(args []model.Evaluable) (fn.Invoker, error) {
+			if len(args) < 1 {
+				return nil, &fn.ArityError{Wanted: []int{1}, Variadic: true, Got: len(args)}
+			}
+
+			fn := fn.InvokerFunc(func(ctx context.Context) (*model.Result, error) {
+				rs := make([]*model.Result, len(args))
+
+				var sensitive bool
+				for i, arg := range args {
+					r, err := arg.EvaluateAll(ctx)
+					if err != nil {
+						return nil, err
+					}
+
+					rs[i] = r
+					if !r.Complete() {
+						// The outcome may still be decided by a later
+						// argument.
+						continue
+					}
+
+					b, ok := r.Value.(bool)
+					if !ok {
+						return nil, &fn.PositionalArgError{
+							Arg: i + 1,
+							Cause: &fn.UnexpectedTypeError{
+								Wanted: []reflect.Type{reflect.TypeOf(false)},
+								Got:    reflect.TypeOf(r.Value),
+							},
+						}
+					}
+
+					sensitive = sensitive || r.Sensitive
+					if b == stop {
+						return &model.Result{Value: stop, Sensitive: sensitive}, nil
+					}
+				}
+
+				r := model.CombineResultSlice(rs)
+				if !r.Complete() {
+					return r, nil
+				}
+
+				return &model.Result{Value: !stop, Sensitive: r.Sensitive}, nil
+			})
+			return fn, nil
+		},
+		SignatureFunc: func() *fn.Signature {
+			return &fn.Signature{
+				Positional: &fn.Arguments{
+					Params: []fn.Param{
+						{Name: "value", Type: fn.TypeBoolean},
+					},
+					Required: 1,
+					Variadic: true,
+				},
+				Returns: fn.TypeBoolean,
+			}
+		},
+	}
+}
+
+var andDescriptor = shortCircuitDescriptor(
+	"Returns true if every argument is true. Arguments after the first false argument are not evaluated",
+	false,
+)
+
+var orDescriptor = shortCircuitDescriptor(
+	"Returns true if any argument is true. Arguments after the first true argument are not evaluated",
+	true,
+)
+
+var notDescriptor = evaluatedDescriptor(
+	"Returns the logical negation of a boolean",
+	[]fn.Param{
+		{Name: "value", Type: fn.TypeBoolean},
+	}, 1, fn.TypeBoolean,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		b, err := args.boolAt(0)
+		if err != nil {
+			return nil, err
+		}
+
+		return !b, nil
+	},
+)
+
+func ifInvoker(names []string, keyword bool, args []model.Evaluable) fn.Invoker {
+	return fn.InvokerFunc(func(ctx context.Context) (*model.Result, error) {
+		cr, err := args[0].EvaluateAll(ctx)
+		if err != nil {
+			return nil, err
+		} else if !cr.Complete() {
+			// Without knowing which branch to take, we don't evaluate
+			// either of them.
+			return cr, nil
+		}
+
+		cond, ok := cr.Value.(bool)
+		if !ok {
+			return nil, argError(names, keyword, 0, &fn.UnexpectedTypeError{
+				Wanted: []reflect.Type{reflect.TypeOf(false)},
+				Got:    reflect.TypeOf(cr.Value),
+			})
+		}
+
+		branch := args[1]
+		if !cond {
+			branch = args[2]
+		}
+
+		if branch == nil {
+			return &model.Result{Value: nil, Sensitive: cr.Sensitive}, nil
+		}
+
+		r, err := branch.EvaluateAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		// The choice of branch reveals the condition.
+		r.Sensitive = r.Sensitive || cr.Sensitive
+		return r, nil
+	})
+}
+
+var ifDescriptor = fn.DescriptorFuncs{
+	DescriptionFunc: func() string {
+		return "Evaluates to the second argument if the first argument is true, and to the third argument, or null if omitted, otherwise. Only the selected argument is evaluated"
+	},
+	PositionalInvokerFunc: func(args []model.Evaluable) (fn.Invoker, error) {
+		if len(args) < 2 || len(args) > 3 {
+			return nil, &fn.ArityError{Wanted: []int{2, 3}, Got: len(args)}
+		}
+
+		names := []string{"condition", "then", "else"}
+
+		evs := make([]model.Evaluable, len(names))
+		copy(evs, args)
+
+		return ifInvoker(names, false, evs), nil
+	},
+	KeywordInvokerFunc: func(args map[string]model.Evaluable) (fn.Invoker, error) {
+		names := []string{"condition", "then", "else"}
+
+		for _, name := range names[:2] {
+			if _, found := args[name]; !found {
+				return nil, &fn.KeywordArgError{Arg: name, Cause: fn.ErrArgNotFound}
+			}
+		}
+
+		evs := make([]model.Evaluable, len(names))
+		for i, name := range names {
+			evs[i] = args[name]
+		}
+
+		return ifInvoker(names, true, evs), nil
+	},
+	SignatureFunc: func() *fn.Signature {
+		args := &fn.Arguments{
+			Params: []fn.Param{
+				{Name: "condition", Type: fn.TypeBoolean},
+				{Name: "then", Type: fn.TypeAny},
+				{Name: "else", Type: fn.TypeAny},
+			},
+			Required: 2,
+		}
+		return &fn.Signature{Positional: args, Keyword: args, Returns: fn.TypeAny}
+	},
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	switch vt := v.(type) {
+	case int:
+		return float64(vt), true
+	case int64:
+		return float64(vt), true
+	case float64:
+		return vt, true
+	default:
+		return 0, false
+	}
+}
+
+// compareAt compares the arguments at the given positions, which must both be
+// numbers, both be strings, or both be times. It returns a negative number if
+// the first is less than the second, zero if they are equal, and a positive
+// number otherwise.
+func (ea *evaluatedArgs) compareAt(i, j int) (int, error) {
+	a, b := ea.values[i], ea.values[j]
+
+	var wanted []reflect.Type
+	switch at := a.(type) {
+	case int, int64, float64:
+		an, _ := toNumber(a)
+		if bn, ok := toNumber(b); ok {
+			switch {
+			case an < bn:
+				return -1, nil
+			case an > bn:
+				return 1, nil
+			default:
+				return 0, nil
+			}
+		}
+
+		wanted = []reflect.Type{reflect.TypeOf(int(0)), reflect.TypeOf(int64(0)), reflect.TypeOf(float64(0))}
+	case string:
+		if bs, ok := b.(string); ok {
+			return strings.Compare(at, bs), nil
+		}
+
+		wanted = []reflect.Type{reflect.TypeOf("")}
+	case time.Time:
+		if bt, ok := b.(time.Time); ok {
+			switch {
+			case at.Before(bt):
+				return -1, nil
+			case at.After(bt):
+				return 1, nil
+			default:
+				return 0, nil
+			}
+		}
+
+		wanted = []reflect.Type{reflect.TypeOf(time.Time{})}
+	default:
+		return 0, ea.errorAt(i, &fn.UnexpectedTypeError{
+			Wanted: []reflect.Type{
+				reflect.TypeOf(int(0)),
+				reflect.TypeOf(int64(0)),
+				reflect.TypeOf(float64(0)),
+				reflect.TypeOf(""),
+				reflect.TypeOf(time.Time{}),
+			},
+			Got: reflect.TypeOf(a),
+		})
+	}
+
+	return 0, ea.errorAt(j, &fn.UnexpectedTypeError{
+		Wanted: wanted,
+		Got:    reflect.TypeOf(b),
+	})
+}
+
+func comparisonDescriptor(description string, f func(c int) bool) fn.Descriptor {
+	return evaluatedDescriptor(
+		description,
+		[]fn.Param{
+			{Name: "left", Type: fn.TypeNumber | fn.TypeString | fn.TypeTime},
+			{Name: "right", Type: fn.TypeNumber | fn.TypeString | fn.TypeTime},
+		}, 2, fn.TypeBoolean,
+		func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+			c, err := args.compareAt(0, 1)
+			if err != nil {
+				return nil, err
+			}
+
+			return f(c), nil
+		},
+	)
+}
+
+var greaterThanDescriptor = comparisonDescriptor(
+	"Checks if the left side is greater than the right side. Both sides must be numbers, strings, or times",
+	func(c int) bool { return c > 0 },
+)
+
+var greaterThanOrEqualDescriptor = comparisonDescriptor(
+	"Checks if the left side is greater than or equal to the right side. Both sides must be numbers, strings, or times",
+	func(c int) bool { return c >= 0 },
+)
+
+var lessThanDescriptor = comparisonDescriptor(
+	"Checks if the left side is less than the right side. Both sides must be numbers, strings, or times",
+	func(c int) bool { return c < 0 },
+)
+
+var lessThanOrEqualDescriptor = comparisonDescriptor(
+	"Checks if the left side is less than or equal to the right side. Both sides must be numbers, strings, or times",
+	func(c int) bool { return c <= 0 },
+)
+
+var containsDescriptor = evaluatedDescriptor(
+	"Checks if an array contains an element, an object contains a key, or a string contains a substring",
+	[]fn.Param{
+		{Name: "collection", Type: fn.TypeString | fn.TypeArray | fn.TypeObject},
+		{Name: "value", Type: fn.TypeAny},
+	}, 2, fn.TypeBoolean,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		switch c := args.values[0].(type) {
+		case []interface{}:
+			for _, v := range c {
+				if an, ok := toNumber(v); ok {
+					// Numbers may be decoded as different types depending on
+					// where they came from, so compare them by value.
+					if bn, ok := toNumber(args.values[1]); ok && an == bn {
+						return true, nil
+					}
+				} else if reflect.DeepEqual(v, args.values[1]) {
+					return true, nil
+				}
+			}
+
+			return false, nil
+		case map[string]interface{}:
+			k, err := args.stringAt(1)
+			if err != nil {
+				return nil, err
+			}
+
+			_, found := c[k]
+			return found, nil
+		case string:
+			s, err := args.stringAt(1)
+			if err != nil {
+				return nil, err
+			}
+
+			return strings.Contains(c, s), nil
+		default:
+			return nil, args.errorAt(0, &fn.UnexpectedTypeError{
+				Wanted: []reflect.Type{
+					reflect.TypeOf([]interface{}(nil)),
+					reflect.TypeOf(map[string]interface{}(nil)),
+					reflect.TypeOf(""),
+				},
+				Got: reflect.TypeOf(c),
+			})
+		}
+	},
+)
@@ -0,0 +1,306 @@
+package fnlib_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/puppetlabs/relay-core/pkg/expr/evaluate"
+	"github.com/puppetlabs/relay-core/pkg/expr/fn"
+	"github.com/puppetlabs/relay-core/pkg/expr/model"
+	"github.com/puppetlabs/relay-core/pkg/expr/resolve"
+	"github.com/puppetlabs/relay-core/pkg/expr/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogicFuncs(t *testing.T) {
+	ref := time.Date(2020, time.October, 7, 15, 4, 5, 0, time.UTC)
+
+	tests := []stringFuncTest{
+		{
+			Name:           "not",
+			Func:           "not",
+			PositionalArgs: []interface{}{true},
+			KeywordArgs:    map[string]interface{}{"value": true},
+			Expected:       false,
+		},
+		{
+			Name:           "not with non-boolean",
+			Func:           "not",
+			PositionalArgs: []interface{}{"true"},
+			ExpectedPositionalError: &fn.PositionalArgError{
+				Arg: 1,
+				Cause: &fn.UnexpectedTypeError{
+					Wanted: []reflect.Type{reflect.TypeOf(false)},
+					Got:    reflect.TypeOf(""),
+				},
+			},
+		},
+		{
+			Name:           "greaterThan",
+			Func:           "greaterThan",
+			PositionalArgs: []interface{}{3, float64(2.5)},
+			KeywordArgs:    map[string]interface{}{"left": 3, "right": float64(2.5)},
+			Expected:       true,
+		},
+		{
+			Name:           "greaterThanOrEqual",
+			Func:           "greaterThanOrEqual",
+			PositionalArgs: []interface{}{int64(2), float64(2)},
+			Expected:       true,
+		},
+		{
+			Name:           "lessThan with strings",
+			Func:           "lessThan",
+			PositionalArgs: []interface{}{"apple", "banana"},
+			Expected:       true,
+		},
+		{
+			Name:           "lessThanOrEqual with times",
+			Func:           "lessThanOrEqual",
+			PositionalArgs: []interface{}{ref.Add(time.Second), ref},
+			Expected:       false,
+		},
+		{
+			Name:           "lessThan with mismatched types",
+			Func:           "lessThan",
+			PositionalArgs: []interface{}{1, "2"},
+			KeywordArgs:    map[string]interface{}{"left": 1, "right": "2"},
+			ExpectedPositionalError: &fn.PositionalArgError{
+				Arg: 2,
+				Cause: &fn.UnexpectedTypeError{
+					Wanted: []reflect.Type{
+						reflect.TypeOf(int(0)),
+						reflect.TypeOf(int64(0)),
+						reflect.TypeOf(float64(0)),
+					},
+					Got: reflect.TypeOf(""),
+				},
+			},
+			ExpectedKeywordError: &fn.KeywordArgError{
+				Arg: "right",
+				Cause: &fn.UnexpectedTypeError{
+					Wanted: []reflect.Type{
+						reflect.TypeOf(int(0)),
+						reflect.TypeOf(int64(0)),
+						reflect.TypeOf(float64(0)),
+					},
+					Got: reflect.TypeOf(""),
+				},
+			},
+		},
+		{
+			Name:           "contains in array",
+			Func:           "contains",
+			PositionalArgs: []interface{}{[]interface{}{"main", float64(1)}, 1},
+			KeywordArgs:    map[string]interface{}{"collection": []interface{}{"main", float64(1)}, "value": 1},
+			Expected:       true,
+		},
+		{
+			Name:           "contains in object",
+			Func:           "contains",
+			PositionalArgs: []interface{}{map[string]interface{}{"main": true}, "develop"},
+			Expected:       false,
+		},
+		{
+			Name:           "contains in string",
+			Func:           "contains",
+			PositionalArgs: []interface{}{"refs/heads/main", "heads"},
+			Expected:       true,
+		},
+		{
+			Name:           "contains in number",
+			Func:           "contains",
+			PositionalArgs: []interface{}{float64(42), float64(4)},
+			ExpectedPositionalError: &fn.PositionalArgError{
+				Arg: 1,
+				Cause: &fn.UnexpectedTypeError{
+					Wanted: []reflect.Type{
+						reflect.TypeOf([]interface{}(nil)),
+						reflect.TypeOf(map[string]interface{}(nil)),
+						reflect.TypeOf(""),
+					},
+					Got: reflect.TypeOf(float64(0)),
+				},
+			},
+		},
+		{
+			Name:           "if",
+			Func:           "if",
+			PositionalArgs: []interface{}{true, "yes", "no"},
+			KeywordArgs:    map[string]interface{}{"condition": true, "then": "yes", "else": "no"},
+			Expected:       "yes",
+		},
+		{
+			Name:           "if without else",
+			Func:           "if",
+			PositionalArgs: []interface{}{false, "yes"},
+			KeywordArgs:    map[string]interface{}{"condition": false, "then": "yes"},
+			Expected:       nil,
+		},
+		{
+			Name:           "if with non-boolean condition",
+			Func:           "if",
+			PositionalArgs: []interface{}{"true", "yes"},
+			KeywordArgs:    map[string]interface{}{"condition": "true", "then": "yes"},
+			ExpectedPositionalError: &fn.PositionalArgError{
+				Arg: 1,
+				Cause: &fn.UnexpectedTypeError{
+					Wanted: []reflect.Type{reflect.TypeOf(false)},
+					Got:    reflect.TypeOf(""),
+				},
+			},
+			ExpectedKeywordError: &fn.KeywordArgError{
+				Arg: "condition",
+				Cause: &fn.UnexpectedTypeError{
+					Wanted: []reflect.Type{reflect.TypeOf(false)},
+					Got:    reflect.TypeOf(""),
+				},
+			},
+		},
+		{
+			Name:           "and",
+			Func:           "and",
+			PositionalArgs: []interface{}{true, true},
+			Expected:       true,
+		},
+		{
+			Name:           "and without args",
+			Func:           "and",
+			PositionalArgs: []interface{}{},
+			ExpectedPositionalError: &fn.ArityError{
+				Wanted:   []int{1},
+				Variadic: true,
+				Got:      0,
+			},
+		},
+		{
+			Name:           "or",
+			Func:           "or",
+			PositionalArgs: []interface{}{false, false},
+			Expected:       false,
+		},
+		{
+			Name:           "or with non-boolean",
+			Func:           "or",
+			PositionalArgs: []interface{}{false, "true"},
+			ExpectedPositionalError: &fn.PositionalArgError{
+				Arg: 2,
+				Cause: &fn.UnexpectedTypeError{
+					Wanted: []reflect.Type{reflect.TypeOf(false)},
+					Got:    reflect.TypeOf(""),
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, test.Run)
+	}
+}
+
+type recordingSecretTypeResolver struct {
+	resolve.SecretTypeResolver
+	resolved []string
+}
+
+func (rstr *recordingSecretTypeResolver) ResolveSecret(ctx context.Context, name string) (string, error) {
+	rstr.resolved = append(rstr.resolved, name)
+	return rstr.SecretTypeResolver.ResolveSecret(ctx, name)
+}
+
+func TestLogicFuncShortCircuit(t *testing.T) {
+	tests := []struct {
+		Name                 string
+		Tree                 interface{}
+		Expected             interface{}
+		ExpectedUnresolvable model.Unresolvable
+		ExpectedResolved     []string
+	}{
+		{
+			Name: "and stops at false",
+			Tree: testutil.JSONInvocation("and", []interface{}{
+				false,
+				testutil.JSONSecret("a"),
+			}),
+			Expected: false,
+		},
+		{
+			Name: "and decided by a later false",
+			Tree: testutil.JSONInvocation("and", []interface{}{
+				testutil.JSONParameter("unknown"),
+				false,
+				testutil.JSONSecret("a"),
+			}),
+			Expected: false,
+		},
+		{
+			Name: "and undecided",
+			Tree: testutil.JSONInvocation("and", []interface{}{
+				testutil.JSONParameter("unknown"),
+				true,
+			}),
+			ExpectedUnresolvable: model.Unresolvable{Parameters: []model.UnresolvableParameter{
+				{Name: "unknown"},
+			}},
+		},
+		{
+			Name: "or stops at true",
+			Tree: testutil.JSONInvocation("or", []interface{}{
+				testutil.JSONInvocation("equals", []interface{}{testutil.JSONSecret("a"), "a-value"}),
+				testutil.JSONSecret("b"),
+			}),
+			Expected:         true,
+			ExpectedResolved: []string{"a"},
+		},
+		{
+			Name: "if takes then branch",
+			Tree: testutil.JSONInvocation("if", []interface{}{
+				true,
+				testutil.JSONSecret("a"),
+				testutil.JSONSecret("b"),
+			}),
+			Expected:         "a-value",
+			ExpectedResolved: []string{"a"},
+		},
+		{
+			Name: "if takes else branch",
+			Tree: testutil.JSONInvocation("if", map[string]interface{}{
+				"condition": false,
+				"then":      testutil.JSONSecret("a"),
+				"else":      testutil.JSONSecret("b"),
+			}),
+			Expected:         "b-value",
+			ExpectedResolved: []string{"b"},
+		},
+		{
+			Name: "if with unresolvable condition",
+			Tree: testutil.JSONInvocation("if", []interface{}{
+				testutil.JSONParameter("unknown"),
+				testutil.JSONSecret("a"),
+				testutil.JSONSecret("b"),
+			}),
+			ExpectedUnresolvable: model.Unresolvable{Parameters: []model.UnresolvableParameter{
+				{Name: "unknown"},
+			}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sr := &recordingSecretTypeResolver{
+				SecretTypeResolver: resolve.NewMemorySecretTypeResolver(map[string]string{
+					"a": "a-value",
+					"b": "b-value",
+				}),
+			}
+
+			r, err := evaluate.NewEvaluator(evaluate.WithSecretTypeResolver(sr)).EvaluateAll(context.Background(), test.Tree)
+			require.NoError(t, err)
+			require.Equal(t, test.ExpectedUnresolvable, r.Unresolvable)
+			if r.Complete() {
+				require.Equal(t, test.Expected, r.Value)
+			}
+			require.Equal(t, test.ExpectedResolved, sr.resolved)
+		})
+	}
+}
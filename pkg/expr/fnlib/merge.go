@@ -121,4 +121,22 @@ Merges are performed deeply by default. Use the keyword form and set mode: shall
 			return r, nil
 		}), nil
 	},
+	SignatureFunc: func() *fn.Signature {
+		return &fn.Signature{
+			Positional: &fn.Arguments{
+				Params: []fn.Param{
+					{Name: "object", Type: fn.TypeObject},
+				},
+				Variadic: true,
+			},
+			Keyword: &fn.Arguments{
+				Params: []fn.Param{
+					{Name: "objects", Type: fn.TypeArray},
+					{Name: "mode", Type: fn.TypeString},
+				},
+				Required: 1,
+			},
+			Returns: fn.TypeObject,
+		}
+	},
 }
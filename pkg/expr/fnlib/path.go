@@ -164,4 +164,25 @@ var pathDescriptor = fn.DescriptorFuncs{
 		})
 		return fn, nil
 	},
+	SignatureFunc: func() *fn.Signature {
+		return &fn.Signature{
+			Positional: &fn.Arguments{
+				Params: []fn.Param{
+					{Name: "object", Type: fn.TypeAny},
+					{Name: "query", Type: fn.TypeString},
+					{Name: "default", Type: fn.TypeAny},
+				},
+				Required: 2,
+			},
+			Keyword: &fn.Arguments{
+				Params: []fn.Param{
+					{Name: "object", Type: fn.TypeAny},
+					{Name: "query", Type: fn.TypeString},
+					{Name: "default", Type: fn.TypeAny},
+				},
+				Required: 2,
+			},
+			Returns: fn.TypeAny,
+		}
+	},
 }
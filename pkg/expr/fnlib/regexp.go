@@ -0,0 +1,108 @@
+package fnlib
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/puppetlabs/relay-core/pkg/expr/fn"
+)
+
+func (ea *evaluatedArgs) regexpAt(i int) (*regexp.Regexp, error) {
+	pattern, err := ea.stringAt(i)
+	if err != nil {
+		return nil, err
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, ea.errorAt(i, err)
+	}
+
+	return re, nil
+}
+
+var regexMatchDescriptor = evaluatedDescriptor(
+	"Reports whether a string contains a match of a regular expression",
+	[]fn.Param{
+		{Name: "pattern", Type: fn.TypeScalar},
+		{Name: "string", Type: fn.TypeScalar},
+	}, 2, fn.TypeBoolean,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		re, err := args.regexpAt(0)
+		if err != nil {
+			return nil, err
+		}
+
+		s, err := args.stringAt(1)
+		if err != nil {
+			return nil, err
+		}
+
+		return re.MatchString(s), nil
+	},
+)
+
+var regexReplaceDescriptor = evaluatedDescriptor(
+	`Replaces every match of a regular expression in a string with a replacement string.
+
+Within the replacement, $1 or ${1} refers to the text of the first capture group, ${name} to the text of the capture group with the given name, and so on.`,
+	[]fn.Param{
+		{Name: "pattern", Type: fn.TypeScalar},
+		{Name: "string", Type: fn.TypeScalar},
+		{Name: "replacement", Type: fn.TypeScalar},
+	}, 3, fn.TypeString,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		re, err := args.regexpAt(0)
+		if err != nil {
+			return nil, err
+		}
+
+		s, err := args.stringAt(1)
+		if err != nil {
+			return nil, err
+		}
+
+		repl, err := args.stringAt(2)
+		if err != nil {
+			return nil, err
+		}
+
+		return re.ReplaceAllString(s, repl), nil
+	},
+)
+
+var regexFindDescriptor = evaluatedDescriptor(
+	`Finds the first match of a regular expression in a string.
+
+Returns an array containing the text of the match followed by the text of each capture group, or null if the string does not match. Capture groups that do not participate in the match are null.`,
+	[]fn.Param{
+		{Name: "pattern", Type: fn.TypeScalar},
+		{Name: "string", Type: fn.TypeScalar},
+	}, 2, fn.TypeNull|fn.TypeArray,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		re, err := args.regexpAt(0)
+		if err != nil {
+			return nil, err
+		}
+
+		s, err := args.stringAt(1)
+		if err != nil {
+			return nil, err
+		}
+
+		loc := re.FindStringSubmatchIndex(s)
+		if loc == nil {
+			return nil, nil
+		}
+
+		r := make([]interface{}, len(loc)/2)
+		for i := range r {
+			if loc[2*i] < 0 {
+				continue
+			}
+
+			r[i] = s[loc[2*i]:loc[2*i+1]]
+		}
+		return r, nil
+	},
+)
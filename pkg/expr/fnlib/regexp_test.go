@@ -0,0 +1,83 @@
+package fnlib_test
+
+import (
+	"context"
+	"regexp/syntax"
+	"testing"
+
+	"github.com/puppetlabs/relay-core/pkg/expr/evaluate"
+	"github.com/puppetlabs/relay-core/pkg/expr/fn"
+	"github.com/puppetlabs/relay-core/pkg/expr/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegexFuncs(t *testing.T) {
+	tests := []stringFuncTest{
+		{
+			Name:           "regexMatch",
+			Func:           "regexMatch",
+			PositionalArgs: []interface{}{`^v\d+\.\d+\.\d+$`, "v1.2.3"},
+			KeywordArgs:    map[string]interface{}{"pattern": `^v\d+\.\d+\.\d+$`, "string": "v1.2.3"},
+			Expected:       true,
+		},
+		{
+			Name:           "regexMatch without match",
+			Func:           "regexMatch",
+			PositionalArgs: []interface{}{`^v\d+\.\d+\.\d+$`, "main"},
+			Expected:       false,
+		},
+		{
+			Name:           "regexMatch with invalid pattern",
+			Func:           "regexMatch",
+			PositionalArgs: []interface{}{`(`, "main"},
+			KeywordArgs:    map[string]interface{}{"pattern": `(`, "string": "main"},
+			ExpectedPositionalError: &fn.PositionalArgError{
+				Arg:   1,
+				Cause: &syntax.Error{Code: syntax.ErrMissingParen, Expr: `(`},
+			},
+			ExpectedKeywordError: &fn.KeywordArgError{
+				Arg:   "pattern",
+				Cause: &syntax.Error{Code: syntax.ErrMissingParen, Expr: `(`},
+			},
+		},
+		{
+			Name:           "regexReplace",
+			Func:           "regexReplace",
+			PositionalArgs: []interface{}{`^refs/(heads|tags)/(?P<name>.*)$`, "refs/tags/v1", "${1}:${name}"},
+			KeywordArgs: map[string]interface{}{
+				"pattern":     `^refs/(heads|tags)/(?P<name>.*)$`,
+				"string":      "refs/tags/v1",
+				"replacement": "${1}:${name}",
+			},
+			Expected: "tags:v1",
+		},
+		{
+			Name:           "regexFind",
+			Func:           "regexFind",
+			PositionalArgs: []interface{}{`(\d+)\.(\d+)(\.(\d+))?`, "release 1.2 is out"},
+			KeywordArgs:    map[string]interface{}{"pattern": `(\d+)\.(\d+)(\.(\d+))?`, "string": "release 1.2 is out"},
+			Expected:       []interface{}{"1.2", "1", "2", nil, nil},
+		},
+		{
+			Name:           "regexFind without match",
+			Func:           "regexFind",
+			PositionalArgs: []interface{}{`\d+`, "no numbers"},
+			Expected:       nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, test.Run)
+	}
+}
+
+func TestRegexFuncInvocationError(t *testing.T) {
+	r, err := evaluate.NewEvaluator().EvaluateAll(context.Background(), testutil.JSONInvocation("regexMatch", []interface{}{"(", "main"}))
+	require.Nil(t, r)
+	require.Equal(t, &evaluate.InvocationError{
+		Name: "regexMatch",
+		Cause: &fn.PositionalArgError{
+			Arg:   1,
+			Cause: &syntax.Error{Code: syntax.ErrMissingParen, Expr: `(`},
+		},
+	}, err)
+}
@@ -0,0 +1,241 @@
+package fnlib
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/puppetlabs/relay-core/pkg/expr/fn"
+	"github.com/puppetlabs/relay-core/pkg/expr/model"
+)
+
+var splitDescriptor = evaluatedDescriptor(
+	"Splits a string into an array of substrings separated by a separator",
+	[]fn.Param{
+		{Name: "string", Type: fn.TypeScalar},
+		{Name: "separator", Type: fn.TypeScalar},
+	}, 2, fn.TypeArray,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		s, err := args.stringAt(0)
+		if err != nil {
+			return nil, err
+		}
+
+		sep, err := args.stringAt(1)
+		if err != nil {
+			return nil, err
+		}
+
+		parts := strings.Split(s, sep)
+
+		r := make([]interface{}, len(parts))
+		for i, part := range parts {
+			r[i] = part
+		}
+		return r, nil
+	},
+)
+
+var joinDescriptor = evaluatedDescriptor(
+	"Joins an array of strings into a single string, placing a separator between each element",
+	[]fn.Param{
+		{Name: "strings", Type: fn.TypeArray},
+		{Name: "separator", Type: fn.TypeScalar},
+	}, 2, fn.TypeString,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		elems, err := args.arrayAt(0)
+		if err != nil {
+			return nil, err
+		}
+
+		sep, err := args.stringAt(1)
+		if err != nil {
+			return nil, err
+		}
+
+		strs := make([]string, len(elems))
+		for i, elem := range elems {
+			strs[i], err = toString(elem)
+			if err != nil {
+				return nil, args.errorAt(0, err)
+			}
+		}
+
+		return strings.Join(strs, sep), nil
+	},
+)
+
+func stringTransformDescriptor(description string, names []string, f func(args []string) string) fn.Descriptor {
+	params := make([]fn.Param, len(names))
+	for i, name := range names {
+		params[i] = fn.Param{Name: name, Type: fn.TypeScalar}
+	}
+
+	return evaluatedDescriptor(description, params, len(params), fn.TypeString, func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		strs := make([]string, len(names))
+		for i := range strs {
+			s, err := args.stringAt(i)
+			if err != nil {
+				return nil, err
+			}
+
+			strs[i] = s
+		}
+
+		return f(strs), nil
+	})
+}
+
+var replaceDescriptor = stringTransformDescriptor(
+	"Replaces every occurrence of a substring in a string with another string",
+	[]string{"string", "old", "new"},
+	func(args []string) string { return strings.ReplaceAll(args[0], args[1], args[2]) },
+)
+
+var trimPrefixDescriptor = stringTransformDescriptor(
+	"Removes a prefix from a string, if present",
+	[]string{"string", "prefix"},
+	func(args []string) string { return strings.TrimPrefix(args[0], args[1]) },
+)
+
+var trimSuffixDescriptor = stringTransformDescriptor(
+	"Removes a suffix from a string, if present",
+	[]string{"string", "suffix"},
+	func(args []string) string { return strings.TrimSuffix(args[0], args[1]) },
+)
+
+var upperDescriptor = stringTransformDescriptor(
+	"Converts a string to upper case",
+	[]string{"string"},
+	func(args []string) string { return strings.ToUpper(args[0]) },
+)
+
+var lowerDescriptor = stringTransformDescriptor(
+	"Converts a string to lower case",
+	[]string{"string"},
+	func(args []string) string { return strings.ToLower(args[0]) },
+)
+
+// substringIndex converts a possibly negative character index into an offset
+// from the start of a string of the given length, clamping it to the bounds of
+// the string.
+func substringIndex(i, length int) int {
+	if i < 0 {
+		i += length
+	}
+
+	if i < 0 {
+		return 0
+	} else if i > length {
+		return length
+	}
+
+	return i
+}
+
+var substringDescriptor = evaluatedDescriptor(
+	`Returns the characters of a string from a start index up to, but not including, an optional end index.
+
+Negative indices count back from the end of the string. Indices beyond the bounds of the string are clamped to the string.`,
+	[]fn.Param{
+		{Name: "string", Type: fn.TypeScalar},
+		{Name: "start", Type: fn.TypeNumber},
+		{Name: "end", Type: fn.TypeNumber},
+	}, 2, fn.TypeString,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		s, err := args.stringAt(0)
+		if err != nil {
+			return nil, err
+		}
+
+		rs := []rune(s)
+
+		start, err := args.intAt(1)
+		if err != nil {
+			return nil, err
+		}
+		start = substringIndex(start, len(rs))
+
+		end := len(rs)
+		if args.hasAt(2) {
+			end, err = args.intAt(2)
+			if err != nil {
+				return nil, err
+			}
+			end = substringIndex(end, len(rs))
+		}
+
+		if start >= end {
+			return "", nil
+		}
+
+		return string(rs[start:end]), nil
+	},
+)
+
+var formatDescriptor = fn.DescriptorFuncs{
+	DescriptionFunc: func() string {
+		return "Formats its arguments according to a printf-style format string"
+	},
+	PositionalInvokerFunc: func(args []model.Evaluable) (fn.Invoker, error) {
+		if len(args) == 0 {
+			return nil, &fn.ArityError{Wanted: []int{1}, Variadic: true, Got: len(args)}
+		}
+
+		return fn.EvaluatedPositionalInvoker(args, func(ctx context.Context, args []interface{}) (interface{}, error) {
+			f, err := toString(args[0])
+			if err != nil {
+				return nil, &fn.PositionalArgError{
+					Arg:   1,
+					Cause: err,
+				}
+			}
+
+			return fmt.Sprintf(f, args[1:]...), nil
+		}), nil
+	},
+	KeywordInvokerFunc: func(args map[string]model.Evaluable) (fn.Invoker, error) {
+		if _, found := args["format"]; !found {
+			return nil, &fn.KeywordArgError{Arg: "format", Cause: fn.ErrArgNotFound}
+		}
+
+		return fn.EvaluatedKeywordInvoker(args, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			ea := newKeywordEvaluatedArgs([]string{"format", "args"}, args)
+
+			f, err := ea.stringAt(0)
+			if err != nil {
+				return nil, err
+			}
+
+			var values []interface{}
+			if ea.hasAt(1) {
+				values, err = ea.arrayAt(1)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			return fmt.Sprintf(f, values...), nil
+		}), nil
+	},
+	SignatureFunc: func() *fn.Signature {
+		return &fn.Signature{
+			Positional: &fn.Arguments{
+				Params: []fn.Param{
+					{Name: "format", Type: fn.TypeScalar},
+					{Name: "args", Type: fn.TypeAny},
+				},
+				Required: 1,
+				Variadic: true,
+			},
+			Keyword: &fn.Arguments{
+				Params: []fn.Param{
+					{Name: "format", Type: fn.TypeScalar},
+					{Name: "args", Type: fn.TypeArray},
+				},
+				Required: 1,
+			},
+			Returns: fn.TypeString,
+		}
+	},
+}
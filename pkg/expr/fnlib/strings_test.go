@@ -0,0 +1,264 @@
+package fnlib_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/puppetlabs/relay-core/pkg/expr/fn"
+	"github.com/puppetlabs/relay-core/pkg/expr/fnlib"
+	"github.com/puppetlabs/relay-core/pkg/expr/model"
+	"github.com/stretchr/testify/require"
+)
+
+type stringFuncTest struct {
+	Name                    string
+	Func                    string
+	PositionalArgs          []interface{}
+	KeywordArgs             map[string]interface{}
+	Expected                interface{}
+	ExpectedPositionalError error
+	ExpectedKeywordError    error
+}
+
+func (tt stringFuncTest) Run(t *testing.T) {
+	desc, err := fnlib.Library().Descriptor(tt.Func)
+	require.NoError(t, err)
+
+	if tt.PositionalArgs != nil {
+		t.Run("positional", func(t *testing.T) {
+			args := make([]model.Evaluable, len(tt.PositionalArgs))
+			for i, arg := range tt.PositionalArgs {
+				args[i] = model.StaticEvaluable(arg)
+			}
+
+			invoker, err := desc.PositionalInvoker(args)
+			if err == nil {
+				var r *model.Result
+				r, err = invoker.Invoke(context.Background())
+				if err == nil {
+					require.True(t, r.Complete())
+					require.Equal(t, tt.Expected, r.Value)
+				}
+			}
+			require.Equal(t, tt.ExpectedPositionalError, err)
+		})
+	}
+
+	if tt.KeywordArgs != nil {
+		t.Run("keyword", func(t *testing.T) {
+			args := make(map[string]model.Evaluable, len(tt.KeywordArgs))
+			for name, arg := range tt.KeywordArgs {
+				args[name] = model.StaticEvaluable(arg)
+			}
+
+			invoker, err := desc.KeywordInvoker(args)
+			if err == nil {
+				var r *model.Result
+				r, err = invoker.Invoke(context.Background())
+				if err == nil {
+					require.True(t, r.Complete())
+					require.Equal(t, tt.Expected, r.Value)
+				}
+			}
+			require.Equal(t, tt.ExpectedKeywordError, err)
+		})
+	}
+}
+
+func TestStringFuncs(t *testing.T) {
+	tests := []stringFuncTest{
+		{
+			Name:           "split",
+			Func:           "split",
+			PositionalArgs: []interface{}{"refs/heads/main", "/"},
+			KeywordArgs:    map[string]interface{}{"string": "refs/heads/main", "separator": "/"},
+			Expected:       []interface{}{"refs", "heads", "main"},
+		},
+		{
+			Name:           "split without separator",
+			Func:           "split",
+			PositionalArgs: []interface{}{"main"},
+			KeywordArgs:    map[string]interface{}{"string": "main"},
+			ExpectedPositionalError: &fn.ArityError{
+				Wanted: []int{2},
+				Got:    1,
+			},
+			ExpectedKeywordError: &fn.KeywordArgError{
+				Arg:   "separator",
+				Cause: fn.ErrArgNotFound,
+			},
+		},
+		{
+			Name:           "join",
+			Func:           "join",
+			PositionalArgs: []interface{}{[]interface{}{"a", 1, true}, ", "},
+			KeywordArgs:    map[string]interface{}{"strings": []interface{}{"a", 1, true}, "separator": ", "},
+			Expected:       "a, 1, true",
+		},
+		{
+			Name:           "join with non-array",
+			Func:           "join",
+			PositionalArgs: []interface{}{"a", ", "},
+			KeywordArgs:    map[string]interface{}{"strings": "a", "separator": ", "},
+			ExpectedPositionalError: &fn.PositionalArgError{
+				Arg: 1,
+				Cause: &fn.UnexpectedTypeError{
+					Wanted: []reflect.Type{reflect.TypeOf([]interface{}(nil))},
+					Got:    reflect.TypeOf(""),
+				},
+			},
+			ExpectedKeywordError: &fn.KeywordArgError{
+				Arg: "strings",
+				Cause: &fn.UnexpectedTypeError{
+					Wanted: []reflect.Type{reflect.TypeOf([]interface{}(nil))},
+					Got:    reflect.TypeOf(""),
+				},
+			},
+		},
+		{
+			Name:           "replace",
+			Func:           "replace",
+			PositionalArgs: []interface{}{"feature/foo/bar", "/", "-"},
+			KeywordArgs:    map[string]interface{}{"string": "feature/foo/bar", "old": "/", "new": "-"},
+			Expected:       "feature-foo-bar",
+		},
+		{
+			Name:           "trimPrefix",
+			Func:           "trimPrefix",
+			PositionalArgs: []interface{}{"refs/heads/main", "refs/heads/"},
+			KeywordArgs:    map[string]interface{}{"string": "refs/heads/main", "prefix": "refs/heads/"},
+			Expected:       "main",
+		},
+		{
+			Name:           "trimPrefix without prefix",
+			Func:           "trimPrefix",
+			PositionalArgs: []interface{}{"main", "refs/heads/"},
+			Expected:       "main",
+		},
+		{
+			Name:           "trimSuffix",
+			Func:           "trimSuffix",
+			PositionalArgs: []interface{}{"image.tar.gz", ".gz"},
+			KeywordArgs:    map[string]interface{}{"string": "image.tar.gz", "suffix": ".gz"},
+			Expected:       "image.tar",
+		},
+		{
+			Name:           "upper",
+			Func:           "upper",
+			PositionalArgs: []interface{}{"Hello"},
+			KeywordArgs:    map[string]interface{}{"string": "Hello"},
+			Expected:       "HELLO",
+		},
+		{
+			Name:           "lower",
+			Func:           "lower",
+			PositionalArgs: []interface{}{"Hello"},
+			KeywordArgs:    map[string]interface{}{"string": "Hello"},
+			Expected:       "hello",
+		},
+		{
+			Name:           "lower with non-string",
+			Func:           "lower",
+			PositionalArgs: []interface{}{map[string]interface{}{}},
+			ExpectedPositionalError: &fn.PositionalArgError{
+				Arg: 1,
+				Cause: &fn.UnexpectedTypeError{
+					Wanted: []reflect.Type{
+						reflect.TypeOf(nil),
+						reflect.TypeOf(""),
+						reflect.TypeOf([]byte(nil)),
+						reflect.TypeOf(time.Time{}),
+						reflect.TypeOf(int(0)),
+						reflect.TypeOf(int64(0)),
+						reflect.TypeOf(float64(0)),
+						reflect.TypeOf(false),
+					},
+					Got: reflect.TypeOf(map[string]interface{}{}),
+				},
+			},
+		},
+		{
+			Name:           "substring",
+			Func:           "substring",
+			PositionalArgs: []interface{}{"0123456789abcdef", 0, 7},
+			KeywordArgs:    map[string]interface{}{"string": "0123456789abcdef", "start": 0, "end": 7},
+			Expected:       "0123456",
+		},
+		{
+			Name:           "substring without end",
+			Func:           "substring",
+			PositionalArgs: []interface{}{"héllo", float64(1)},
+			KeywordArgs:    map[string]interface{}{"string": "héllo", "start": float64(1)},
+			Expected:       "éllo",
+		},
+		{
+			Name:           "substring with negative indices",
+			Func:           "substring",
+			PositionalArgs: []interface{}{"v1.2.3", -5, -2},
+			Expected:       "1.2",
+		},
+		{
+			Name:           "substring out of bounds",
+			Func:           "substring",
+			PositionalArgs: []interface{}{"abc", 1, 100},
+			KeywordArgs:    map[string]interface{}{"string": "abc", "start": 1, "end": 100},
+			Expected:       "bc",
+		},
+		{
+			Name:           "substring with start after end",
+			Func:           "substring",
+			PositionalArgs: []interface{}{"abc", 2, 1},
+			Expected:       "",
+		},
+		{
+			Name:           "substring with fractional index",
+			Func:           "substring",
+			PositionalArgs: []interface{}{"abc", 1.5},
+			ExpectedPositionalError: &fn.PositionalArgError{
+				Arg: 2,
+				Cause: &fn.UnexpectedTypeError{
+					Wanted: []reflect.Type{
+						reflect.TypeOf(int(0)),
+						reflect.TypeOf(int64(0)),
+						reflect.TypeOf(float64(0)),
+					},
+					Got: reflect.TypeOf(float64(0)),
+				},
+			},
+		},
+		{
+			Name:           "format",
+			Func:           "format",
+			PositionalArgs: []interface{}{"%s-%v", "build", 42},
+			KeywordArgs:    map[string]interface{}{"format": "%s-%v", "args": []interface{}{"build", 42}},
+			Expected:       "build-42",
+		},
+		{
+			Name:           "format without args",
+			Func:           "format",
+			PositionalArgs: []interface{}{"100%%"},
+			KeywordArgs:    map[string]interface{}{"format": "100%%"},
+			Expected:       "100%",
+		},
+		{
+			Name:           "format without format",
+			Func:           "format",
+			PositionalArgs: []interface{}{},
+			KeywordArgs:    map[string]interface{}{},
+			ExpectedPositionalError: &fn.ArityError{
+				Wanted:   []int{1},
+				Variadic: true,
+				Got:      0,
+			},
+			ExpectedKeywordError: &fn.KeywordArgError{
+				Arg:   "format",
+				Cause: fn.ErrArgNotFound,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, test.Run)
+	}
+}
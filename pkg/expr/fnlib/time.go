@@ -0,0 +1,316 @@
+package fnlib
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/puppetlabs/relay-core/pkg/expr/fn"
+	"github.com/puppetlabs/relay-core/pkg/expr/model"
+)
+
+type timeContextKey int
+
+const (
+	nowTimeContextKey timeContextKey = iota
+)
+
+// NewContextWithTime returns a context in which the now function always
+// returns the given time. Callers should use it to make sure every use of now
+// in a single evaluation agrees.
+func NewContextWithTime(ctx context.Context, t time.Time) context.Context {
+	return context.WithValue(ctx, nowTimeContextKey, t)
+}
+
+// TimeFromContext returns the time stored in the given context.
+//
+// If none is set, the current time is returned instead so that expressions
+// using now can still be evaluated by callers that don't care about a
+// consistent time, like command-line tools. In that case, each use of now
+// returns the time at which it is evaluated, and uses of now in the same
+// evaluation may not agree. The metadata API always sets a time for each
+// request.
+func TimeFromContext(ctx context.Context) time.Time {
+	t, ok := ctx.Value(nowTimeContextKey).(time.Time)
+	if !ok {
+		return time.Now()
+	}
+
+	return t
+}
+
+// timeLayouts are the names of well-known layouts that may be used in place of
+// a reference layout when parsing or formatting times.
+var timeLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"Date":        "2006-01-02",
+}
+
+func (ea *evaluatedArgs) layoutAt(i int) (string, error) {
+	if !ea.hasAt(i) {
+		return time.RFC3339, nil
+	}
+
+	layout, err := ea.stringAt(i)
+	if err != nil {
+		return "", err
+	}
+
+	if named, found := timeLayouts[layout]; found {
+		layout = named
+	}
+
+	return layout, nil
+}
+
+func (ea *evaluatedArgs) timeAt(i int) (time.Time, error) {
+	switch v := ea.values[i].(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, ea.errorAt(i, err)
+		}
+
+		return t, nil
+	default:
+		return time.Time{}, ea.errorAt(i, &fn.UnexpectedTypeError{
+			Wanted: []reflect.Type{
+				reflect.TypeOf(time.Time{}),
+				reflect.TypeOf(""),
+			},
+			Got: reflect.TypeOf(v),
+		})
+	}
+}
+
+func (ea *evaluatedArgs) durationAt(i int) (time.Duration, error) {
+	switch v := ea.values[i].(type) {
+	case string:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return 0, ea.errorAt(i, err)
+		}
+
+		return d, nil
+	case int:
+		return time.Duration(v) * time.Second, nil
+	case int64:
+		return time.Duration(v) * time.Second, nil
+	case float64:
+		return time.Duration(v * float64(time.Second)), nil
+	default:
+		return 0, ea.errorAt(i, &fn.UnexpectedTypeError{
+			Wanted: []reflect.Type{
+				reflect.TypeOf(""),
+				reflect.TypeOf(int(0)),
+				reflect.TypeOf(int64(0)),
+				reflect.TypeOf(float64(0)),
+			},
+			Got: reflect.TypeOf(v),
+		})
+	}
+}
+
+type TimeUnitError struct {
+	Unit string
+}
+
+func (e *TimeUnitError) Error() string {
+	return fmt.Sprintf("fnlib: unknown time unit %q", e.Unit)
+}
+
+func truncateTime(t time.Time, unit string) (time.Time, error) {
+	switch strings.ToLower(unit) {
+	case "second":
+		return t.Truncate(time.Second), nil
+	case "minute":
+		return t.Truncate(time.Minute), nil
+	case "hour":
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location()), nil
+	case "day":
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()), nil
+	case "week":
+		// Weeks start on Monday.
+		days := (int(t.Weekday()) + 6) % 7
+		return time.Date(t.Year(), t.Month(), t.Day()-days, 0, 0, 0, 0, t.Location()), nil
+	case "month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()), nil
+	case "year":
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location()), nil
+	}
+
+	d, err := time.ParseDuration(unit)
+	if err != nil || d <= 0 {
+		return time.Time{}, &TimeUnitError{Unit: unit}
+	}
+
+	return t.Truncate(d), nil
+}
+
+func now(ctx context.Context) (*model.Result, error) {
+	return &model.Result{Value: TimeFromContext(ctx).UTC()}, nil
+}
+
+var nowDescriptor = fn.DescriptorFuncs{
+	DescriptionFunc: func() string {
+		return "Returns the current time in UTC. Every use of now in a single request returns the same time"
+	},
+	PositionalInvokerFunc: func(args []model.Evaluable) (fn.Invoker, error) {
+		if len(args) != 0 {
+			return nil, &fn.ArityError{Wanted: []int{0}, Got: len(args)}
+		}
+
+		return fn.InvokerFunc(now), nil
+	},
+	KeywordInvokerFunc: func(args map[string]model.Evaluable) (fn.Invoker, error) {
+		if len(args) != 0 {
+			return nil, &fn.ArityError{Wanted: []int{0}, Got: len(args)}
+		}
+
+		return fn.InvokerFunc(now), nil
+	},
+	SignatureFunc: func() *fn.Signature {
+		return &fn.Signature{
+			Positional: &fn.Arguments{},
+			Keyword:    &fn.Arguments{},
+			Returns:    fn.TypeTime,
+		}
+	},
+}
+
+var parseTimeDescriptor = evaluatedDescriptor(
+	`Parses a string as a time.
+
+The layout defaults to RFC3339. It may be the name of a well-known layout, like RFC1123 or Date, or a reference layout describing how the time Mon Jan 2 15:04:05 MST 2006 would be written.`,
+	[]fn.Param{
+		{Name: "string", Type: fn.TypeScalar},
+		{Name: "layout", Type: fn.TypeScalar},
+	}, 1, fn.TypeTime,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		s, err := args.stringAt(0)
+		if err != nil {
+			return nil, err
+		}
+
+		layout, err := args.layoutAt(1)
+		if err != nil {
+			return nil, err
+		}
+
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			return nil, args.errorAt(0, err)
+		}
+
+		return t, nil
+	},
+)
+
+var formatTimeDescriptor = evaluatedDescriptor(
+	`Formats a time as a string.
+
+The layout defaults to RFC3339. It may be the name of a well-known layout, like RFC1123 or Date, or a reference layout describing how the time Mon Jan 2 15:04:05 MST 2006 would be written.`,
+	[]fn.Param{
+		{Name: "time", Type: fn.TypeTime | fn.TypeString},
+		{Name: "layout", Type: fn.TypeScalar},
+	}, 1, fn.TypeString,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		t, err := args.timeAt(0)
+		if err != nil {
+			return nil, err
+		}
+
+		layout, err := args.layoutAt(1)
+		if err != nil {
+			return nil, err
+		}
+
+		return t.Format(layout), nil
+	},
+)
+
+var addTimeDescriptor = evaluatedDescriptor(
+	`Adds a duration to a time.
+
+The duration is either a number of seconds or a string like 1h30m. Negative durations subtract from the time.`,
+	[]fn.Param{
+		{Name: "time", Type: fn.TypeTime | fn.TypeString},
+		{Name: "duration", Type: fn.TypeNumber | fn.TypeString},
+	}, 2, fn.TypeTime,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		t, err := args.timeAt(0)
+		if err != nil {
+			return nil, err
+		}
+
+		d, err := args.durationAt(1)
+		if err != nil {
+			return nil, err
+		}
+
+		return t.Add(d), nil
+	},
+)
+
+var diffTimeDescriptor = evaluatedDescriptor(
+	"Returns the number of seconds elapsed from the second time to the first time",
+	[]fn.Param{
+		{Name: "end", Type: fn.TypeTime | fn.TypeString},
+		{Name: "start", Type: fn.TypeTime | fn.TypeString},
+	}, 2, fn.TypeNumber,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		end, err := args.timeAt(0)
+		if err != nil {
+			return nil, err
+		}
+
+		start, err := args.timeAt(1)
+		if err != nil {
+			return nil, err
+		}
+
+		return end.Sub(start).Seconds(), nil
+	},
+)
+
+var truncateTimeDescriptor = evaluatedDescriptor(
+	`Rounds a time down to the start of a unit.
+
+The unit is one of second, minute, hour, day, week, month, or year, or a duration like 15m. Weeks start on Monday.`,
+	[]fn.Param{
+		{Name: "time", Type: fn.TypeTime | fn.TypeString},
+		{Name: "unit", Type: fn.TypeScalar},
+	}, 2, fn.TypeTime,
+	func(ctx context.Context, args *evaluatedArgs) (interface{}, error) {
+		t, err := args.timeAt(0)
+		if err != nil {
+			return nil, err
+		}
+
+		unit, err := args.stringAt(1)
+		if err != nil {
+			return nil, err
+		}
+
+		t, err = truncateTime(t, unit)
+		if err != nil {
+			return nil, args.errorAt(1, err)
+		}
+
+		return t, nil
+	},
+)
@@ -0,0 +1,203 @@
+package fnlib_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/puppetlabs/relay-core/pkg/expr/evaluate"
+	"github.com/puppetlabs/relay-core/pkg/expr/fn"
+	"github.com/puppetlabs/relay-core/pkg/expr/fnlib"
+	"github.com/puppetlabs/relay-core/pkg/expr/model"
+	"github.com/puppetlabs/relay-core/pkg/expr/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNow(t *testing.T) {
+	now := time.Date(2020, time.October, 1, 12, 30, 0, 0, time.FixedZone("PDT", -7*60*60))
+	ctx := fnlib.NewContextWithTime(context.Background(), now)
+
+	desc, err := fnlib.Library().Descriptor("now")
+	require.NoError(t, err)
+
+	invoker, err := desc.PositionalInvoker(nil)
+	require.NoError(t, err)
+
+	r, err := invoker.Invoke(ctx)
+	require.NoError(t, err)
+	require.Equal(t, now.UTC(), r.Value)
+
+	// Every use of now in the same evaluation should agree.
+	r, err = evaluate.NewEvaluator().EvaluateAll(ctx, map[string]interface{}{
+		"a": testutil.JSONInvocation("now", []interface{}{}),
+		"b": testutil.JSONInvocation("formatTime", []interface{}{
+			testutil.JSONInvocation("now", []interface{}{}),
+			"2006.01.02",
+		}),
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"a": now.UTC(),
+		"b": "2020.10.01",
+	}, r.Value)
+
+	_, err = desc.PositionalInvoker([]model.Evaluable{model.StaticEvaluable("foo")})
+	require.Equal(t, &fn.ArityError{Wanted: []int{0}, Got: 1}, err)
+}
+
+func TestNowWithoutContextTime(t *testing.T) {
+	ctx := context.Background()
+
+	desc, err := fnlib.Library().Descriptor("now")
+	require.NoError(t, err)
+
+	invoker, err := desc.PositionalInvoker(nil)
+	require.NoError(t, err)
+
+	// Without a time in the context, now falls back to the current time.
+	before := time.Now().UTC()
+	r, err := invoker.Invoke(ctx)
+	after := time.Now().UTC()
+	require.NoError(t, err)
+
+	got, ok := r.Value.(time.Time)
+	require.True(t, ok)
+	require.Equal(t, time.UTC, got.Location())
+	require.False(t, got.Before(before))
+	require.False(t, got.After(after))
+
+	require.False(t, fnlib.TimeFromContext(ctx).Before(after))
+}
+
+func TestTimeFuncs(t *testing.T) {
+	ref := time.Date(2020, time.October, 7, 15, 4, 5, 0, time.UTC)
+
+	tests := []stringFuncTest{
+		{
+			Name:           "parseTime",
+			Func:           "parseTime",
+			PositionalArgs: []interface{}{"2020-10-07T15:04:05Z"},
+			KeywordArgs:    map[string]interface{}{"string": "2020-10-07T15:04:05Z"},
+			Expected:       ref,
+		},
+		{
+			Name:           "parseTime with named layout",
+			Func:           "parseTime",
+			PositionalArgs: []interface{}{"2020-10-07", "Date"},
+			KeywordArgs:    map[string]interface{}{"string": "2020-10-07", "layout": "Date"},
+			Expected:       time.Date(2020, time.October, 7, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Name:           "parseTime with reference layout",
+			Func:           "parseTime",
+			PositionalArgs: []interface{}{"07/10/2020 15:04", "02/01/2006 15:04"},
+			Expected:       time.Date(2020, time.October, 7, 15, 4, 0, 0, time.UTC),
+		},
+		{
+			Name:           "parseTime with invalid time",
+			Func:           "parseTime",
+			PositionalArgs: []interface{}{"yesterday"},
+			ExpectedPositionalError: &fn.PositionalArgError{
+				Arg: 1,
+				Cause: &time.ParseError{
+					Layout:     time.RFC3339,
+					Value:      "yesterday",
+					LayoutElem: "2006",
+					ValueElem:  "yesterday",
+				},
+			},
+		},
+		{
+			Name:           "formatTime",
+			Func:           "formatTime",
+			PositionalArgs: []interface{}{ref},
+			KeywordArgs:    map[string]interface{}{"time": ref},
+			Expected:       "2020-10-07T15:04:05Z",
+		},
+		{
+			Name:           "formatTime with string",
+			Func:           "formatTime",
+			PositionalArgs: []interface{}{"2020-10-07T15:04:05Z", "v2006.01.02-150405"},
+			KeywordArgs:    map[string]interface{}{"time": "2020-10-07T15:04:05Z", "layout": "v2006.01.02-150405"},
+			Expected:       "v2020.10.07-150405",
+		},
+		{
+			Name:           "formatTime with non-time",
+			Func:           "formatTime",
+			PositionalArgs: []interface{}{true},
+			ExpectedPositionalError: &fn.PositionalArgError{
+				Arg: 1,
+				Cause: &fn.UnexpectedTypeError{
+					Wanted: []reflect.Type{
+						reflect.TypeOf(time.Time{}),
+						reflect.TypeOf(""),
+					},
+					Got: reflect.TypeOf(true),
+				},
+			},
+		},
+		{
+			Name:           "addTime",
+			Func:           "addTime",
+			PositionalArgs: []interface{}{ref, "72h"},
+			KeywordArgs:    map[string]interface{}{"time": ref, "duration": "72h"},
+			Expected:       ref.Add(72 * time.Hour),
+		},
+		{
+			Name:           "addTime with seconds",
+			Func:           "addTime",
+			PositionalArgs: []interface{}{ref, float64(-90)},
+			Expected:       ref.Add(-90 * time.Second),
+		},
+		{
+			Name:           "diffTime",
+			Func:           "diffTime",
+			PositionalArgs: []interface{}{ref, "2020-10-07T14:04:05Z"},
+			KeywordArgs:    map[string]interface{}{"end": ref, "start": "2020-10-07T14:04:05Z"},
+			Expected:       float64(3600),
+		},
+		{
+			Name:           "truncateTime to day",
+			Func:           "truncateTime",
+			PositionalArgs: []interface{}{ref, "day"},
+			KeywordArgs:    map[string]interface{}{"time": ref, "unit": "day"},
+			Expected:       time.Date(2020, time.October, 7, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Name:           "truncateTime to week",
+			Func:           "truncateTime",
+			PositionalArgs: []interface{}{ref, "week"},
+			Expected:       time.Date(2020, time.October, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Name:           "truncateTime to month",
+			Func:           "truncateTime",
+			PositionalArgs: []interface{}{ref, "month"},
+			Expected:       time.Date(2020, time.October, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Name:           "truncateTime to duration",
+			Func:           "truncateTime",
+			PositionalArgs: []interface{}{ref, "15m"},
+			Expected:       time.Date(2020, time.October, 7, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			Name:           "truncateTime to unknown unit",
+			Func:           "truncateTime",
+			PositionalArgs: []interface{}{ref, "fortnight"},
+			KeywordArgs:    map[string]interface{}{"time": ref, "unit": "fortnight"},
+			ExpectedPositionalError: &fn.PositionalArgError{
+				Arg:   2,
+				Cause: &fnlib.TimeUnitError{Unit: "fortnight"},
+			},
+			ExpectedKeywordError: &fn.KeywordArgError{
+				Arg:   "unit",
+				Cause: &fnlib.TimeUnitError{Unit: "fortnight"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, test.Run)
+	}
+}
@@ -27,4 +27,15 @@ var toStringDescriptor = fn.DescriptorFuncs{
 		})
 		return fn, nil
 	},
+	SignatureFunc: func() *fn.Signature {
+		return &fn.Signature{
+			Positional: &fn.Arguments{
+				Params: []fn.Param{
+					{Name: "value", Type: fn.TypeScalar},
+				},
+				Required: 1,
+			},
+			Returns: fn.TypeString,
+		}
+	},
 }
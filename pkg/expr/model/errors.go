@@ -35,10 +35,15 @@ func (e *DataNotFoundError) Error() string {
 }
 
 type SecretNotFoundError struct {
-	Name string
+	Name    string
+	Version int
 }
 
 func (e *SecretNotFoundError) Error() string {
+	if e.Version > 0 {
+		return fmt.Sprintf("model: version %d of secret %q could not be found", e.Version, e.Name)
+	}
+
 	return fmt.Sprintf("model: secret %q could not be found", e.Name)
 }
 
@@ -12,13 +12,19 @@ type UnresolvableData struct {
 
 type UnresolvableSecret struct {
 	Name string
+
+	// Version is the version of the secret that was requested, or 0 if the
+	// latest version was requested.
+	Version int
 }
 
 type unresolvableSecretSort []UnresolvableSecret
 
-func (s unresolvableSecretSort) Len() int           { return len(s) }
-func (s unresolvableSecretSort) Less(i, j int) bool { return s[i].Name < s[j].Name }
-func (s unresolvableSecretSort) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s unresolvableSecretSort) Len() int { return len(s) }
+func (s unresolvableSecretSort) Less(i, j int) bool {
+	return s[i].Name < s[j].Name || (s[i].Name == s[j].Name && s[i].Version < s[j].Version)
+}
+func (s unresolvableSecretSort) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
 
 type UnresolvableConnection struct {
 	Type string
@@ -100,7 +106,7 @@ func (u *Unresolvable) AsError() error {
 	}
 
 	for _, s := range u.Secrets {
-		err.Causes = append(err.Causes, &SecretNotFoundError{Name: s.Name})
+		err.Causes = append(err.Causes, &SecretNotFoundError{Name: s.Name, Version: s.Version})
 	}
 
 	for _, c := range u.Connections {
@@ -246,6 +252,11 @@ func (u *Unresolvable) Extends(other Unresolvable) {
 type Result struct {
 	Value        interface{}
 	Unresolvable Unresolvable
+
+	// Sensitive indicates that the value was derived, in whole or in part,
+	// from a secret or connection. Consumers should avoid logging or
+	// otherwise exposing sensitive values.
+	Sensitive bool
 }
 
 func (r *Result) Complete() bool {
@@ -254,9 +265,11 @@ func (r *Result) Complete() bool {
 
 func (r *Result) Extends(other *Result) *Result {
 	// For convenience, we can copy in the information from another result,
-	// which extends the unresolvables here.
+	// which extends the unresolvables here. A result that incorporates a
+	// sensitive result is also sensitive.
 
 	r.Unresolvable.Extends(other.Unresolvable)
+	r.Sensitive = r.Sensitive || other.Sensitive
 	return r
 }
 
@@ -3,7 +3,8 @@ package model
 import "github.com/puppetlabs/horsehead/v2/encoding/transfer"
 
 type JSONUnresolvableSecretEnvelope struct {
-	Name string `json:"name"`
+	Name    string `json:"name"`
+	Version int    `json:"version,omitempty"`
 }
 
 type JSONUnresolvableConnectionEnvelope struct {
@@ -45,7 +46,8 @@ func NewJSONUnresolvableEnvelope(ur Unresolvable) *JSONUnresolvableEnvelope {
 		env.Secrets = make([]*JSONUnresolvableSecretEnvelope, len(ur.Secrets))
 		for i, s := range ur.Secrets {
 			env.Secrets[i] = &JSONUnresolvableSecretEnvelope{
-				Name: s.Name,
+				Name:    s.Name,
+				Version: s.Version,
 			}
 		}
 	}
@@ -106,6 +108,10 @@ type JSONResultEnvelope struct {
 	Value        transfer.JSONInterface    `json:"value"`
 	Unresolvable *JSONUnresolvableEnvelope `json:"unresolvable"`
 	Complete     bool                      `json:"complete"`
+	Sensitive    bool                      `json:"sensitive,omitempty"`
+
+	// Trace is the record of the evaluation of the result, if requested.
+	Trace []*JSONTraceNodeEnvelope `json:"trace,omitempty"`
 }
 
 func NewJSONResultEnvelope(rv *Result) *JSONResultEnvelope {
@@ -113,5 +119,6 @@ func NewJSONResultEnvelope(rv *Result) *JSONResultEnvelope {
 		Value:        transfer.JSONInterface{Data: rv.Value},
 		Unresolvable: NewJSONUnresolvableEnvelope(rv.Unresolvable),
 		Complete:     rv.Complete(),
+		Sensitive:    rv.Sensitive,
 	}
 }
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// RedactedValue replaces the value of a sensitive trace node.
+const RedactedValue = "[redacted]"
+
+type TraceNodeKind string
+
+const (
+	TraceNodeKindType       TraceNodeKind = "type"
+	TraceNodeKindEncoding   TraceNodeKind = "encoding"
+	TraceNodeKindInvocation TraceNodeKind = "invocation"
+	TraceNodeKindQuery      TraceNodeKind = "query"
+)
+
+// TraceNode records the evaluation of a single $type, $encoding, or $fn node,
+// or of a query against a tree.
+type TraceNode struct {
+	// Path is the dot-separated location of the node in the tree being
+	// evaluated. The arguments of an invocation are located under the $fn key.
+	Path string
+
+	Kind TraceNodeKind
+
+	// Name is the $type or $encoding of the node, the name of the function
+	// invoked, or the query.
+	Name string
+
+	// Resolver describes the resolver consulted to evaluate the node, if any.
+	// For a query, it is the query language.
+	Resolver string
+
+	// Value is the result of evaluating the node. If the result is sensitive,
+	// the value is RedactedValue.
+	Value        interface{}
+	Unresolvable Unresolvable
+	Sensitive    bool
+
+	// Error is the error returned by the evaluator, if any. Errors are
+	// recorded on the node where they occur and on each of its ancestors.
+	Error error
+
+	Elapsed  time.Duration
+	Children []*TraceNode
+}
+
+func (tn *TraceNode) Complete() bool {
+	return tn.Error == nil && tn.Unresolvable.AsError() == nil
+}
+
+// Trace collects the nodes recorded while evaluating one or more trees. It is
+// safe for concurrent use.
+type Trace struct {
+	mut   sync.Mutex
+	nodes []*TraceNode
+}
+
+// Add appends a node to the children of the given parent, or to the top level
+// of the trace if the parent is nil.
+func (t *Trace) Add(parent, node *TraceNode) {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+
+	if parent == nil {
+		t.nodes = append(t.nodes, node)
+	} else {
+		parent.Children = append(parent.Children, node)
+	}
+}
+
+// Nodes returns the top-level nodes of the trace. Nodes with the same parent
+// are ordered by path, and then by the order in which they were added.
+func (t *Trace) Nodes() []*TraceNode {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+
+	nodes := append([]*TraceNode{}, t.nodes...)
+	sortTraceNodes(nodes)
+	return nodes
+}
+
+func sortTraceNodes(nodes []*TraceNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].Path < nodes[j].Path
+	})
+
+	for _, node := range nodes {
+		sortTraceNodes(node.Children)
+	}
+}
+
+func NewTrace() *Trace {
+	return &Trace{}
+}
@@ -0,0 +1,51 @@
+package model
+
+import "github.com/puppetlabs/horsehead/v2/encoding/transfer"
+
+type JSONTraceNodeEnvelope struct {
+	Path         string                    `json:"path"`
+	Kind         TraceNodeKind             `json:"kind"`
+	Name         string                    `json:"name"`
+	Resolver     string                    `json:"resolver,omitempty"`
+	Value        transfer.JSONInterface    `json:"value"`
+	Unresolvable *JSONUnresolvableEnvelope `json:"unresolvable"`
+	Complete     bool                      `json:"complete"`
+	Sensitive    bool                      `json:"sensitive,omitempty"`
+	Error        string                    `json:"error,omitempty"`
+	Elapsed      string                    `json:"elapsed"`
+	Children     []*JSONTraceNodeEnvelope  `json:"children,omitempty"`
+}
+
+func NewJSONTraceNodeEnvelope(tn *TraceNode) *JSONTraceNodeEnvelope {
+	env := &JSONTraceNodeEnvelope{
+		Path:         tn.Path,
+		Kind:         tn.Kind,
+		Name:         tn.Name,
+		Resolver:     tn.Resolver,
+		Value:        transfer.JSONInterface{Data: tn.Value},
+		Unresolvable: NewJSONUnresolvableEnvelope(tn.Unresolvable),
+		Complete:     tn.Complete(),
+		Sensitive:    tn.Sensitive,
+		Elapsed:      tn.Elapsed.String(),
+		Children:     NewJSONTraceEnvelope(tn.Children),
+	}
+
+	if tn.Error != nil {
+		env.Error = tn.Error.Error()
+	}
+
+	return env
+}
+
+func NewJSONTraceEnvelope(nodes []*TraceNode) []*JSONTraceNodeEnvelope {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	env := make([]*JSONTraceNodeEnvelope, len(nodes))
+	for i, node := range nodes {
+		env[i] = NewJSONTraceNodeEnvelope(node)
+	}
+
+	return env
+}
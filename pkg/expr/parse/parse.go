@@ -16,7 +16,7 @@ func ParseFile(name string) (Tree, error) {
 	defer f.Close()
 
 	switch strings.ToLower(filepath.Ext(f.Name())) {
-	case "yaml", "yml":
+	case ".yaml", ".yml":
 		return ParseYAML(f)
 	default:
 		return ParseJSON(f)
@@ -60,14 +60,23 @@ func (YAMLSecretTransformer) Transform(node *yaml.Node) (bool, error) {
 		return false, nil
 	}
 
-	var name *yaml.Node
+	var name, version *yaml.Node
 	switch node.Kind {
 	case yaml.MappingNode:
-		if len(node.Content) != 2 || node.Content[0].Value != "name" {
-			return false, fmt.Errorf(`expected mapping-style !Secret to have exactly one key, "name"`)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			switch node.Content[i].Value {
+			case "name":
+				name = node.Content[i+1]
+			case "version":
+				version = node.Content[i+1]
+			default:
+				return false, fmt.Errorf(`expected mapping-style !Secret to have a key "name" and optionally a key "version"`)
+			}
 		}
 
-		name = node.Content[1]
+		if name == nil {
+			return false, fmt.Errorf(`expected mapping-style !Secret to have a key "name" and optionally a key "version"`)
+		}
 	case yaml.SequenceNode:
 		if len(node.Content) != 1 {
 			return false, fmt.Errorf(`expected sequence-style !Secret to have exactly one item`)
@@ -81,7 +90,7 @@ func (YAMLSecretTransformer) Transform(node *yaml.Node) (bool, error) {
 		}
 	}
 
-	// {$type: Secret, name: <name>}
+	// {$type: Secret, name: <name>[, version: <version>]}
 	*node = yaml.Node{
 		Kind: yaml.MappingNode,
 		Content: []*yaml.Node{
@@ -91,6 +100,9 @@ func (YAMLSecretTransformer) Transform(node *yaml.Node) (bool, error) {
 			name,
 		},
 	}
+	if version != nil {
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "version"}, version)
+	}
 	return true, nil
 }
 
@@ -285,6 +297,47 @@ func (YAMLAnswerTransformer) Transform(node *yaml.Node) (bool, error) {
 	return true, nil
 }
 
+type YAMLBindingTransformer struct{}
+
+func (YAMLBindingTransformer) Transform(node *yaml.Node) (bool, error) {
+	if node.ShortTag() != "!Binding" {
+		return false, nil
+	}
+
+	var name *yaml.Node
+	switch node.Kind {
+	case yaml.MappingNode:
+		if len(node.Content) != 2 || node.Content[0].Value != "name" {
+			return false, fmt.Errorf(`expected mapping-style !Binding to have exactly one key, "name"`)
+		}
+
+		name = node.Content[1]
+	case yaml.SequenceNode:
+		if len(node.Content) != 1 {
+			return false, fmt.Errorf(`expected sequence-style !Binding to have exactly one item`)
+		}
+
+		name = node.Content[0]
+	case yaml.ScalarNode:
+		name = &yaml.Node{
+			Kind:  yaml.ScalarNode,
+			Value: node.Value,
+		}
+	}
+
+	// {$type: Binding, name: <name>}
+	*node = yaml.Node{
+		Kind: yaml.MappingNode,
+		Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: "$type"},
+			{Kind: yaml.ScalarNode, Value: "Binding"},
+			{Kind: yaml.ScalarNode, Value: "name"},
+			name,
+		},
+	}
+	return true, nil
+}
+
 type YAMLInvocationTransformer struct{}
 
 func (YAMLInvocationTransformer) Transform(node *yaml.Node) (bool, error) {
@@ -347,6 +400,168 @@ func (YAMLBinaryToEncodingTransformer) Transform(node *yaml.Node) (bool, error)
 	return true, nil
 }
 
+// YAMLTemplateTransformer compiles a !Template string into an invocation of
+// concat. Each ${...} placeholder in the string refers to a parameter, secret,
+// output, connection field, or answer using a dotted path:
+//
+//	${parameters.<name>}
+//	${secrets.<name>}
+//	${outputs.<step>.<name>}
+//	${connections.<type>.<name>.<field>}
+//	${answers.<askRef>.<name>}
+//
+// The final segment of a path may itself contain dots. A connection is always
+// an object, so a placeholder must refer to one of its fields instead. Any
+// other value that is not a scalar when the template is evaluated is reported
+// as an error by concat.
+//
+// In a run of "$" before a "{", each "$$" is a literal "$". An odd "$" left
+// over starts a placeholder. For example, "$${x}" is the literal "${x}" and
+// "$$${x}" is a "$" followed by a placeholder. A "$" anywhere else, including
+// at the end of the string, is literal.
+type YAMLTemplateTransformer struct{}
+
+func (YAMLTemplateTransformer) Transform(node *yaml.Node) (bool, error) {
+	if node.ShortTag() != "!Template" {
+		return false, nil
+	} else if node.Kind != yaml.ScalarNode {
+		return false, fmt.Errorf(`unexpected non-scalar value for !Template, must be a string`)
+	}
+
+	var args []*yaml.Node
+	var literal strings.Builder
+
+	for rest := node.Value; rest != ""; {
+		i := strings.IndexByte(rest, '$')
+		if i < 0 {
+			literal.WriteString(rest)
+			break
+		}
+
+		literal.WriteString(rest[:i])
+		rest = rest[i:]
+
+		n := len(rest) - len(strings.TrimLeft(rest, "$"))
+		if n == len(rest) || rest[n] != '{' {
+			literal.WriteString(rest[:n])
+			rest = rest[n:]
+			continue
+		}
+
+		literal.WriteString(rest[:n/2])
+		if n%2 == 0 {
+			literal.WriteByte('{')
+			rest = rest[n+1:]
+			continue
+		}
+
+		rest = rest[n+1:]
+
+		j := strings.IndexByte(rest, '}')
+		if j < 0 {
+			return false, fmt.Errorf(`expected !Template placeholder to be terminated by "}"`)
+		}
+
+		ref, err := yamlTemplateReference(strings.TrimSpace(rest[:j]))
+		if err != nil {
+			return false, err
+		}
+
+		if literal.Len() > 0 {
+			args = append(args, yamlTemplateString(literal.String()))
+			literal.Reset()
+		}
+		args = append(args, ref)
+
+		rest = rest[j+1:]
+	}
+
+	if len(args) == 0 {
+		// No placeholders, so this is just a string.
+		*node = *yamlTemplateString(literal.String())
+		return true, nil
+	}
+
+	if literal.Len() > 0 {
+		args = append(args, yamlTemplateString(literal.String()))
+	}
+
+	// {$fn.concat: [<args>...]}
+	*node = *yamlTemplateInvocation("concat", args...)
+	return true, nil
+}
+
+// yamlTemplateReference returns a tagged node for the given !Template
+// placeholder, which is transformed into a $type node along with the rest of
+// the document.
+func yamlTemplateReference(path string) (*yaml.Node, error) {
+	parts := strings.SplitN(path, ".", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, fmt.Errorf(`expected !Template placeholder %q to have the syntax <kind>.<name>`, path)
+	}
+
+	switch parts[0] {
+	case "parameters", "secrets":
+		tag := "!Parameter"
+		if parts[0] == "secrets" {
+			tag = "!Secret"
+		}
+
+		return &yaml.Node{
+			Kind:    yaml.SequenceNode,
+			Tag:     tag,
+			Content: []*yaml.Node{yamlTemplateString(parts[1])},
+		}, nil
+	case "outputs", "answers":
+		scoped := strings.SplitN(parts[1], ".", 2)
+		if len(scoped) != 2 || scoped[0] == "" || scoped[1] == "" {
+			return nil, fmt.Errorf(`expected !Template placeholder %q to have the syntax %s.<scope>.<name>`, path, parts[0])
+		}
+
+		tag := "!Output"
+		if parts[0] == "answers" {
+			tag = "!Answer"
+		}
+
+		return &yaml.Node{
+			Kind:    yaml.SequenceNode,
+			Tag:     tag,
+			Content: []*yaml.Node{yamlTemplateString(scoped[0]), yamlTemplateString(scoped[1])},
+		}, nil
+	case "connections":
+		scoped := strings.SplitN(parts[1], ".", 3)
+		if len(scoped) != 3 || scoped[0] == "" || scoped[1] == "" || scoped[2] == "" {
+			return nil, fmt.Errorf(`expected !Template placeholder %q to have the syntax connections.<type>.<name>.<field>`, path)
+		}
+
+		// {$fn.path: [!Connection [<type>, <name>], <field>]}
+		return yamlTemplateInvocation("path",
+			&yaml.Node{
+				Kind:    yaml.SequenceNode,
+				Tag:     "!Connection",
+				Content: []*yaml.Node{yamlTemplateString(scoped[0]), yamlTemplateString(scoped[1])},
+			},
+			yamlTemplateString(scoped[2]),
+		), nil
+	default:
+		return nil, fmt.Errorf(`unknown !Template placeholder kind %q, must be one of "parameters", "secrets", "outputs", "connections", or "answers"`, parts[0])
+	}
+}
+
+func yamlTemplateInvocation(name string, args ...*yaml.Node) *yaml.Node {
+	return &yaml.Node{
+		Kind: yaml.MappingNode,
+		Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: "$fn." + name},
+			{Kind: yaml.SequenceNode, Content: args},
+		},
+	}
+}
+
+func yamlTemplateString(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
 type YAMLUnknownTagTransformer struct{}
 
 func (YAMLUnknownTagTransformer) Transform(node *yaml.Node) (bool, error) {
@@ -364,8 +579,10 @@ var YAMLTransformers = []YAMLTransformer{
 	YAMLOutputTransformer{},
 	YAMLParameterTransformer{},
 	YAMLAnswerTransformer{},
+	YAMLBindingTransformer{},
 	YAMLInvocationTransformer{},
 	YAMLBinaryToEncodingTransformer{},
+	YAMLTemplateTransformer{},
 	YAMLUnknownTagTransformer{},
 }
 
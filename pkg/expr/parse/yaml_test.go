@@ -130,6 +130,22 @@ func TestYAML(t *testing.T) {
 				"op": "something",
 			}),
 		},
+		{
+			Name: "secret mapping with version",
+			Data: yaml(`
+				aws:
+					accessKeyID: foo
+					secretAccessKey: !Secret {name: secretAccessKey, version: 3}
+				op: something
+			`),
+			ExpectedTree: parse.Tree(map[string]interface{}{
+				"aws": map[string]interface{}{
+					"accessKeyID":     "foo",
+					"secretAccessKey": testutil.JSONSecretVersion("secretAccessKey", 3),
+				},
+				"op": "something",
+			}),
+		},
 		{
 			Name: "connection sequence",
 			Data: yaml(`
@@ -242,6 +258,23 @@ func TestYAML(t *testing.T) {
 				"op": "something",
 			}),
 		},
+		{
+			Name: "binding in invocation",
+			Data: yaml(`
+				names: !Fn.map [!Parameter users, !Fn.path [!Binding $item, name]]
+				indexes: !Fn.map [!Parameter users, !Binding {name: $index}]
+			`),
+			ExpectedTree: parse.Tree(map[string]interface{}{
+				"names": testutil.JSONInvocation("map", []interface{}{
+					testutil.JSONParameter("users"),
+					testutil.JSONInvocation("path", []interface{}{testutil.JSONBinding("$item"), "name"}),
+				}),
+				"indexes": testutil.JSONInvocation("map", []interface{}{
+					testutil.JSONParameter("users"),
+					testutil.JSONBinding("$index"),
+				}),
+			}),
+		},
 		{
 			Name: "conditional invocation",
 			Data: yaml(`
@@ -296,6 +329,94 @@ func TestYAML(t *testing.T) {
 				"message": testutil.JSONEncoding("base64", "SGVsbG8sIJCiikU="),
 			}),
 		},
+		{
+			Name: "template",
+			Data: yaml(`
+				message: !Template 'Deploying ${outputs.build.image} to ${ connections.kubernetes.prod.server } as ${parameters.user}'
+				password: !Template '${secrets.db.password}'
+				answer: !Template '${answers.approval.approved}!'
+			`),
+			ExpectedTree: parse.Tree(map[string]interface{}{
+				"message": testutil.JSONInvocation("concat", []interface{}{
+					"Deploying ",
+					testutil.JSONOutput("build", "image"),
+					" to ",
+					testutil.JSONInvocation("path", []interface{}{
+						testutil.JSONConnection("kubernetes", "prod"),
+						"server",
+					}),
+					" as ",
+					testutil.JSONParameter("user"),
+				}),
+				"password": testutil.JSONInvocation("concat", []interface{}{
+					testutil.JSONSecret("db.password"),
+				}),
+				"answer": testutil.JSONInvocation("concat", []interface{}{
+					testutil.JSONAnswer("approval", "approved"),
+					"!",
+				}),
+			}),
+		},
+		{
+			Name: "template without placeholders",
+			Data: yaml(`
+				- !Template '123'
+				- !Template 'echo $${HOME} $HOME'
+			`),
+			ExpectedTree: parse.Tree([]interface{}{"123", "echo ${HOME} $HOME"}),
+		},
+		{
+			Name: "template with escapes",
+			Data: yaml(`
+				- !Template '$$${parameters.price}'
+				- !Template '$$$${parameters.price}'
+				- !Template 'echo $$ $${x} costs ${parameters.price}$'
+				- !Template '$'
+				- !Template '$$'
+			`),
+			ExpectedTree: parse.Tree([]interface{}{
+				testutil.JSONInvocation("concat", []interface{}{
+					"$",
+					testutil.JSONParameter("price"),
+				}),
+				"$${parameters.price}",
+				testutil.JSONInvocation("concat", []interface{}{
+					"echo $$ ${x} costs ",
+					testutil.JSONParameter("price"),
+					"$",
+				}),
+				"$",
+				"$$",
+			}),
+		},
+		{
+			Name: "template with unterminated placeholder",
+			Data: yaml(`
+				message: !Template 'Hello, ${parameters.name'
+			`),
+			ExpectedError: fmt.Errorf(`expected !Template placeholder to be terminated by "}"`),
+		},
+		{
+			Name: "template with unknown placeholder kind",
+			Data: yaml(`
+				message: !Template 'Hello, ${params.name}'
+			`),
+			ExpectedError: fmt.Errorf(`unknown !Template placeholder kind "params", must be one of "parameters", "secrets", "outputs", "connections", or "answers"`),
+		},
+		{
+			Name: "template with incomplete output placeholder",
+			Data: yaml(`
+				message: !Template 'Hello, ${outputs.name}'
+			`),
+			ExpectedError: fmt.Errorf(`expected !Template placeholder "outputs.name" to have the syntax outputs.<scope>.<name>`),
+		},
+		{
+			Name: "template with connection placeholder without a field",
+			Data: yaml(`
+				message: !Template 'Deploying to ${connections.kubernetes.prod}'
+			`),
+			ExpectedError: fmt.Errorf(`expected !Template placeholder "connections.kubernetes.prod" to have the syntax connections.<type>.<name>.<field>`),
+		},
 		{
 			Name: "invalid tag",
 			Data: yaml(`
@@ -48,6 +48,28 @@ func (cr *chainSecretTypeResolvers) ResolveSecret(ctx context.Context, name stri
 	return "", &model.SecretNotFoundError{Name: name}
 }
 
+// ResolveSecretVersion tries each resolver that supports versioned secrets in
+// turn. Resolvers that do not support versioned secrets are skipped.
+func (cr *chainSecretTypeResolvers) ResolveSecretVersion(ctx context.Context, name string, version int) (string, error) {
+	for _, r := range cr.resolvers {
+		vr, ok := r.(SecretVersionTypeResolver)
+		if !ok {
+			continue
+		}
+
+		s, err := vr.ResolveSecretVersion(ctx, name, version)
+		if _, ok := err.(*model.SecretNotFoundError); ok {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+
+		return s, nil
+	}
+
+	return "", &model.SecretNotFoundError{Name: name}
+}
+
 func ChainSecretTypeResolvers(resolvers ...SecretTypeResolver) SecretTypeResolver {
 	return &chainSecretTypeResolvers{resolvers: resolvers}
 }
@@ -31,6 +31,20 @@ func (f SecretTypeResolverFunc) ResolveSecret(ctx context.Context, name string)
 	return f(ctx, name)
 }
 
+// SecretVersionTypeResolver is implemented by secret resolvers that can
+// resolve a specific version of a secret.
+type SecretVersionTypeResolver interface {
+	ResolveSecretVersion(ctx context.Context, name string, version int) (string, error)
+}
+
+type SecretVersionTypeResolverFunc func(ctx context.Context, name string, version int) (string, error)
+
+var _ SecretVersionTypeResolver = SecretVersionTypeResolverFunc(nil)
+
+func (f SecretVersionTypeResolverFunc) ResolveSecretVersion(ctx context.Context, name string, version int) (string, error) {
+	return f(ctx, name, version)
+}
+
 type ConnectionTypeResolver interface {
 	ResolveConnection(ctx context.Context, connectionType, name string) (interface{}, error)
 }
@@ -14,6 +14,10 @@ func JSONSecret(name string) map[string]interface{} {
 	return map[string]interface{}{"$type": "Secret", "name": name}
 }
 
+func JSONSecretVersion(name string, version int) map[string]interface{} {
+	return map[string]interface{}{"$type": "Secret", "name": name, "version": version}
+}
+
 func JSONConnection(connectionType, name string) map[string]interface{} {
 	return map[string]interface{}{"$type": "Connection", "type": connectionType, "name": name}
 }
@@ -30,6 +34,10 @@ func JSONAnswer(askRef, name string) map[string]interface{} {
 	return map[string]interface{}{"$type": "Answer", "askRef": askRef, "name": name}
 }
 
+func JSONBinding(name string) map[string]interface{} {
+	return map[string]interface{}{"$type": "Binding", "name": name}
+}
+
 func JSONInvocation(name string, args interface{}) map[string]interface{} {
 	return map[string]interface{}{fmt.Sprintf("$fn.%s", name): args}
 }
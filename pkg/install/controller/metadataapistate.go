@@ -3,9 +3,11 @@ package controller
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 
 	"github.com/go-logr/logr"
 	installerv1alpha1 "github.com/puppetlabs/relay-core/pkg/apis/install.relay.sh/v1alpha1"
+	"github.com/puppetlabs/relay-core/pkg/install/jwt"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -161,6 +163,21 @@ func (m *metadataAPIStateManager) deployment(deployment *appsv1.Deployment) {
 		})
 	}
 
+	if m.rc.Spec.Operator.GenerateJWTSigningKey {
+		// Only the public keys are made available to the metadata API.
+		template.Volumes = append(template.Volumes, corev1.Volume{
+			Name: "jwt-key-set",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: jwtSigningKeysSecretName(m.rc),
+					Items: []corev1.KeyToPath{
+						{Key: jwt.KeySetName, Path: filepath.Base(jwtKeySetPath)},
+					},
+				},
+			},
+		})
+	}
+
 	if len(template.Containers) == 0 {
 		template.Containers = make([]corev1.Container, 2)
 	}
@@ -193,6 +210,10 @@ func (m *metadataAPIStateManager) deploymentEnv() []corev1.EnvVar {
 		env = append(env, corev1.EnvVar{Name: "RELAY_METADATA_API_DEBUG", Value: "true"})
 	}
 
+	if m.rc.Spec.Operator.GenerateJWTSigningKey {
+		env = append(env, corev1.EnvVar{Name: "RELAY_METADATA_API_JWT_KEY_SET_FILE", Value: jwtKeySetPath})
+	}
+
 	if m.rc.Spec.SentryDSNSecretName != nil {
 		env = append(env, corev1.EnvVar{
 			Name: "RELAY_METADATA_API_SENTRY_DSN",
@@ -247,13 +268,19 @@ func (m *metadataAPIStateManager) serverContainer(container *corev1.Container) {
 	container.ReadinessProbe = probe
 
 	if m.rc.Spec.MetadataAPI.TLSSecretName != nil {
-		container.VolumeMounts = []corev1.VolumeMount{
-			{
-				Name:      "tls-crt",
-				MountPath: metadataAPITLSDirPath,
-				ReadOnly:  true,
-			},
-		}
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      "tls-crt",
+			MountPath: metadataAPITLSDirPath,
+			ReadOnly:  true,
+		})
+	}
+
+	if m.rc.Spec.Operator.GenerateJWTSigningKey {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      "jwt-key-set",
+			MountPath: jwtKeySetDirPath,
+			ReadOnly:  true,
+		})
 	}
 }
 
@@ -278,8 +305,9 @@ func (m *metadataAPIStateManager) httpService(svc *corev1.Service) {
 func (m *metadataAPIStateManager) clusterRole(clusterRole *rbacv1.ClusterRole) {
 	clusterRole.Rules = []rbacv1.PolicyRule{
 		{APIGroups: []string{""}, Resources: []string{"namespaces"}, Verbs: []string{"get"}},
-		{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list"}},
+		{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list", "watch"}},
 		{APIGroups: []string{"tekton.dev"}, Resources: []string{"conditions"}, Verbs: []string{"get", "list"}},
+		{APIGroups: []string{"authentication.k8s.io"}, Resources: []string{"tokenreviews"}, Verbs: []string{"create"}},
 	}
 }
 
@@ -6,6 +6,7 @@ import (
 	"net/url"
 	"path/filepath"
 	"strconv"
+	"time"
 
 	"github.com/go-logr/logr"
 	installerv1alpha1 "github.com/puppetlabs/relay-core/pkg/apis/install.relay.sh/v1alpha1"
@@ -40,7 +41,7 @@ func newOperatorObjects(rc *installerv1alpha1.RelayCore) *operatorObjects {
 	vaultName := fmt.Sprintf("%s-vault", name)
 	objectMeta := metav1.ObjectMeta{Name: name, Namespace: rc.Namespace}
 	vaultObjectMeta := metav1.ObjectMeta{Name: vaultName, Namespace: rc.Namespace}
-	signingKeysSecretObjectMeta := metav1.ObjectMeta{Name: fmt.Sprintf("%s-signing-keys", name), Namespace: rc.Namespace}
+	signingKeysSecretObjectMeta := metav1.ObjectMeta{Name: jwtSigningKeysSecretName(rc), Namespace: rc.Namespace}
 
 	return &operatorObjects{
 		deployment:                     appsv1.Deployment{ObjectMeta: objectMeta},
@@ -65,6 +66,10 @@ type operatorStateManager struct {
 	scheme            *runtime.Scheme
 	vaultAgentManager *vaultAgentManager
 	baseLabels        map[string]string
+
+	// requeueAfter is set when the state of the operator must be reconciled
+	// again at a later time, like when a signing key needs to be rotated.
+	requeueAfter time.Duration
 }
 
 func (m *operatorStateManager) reconcile(ctx context.Context) error {
@@ -140,12 +145,13 @@ func (m *operatorStateManager) reconcile(ctx context.Context) error {
 		log.Info("generation of jwt signing keys is enabled")
 		log.Info("processing jwt signing keys Secret")
 		if _, err := ctrl.CreateOrUpdate(ctx, m, &m.objects.signingKeysSecret, func() error {
-			if m.objects.signingKeysSecret.CreationTimestamp.IsZero() {
-				if err := m.signingKeysSecret(&m.objects.signingKeysSecret); err != nil {
-					return err
-				}
+			requeueAfter, err := jwt.ReconcileSigningKeys(&m.objects.signingKeysSecret, m.signingKeyRotationPolicy(), time.Now())
+			if err != nil {
+				return err
 			}
 
+			m.requeueAfter = requeueAfter
+
 			return ctrl.SetControllerReference(m.rc, &m.objects.signingKeysSecret, m.scheme)
 		}); err != nil {
 			return err
@@ -156,7 +162,9 @@ func (m *operatorStateManager) reconcile(ctx context.Context) error {
 
 	log.Info("processing Deployment")
 	if _, err := ctrl.CreateOrUpdate(ctx, m, &m.objects.deployment, func() error {
-		m.deployment(&m.objects.deployment)
+		if err := m.deployment(&m.objects.deployment); err != nil {
+			return err
+		}
 
 		return ctrl.SetControllerReference(m.rc, &m.objects.deployment, m.scheme)
 	}); err != nil {
@@ -207,9 +215,24 @@ func (m *operatorStateManager) reconcile(ctx context.Context) error {
 	return nil
 }
 
-func (m *operatorStateManager) deployment(deployment *appsv1.Deployment) {
+func (m *operatorStateManager) deployment(deployment *appsv1.Deployment) error {
 	setDeploymentLabels(m.baseLabels, deployment)
 
+	if m.rc.Spec.Operator.GenerateJWTSigningKey {
+		// The operator only reads its signing key at startup, so we roll out
+		// new pods whenever the key changes.
+		kid, err := jwt.SigningKeyID(&m.objects.signingKeysSecret)
+		if err != nil {
+			return err
+		}
+
+		if deployment.Spec.Template.Annotations == nil {
+			deployment.Spec.Template.Annotations = make(map[string]string)
+		}
+
+		deployment.Spec.Template.Annotations[jwtSigningKeyIDAnnotation] = kid
+	}
+
 	template := &deployment.Spec.Template.Spec
 
 	template.ServiceAccountName = deployment.Name
@@ -268,6 +291,8 @@ func (m *operatorStateManager) deployment(deployment *appsv1.Deployment) {
 	m.vaultAgentManager.sidecarContainer(&vaultSidecar)
 
 	template.Containers[1] = vaultSidecar
+
+	return nil
 }
 
 func (m *operatorStateManager) deploymentCommand() []string {
@@ -296,6 +321,13 @@ func (m *operatorStateManager) deploymentCommand() []string {
 		cmd = append(cmd, "-metrics-enabled", "-metrics-server-bind-addr", "0.0.0.0:3050")
 	}
 
+	if m.rc.Spec.SecretStore != nil && m.rc.Spec.SecretStore.Backend != "" {
+		cmd = append(cmd,
+			"-default-secret-store-backend",
+			m.rc.Spec.SecretStore.Backend,
+		)
+	}
+
 	if m.rc.Spec.Operator.TenantSandboxingRuntimeClassName != nil {
 		cmd = append(cmd,
 			"-tenant-sandboxing",
@@ -408,20 +440,16 @@ func (m *operatorStateManager) serverContainer(container *corev1.Container) {
 	})
 }
 
-func (m *operatorStateManager) signingKeysSecret(sec *corev1.Secret) error {
-	pair, err := jwt.GenerateSigningKeys()
-	if err != nil {
-		return err
+func (m *operatorStateManager) signingKeyRotationPolicy() *jwt.RotationPolicy {
+	rotation := m.rc.Spec.Operator.JWTSigningKeyRotation
+	if rotation == nil {
+		return nil
 	}
 
-	if sec.Data == nil {
-		sec.Data = make(map[string][]byte)
+	return &jwt.RotationPolicy{
+		Interval: rotation.Interval.Duration,
+		Overlap:  rotation.Overlap.Duration,
 	}
-
-	sec.Data["private-key.pem"] = pair.PrivateKey
-	sec.Data["public-key.pem"] = pair.PublicKey
-
-	return nil
 }
 
 func (m *operatorStateManager) clusterRole(clusterRole *rbacv1.ClusterRole) {
@@ -624,6 +652,10 @@ func (m *operatorStateManager) storageAddress() string {
 	return ""
 }
 
+func jwtSigningKeysSecretName(rc *installerv1alpha1.RelayCore) string {
+	return fmt.Sprintf("%s-operator-signing-keys", rc.Name)
+}
+
 func newOperatorStateManager(rc *installerv1alpha1.RelayCore, r *RelayCoreReconciler, log logr.Logger) *operatorStateManager {
 	m := &operatorStateManager{
 		Client:            r.Client,
@@ -36,10 +36,14 @@ const (
 	ownerKey                = ".metadata.controller"
 	jwtSigningKeyDirPath    = "/var/run/secrets/puppet/relay/jwt"
 	jwtSigningKeyPath       = "/var/run/secrets/puppet/relay/jwt/private-key.pem"
+	jwtKeySetDirPath        = "/var/run/secrets/puppet/relay/jwks"
+	jwtKeySetPath           = "/var/run/secrets/puppet/relay/jwks/jwks.json"
 	webhookTLSDirPath       = "/var/run/secrets/puppet/relay/webhook-tls"
 	vaultAgentConfigDirPath = "/var/run/vault/config"
 	vaultAgentSATokenPath   = "/var/run/secrets/kubernetes.io/serviceaccount@vault"
 	metadataAPITLSDirPath   = "/var/run/secrets/puppet/relay/tls"
+
+	jwtSigningKeyIDAnnotation = "install.relay.sh/jwt-signing-key-id"
 )
 
 // RelayCoreReconciler reconciles a RelayCore object
@@ -54,6 +58,7 @@ type RelayCoreReconciler struct {
 // +kubebuilder:rbac:groups=core,resources=configmaps;limitranges;serviceaccounts;services;secrets;namespaces;persistentvolumes;persistentvolumeclaims,verbs=get;list;watch;patch;create;update;delete
 // +kubebuilder:rbac:groups=core,resources=pods;pods/log,verbs=get;list;watch
 // +kubebuilder:rbac:groups=tekton.dev,resources=pipelineruns;taskruns;pipelines;tasks;conditions,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
 // +kubebuilder:rbac:groups=batch;extensions,resources=jobs,verbs=get;list;watch;patch;create;update;delete
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;patch;create;update
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;clusterrolebindings,verbs=get;list;watch;patch;create;update
@@ -136,7 +141,8 @@ func (r *RelayCoreReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	// Come back when the signing keys need to be rotated or expired.
+	return ctrl.Result{RequeueAfter: osm.requeueAfter}, nil
 }
 
 func (r *RelayCoreReconciler) updateStatus(ctx context.Context, relayCore *installerv1alpha1.RelayCore) error {
@@ -0,0 +1,200 @@
+package jwt
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/puppetlabs/relay-core/pkg/authenticate"
+	jose "gopkg.in/square/go-jose.v2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	PrivateKeyName        = "private-key.pem"
+	PublicKeyName         = "public-key.pem"
+	NextPrivateKeyName    = "next-private-key.pem"
+	NextPublicKeyName     = "next-public-key.pem"
+	PreviousPublicKeyName = "previous-public-key.pem"
+	KeySetName            = authenticate.KeySetJWKSKey
+
+	RotatedAtAnnotation         = "install.relay.sh/jwt-signing-key-rotated-at"
+	PreviousExpiresAtAnnotation = "install.relay.sh/jwt-signing-key-previous-expires-at"
+)
+
+// RotationPolicy determines how often signing keys are replaced.
+type RotationPolicy struct {
+	// Interval is how long a key is used to sign tokens before it is
+	// replaced.
+	Interval time.Duration
+
+	// Overlap is how long tokens signed by a replaced key continue to be
+	// accepted.
+	Overlap time.Duration
+}
+
+// ReconcileSigningKeys generates, rotates, and expires the keys stored in the
+// given secret and updates the JSON Web Key Set that verifiers use.
+//
+// When a rotation policy is provided, the key that will be used after the next
+// rotation is published in the key set ahead of time so that verifiers know
+// about it before any tokens are signed with it. After a rotation, the
+// replaced key remains in the key set for the overlap period.
+//
+// The returned duration is the time until the secret should next be
+// reconciled, or zero if no change is scheduled.
+func ReconcileSigningKeys(sec *corev1.Secret, policy *RotationPolicy, now time.Time) (time.Duration, error) {
+	if sec.Data == nil {
+		sec.Data = make(map[string][]byte)
+	}
+
+	if len(sec.Data[PrivateKeyName]) == 0 {
+		if err := generateSigningKeysInto(sec, PrivateKeyName, PublicKeyName); err != nil {
+			return 0, err
+		}
+	}
+
+	var requeueAfter time.Duration
+	schedule := func(t time.Time) {
+		if d := t.Sub(now); d > 0 && (requeueAfter == 0 || d < requeueAfter) {
+			requeueAfter = d
+		}
+	}
+
+	if expiresAt, ok := annotationTime(sec, PreviousExpiresAtAnnotation); !ok || !now.Before(expiresAt) {
+		delete(sec.Data, PreviousPublicKeyName)
+		delete(sec.Annotations, PreviousExpiresAtAnnotation)
+	} else {
+		schedule(expiresAt)
+	}
+
+	if policy == nil {
+		delete(sec.Data, NextPrivateKeyName)
+		delete(sec.Data, NextPublicKeyName)
+		delete(sec.Annotations, RotatedAtAnnotation)
+	} else {
+		if len(sec.Data[NextPrivateKeyName]) == 0 {
+			if err := generateSigningKeysInto(sec, NextPrivateKeyName, NextPublicKeyName); err != nil {
+				return 0, err
+			}
+		}
+
+		rotatedAt, ok := annotationTime(sec, RotatedAtAnnotation)
+		if !ok {
+			rotatedAt = now
+			setAnnotationTime(sec, RotatedAtAnnotation, rotatedAt)
+		}
+
+		rotateAt := rotatedAt.Add(policy.Interval)
+
+		// If the previous key is still being accepted, we wait for it to
+		// expire before rotating again; its expiry is already scheduled.
+		if _, pending := sec.Data[PreviousPublicKeyName]; !pending && !now.Before(rotateAt) {
+			sec.Data[PreviousPublicKeyName] = sec.Data[PublicKeyName]
+			sec.Data[PrivateKeyName] = sec.Data[NextPrivateKeyName]
+			sec.Data[PublicKeyName] = sec.Data[NextPublicKeyName]
+
+			if err := generateSigningKeysInto(sec, NextPrivateKeyName, NextPublicKeyName); err != nil {
+				return 0, err
+			}
+
+			setAnnotationTime(sec, RotatedAtAnnotation, now)
+			setAnnotationTime(sec, PreviousExpiresAtAnnotation, now.Add(policy.Overlap))
+
+			schedule(now.Add(policy.Overlap))
+			rotateAt = now.Add(policy.Interval)
+		}
+
+		schedule(rotateAt)
+	}
+
+	jwks := &jose.JSONWebKeySet{}
+	for _, name := range []string{PublicKeyName, NextPublicKeyName, PreviousPublicKeyName} {
+		b, found := sec.Data[name]
+		if !found {
+			continue
+		}
+
+		jwk, err := publicJSONWebKey(b)
+		if err != nil {
+			return 0, fmt.Errorf("jwt: %s: %+v", name, err)
+		}
+
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	b, err := json.Marshal(jwks)
+	if err != nil {
+		return 0, err
+	}
+
+	sec.Data[KeySetName] = b
+
+	return requeueAfter, nil
+}
+
+// SigningKeyID returns the ID of the key currently used to sign tokens in the
+// given secret.
+func SigningKeyID(sec *corev1.Secret) (string, error) {
+	jwk, err := publicJSONWebKey(sec.Data[PublicKeyName])
+	if err != nil {
+		return "", err
+	}
+
+	return jwk.KeyID, nil
+}
+
+func generateSigningKeysInto(sec *corev1.Secret, privateKeyName, publicKeyName string) error {
+	pair, err := GenerateSigningKeys()
+	if err != nil {
+		return err
+	}
+
+	sec.Data[privateKeyName] = pair.PrivateKey
+	sec.Data[publicKeyName] = pair.PublicKey
+
+	return nil
+}
+
+func publicJSONWebKey(b []byte) (jose.JSONWebKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return jose.JSONWebKey{}, fmt.Errorf("no PEM data found")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return jose.JSONWebKey{}, err
+	}
+
+	kid, err := authenticate.KeyID(key)
+	if err != nil {
+		return jose.JSONWebKey{}, err
+	}
+
+	return jose.JSONWebKey{Key: key, KeyID: kid, Use: "sig"}, nil
+}
+
+func annotationTime(sec *corev1.Secret, name string) (time.Time, bool) {
+	value, found := sec.Annotations[name]
+	if !found {
+		return time.Time{}, false
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+func setAnnotationTime(sec *corev1.Secret, name string, t time.Time) {
+	if sec.Annotations == nil {
+		sec.Annotations = make(map[string]string)
+	}
+
+	sec.Annotations[name] = t.UTC().Format(time.RFC3339)
+}
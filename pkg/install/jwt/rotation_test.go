@@ -0,0 +1,142 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/puppetlabs/relay-core/pkg/install/jwt"
+	"github.com/stretchr/testify/require"
+	jose "gopkg.in/square/go-jose.v2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func keySetIDs(t *testing.T, sec *corev1.Secret) []string {
+	var jwks jose.JSONWebKeySet
+	require.NoError(t, json.Unmarshal(sec.Data[jwt.KeySetName], &jwks))
+
+	var kids []string
+	for _, key := range jwks.Keys {
+		require.True(t, key.IsPublic())
+		kids = append(kids, key.KeyID)
+	}
+
+	return kids
+}
+
+func TestReconcileSigningKeysWithoutRotation(t *testing.T) {
+	now := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	sec := &corev1.Secret{}
+
+	requeueAfter, err := jwt.ReconcileSigningKeys(sec, nil, now)
+	require.NoError(t, err)
+	require.Zero(t, requeueAfter)
+	require.NotEmpty(t, sec.Data[jwt.PrivateKeyName])
+	require.NotContains(t, sec.Data, jwt.NextPrivateKeyName)
+
+	kid, err := jwt.SigningKeyID(sec)
+	require.NoError(t, err)
+	require.Equal(t, []string{kid}, keySetIDs(t, sec))
+
+	// Reconciling again should not change anything.
+	private := sec.Data[jwt.PrivateKeyName]
+	keySet := sec.Data[jwt.KeySetName]
+
+	_, err = jwt.ReconcileSigningKeys(sec, nil, now.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, private, sec.Data[jwt.PrivateKeyName])
+	require.Equal(t, keySet, sec.Data[jwt.KeySetName])
+}
+
+func TestReconcileSigningKeysWithRotation(t *testing.T) {
+	now := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+	policy := &jwt.RotationPolicy{
+		Interval: 24 * time.Hour,
+		Overlap:  2 * time.Hour,
+	}
+
+	sec := &corev1.Secret{}
+
+	requeueAfter, err := jwt.ReconcileSigningKeys(sec, policy, now)
+	require.NoError(t, err)
+	require.Equal(t, 24*time.Hour, requeueAfter)
+
+	first, err := jwt.SigningKeyID(sec)
+	require.NoError(t, err)
+
+	// The next key is published before it is used.
+	kids := keySetIDs(t, sec)
+	require.Len(t, kids, 2)
+	require.Equal(t, first, kids[0])
+	second := kids[1]
+
+	// Nothing happens before the interval elapses.
+	requeueAfter, err = jwt.ReconcileSigningKeys(sec, policy, now.Add(12*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 12*time.Hour, requeueAfter)
+	require.Equal(t, []string{first, second}, keySetIDs(t, sec))
+
+	// Rotate. The previous key is kept for the overlap period.
+	now = now.Add(24 * time.Hour)
+
+	requeueAfter, err = jwt.ReconcileSigningKeys(sec, policy, now)
+	require.NoError(t, err)
+	require.Equal(t, 2*time.Hour, requeueAfter)
+
+	kid, err := jwt.SigningKeyID(sec)
+	require.NoError(t, err)
+	require.Equal(t, second, kid)
+
+	kids = keySetIDs(t, sec)
+	require.Len(t, kids, 3)
+	require.Equal(t, second, kids[0])
+	require.Equal(t, first, kids[2])
+	third := kids[1]
+
+	// After the overlap, the previous key is removed.
+	requeueAfter, err = jwt.ReconcileSigningKeys(sec, policy, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 22*time.Hour, requeueAfter)
+	require.Equal(t, []string{second, third}, keySetIDs(t, sec))
+	require.NotContains(t, sec.Data, jwt.PreviousPublicKeyName)
+	require.NotContains(t, sec.Annotations, jwt.PreviousExpiresAtAnnotation)
+
+	// Disabling rotation removes the next key.
+	_, err = jwt.ReconcileSigningKeys(sec, nil, now.Add(3*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, []string{second}, keySetIDs(t, sec))
+	require.NotContains(t, sec.Data, jwt.NextPrivateKeyName)
+}
+
+func TestReconcileSigningKeysWaitsForOverlap(t *testing.T) {
+	now := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+	policy := &jwt.RotationPolicy{
+		Interval: 1 * time.Hour,
+		Overlap:  2 * time.Hour,
+	}
+
+	sec := &corev1.Secret{}
+
+	_, err := jwt.ReconcileSigningKeys(sec, policy, now)
+	require.NoError(t, err)
+
+	now = now.Add(1 * time.Hour)
+
+	_, err = jwt.ReconcileSigningKeys(sec, policy, now)
+	require.NoError(t, err)
+
+	kid, err := jwt.SigningKeyID(sec)
+	require.NoError(t, err)
+
+	// The interval has elapsed again, but the previous key is still within
+	// its overlap, so we can't rotate yet.
+	requeueAfter, err := jwt.ReconcileSigningKeys(sec, policy, now.Add(1*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1*time.Hour, requeueAfter)
+
+	same, err := jwt.SigningKeyID(sec)
+	require.NoError(t, err)
+	require.Equal(t, kid, same)
+	require.Len(t, keySetIDs(t, sec), 3)
+}
@@ -0,0 +1,104 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+// Access describes how an action requested sensitive data.
+type Access string
+
+const (
+	// AccessDirect indicates that the action requested the data by name, for
+	// example, using the secrets endpoint of the metadata API.
+	AccessDirect Access = "direct"
+
+	// AccessExpression indicates that the data was requested as part of
+	// resolving a reference in an expression, like a !Secret in a step spec.
+	AccessExpression Access = "expression"
+)
+
+// Result describes the outcome of a request for sensitive data.
+type Result string
+
+const (
+	ResultGranted  Result = "granted"
+	ResultNotFound Result = "not_found"
+	ResultRejected Result = "rejected"
+	ResultError    Result = "error"
+)
+
+func resultFromError(err error) Result {
+	switch err {
+	case nil:
+		return ResultGranted
+	case model.ErrNotFound:
+		return ResultNotFound
+	case model.ErrRejected:
+		return ResultRejected
+	default:
+		return ResultError
+	}
+}
+
+// Kind is the type of data that was accessed.
+type Kind string
+
+const (
+	KindSecret     Kind = "secret"
+	KindConnection Kind = "connection"
+)
+
+// Actor identifies the action that accessed data.
+type Actor struct {
+	DomainID   string `json:"domainID,omitempty"`
+	TenantID   string `json:"tenantID,omitempty"`
+	RunID      string `json:"runID,omitempty"`
+	ActionType string `json:"actionType,omitempty"`
+	ActionName string `json:"actionName,omitempty"`
+}
+
+// Event is a single audited access.
+type Event struct {
+	Time   time.Time `json:"time"`
+	Actor  Actor     `json:"actor"`
+	Kind   Kind      `json:"kind"`
+	Type   string    `json:"type,omitempty"`
+	Name   string    `json:"name"`
+	Access Access    `json:"access"`
+	Result Result    `json:"result"`
+
+	// Version is the version of a secret that was requested, if the action
+	// pinned one.
+	Version int `json:"version,omitempty"`
+}
+
+// Sink receives audit events.
+type Sink interface {
+	Emit(ctx context.Context, ev *Event) error
+}
+
+type accessContextKey int
+
+const (
+	accessAccessContextKey accessContextKey = iota
+)
+
+// NewContextWithAccess returns a context that indicates to audited managers
+// how data is being requested.
+func NewContextWithAccess(ctx context.Context, access Access) context.Context {
+	return context.WithValue(ctx, accessAccessContextKey, access)
+}
+
+// AccessFromContext returns the access method stored in the given context. If
+// none is set, the access is assumed to be direct.
+func AccessFromContext(ctx context.Context) Access {
+	access, ok := ctx.Value(accessAccessContextKey).(Access)
+	if !ok {
+		return AccessDirect
+	}
+
+	return access
+}
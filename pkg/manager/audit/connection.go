@@ -0,0 +1,47 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+// ConnectionManager records an audit event for every connection request made
+// to its delegate.
+type ConnectionManager struct {
+	delegate model.ConnectionManager
+	sink     Sink
+	actor    Actor
+	now      func() time.Time
+}
+
+var _ model.ConnectionManager = &ConnectionManager{}
+
+func (m *ConnectionManager) Get(ctx context.Context, typ, name string) (*model.Connection, error) {
+	conn, err := m.delegate.Get(ctx, typ, name)
+
+	if aerr := m.sink.Emit(ctx, &Event{
+		Time:   m.now(),
+		Actor:  m.actor,
+		Kind:   KindConnection,
+		Type:   typ,
+		Name:   name,
+		Access: AccessFromContext(ctx),
+		Result: resultFromError(err),
+	}); aerr != nil {
+		// If we can't record the access, we can't allow it.
+		return nil, aerr
+	}
+
+	return conn, err
+}
+
+func NewConnectionManager(delegate model.ConnectionManager, sink Sink, actor Actor) *ConnectionManager {
+	return &ConnectionManager{
+		delegate: delegate,
+		sink:     sink,
+		actor:    actor,
+		now:      time.Now,
+	}
+}
@@ -0,0 +1,47 @@
+package audit_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/puppetlabs/relay-core/pkg/manager/audit"
+	"github.com/puppetlabs/relay-core/pkg/manager/memory"
+	"github.com/puppetlabs/relay-core/pkg/manager/resolve"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectionManager(t *testing.T) {
+	ctx := context.Background()
+
+	actor := audit.Actor{
+		TenantID:   "tenant-1",
+		RunID:      "run-1",
+		ActionType: "step",
+		ActionName: "step-1",
+	}
+
+	buf := &bytes.Buffer{}
+	cm := audit.NewConnectionManager(memory.NewConnectionManager(map[memory.ConnectionKey]map[string]interface{}{
+		{Type: "aws", Name: "prod"}: {"accessKeyID": "abc"},
+	}), audit.NewJSONLinesSink(buf), actor)
+
+	_, err := cm.Get(ctx, "aws", "prod")
+	require.NoError(t, err)
+
+	_, err = resolve.NewConnectionTypeResolver(cm).ResolveConnection(ctx, "aws", "staging")
+	require.Error(t, err)
+
+	evs := decodeEvents(t, buf)
+	require.Len(t, evs, 2)
+
+	require.Equal(t, audit.KindConnection, evs[0].Kind)
+	require.Equal(t, "aws", evs[0].Type)
+	require.Equal(t, "prod", evs[0].Name)
+	require.Equal(t, audit.AccessDirect, evs[0].Access)
+	require.Equal(t, audit.ResultGranted, evs[0].Result)
+
+	require.Equal(t, "staging", evs[1].Name)
+	require.Equal(t, audit.AccessExpression, evs[1].Access)
+	require.Equal(t, audit.ResultNotFound, evs[1].Result)
+}
@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+// SecretManager records an audit event for every secret request made to its
+// delegate.
+type SecretManager struct {
+	delegate model.SecretManager
+	sink     Sink
+	actor    Actor
+	now      func() time.Time
+}
+
+var _ model.SecretManager = &SecretManager{}
+
+func (m *SecretManager) Get(ctx context.Context, name string) (*model.Secret, error) {
+	sec, err := m.delegate.Get(ctx, name)
+	return m.emit(ctx, sec, name, 0, err)
+}
+
+func (m *SecretManager) GetVersion(ctx context.Context, name string, version int) (*model.Secret, error) {
+	sec, err := m.delegate.GetVersion(ctx, name, version)
+	return m.emit(ctx, sec, name, version, err)
+}
+
+func (m *SecretManager) emit(ctx context.Context, sec *model.Secret, name string, version int, err error) (*model.Secret, error) {
+	if aerr := m.sink.Emit(ctx, &Event{
+		Time:    m.now(),
+		Actor:   m.actor,
+		Kind:    KindSecret,
+		Name:    name,
+		Version: version,
+		Access:  AccessFromContext(ctx),
+		Result:  resultFromError(err),
+	}); aerr != nil {
+		// If we can't record the access, we can't allow it.
+		return nil, aerr
+	}
+
+	return sec, err
+}
+
+func NewSecretManager(delegate model.SecretManager, sink Sink, actor Actor) *SecretManager {
+	return &SecretManager{
+		delegate: delegate,
+		sink:     sink,
+		actor:    actor,
+		now:      time.Now,
+	}
+}
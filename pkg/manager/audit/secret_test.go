@@ -0,0 +1,82 @@
+package audit_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/puppetlabs/relay-core/pkg/manager/audit"
+	"github.com/puppetlabs/relay-core/pkg/manager/memory"
+	"github.com/puppetlabs/relay-core/pkg/manager/reject"
+	"github.com/puppetlabs/relay-core/pkg/manager/resolve"
+	"github.com/puppetlabs/relay-core/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeEvents(t *testing.T, buf *bytes.Buffer) []*audit.Event {
+	var evs []*audit.Event
+
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		ev := &audit.Event{}
+		require.NoError(t, dec.Decode(ev))
+		evs = append(evs, ev)
+	}
+
+	return evs
+}
+
+func TestSecretManager(t *testing.T) {
+	ctx := context.Background()
+
+	actor := audit.Actor{
+		TenantID:   "tenant-1",
+		RunID:      "run-1",
+		ActionType: "step",
+		ActionName: "step-1",
+	}
+
+	buf := &bytes.Buffer{}
+	sm := audit.NewSecretManager(memory.NewSecretManager(map[string]string{"foo": "bar"}), audit.NewJSONLinesSink(buf), actor)
+
+	sec, err := sm.Get(ctx, "foo")
+	require.NoError(t, err)
+	require.Equal(t, "bar", sec.Value)
+
+	_, err = sm.Get(ctx, "baz")
+	require.Equal(t, model.ErrNotFound, err)
+
+	value, err := resolve.NewSecretTypeResolver(sm).ResolveSecret(ctx, "foo")
+	require.NoError(t, err)
+	require.Equal(t, "bar", value)
+
+	_, err = audit.NewSecretManager(reject.SecretManager, audit.NewJSONLinesSink(buf), actor).Get(ctx, "foo")
+	require.Equal(t, model.ErrRejected, err)
+
+	evs := decodeEvents(t, buf)
+	require.Len(t, evs, 4)
+
+	for _, ev := range evs {
+		require.Equal(t, actor, ev.Actor)
+		require.Equal(t, audit.KindSecret, ev.Kind)
+		require.False(t, ev.Time.IsZero())
+	}
+
+	require.Equal(t, "foo", evs[0].Name)
+	require.Equal(t, audit.AccessDirect, evs[0].Access)
+	require.Equal(t, audit.ResultGranted, evs[0].Result)
+
+	require.Equal(t, "baz", evs[1].Name)
+	require.Equal(t, audit.AccessDirect, evs[1].Access)
+	require.Equal(t, audit.ResultNotFound, evs[1].Result)
+
+	require.Equal(t, "foo", evs[2].Name)
+	require.Equal(t, audit.AccessExpression, evs[2].Access)
+	require.Equal(t, audit.ResultGranted, evs[2].Result)
+
+	require.Equal(t, audit.ResultRejected, evs[3].Result)
+
+	// The secret value must never be recorded.
+	require.NotContains(t, buf.String(), "bar")
+}
@@ -0,0 +1,51 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// JSONLinesSink writes each event as a single line of JSON to a writer.
+type JSONLinesSink struct {
+	mut sync.Mutex
+	w   io.Writer
+}
+
+var _ Sink = &JSONLinesSink{}
+
+func (s *JSONLinesSink) Emit(ctx context.Context, ev *Event) error {
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	_, err = s.w.Write(append(b, '\n'))
+	return err
+}
+
+func NewJSONLinesSink(w io.Writer) *JSONLinesSink {
+	return &JSONLinesSink{w: w}
+}
+
+// NewStdoutSink creates a sink that writes events to the standard output of
+// this process.
+func NewStdoutSink() *JSONLinesSink {
+	return NewJSONLinesSink(os.Stdout)
+}
+
+// NewFileSink creates a sink that appends events to the file at the given
+// path, creating it if necessary.
+func NewFileSink(path string) (*JSONLinesSink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewJSONLinesSink(f), nil
+}
@@ -65,6 +65,13 @@ func (mm *metadataManagers) StepOutputs() model.StepOutputManager {
 	return mm.stepOutputs
 }
 
+// SecretManagerDecoratorFunc wraps a secret manager to augment its behavior.
+type SecretManagerDecoratorFunc func(m model.SecretManager) model.SecretManager
+
+// ConnectionManagerDecoratorFunc wraps a connection manager to augment its
+// behavior.
+type ConnectionManagerDecoratorFunc func(m model.ConnectionManager) model.ConnectionManager
+
 type MetadataBuilder struct {
 	actionMetadata model.ActionMetadataManager
 	connections    model.ConnectionManager
@@ -77,6 +84,9 @@ type MetadataBuilder struct {
 	spec           model.SpecGetterManager
 	state          model.StateGetterManager
 	stepOutputs    model.StepOutputManager
+
+	secretDecorators     []SecretManagerDecoratorFunc
+	connectionDecorators []ConnectionManagerDecoratorFunc
 }
 
 func (mb *MetadataBuilder) SetActionMetadata(m model.ActionMetadataManager) *MetadataBuilder {
@@ -134,16 +144,42 @@ func (mb *MetadataBuilder) SetStepOutputs(m model.StepOutputManager) *MetadataBu
 	return mb
 }
 
+// DecorateSecrets adds a decorator to apply to the secret manager when the
+// managers are built. Decorators are applied in the order they are added, so
+// the last decorator added is the outermost one.
+func (mb *MetadataBuilder) DecorateSecrets(fn SecretManagerDecoratorFunc) *MetadataBuilder {
+	mb.secretDecorators = append(mb.secretDecorators, fn)
+	return mb
+}
+
+// DecorateConnections adds a decorator to apply to the connection manager when
+// the managers are built. Decorators are applied in the order they are added,
+// so the last decorator added is the outermost one.
+func (mb *MetadataBuilder) DecorateConnections(fn ConnectionManagerDecoratorFunc) *MetadataBuilder {
+	mb.connectionDecorators = append(mb.connectionDecorators, fn)
+	return mb
+}
+
 func (mb *MetadataBuilder) Build() model.MetadataManagers {
+	secrets := mb.secrets
+	for _, fn := range mb.secretDecorators {
+		secrets = fn(secrets)
+	}
+
+	connections := mb.connections
+	for _, fn := range mb.connectionDecorators {
+		connections = fn(connections)
+	}
+
 	return &metadataManagers{
 		actionMetadata: mb.actionMetadata,
-		connections:    mb.connections,
+		connections:    connections,
 		conditions:     mb.conditions,
 		events:         mb.events,
 		environment:    mb.environment,
 		logs:           mb.logs,
 		parameters:     mb.parameters,
-		secrets:        mb.secrets,
+		secrets:        secrets,
 		spec:           mb.spec,
 		state:          mb.state,
 		stepOutputs:    mb.stepOutputs,
@@ -0,0 +1,217 @@
+// Package cache provides decorators for secret and connection managers that
+// remember the values returned by their delegates for a short time.
+//
+// Resolving a step specification can reference the same secret or connection
+// many times, and actions often request their specification repeatedly. The
+// decorators in this package prevent each of those references from making a
+// request to the underlying secret store.
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/puppetlabs/horsehead/v2/instrumentation/metrics"
+	"github.com/puppetlabs/horsehead/v2/instrumentation/metrics/collectors"
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+const (
+	DefaultTTL         = 30 * time.Second
+	DefaultNegativeTTL = 5 * time.Second
+
+	metricCacheLookups = "secret_cache_lookups"
+)
+
+type kind string
+
+const (
+	kindSecret     kind = "secret"
+	kindConnection kind = "connection"
+)
+
+type result string
+
+const (
+	resultHit         result = "hit"
+	resultNegativeHit result = "negative_hit"
+	resultMiss        result = "miss"
+)
+
+type key struct {
+	scope   string
+	kind    kind
+	typ     string
+	name    string
+	version int
+}
+
+type entry struct {
+	value   interface{}
+	err     error
+	expires time.Time
+}
+
+// Cache stores the values of secrets and connections so that they can be
+// shared by managers created for different requests. Entries are partitioned
+// by a scope, like a workflow run, so that values are never shared between
+// actions that might not have access to the same secret store.
+//
+// Only successful lookups and lookups that result in model.ErrNotFound are
+// cached. Any other error is returned to the caller without being recorded.
+type Cache struct {
+	ttl         time.Duration
+	negativeTTL time.Duration
+	metrics     *metrics.Metrics
+	now         func() time.Time
+
+	mut       sync.Mutex
+	entries   map[key]*entry
+	lastSweep time.Time
+}
+
+// SecretManager returns a secret manager that caches the values returned by
+// the given delegate in the given scope.
+func (c *Cache) SecretManager(scope string, delegate model.SecretManager) *SecretManager {
+	return &SecretManager{
+		cache:    c,
+		scope:    scope,
+		delegate: delegate,
+	}
+}
+
+// ConnectionManager returns a connection manager that caches the values
+// returned by the given delegate in the given scope.
+func (c *Cache) ConnectionManager(scope string, delegate model.ConnectionManager) *ConnectionManager {
+	return &ConnectionManager{
+		cache:    c,
+		scope:    scope,
+		delegate: delegate,
+	}
+}
+
+func (c *Cache) get(k key) (*entry, bool) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	e, found := c.entries[k]
+	if !found || !c.now().Before(e.expires) {
+		c.observe(k.kind, resultMiss)
+		return nil, false
+	}
+
+	if e.err != nil {
+		c.observe(k.kind, resultNegativeHit)
+	} else {
+		c.observe(k.kind, resultHit)
+	}
+
+	return e, true
+}
+
+// set records the outcome of a lookup. If the delegate reported a lease
+// duration for the value that is shorter than the configured TTL, the lease
+// duration is used instead.
+func (c *Cache) set(k key, value interface{}, lease time.Duration, err error) {
+	var ttl time.Duration
+	switch err {
+	case nil:
+		ttl = c.ttl
+		if lease > 0 && lease < ttl {
+			ttl = lease
+		}
+	case model.ErrNotFound:
+		ttl = c.negativeTTL
+	default:
+		return
+	}
+
+	if ttl <= 0 {
+		return
+	}
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	now := c.now()
+
+	// Remove expired entries, but not on every lookup.
+	if now.Sub(c.lastSweep) > c.ttl {
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+
+		c.lastSweep = now
+	}
+
+	c.entries[k] = &entry{
+		value:   value,
+		err:     err,
+		expires: now.Add(ttl),
+	}
+}
+
+func (c *Cache) observe(k kind, r result) {
+	if c.metrics == nil {
+		return
+	}
+
+	c.metrics.MustCounter(metricCacheLookups, metrics.NewLabel("kind", string(k)), metrics.NewLabel("result", string(r))).Inc()
+}
+
+type CacheOption func(c *Cache)
+
+// CacheWithTTL sets the maximum amount of time to remember a secret or
+// connection for. If zero, values are not cached.
+func CacheWithTTL(ttl time.Duration) CacheOption {
+	return func(c *Cache) {
+		c.ttl = ttl
+	}
+}
+
+// CacheWithNegativeTTL sets the amount of time to remember that a secret or
+// connection does not exist for. If zero, missing values are not cached.
+func CacheWithNegativeTTL(ttl time.Duration) CacheOption {
+	return func(c *Cache) {
+		c.negativeTTL = ttl
+	}
+}
+
+// CacheWithMetrics reports the outcome of each cache lookup to the given
+// metrics collector.
+func CacheWithMetrics(mets *metrics.Metrics) CacheOption {
+	return func(c *Cache) {
+		c.metrics = mets
+	}
+}
+
+// CacheWithClock overrides the function used to determine the current time.
+func CacheWithClock(now func() time.Time) CacheOption {
+	return func(c *Cache) {
+		c.now = now
+	}
+}
+
+func NewCache(opts ...CacheOption) *Cache {
+	c := &Cache{
+		ttl:         DefaultTTL,
+		negativeTTL: DefaultNegativeTTL,
+		now:         time.Now,
+		entries:     make(map[key]*entry),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.metrics != nil {
+		c.metrics.MustRegisterCounter(metricCacheLookups, collectors.CounterOptions{
+			Description: "number of secret and connection lookups by the metadata API partitioned by whether the cache could answer them",
+			Labels:      []string{"kind", "result"},
+		})
+	}
+
+	return c
+}
@@ -0,0 +1,233 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/puppetlabs/relay-core/pkg/manager/cache"
+	"github.com/puppetlabs/relay-core/pkg/manager/memory"
+	"github.com/puppetlabs/relay-core/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+type countingSecretManager struct {
+	delegate model.SecretManager
+	lease    time.Duration
+	err      error
+	calls    int
+}
+
+func (m *countingSecretManager) Get(ctx context.Context, name string) (*model.Secret, error) {
+	sec, _, err := m.GetWithLease(ctx, name)
+	return sec, err
+}
+
+func (m *countingSecretManager) GetWithLease(ctx context.Context, name string) (*model.Secret, time.Duration, error) {
+	m.calls++
+
+	if m.err != nil {
+		return nil, 0, m.err
+	}
+
+	sec, err := m.delegate.Get(ctx, name)
+	return sec, m.lease, err
+}
+
+// GetVersion treats the current value of a secret as its only version.
+func (m *countingSecretManager) GetVersion(ctx context.Context, name string, version int) (*model.Secret, error) {
+	m.calls++
+
+	if m.err != nil {
+		return nil, m.err
+	} else if version != 1 {
+		return nil, model.ErrNotFound
+	}
+
+	return m.delegate.Get(ctx, name)
+}
+
+type countingConnectionManager struct {
+	delegate model.ConnectionManager
+	calls    int
+}
+
+func (m *countingConnectionManager) Get(ctx context.Context, typ, name string) (*model.Connection, error) {
+	m.calls++
+	return m.delegate.Get(ctx, typ, name)
+}
+
+func TestSecretManager(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+	c := cache.NewCache(
+		cache.CacheWithTTL(time.Minute),
+		cache.CacheWithNegativeTTL(10*time.Second),
+		cache.CacheWithClock(func() time.Time { return now }),
+	)
+
+	delegate := &countingSecretManager{
+		delegate: memory.NewSecretManager(map[string]string{"foo": "bar"}),
+	}
+	sm := c.SecretManager("run-1", delegate)
+
+	for i := 0; i < 3; i++ {
+		sec, err := sm.Get(ctx, "foo")
+		require.NoError(t, err)
+		require.Equal(t, "bar", sec.Value)
+
+		// Modifying the returned secret must not affect the cache.
+		sec.Value = "modified"
+	}
+	require.Equal(t, 1, delegate.calls)
+
+	// Missing secrets are cached too.
+	for i := 0; i < 3; i++ {
+		_, err := sm.Get(ctx, "missing")
+		require.Equal(t, model.ErrNotFound, err)
+	}
+	require.Equal(t, 2, delegate.calls)
+
+	// Other scopes do not share entries.
+	_, err := c.SecretManager("run-2", delegate).Get(ctx, "foo")
+	require.NoError(t, err)
+	require.Equal(t, 3, delegate.calls)
+
+	// The negative entry expires first.
+	now = now.Add(15 * time.Second)
+
+	_, err = sm.Get(ctx, "missing")
+	require.Equal(t, model.ErrNotFound, err)
+	require.Equal(t, 4, delegate.calls)
+
+	_, err = sm.Get(ctx, "foo")
+	require.NoError(t, err)
+	require.Equal(t, 4, delegate.calls)
+
+	// Then the positive one.
+	now = now.Add(time.Minute)
+
+	_, err = sm.Get(ctx, "foo")
+	require.NoError(t, err)
+	require.Equal(t, 5, delegate.calls)
+}
+
+func TestSecretManagerVersion(t *testing.T) {
+	ctx := context.Background()
+
+	c := cache.NewCache(
+		cache.CacheWithTTL(time.Minute),
+		cache.CacheWithNegativeTTL(10*time.Second),
+	)
+
+	delegate := &countingSecretManager{
+		delegate: memory.NewSecretManager(map[string]string{"foo": "bar"}),
+	}
+	sm := c.SecretManager("run-1", delegate)
+
+	for i := 0; i < 3; i++ {
+		sec, err := sm.GetVersion(ctx, "foo", 1)
+		require.NoError(t, err)
+		require.Equal(t, "bar", sec.Value)
+	}
+	require.Equal(t, 1, delegate.calls)
+
+	// The latest version is cached separately.
+	_, err := sm.Get(ctx, "foo")
+	require.NoError(t, err)
+	require.Equal(t, 2, delegate.calls)
+
+	// And so is each other version.
+	for i := 0; i < 3; i++ {
+		_, err := sm.GetVersion(ctx, "foo", 2)
+		require.Equal(t, model.ErrNotFound, err)
+	}
+	require.Equal(t, 3, delegate.calls)
+}
+
+func TestSecretManagerLease(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+	c := cache.NewCache(
+		cache.CacheWithTTL(time.Minute),
+		cache.CacheWithClock(func() time.Time { return now }),
+	)
+
+	delegate := &countingSecretManager{
+		delegate: memory.NewSecretManager(map[string]string{"foo": "bar"}),
+		lease:    10 * time.Second,
+	}
+	sm := c.SecretManager("run-1", delegate)
+
+	_, err := sm.Get(ctx, "foo")
+	require.NoError(t, err)
+
+	now = now.Add(5 * time.Second)
+
+	_, err = sm.Get(ctx, "foo")
+	require.NoError(t, err)
+	require.Equal(t, 1, delegate.calls)
+
+	// The lease is shorter than the TTL, so it takes precedence.
+	now = now.Add(10 * time.Second)
+
+	_, err = sm.Get(ctx, "foo")
+	require.NoError(t, err)
+	require.Equal(t, 2, delegate.calls)
+}
+
+func TestSecretManagerErrorsAreNotCached(t *testing.T) {
+	ctx := context.Background()
+
+	c := cache.NewCache()
+
+	delegate := &countingSecretManager{
+		err: errors.New("vault is unavailable"),
+	}
+	sm := c.SecretManager("run-1", delegate)
+
+	for i := 0; i < 3; i++ {
+		_, err := sm.Get(ctx, "foo")
+		require.Equal(t, delegate.err, err)
+	}
+	require.Equal(t, 3, delegate.calls)
+}
+
+func TestConnectionManager(t *testing.T) {
+	ctx := context.Background()
+
+	c := cache.NewCache()
+
+	delegate := &countingConnectionManager{
+		delegate: memory.NewConnectionManager(map[memory.ConnectionKey]map[string]interface{}{
+			{Type: "aws", Name: "foo"}: {"accessKeyID": "AKIA123456789"},
+		}),
+	}
+	cm := c.ConnectionManager("run-1", delegate)
+
+	for i := 0; i < 3; i++ {
+		conn, err := cm.Get(ctx, "aws", "foo")
+		require.NoError(t, err)
+		require.Equal(t, "AKIA123456789", conn.Attributes["accessKeyID"])
+
+		// Modifying the returned attributes must not affect the cache.
+		conn.Attributes["accessKeyID"] = "modified"
+	}
+	require.Equal(t, 1, delegate.calls)
+
+	// Connections of different types are cached separately.
+	_, err := cm.Get(ctx, "gcp", "foo")
+	require.Equal(t, model.ErrNotFound, err)
+	require.Equal(t, 2, delegate.calls)
+
+	// A cache with no TTL does not remember anything.
+	uncached := cache.NewCache(cache.CacheWithTTL(0), cache.CacheWithNegativeTTL(0)).ConnectionManager("run-1", delegate)
+	for i := 0; i < 2; i++ {
+		_, err := uncached.Get(ctx, "aws", "foo")
+		require.NoError(t, err)
+	}
+	require.Equal(t, 4, delegate.calls)
+}
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+// LeasedConnectionManager is implemented by connection managers that know how
+// long the connections they return remain valid.
+type LeasedConnectionManager interface {
+	// GetWithLease retrieves a connection and its lease duration. If the
+	// lease duration is zero, the connection does not expire.
+	GetWithLease(ctx context.Context, typ, name string) (*model.Connection, time.Duration, error)
+}
+
+// ConnectionManager caches the connections returned by its delegate.
+type ConnectionManager struct {
+	cache    *Cache
+	scope    string
+	delegate model.ConnectionManager
+}
+
+var _ model.ConnectionManager = &ConnectionManager{}
+
+func (m *ConnectionManager) Get(ctx context.Context, typ, name string) (*model.Connection, error) {
+	k := key{scope: m.scope, kind: kindConnection, typ: typ, name: name}
+
+	if e, found := m.cache.get(k); found {
+		if e.err != nil {
+			return nil, e.err
+		}
+
+		return copyConnection(e.value.(*model.Connection)), nil
+	}
+
+	var conn *model.Connection
+	var lease time.Duration
+	var err error
+	if lm, ok := m.delegate.(LeasedConnectionManager); ok {
+		conn, lease, err = lm.GetWithLease(ctx, typ, name)
+	} else {
+		conn, err = m.delegate.Get(ctx, typ, name)
+	}
+
+	if err != nil {
+		m.cache.set(k, nil, 0, err)
+		return nil, err
+	}
+
+	m.cache.set(k, copyConnection(conn), lease, nil)
+
+	return conn, nil
+}
+
+// copyConnection makes a copy of the given connection so that callers that
+// modify the attributes of a connection do not affect the cached value.
+func copyConnection(conn *model.Connection) *model.Connection {
+	attrs := make(map[string]interface{}, len(conn.Attributes))
+	for k, v := range conn.Attributes {
+		attrs[k] = v
+	}
+
+	return &model.Connection{
+		Type:       conn.Type,
+		Name:       conn.Name,
+		Attributes: attrs,
+	}
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+// LeasedSecretManager is implemented by secret managers that know how long
+// the secrets they return remain valid.
+type LeasedSecretManager interface {
+	// GetWithLease retrieves a secret and its lease duration. If the lease
+	// duration is zero, the secret does not expire.
+	GetWithLease(ctx context.Context, name string) (*model.Secret, time.Duration, error)
+}
+
+// SecretManager caches the secrets returned by its delegate.
+type SecretManager struct {
+	cache    *Cache
+	scope    string
+	delegate model.SecretManager
+}
+
+var _ model.SecretManager = &SecretManager{}
+
+func (m *SecretManager) Get(ctx context.Context, name string) (*model.Secret, error) {
+	return m.get(key{scope: m.scope, kind: kindSecret, name: name}, func() (*model.Secret, time.Duration, error) {
+		if lm, ok := m.delegate.(LeasedSecretManager); ok {
+			return lm.GetWithLease(ctx, name)
+		}
+
+		sec, err := m.delegate.Get(ctx, name)
+		return sec, 0, err
+	})
+}
+
+// GetVersion retrieves a specific version of a secret. The content of a
+// version never changes, but the version may still be deleted, so it is only
+// cached for the configured TTL.
+func (m *SecretManager) GetVersion(ctx context.Context, name string, version int) (*model.Secret, error) {
+	return m.get(key{scope: m.scope, kind: kindSecret, name: name, version: version}, func() (*model.Secret, time.Duration, error) {
+		sec, err := m.delegate.GetVersion(ctx, name, version)
+		return sec, 0, err
+	})
+}
+
+func (m *SecretManager) get(k key, fetch func() (*model.Secret, time.Duration, error)) (*model.Secret, error) {
+	if e, found := m.cache.get(k); found {
+		if e.err != nil {
+			return nil, e.err
+		}
+
+		sec := *e.value.(*model.Secret)
+		return &sec, nil
+	}
+
+	sec, lease, err := fetch()
+	if err != nil {
+		m.cache.set(k, nil, 0, err)
+		return nil, err
+	}
+
+	cached := *sec
+	m.cache.set(k, &cached, lease, nil)
+
+	return sec, nil
+}
@@ -0,0 +1,92 @@
+package configmap
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/puppetlabs/relay-core/pkg/manager/refresh"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+const leaseKeyPrefix = "leases."
+
+// LeaseManager records the leases on credentials issued to the actions of a
+// run so that the operator can revoke them when the run ends.
+type LeaseManager struct {
+	cm ConfigMap
+}
+
+var _ refresh.LeaseRecorder = &LeaseManager{}
+
+func (m *LeaseManager) AddLease(ctx context.Context, lease *refresh.Lease) error {
+	encoded, err := json.Marshal(lease)
+	if err != nil {
+		return err
+	}
+
+	_, err = MutateConfigMap(ctx, m.cm, func(cm *corev1.ConfigMap) {
+		cm.Data[leaseKey(lease.ID)] = string(encoded)
+	})
+	return err
+}
+
+// List returns the recorded leases ordered by their IDs.
+func (m *LeaseManager) List(ctx context.Context) ([]*refresh.Lease, error) {
+	cm, err := m.cm.Get(ctx)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var leases []*refresh.Lease
+	for key, encoded := range cm.Data {
+		if !strings.HasPrefix(key, leaseKeyPrefix) {
+			continue
+		}
+
+		lease := &refresh.Lease{}
+		if err := json.Unmarshal([]byte(encoded), lease); err != nil {
+			return nil, err
+		}
+
+		leases = append(leases, lease)
+	}
+
+	sort.Slice(leases, func(i, j int) bool {
+		return leases[i].ID < leases[j].ID
+	})
+
+	return leases, nil
+}
+
+// Remove forgets the given leases, for example because they have been
+// revoked.
+func (m *LeaseManager) Remove(ctx context.Context, leases ...*refresh.Lease) error {
+	if len(leases) == 0 {
+		return nil
+	}
+
+	_, err := MutateConfigMap(ctx, m.cm, func(cm *corev1.ConfigMap) {
+		for _, lease := range leases {
+			delete(cm.Data, leaseKey(lease.ID))
+		}
+	})
+	return err
+}
+
+func NewLeaseManager(cm ConfigMap) *LeaseManager {
+	return &LeaseManager{
+		cm: cm,
+	}
+}
+
+func leaseKey(id string) string {
+	// Lease IDs contain slashes, which are not valid in keys.
+	return fmt.Sprintf("%s%x", leaseKeyPrefix, sha1.Sum([]byte(id)))
+}
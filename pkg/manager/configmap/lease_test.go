@@ -0,0 +1,46 @@
+package configmap_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/puppetlabs/relay-core/pkg/manager/configmap"
+	"github.com/puppetlabs/relay-core/pkg/manager/refresh"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestLeaseManager(t *testing.T) {
+	ctx := context.Background()
+
+	obj := &corev1.ConfigMap{
+		Data: map[string]string{
+			"steps.foo.state.bar": `{"$encoding":"json","data":"baz"}`,
+		},
+	}
+	lm := configmap.NewLeaseManager(configmap.NewLocalConfigMap(obj))
+
+	leases, err := lm.List(ctx)
+	require.NoError(t, err)
+	require.Empty(t, leases)
+
+	a := &refresh.Lease{ID: "aws/creds/deploy/a"}
+	b := &refresh.Lease{ID: "database/creds/deploy/b", Namespace: "tenant"}
+
+	require.NoError(t, lm.AddLease(ctx, b))
+	require.NoError(t, lm.AddLease(ctx, a))
+	require.NoError(t, lm.AddLease(ctx, a))
+
+	leases, err = lm.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []*refresh.Lease{a, b}, leases)
+
+	require.NoError(t, lm.Remove(ctx, a))
+
+	leases, err = lm.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []*refresh.Lease{b}, leases)
+
+	// Other data in the config map is left alone.
+	require.Contains(t, obj.Data, "steps.foo.state.bar")
+}
@@ -0,0 +1,65 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+type ConnectionManager struct {
+	dir string
+}
+
+var _ model.ConnectionManager = &ConnectionManager{}
+
+func (m *ConnectionManager) path(typ, name string) (string, error) {
+	return filePath(filepath.Join(m.dir, ConnectionsDirName), typ, name+jsonExt)
+}
+
+func (m *ConnectionManager) Get(ctx context.Context, typ, name string) (*model.Connection, error) {
+	path, err := m.path(typ, name)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := readValueFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("filesystem: %s: connection attributes must be an object", path)
+	}
+
+	return &model.Connection{
+		Type:       typ,
+		Name:       name,
+		Attributes: attrs,
+	}, nil
+}
+
+func (m *ConnectionManager) Set(ctx context.Context, typ, name string, attrs map[string]interface{}) (*model.Connection, error) {
+	path, err := m.path(typ, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeValueFile(path, attrs); err != nil {
+		return nil, err
+	}
+
+	return &model.Connection{
+		Type:       typ,
+		Name:       name,
+		Attributes: attrs,
+	}, nil
+}
+
+func NewConnectionManager(dir string) *ConnectionManager {
+	return &ConnectionManager{
+		dir: dir,
+	}
+}
@@ -0,0 +1,147 @@
+// Package filesystem provides managers that store the metadata of workflow
+// runs as files in a directory. It is intended for developing workflows
+// locally, where it is useful to inspect and edit what each step reads and
+// writes.
+//
+// The directory is laid out as follows:
+//
+//	secrets/<name>                                  the raw value of a secret
+//	connections/<type>/<name>.json                  a JSON object of attributes
+//	runs/<run>/parameters/<name>.json               a JSON value
+//	runs/<run>/steps/<step>/outputs/<name>.json     a JSON value
+//	runs/<run>/steps/<step>/state/<name>.json       a JSON value
+//
+// Answers to queries are read from the state of a step, the same way the
+// metadata API resolves them from any other state manager.
+//
+// Values are encoded as JSON, with strings that are not valid UTF-8 encoded
+// the same way the metadata API transfers them. Names are escaped as URL path
+// segments to form file names.
+package filesystem
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/puppetlabs/horsehead/v2/encoding/transfer"
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+const (
+	SecretsDirName     = "secrets"
+	ConnectionsDirName = "connections"
+	RunsDirName        = "runs"
+	ParametersDirName  = "parameters"
+	OutputsDirName     = "outputs"
+	StateDirName       = "state"
+
+	jsonExt = ".json"
+)
+
+type InvalidNameError struct {
+	Name string
+}
+
+func (e *InvalidNameError) Error() string {
+	return fmt.Sprintf("filesystem: %q cannot be used as a name", e.Name)
+}
+
+func fileName(name string) (string, error) {
+	switch name {
+	case "", ".", "..":
+		return "", &InvalidNameError{Name: name}
+	}
+
+	return url.PathEscape(name), nil
+}
+
+func filePath(dir string, elems ...string) (string, error) {
+	parts := []string{dir}
+	for _, elem := range elems {
+		name, err := fileName(elem)
+		if err != nil {
+			return "", err
+		}
+
+		parts = append(parts, name)
+	}
+
+	return filepath.Join(parts...), nil
+}
+
+func runDir(dir string, run model.Run) (string, error) {
+	return filePath(filepath.Join(dir, RunsDirName), run.ID)
+}
+
+func stepDir(dir string, step *model.Step) (string, error) {
+	rd, err := runDir(dir, step.Run)
+	if err != nil {
+		return "", err
+	}
+
+	return filePath(filepath.Join(rd, step.Type().Plural), step.Name)
+}
+
+func readFile(path string) ([]byte, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, model.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func readValueFile(path string) (interface{}, error) {
+	b, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var value transfer.JSONInterface
+	if err := json.Unmarshal(b, &value); err != nil {
+		return nil, fmt.Errorf("filesystem: %s: %+v", path, err)
+	}
+
+	return value.Data, nil
+}
+
+// writeFile replaces the content of the file at the given path atomically so
+// that concurrent readers never observe a partial write.
+func writeFile(path string, b []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(dir, "."+filepath.Base(path)+".")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+func writeValueFile(path string, value interface{}) error {
+	b, err := json.MarshalIndent(transfer.JSONInterface{Data: value}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFile(path, append(b, '\n'))
+}
@@ -0,0 +1,108 @@
+package filesystem_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/puppetlabs/relay-core/pkg/manager/filesystem"
+	"github.com/puppetlabs/relay-core/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretManager(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "relay-filesystem-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	sm := filesystem.NewSecretManager(dir)
+
+	_, err = sm.Get(ctx, "foo")
+	require.Equal(t, model.ErrNotFound, err)
+
+	_, err = sm.Set(ctx, "foo/bar", "baz")
+	require.NoError(t, err)
+
+	sec, err := sm.Get(ctx, "foo/bar")
+	require.NoError(t, err)
+	require.Equal(t, "baz", sec.Value)
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "secrets", "foo%2Fbar"))
+	require.NoError(t, err)
+	require.Equal(t, "baz", string(b))
+
+	_, err = sm.Get(ctx, "..")
+	require.Equal(t, &filesystem.InvalidNameError{Name: ".."}, err)
+}
+
+func TestConnectionManager(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "relay-filesystem-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cm := filesystem.NewConnectionManager(dir)
+
+	_, err = cm.Get(ctx, "aws", "foo")
+	require.Equal(t, model.ErrNotFound, err)
+
+	_, err = cm.Set(ctx, "aws", "foo", map[string]interface{}{"accessKeyID": "AKIA123456789"})
+	require.NoError(t, err)
+
+	conn, err := cm.Get(ctx, "aws", "foo")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"accessKeyID": "AKIA123456789"}, conn.Attributes)
+
+	require.FileExists(t, filepath.Join(dir, "connections", "aws", "foo.json"))
+}
+
+func TestRunManagers(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "relay-filesystem-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	run := model.Run{ID: "run-1"}
+	previous := &model.Step{Run: run, Name: "previous"}
+	current := &model.Step{Run: run, Name: "current"}
+
+	pm := filesystem.NewParameterManager(run, dir)
+	_, err = pm.Set(ctx, "version", map[string]interface{}{"major": float64(1)})
+	require.NoError(t, err)
+
+	param, err := pm.Get(ctx, "version")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"major": float64(1)}, param.Value)
+
+	_, err = filesystem.NewStepOutputManager(previous, dir).Set(ctx, "foo", "bar")
+	require.NoError(t, err)
+
+	// Outputs written by one step are visible to the next, even across
+	// manager instances.
+	out, err := filesystem.NewStepOutputManager(current, dir).Get(ctx, "previous", "foo")
+	require.NoError(t, err)
+	require.Equal(t, "bar", out.Value)
+	require.Equal(t, "previous", out.Step.Name)
+
+	_, err = filesystem.NewStepOutputManager(current, dir).Get(ctx, "previous", "baz")
+	require.Equal(t, model.ErrNotFound, err)
+
+	sm := filesystem.NewStateManager(current, dir)
+	_, err = sm.Set(ctx, "answers", []interface{}{"yes"})
+	require.NoError(t, err)
+
+	st, err := sm.Get(ctx, "answers")
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"yes"}, st.Value)
+
+	// State is private to each step.
+	_, err = filesystem.NewStateManager(previous, dir).Get(ctx, "answers")
+	require.Equal(t, model.ErrNotFound, err)
+
+	require.FileExists(t, filepath.Join(dir, "runs", "run-1", "parameters", "version.json"))
+	require.FileExists(t, filepath.Join(dir, "runs", "run-1", "steps", "previous", "outputs", "foo.json"))
+	require.FileExists(t, filepath.Join(dir, "runs", "run-1", "steps", "current", "state", "answers.json"))
+}
@@ -0,0 +1,64 @@
+package filesystem
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+type ParameterManager struct {
+	run model.Run
+	dir string
+}
+
+var _ model.ParameterManager = &ParameterManager{}
+
+func (m *ParameterManager) path(name string) (string, error) {
+	rd, err := runDir(m.dir, m.run)
+	if err != nil {
+		return "", err
+	}
+
+	return filePath(filepath.Join(rd, ParametersDirName), name+jsonExt)
+}
+
+func (m *ParameterManager) Get(ctx context.Context, name string) (*model.Parameter, error) {
+	path, err := m.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := readValueFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Parameter{
+		Name:  name,
+		Value: value,
+	}, nil
+}
+
+func (m *ParameterManager) Set(ctx context.Context, name string, value interface{}) (*model.Parameter, error) {
+	path, err := m.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeValueFile(path, value); err != nil {
+		return nil, err
+	}
+
+	return &model.Parameter{
+		Name:  name,
+		Value: value,
+	}, nil
+}
+
+func NewParameterManager(run model.Run, dir string) *ParameterManager {
+	return &ParameterManager{
+		run: run,
+		dir: dir,
+	}
+}
@@ -0,0 +1,59 @@
+package filesystem
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+type SecretManager struct {
+	dir string
+}
+
+var _ model.SecretManager = &SecretManager{}
+
+func (m *SecretManager) Get(ctx context.Context, name string) (*model.Secret, error) {
+	path, err := filePath(filepath.Join(m.dir, SecretsDirName), name)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Secret{
+		Name:  name,
+		Value: string(b),
+	}, nil
+}
+
+// GetVersion always returns ErrNotFound because the filesystem only holds the
+// current version of each secret.
+func (m *SecretManager) GetVersion(ctx context.Context, name string, version int) (*model.Secret, error) {
+	return nil, model.ErrNotFound
+}
+
+func (m *SecretManager) Set(ctx context.Context, name, value string) (*model.Secret, error) {
+	path, err := filePath(filepath.Join(m.dir, SecretsDirName), name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeFile(path, []byte(value)); err != nil {
+		return nil, err
+	}
+
+	return &model.Secret{
+		Name:  name,
+		Value: value,
+	}, nil
+}
+
+func NewSecretManager(dir string) *SecretManager {
+	return &SecretManager{
+		dir: dir,
+	}
+}
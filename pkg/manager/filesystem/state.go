@@ -0,0 +1,64 @@
+package filesystem
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+type StateManager struct {
+	me  *model.Step
+	dir string
+}
+
+var _ model.StateManager = &StateManager{}
+
+func (m *StateManager) path(name string) (string, error) {
+	sd, err := stepDir(m.dir, m.me)
+	if err != nil {
+		return "", err
+	}
+
+	return filePath(filepath.Join(sd, StateDirName), name+jsonExt)
+}
+
+func (m *StateManager) Get(ctx context.Context, name string) (*model.State, error) {
+	path, err := m.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := readValueFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.State{
+		Name:  name,
+		Value: value,
+	}, nil
+}
+
+func (m *StateManager) Set(ctx context.Context, name string, value interface{}) (*model.State, error) {
+	path, err := m.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeValueFile(path, value); err != nil {
+		return nil, err
+	}
+
+	return &model.State{
+		Name:  name,
+		Value: value,
+	}, nil
+}
+
+func NewStateManager(step *model.Step, dir string) *StateManager {
+	return &StateManager{
+		me:  step,
+		dir: dir,
+	}
+}
@@ -0,0 +1,71 @@
+package filesystem
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+type StepOutputManager struct {
+	me  *model.Step
+	dir string
+}
+
+var _ model.StepOutputManager = &StepOutputManager{}
+
+func (m *StepOutputManager) path(step *model.Step, name string) (string, error) {
+	sd, err := stepDir(m.dir, step)
+	if err != nil {
+		return "", err
+	}
+
+	return filePath(filepath.Join(sd, OutputsDirName), name+jsonExt)
+}
+
+func (m *StepOutputManager) Get(ctx context.Context, stepName, name string) (*model.StepOutput, error) {
+	step := &model.Step{
+		Run:  m.me.Run,
+		Name: stepName,
+	}
+
+	path, err := m.path(step, name)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := readValueFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.StepOutput{
+		Step:  step,
+		Name:  name,
+		Value: value,
+	}, nil
+}
+
+func (m *StepOutputManager) Set(ctx context.Context, name string, value interface{}) (*model.StepOutput, error) {
+	path, err := m.path(m.me, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeValueFile(path, value); err != nil {
+		return nil, err
+	}
+
+	return &model.StepOutput{
+		Step:  m.me,
+		Name:  name,
+		Value: value,
+	}, nil
+}
+
+func NewStepOutputManager(step *model.Step, dir string) *StepOutputManager {
+	return &StepOutputManager{
+		me:  step,
+		dir: dir,
+	}
+}
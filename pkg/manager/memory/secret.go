@@ -24,6 +24,12 @@ func (m *SecretManager) Get(ctx context.Context, name string) (*model.Secret, er
 	}, nil
 }
 
+// GetVersion always returns ErrNotFound because previous versions of secrets
+// are not kept in memory.
+func (m *SecretManager) GetVersion(ctx context.Context, name string, version int) (*model.Secret, error) {
+	return nil, model.ErrNotFound
+}
+
 func NewSecretManager(secrets map[string]string) *SecretManager {
 	m := make(map[string]string, len(secrets))
 	for k, v := range secrets {
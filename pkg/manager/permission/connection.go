@@ -0,0 +1,31 @@
+package permission
+
+import (
+	"context"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+// ConnectionManager rejects requests for connections the action was not
+// granted access to.
+type ConnectionManager struct {
+	delegate    model.ConnectionManager
+	permissions *model.ActionPermissions
+}
+
+var _ model.ConnectionManager = &ConnectionManager{}
+
+func (m *ConnectionManager) Get(ctx context.Context, typ, name string) (*model.Connection, error) {
+	if !m.permissions.AllowsConnection(typ, name) {
+		return nil, model.ErrRejected
+	}
+
+	return m.delegate.Get(ctx, typ, name)
+}
+
+func NewConnectionManager(delegate model.ConnectionManager, permissions *model.ActionPermissions) *ConnectionManager {
+	return &ConnectionManager{
+		delegate:    delegate,
+		permissions: permissions,
+	}
+}
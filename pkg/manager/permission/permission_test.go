@@ -0,0 +1,98 @@
+package permission_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/puppetlabs/relay-core/pkg/manager/memory"
+	"github.com/puppetlabs/relay-core/pkg/manager/permission"
+	"github.com/puppetlabs/relay-core/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretManager(t *testing.T) {
+	ctx := context.Background()
+
+	delegate := memory.NewSecretManager(map[string]string{
+		"foo": "bar",
+		"baz": "quux",
+	})
+
+	sm := permission.NewSecretManager(delegate, &model.ActionPermissions{
+		Secrets: []string{"foo", "missing"},
+	})
+
+	sec, err := sm.Get(ctx, "foo")
+	require.NoError(t, err)
+	require.Equal(t, "bar", sec.Value)
+
+	_, err = sm.Get(ctx, "baz")
+	require.Equal(t, model.ErrRejected, err)
+
+	_, err = sm.Get(ctx, "missing")
+	require.Equal(t, model.ErrNotFound, err)
+
+	// Without any permissions, all secrets are accessible.
+	sec, err = permission.NewSecretManager(delegate, nil).Get(ctx, "baz")
+	require.NoError(t, err)
+	require.Equal(t, "quux", sec.Value)
+
+	// With empty permissions, no secrets are accessible.
+	_, err = permission.NewSecretManager(delegate, &model.ActionPermissions{}).Get(ctx, "foo")
+	require.Equal(t, model.ErrRejected, err)
+}
+
+func TestConnectionManager(t *testing.T) {
+	ctx := context.Background()
+
+	delegate := memory.NewConnectionManager(map[memory.ConnectionKey]map[string]interface{}{
+		{Type: "aws", Name: "foo"}: {"accessKeyID": "AKIA123456789"},
+		{Type: "gcp", Name: "foo"}: {"serviceAccountInfo": "{}"},
+	})
+
+	cm := permission.NewConnectionManager(delegate, &model.ActionPermissions{
+		Connections: []model.ActionPermissionConnection{
+			{Type: "aws", Name: "foo"},
+		},
+	})
+
+	conn, err := cm.Get(ctx, "aws", "foo")
+	require.NoError(t, err)
+	require.Equal(t, "AKIA123456789", conn.Attributes["accessKeyID"])
+
+	_, err = cm.Get(ctx, "gcp", "foo")
+	require.Equal(t, model.ErrRejected, err)
+
+	_, err = permission.NewConnectionManager(delegate, nil).Get(ctx, "gcp", "foo")
+	require.NoError(t, err)
+}
+
+func TestStepOutputManager(t *testing.T) {
+	ctx := context.Background()
+
+	run := model.Run{ID: "run-1"}
+	backend := memory.NewStepOutputMap()
+
+	_, err := memory.NewStepOutputManager(&model.Step{Run: run, Name: "previous"}, backend).Set(ctx, "foo", "bar")
+	require.NoError(t, err)
+
+	_, err = memory.NewStepOutputManager(&model.Step{Run: run, Name: "previous"}, backend).Set(ctx, "baz", "quux")
+	require.NoError(t, err)
+
+	om := permission.NewStepOutputManager(memory.NewStepOutputManager(&model.Step{Run: run, Name: "current"}, backend), &model.ActionPermissions{
+		Outputs: []model.ActionPermissionOutput{
+			{From: "previous", Name: "foo"},
+		},
+	})
+
+	out, err := om.Get(ctx, "previous", "foo")
+	require.NoError(t, err)
+	require.Equal(t, "bar", out.Value)
+
+	_, err = om.Get(ctx, "previous", "baz")
+	require.Equal(t, model.ErrRejected, err)
+
+	// A step can always set its own outputs.
+	_, err = om.Set(ctx, "result", "ok")
+	require.NoError(t, err)
+}
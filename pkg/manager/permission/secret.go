@@ -0,0 +1,39 @@
+package permission
+
+import (
+	"context"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+// SecretManager rejects requests for secrets the action was not granted
+// access to.
+type SecretManager struct {
+	delegate    model.SecretManager
+	permissions *model.ActionPermissions
+}
+
+var _ model.SecretManager = &SecretManager{}
+
+func (m *SecretManager) Get(ctx context.Context, name string) (*model.Secret, error) {
+	if !m.permissions.AllowsSecret(name) {
+		return nil, model.ErrRejected
+	}
+
+	return m.delegate.Get(ctx, name)
+}
+
+func (m *SecretManager) GetVersion(ctx context.Context, name string, version int) (*model.Secret, error) {
+	if !m.permissions.AllowsSecret(name) {
+		return nil, model.ErrRejected
+	}
+
+	return m.delegate.GetVersion(ctx, name, version)
+}
+
+func NewSecretManager(delegate model.SecretManager, permissions *model.ActionPermissions) *SecretManager {
+	return &SecretManager{
+		delegate:    delegate,
+		permissions: permissions,
+	}
+}
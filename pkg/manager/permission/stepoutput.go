@@ -0,0 +1,35 @@
+package permission
+
+import (
+	"context"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+// StepOutputManager rejects requests for the outputs of other steps that the
+// action was not granted access to. An action may always set its own outputs.
+type StepOutputManager struct {
+	delegate    model.StepOutputManager
+	permissions *model.ActionPermissions
+}
+
+var _ model.StepOutputManager = &StepOutputManager{}
+
+func (m *StepOutputManager) Get(ctx context.Context, stepName, name string) (*model.StepOutput, error) {
+	if !m.permissions.AllowsOutput(stepName, name) {
+		return nil, model.ErrRejected
+	}
+
+	return m.delegate.Get(ctx, stepName, name)
+}
+
+func (m *StepOutputManager) Set(ctx context.Context, name string, value interface{}) (*model.StepOutput, error) {
+	return m.delegate.Set(ctx, name, value)
+}
+
+func NewStepOutputManager(delegate model.StepOutputManager, permissions *model.ActionPermissions) *StepOutputManager {
+	return &StepOutputManager{
+		delegate:    delegate,
+		permissions: permissions,
+	}
+}
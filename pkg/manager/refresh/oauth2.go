@@ -0,0 +1,165 @@
+package refresh
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+	"golang.org/x/oauth2"
+)
+
+const (
+	OAuth2AccessTokenAttribute  = "accessToken"
+	OAuth2RefreshTokenAttribute = "refreshToken"
+	OAuth2TokenTypeAttribute    = "tokenType"
+	OAuth2ExpiresAtAttribute    = "expiresAt"
+	OAuth2ClientIDAttribute     = "clientID"
+	OAuth2ClientSecretAttribute = "clientSecret"
+)
+
+// DefaultOAuth2ExpiryDelta is the default amount of time before an access token
+// expires that it is no longer considered valid.
+const DefaultOAuth2ExpiryDelta = time.Minute
+
+// OAuth2Refresher exchanges the refresh token of a connection for a new access
+// token using the OAuth 2 refresh token grant against a token endpoint.
+//
+// The refresh token is read from the refreshToken attribute of the connection.
+// Connections without a refresh token are returned unchanged. The client
+// credentials to present to the token endpoint are taken from the
+// configuration of the refresher or, if not configured, from the clientID and
+// clientSecret attributes of the connection.
+//
+// The refreshed connection has the accessToken, tokenType, and expiresAt
+// attributes set. If the token endpoint rotates the refresh token, the new
+// refresh token replaces the old one in the returned connection and is written
+// to the store given to Refresh so that the next refresh presents it. Without
+// a store, a rotated refresh token would be lost, so Refresh fails instead.
+type OAuth2Refresher struct {
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scopes       []string
+	authStyle    oauth2.AuthStyle
+	expiryDelta  time.Duration
+}
+
+var _ Refresher = &OAuth2Refresher{}
+
+func (r *OAuth2Refresher) Refresh(ctx context.Context, conn *model.Connection, store Store) (*model.Connection, time.Duration, error) {
+	refreshToken, _ := conn.Attributes[OAuth2RefreshTokenAttribute].(string)
+	if refreshToken == "" {
+		return conn, 0, nil
+	}
+
+	cfg := &oauth2.Config{
+		ClientID:     r.clientID,
+		ClientSecret: r.clientSecret,
+		Endpoint: oauth2.Endpoint{
+			TokenURL:  r.tokenURL,
+			AuthStyle: r.authStyle,
+		},
+		Scopes: r.scopes,
+	}
+	if cfg.ClientID == "" {
+		cfg.ClientID, _ = conn.Attributes[OAuth2ClientIDAttribute].(string)
+		cfg.ClientSecret, _ = conn.Attributes[OAuth2ClientSecretAttribute].(string)
+	}
+
+	tok, err := cfg.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		return nil, 0, fmt.Errorf("refresh: failed to refresh OAuth 2 token for connection %s/%s: %+v", conn.Type, conn.Name, err)
+	}
+
+	attrs := copyAttributes(conn)
+	attrs[OAuth2AccessTokenAttribute] = tok.AccessToken
+	attrs[OAuth2TokenTypeAttribute] = tok.Type()
+	if tok.RefreshToken != "" && tok.RefreshToken != refreshToken {
+		// Many authorization servers invalidate the old refresh token as soon
+		// as they issue a new one.
+		if store == nil {
+			return nil, 0, fmt.Errorf("refresh: token endpoint rotated the refresh token for connection %s/%s, but there is nowhere to store it", conn.Type, conn.Name)
+		}
+
+		if err := store.SetAttribute(ctx, OAuth2RefreshTokenAttribute, tok.RefreshToken); err != nil {
+			return nil, 0, fmt.Errorf("refresh: failed to store rotated refresh token for connection %s/%s: %+v", conn.Type, conn.Name, err)
+		}
+
+		attrs[OAuth2RefreshTokenAttribute] = tok.RefreshToken
+	}
+
+	var lease time.Duration
+	if !tok.Expiry.IsZero() {
+		attrs[OAuth2ExpiresAtAttribute] = tok.Expiry.UTC().Format(time.RFC3339)
+
+		// Stop handing out the token a little before it actually expires so
+		// that steps have time to use it. If the token is already that close
+		// to expiring, use whatever time remains.
+		lease = time.Until(tok.Expiry)
+		if lease > r.expiryDelta {
+			lease -= r.expiryDelta
+		} else if lease <= 0 {
+			// The token is already expired. A zero lease would mean the
+			// validity is unknown and let callers keep the token around, so
+			// make the lease as short as possible instead.
+			lease = time.Nanosecond
+		}
+	} else {
+		delete(attrs, OAuth2ExpiresAtAttribute)
+	}
+
+	return &model.Connection{
+		Type:       conn.Type,
+		Name:       conn.Name,
+		Attributes: attrs,
+	}, lease, nil
+}
+
+type OAuth2RefresherOption func(r *OAuth2Refresher)
+
+// OAuth2RefresherWithClientCredentials presents the given client ID and secret
+// to the token endpoint instead of the client credentials stored with each
+// connection.
+func OAuth2RefresherWithClientCredentials(id, secret string) OAuth2RefresherOption {
+	return func(r *OAuth2Refresher) {
+		r.clientID = id
+		r.clientSecret = secret
+	}
+}
+
+// OAuth2RefresherWithScopes requests the given scopes for new access tokens.
+func OAuth2RefresherWithScopes(scopes []string) OAuth2RefresherOption {
+	return func(r *OAuth2Refresher) {
+		r.scopes = append([]string{}, scopes...)
+	}
+}
+
+// OAuth2RefresherWithAuthStyle sets how client credentials are presented to
+// the token endpoint. By default, the style is detected automatically.
+func OAuth2RefresherWithAuthStyle(style oauth2.AuthStyle) OAuth2RefresherOption {
+	return func(r *OAuth2Refresher) {
+		r.authStyle = style
+	}
+}
+
+// OAuth2RefresherWithExpiryDelta sets how long before an access token expires
+// it is no longer considered valid. The default is DefaultOAuth2ExpiryDelta.
+func OAuth2RefresherWithExpiryDelta(delta time.Duration) OAuth2RefresherOption {
+	return func(r *OAuth2Refresher) {
+		r.expiryDelta = delta
+	}
+}
+
+func NewOAuth2Refresher(tokenURL string, opts ...OAuth2RefresherOption) *OAuth2Refresher {
+	r := &OAuth2Refresher{
+		tokenURL:    tokenURL,
+		expiryDelta: DefaultOAuth2ExpiryDelta,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
@@ -0,0 +1,105 @@
+// Package refresh provides fresh credentials for connections whose stored
+// attributes only describe how to obtain them, like an OAuth 2 refresh token
+// or the path to a dynamic secret.
+package refresh
+
+import (
+	"context"
+	"time"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+// Lease identifies credentials issued by a secrets engine that can be revoked
+// before they expire.
+type Lease struct {
+	// ID is the identifier of the lease assigned by the secrets engine.
+	ID string `json:"id"`
+
+	// Namespace is the Vault Enterprise namespace that issued the lease, if
+	// any.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// LeaseRecorder keeps track of the leases on credentials issued to an action
+// so that they can be revoked once the action no longer needs them.
+type LeaseRecorder interface {
+	AddLease(ctx context.Context, lease *Lease) error
+}
+
+// Store persists the changes a refresher makes while refreshing a connection.
+type Store interface {
+	LeaseRecorder
+
+	// SetAttribute replaces the stored value of an attribute of the
+	// connection, like a refresh token rotated by a token endpoint.
+	SetAttribute(ctx context.Context, name, value string) error
+}
+
+// Refresher obtains up-to-date credentials for a connection.
+type Refresher interface {
+	// Refresh returns a connection with current credentials derived from the
+	// given connection along with the duration for which the credentials are
+	// valid. A zero duration means the validity of the credentials is
+	// unknown. Refresh must not modify the given connection.
+	//
+	// Any state that must outlive the refreshed connection is written to the
+	// given store. If the store is nil and the refresher needs to write to
+	// it, Refresh returns an error instead of losing the state.
+	Refresh(ctx context.Context, conn *model.Connection, store Store) (*model.Connection, time.Duration, error)
+}
+
+type RefresherFunc func(ctx context.Context, conn *model.Connection, store Store) (*model.Connection, time.Duration, error)
+
+var _ Refresher = RefresherFunc(nil)
+
+func (rf RefresherFunc) Refresh(ctx context.Context, conn *model.Connection, store Store) (*model.Connection, time.Duration, error) {
+	return rf(ctx, conn, store)
+}
+
+// Registry dispatches to a refresher by the type of the connection. Connections
+// of a type without a refresher are returned unchanged.
+type Registry struct {
+	refreshers map[string]Refresher
+}
+
+var _ Refresher = &Registry{}
+
+func (r *Registry) Refresh(ctx context.Context, conn *model.Connection, store Store) (*model.Connection, time.Duration, error) {
+	refresher, found := r.refreshers[conn.Type]
+	if !found {
+		return conn, 0, nil
+	}
+
+	return refresher.Refresh(ctx, conn, store)
+}
+
+type RegistryOption func(r *Registry)
+
+// RegistryWithRefresher uses the given refresher for connections of the given
+// type, replacing any refresher previously configured for the type.
+func RegistryWithRefresher(typ string, refresher Refresher) RegistryOption {
+	return func(r *Registry) {
+		r.refreshers[typ] = refresher
+	}
+}
+
+func NewRegistry(opts ...RegistryOption) *Registry {
+	r := &Registry{
+		refreshers: make(map[string]Refresher),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+func copyAttributes(conn *model.Connection) map[string]interface{} {
+	attrs := make(map[string]interface{}, len(conn.Attributes))
+	for k, v := range conn.Attributes {
+		attrs[k] = v
+	}
+	return attrs
+}
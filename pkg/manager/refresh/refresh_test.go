@@ -0,0 +1,313 @@
+package refresh_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/puppetlabs/relay-core/pkg/manager/refresh"
+	"github.com/puppetlabs/relay-core/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// tokenEndpoint is a minimal OAuth 2 token endpoint that implements the
+// refresh token grant.
+type tokenEndpoint struct {
+	t            *testing.T
+	clientID     string
+	clientSecret string
+	refreshToken string
+	expiresIn    int
+	rotate       bool
+	requests     int
+}
+
+func (te *tokenEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	te.requests++
+
+	require.NoError(te.t, r.ParseForm())
+
+	id, secret := r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	if bid, bsecret, ok := r.BasicAuth(); ok {
+		id, secret = bid, bsecret
+	}
+
+	w.Header().Set("content-type", "application/json")
+
+	if r.PostForm.Get("grant_type") != "refresh_token" ||
+		r.PostForm.Get("refresh_token") != te.refreshToken ||
+		id != te.clientID || secret != te.clientSecret {
+		w.WriteHeader(http.StatusBadRequest)
+		assert.NoError(te.t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "invalid_grant",
+		}))
+		return
+	}
+
+	resp := map[string]interface{}{
+		"access_token": "access-token",
+		"token_type":   "bearer",
+	}
+	if te.expiresIn != 0 {
+		resp["expires_in"] = te.expiresIn
+	}
+	if te.rotate {
+		resp["refresh_token"] = "rotated-refresh-token"
+	}
+
+	assert.NoError(te.t, json.NewEncoder(w).Encode(resp))
+}
+
+// attributeStore is a refresh.Store that keeps attributes and leases in
+// memory.
+type attributeStore struct {
+	attributes map[string]string
+	leases     []*refresh.Lease
+}
+
+func (as *attributeStore) AddLease(ctx context.Context, lease *refresh.Lease) error {
+	as.leases = append(as.leases, lease)
+	return nil
+}
+
+func (as *attributeStore) SetAttribute(ctx context.Context, name, value string) error {
+	if as.attributes == nil {
+		as.attributes = make(map[string]string)
+	}
+
+	as.attributes[name] = value
+	return nil
+}
+
+func TestOAuth2Refresher(t *testing.T) {
+	ctx := context.Background()
+
+	te := &tokenEndpoint{
+		t:            t,
+		clientID:     "client-id",
+		clientSecret: "client-secret",
+		refreshToken: "refresh-token",
+		expiresIn:    3600,
+	}
+
+	srv := httptest.NewServer(te)
+	defer srv.Close()
+
+	conn := &model.Connection{
+		Type: "oauth",
+		Name: "test",
+		Attributes: map[string]interface{}{
+			"clientID":     "client-id",
+			"clientSecret": "client-secret",
+			"refreshToken": "refresh-token",
+		},
+	}
+
+	r := refresh.NewOAuth2Refresher(srv.URL, refresh.OAuth2RefresherWithAuthStyle(oauth2.AuthStyleInParams))
+
+	store := &attributeStore{}
+
+	refreshed, lease, err := r.Refresh(ctx, conn, store)
+	require.NoError(t, err)
+	assert.Equal(t, "oauth", refreshed.Type)
+	assert.Equal(t, "test", refreshed.Name)
+	assert.Equal(t, "access-token", refreshed.Attributes["accessToken"])
+	assert.Equal(t, "Bearer", refreshed.Attributes["tokenType"])
+	assert.Equal(t, "refresh-token", refreshed.Attributes["refreshToken"])
+	assert.Contains(t, refreshed.Attributes, "expiresAt")
+	assert.InDelta(t, float64(59*time.Minute), float64(lease), float64(5*time.Second))
+
+	// The original connection must not be modified.
+	assert.NotContains(t, conn.Attributes, "accessToken")
+	assert.Equal(t, 1, te.requests)
+
+	// Nothing needed to be stored.
+	assert.Empty(t, store.attributes)
+}
+
+func TestOAuth2RefresherConfiguredClientCredentials(t *testing.T) {
+	ctx := context.Background()
+
+	te := &tokenEndpoint{
+		t:            t,
+		clientID:     "configured-client-id",
+		clientSecret: "configured-client-secret",
+		refreshToken: "refresh-token",
+		rotate:       true,
+	}
+
+	srv := httptest.NewServer(te)
+	defer srv.Close()
+
+	r := refresh.NewOAuth2Refresher(
+		srv.URL,
+		refresh.OAuth2RefresherWithAuthStyle(oauth2.AuthStyleInHeader),
+		refresh.OAuth2RefresherWithClientCredentials("configured-client-id", "configured-client-secret"),
+	)
+
+	store := &attributeStore{}
+
+	refreshed, lease, err := r.Refresh(ctx, &model.Connection{
+		Type: "oauth",
+		Name: "test",
+		Attributes: map[string]interface{}{
+			"clientID":     "ignored",
+			"refreshToken": "refresh-token",
+			"expiresAt":    "2006-01-02T15:04:05Z",
+		},
+	}, store)
+	require.NoError(t, err)
+	assert.Equal(t, "access-token", refreshed.Attributes["accessToken"])
+	assert.Equal(t, "rotated-refresh-token", refreshed.Attributes["refreshToken"])
+	assert.NotContains(t, refreshed.Attributes, "expiresAt")
+	assert.Equal(t, time.Duration(0), lease)
+	assert.Equal(t, map[string]string{"refreshToken": "rotated-refresh-token"}, store.attributes)
+}
+
+func TestOAuth2RefresherRotationWithoutStore(t *testing.T) {
+	ctx := context.Background()
+
+	te := &tokenEndpoint{
+		t:            t,
+		clientID:     "client-id",
+		clientSecret: "client-secret",
+		refreshToken: "refresh-token",
+		rotate:       true,
+	}
+
+	srv := httptest.NewServer(te)
+	defer srv.Close()
+
+	r := refresh.NewOAuth2Refresher(srv.URL, refresh.OAuth2RefresherWithAuthStyle(oauth2.AuthStyleInParams))
+
+	// The old refresh token may no longer be valid, so the rotated token must
+	// not be silently discarded.
+	_, _, err := r.Refresh(ctx, &model.Connection{
+		Type: "oauth",
+		Name: "test",
+		Attributes: map[string]interface{}{
+			"clientID":     "client-id",
+			"clientSecret": "client-secret",
+			"refreshToken": "refresh-token",
+		},
+	}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "oauth/test")
+	assert.Contains(t, err.Error(), "rotated the refresh token")
+}
+
+func TestOAuth2RefresherExpiredToken(t *testing.T) {
+	ctx := context.Background()
+
+	te := &tokenEndpoint{
+		t:            t,
+		clientID:     "client-id",
+		clientSecret: "client-secret",
+		refreshToken: "refresh-token",
+		expiresIn:    -60,
+	}
+
+	srv := httptest.NewServer(te)
+	defer srv.Close()
+
+	r := refresh.NewOAuth2Refresher(srv.URL, refresh.OAuth2RefresherWithAuthStyle(oauth2.AuthStyleInParams))
+
+	_, lease, err := r.Refresh(ctx, &model.Connection{
+		Type: "oauth",
+		Name: "test",
+		Attributes: map[string]interface{}{
+			"clientID":     "client-id",
+			"clientSecret": "client-secret",
+			"refreshToken": "refresh-token",
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	// A zero lease would mean the token never expires.
+	assert.True(t, lease > 0)
+	assert.True(t, lease < time.Second)
+}
+
+func TestOAuth2RefresherErrors(t *testing.T) {
+	ctx := context.Background()
+
+	te := &tokenEndpoint{
+		t:            t,
+		clientID:     "client-id",
+		clientSecret: "client-secret",
+		refreshToken: "refresh-token",
+	}
+
+	srv := httptest.NewServer(te)
+	defer srv.Close()
+
+	r := refresh.NewOAuth2Refresher(srv.URL, refresh.OAuth2RefresherWithAuthStyle(oauth2.AuthStyleInParams))
+
+	// Without a refresh token, the connection is passed through.
+	conn := &model.Connection{
+		Type: "oauth",
+		Name: "static",
+		Attributes: map[string]interface{}{
+			"accessToken": "static-token",
+		},
+	}
+
+	refreshed, lease, err := r.Refresh(ctx, conn, nil)
+	require.NoError(t, err)
+	assert.Equal(t, conn, refreshed)
+	assert.Equal(t, time.Duration(0), lease)
+	assert.Equal(t, 0, te.requests)
+
+	// A revoked refresh token is an error.
+	_, _, err = r.Refresh(ctx, &model.Connection{
+		Type: "oauth",
+		Name: "revoked",
+		Attributes: map[string]interface{}{
+			"clientID":     "client-id",
+			"clientSecret": "client-secret",
+			"refreshToken": "revoked-refresh-token",
+		},
+	}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "oauth/revoked")
+	assert.Equal(t, 1, te.requests)
+}
+
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+
+	reg := refresh.NewRegistry(
+		refresh.RegistryWithRefresher("dynamic", refresh.RefresherFunc(func(ctx context.Context, conn *model.Connection, store refresh.Store) (*model.Connection, time.Duration, error) {
+			return &model.Connection{
+				Type: conn.Type,
+				Name: conn.Name,
+				Attributes: map[string]interface{}{
+					"token": "fresh",
+				},
+			}, time.Minute, nil
+		})),
+	)
+
+	refreshed, lease, err := reg.Refresh(ctx, &model.Connection{Type: "dynamic", Name: "test"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "fresh", refreshed.Attributes["token"])
+	assert.Equal(t, time.Minute, lease)
+
+	static := &model.Connection{
+		Type: "static",
+		Name: "test",
+		Attributes: map[string]interface{}{
+			"token": "stale",
+		},
+	}
+
+	refreshed, lease, err = reg.Refresh(ctx, static, nil)
+	require.NoError(t, err)
+	assert.Equal(t, static, refreshed)
+	assert.Equal(t, time.Duration(0), lease)
+}
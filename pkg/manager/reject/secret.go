@@ -12,4 +12,8 @@ func (*secretManager) Get(ctx context.Context, name string) (*model.Secret, erro
 	return nil, model.ErrRejected
 }
 
+func (*secretManager) GetVersion(ctx context.Context, name string, version int) (*model.Secret, error) {
+	return nil, model.ErrRejected
+}
+
 var SecretManager model.SecretManager = &secretManager{}
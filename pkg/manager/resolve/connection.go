@@ -5,6 +5,7 @@ import (
 
 	exprmodel "github.com/puppetlabs/relay-core/pkg/expr/model"
 	"github.com/puppetlabs/relay-core/pkg/expr/resolve"
+	"github.com/puppetlabs/relay-core/pkg/manager/audit"
 	"github.com/puppetlabs/relay-core/pkg/model"
 )
 
@@ -15,7 +16,7 @@ type ConnectionTypeResolver struct {
 var _ resolve.ConnectionTypeResolver = &ConnectionTypeResolver{}
 
 func (ctr *ConnectionTypeResolver) ResolveConnection(ctx context.Context, typ, name string) (interface{}, error) {
-	so, err := ctr.m.Get(ctx, typ, name)
+	so, err := ctr.m.Get(audit.NewContextWithAccess(ctx, audit.AccessExpression), typ, name)
 	if err == model.ErrNotFound {
 		return nil, &exprmodel.ConnectionNotFoundError{Type: typ, Name: name}
 	} else if err != nil {
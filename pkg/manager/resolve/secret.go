@@ -5,6 +5,7 @@ import (
 
 	exprmodel "github.com/puppetlabs/relay-core/pkg/expr/model"
 	"github.com/puppetlabs/relay-core/pkg/expr/resolve"
+	"github.com/puppetlabs/relay-core/pkg/manager/audit"
 	"github.com/puppetlabs/relay-core/pkg/model"
 )
 
@@ -13,9 +14,21 @@ type SecretTypeResolver struct {
 }
 
 var _ resolve.SecretTypeResolver = &SecretTypeResolver{}
+var _ resolve.SecretVersionTypeResolver = &SecretTypeResolver{}
 
 func (str *SecretTypeResolver) ResolveSecret(ctx context.Context, name string) (string, error) {
-	so, err := str.m.Get(ctx, name)
+	so, err := str.m.Get(audit.NewContextWithAccess(ctx, audit.AccessExpression), name)
+	if err == model.ErrNotFound {
+		return "", &exprmodel.SecretNotFoundError{Name: name}
+	} else if err != nil {
+		return "", err
+	}
+
+	return so.Value, nil
+}
+
+func (str *SecretTypeResolver) ResolveSecretVersion(ctx context.Context, name string, version int) (string, error) {
+	so, err := str.m.GetVersion(audit.NewContextWithAccess(ctx, audit.AccessExpression), name, version)
 	if err == model.ErrNotFound {
 		return "", &exprmodel.SecretNotFoundError{Name: name}
 	} else if err != nil {
@@ -0,0 +1,51 @@
+package secret
+
+import (
+	"context"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ConnectionManager reads Relay connections from labelled Kubernetes secrets
+// in a namespace. Each Kubernetes secret must be named using ConnectionName.
+// Each data key of the Kubernetes secret is an attribute of the connection.
+type ConnectionManager struct {
+	store *store
+}
+
+var _ model.ConnectionManager = &ConnectionManager{}
+
+func (m *ConnectionManager) Get(ctx context.Context, typ, name string) (*model.Connection, error) {
+	sec, err := m.store.get(ctx, ConnectionName(typ, name), TypeConnection, map[string]string{
+		ConnectionTypeAnnotation: typ,
+		NameAnnotation:           name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if sec == nil {
+		return nil, model.ErrNotFound
+	}
+
+	attrs := make(map[string]interface{}, len(sec.Data))
+	for key, value := range sec.Data {
+		attrs[key] = string(value)
+	}
+
+	return &model.Connection{
+		Type:       typ,
+		Name:       name,
+		Attributes: attrs,
+	}, nil
+}
+
+func NewConnectionManager(client kubernetes.Interface, namespace string) *ConnectionManager {
+	return &ConnectionManager{
+		store: &store{
+			client:    client,
+			namespace: namespace,
+		},
+	}
+}
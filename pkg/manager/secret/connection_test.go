@@ -0,0 +1,55 @@
+package secret_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/puppetlabs/relay-core/pkg/manager/secret"
+	"github.com/puppetlabs/relay-core/pkg/model"
+	"github.com/puppetlabs/relay-core/pkg/util/testutil"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConnectionManager(t *testing.T) {
+	ctx := context.Background()
+
+	client := testutil.NewMockKubernetesClient(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "my-tenant",
+				Name:      secret.ConnectionName("some-type", "test"),
+				Labels:    map[string]string{secret.TypeLabel: secret.TypeConnection},
+				Annotations: map[string]string{
+					secret.ConnectionTypeAnnotation: "some-type",
+					secret.NameAnnotation:           "test",
+				},
+			},
+			Data: map[string][]byte{
+				"foo": []byte("bar"),
+				"baz": []byte("quux"),
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "my-tenant",
+				Name:        secret.SecretName("test"),
+				Labels:      map[string]string{secret.TypeLabel: secret.TypeSecret},
+				Annotations: map[string]string{secret.NameAnnotation: "test"},
+			},
+			Data: map[string][]byte{secret.ValueKey: []byte("nope")},
+		},
+	)
+
+	cm := secret.NewConnectionManager(client, "my-tenant")
+
+	conn, err := cm.Get(ctx, "some-type", "test")
+	require.NoError(t, err)
+	require.Equal(t, "some-type", conn.Type)
+	require.Equal(t, "test", conn.Name)
+	require.Equal(t, map[string]interface{}{"foo": "bar", "baz": "quux"}, conn.Attributes)
+
+	_, err = cm.Get(ctx, "some-other-type", "test")
+	require.Equal(t, model.ErrNotFound, err)
+}
@@ -0,0 +1,54 @@
+package secret
+
+import (
+	"context"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+	"k8s.io/client-go/kubernetes"
+)
+
+// SecretManager reads Relay secrets from labelled Kubernetes secrets in a
+// namespace. Each Kubernetes secret must be named using SecretName.
+type SecretManager struct {
+	store *store
+}
+
+var _ model.SecretManager = &SecretManager{}
+
+func (m *SecretManager) Get(ctx context.Context, name string) (*model.Secret, error) {
+	sec, err := m.store.get(ctx, SecretName(name), TypeSecret, map[string]string{
+		NameAnnotation: name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if sec == nil {
+		return nil, model.ErrNotFound
+	}
+
+	value, found := sec.Data[ValueKey]
+	if !found {
+		return nil, model.ErrNotFound
+	}
+
+	return &model.Secret{
+		Name:  name,
+		Value: string(value),
+	}, nil
+}
+
+// GetVersion always returns ErrNotFound because Kubernetes secrets do not have
+// versions.
+func (m *SecretManager) GetVersion(ctx context.Context, name string, version int) (*model.Secret, error) {
+	return nil, model.ErrNotFound
+}
+
+func NewSecretManager(client kubernetes.Interface, namespace string) *SecretManager {
+	return &SecretManager{
+		store: &store{
+			client:    client,
+			namespace: namespace,
+		},
+	}
+}
@@ -0,0 +1,92 @@
+package secret_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/puppetlabs/relay-core/pkg/manager/secret"
+	"github.com/puppetlabs/relay-core/pkg/model"
+	"github.com/puppetlabs/relay-core/pkg/util/testutil"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSecretManager(t *testing.T) {
+	ctx := context.Background()
+
+	client := testutil.NewMockKubernetesClient(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "my-tenant",
+				Name:        secret.SecretName("foo"),
+				Labels:      map[string]string{secret.TypeLabel: secret.TypeSecret},
+				Annotations: map[string]string{secret.NameAnnotation: "foo"},
+			},
+			Data: map[string][]byte{secret.ValueKey: []byte("bar")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "my-tenant",
+				Name:        secret.SecretName("unlabeled"),
+				Annotations: map[string]string{secret.NameAnnotation: "unlabeled"},
+			},
+			Data: map[string][]byte{secret.ValueKey: []byte("nope")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "other-tenant",
+				Name:        secret.SecretName("baz"),
+				Labels:      map[string]string{secret.TypeLabel: secret.TypeSecret},
+				Annotations: map[string]string{secret.NameAnnotation: "baz"},
+			},
+			Data: map[string][]byte{secret.ValueKey: []byte("quux")},
+		},
+	)
+
+	sm := secret.NewSecretManager(client, "my-tenant")
+
+	sec, err := sm.Get(ctx, "foo")
+	require.NoError(t, err)
+	require.Equal(t, "foo", sec.Name)
+	require.Equal(t, "bar", sec.Value)
+
+	_, err = sm.Get(ctx, "unlabeled")
+	require.Equal(t, model.ErrNotFound, err)
+
+	_, err = sm.Get(ctx, "baz")
+	require.Equal(t, model.ErrNotFound, err)
+}
+
+func TestSecretManagerMismatchedName(t *testing.T) {
+	ctx := context.Background()
+
+	client := testutil.NewMockKubernetesClient(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "my-tenant",
+				Name:        secret.SecretName("foo"),
+				Labels:      map[string]string{secret.TypeLabel: secret.TypeSecret},
+				Annotations: map[string]string{secret.NameAnnotation: "bar"},
+			},
+			Data: map[string][]byte{secret.ValueKey: []byte("nope")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "my-tenant",
+				Name:        "bar",
+				Labels:      map[string]string{secret.TypeLabel: secret.TypeSecret},
+				Annotations: map[string]string{secret.NameAnnotation: "bar"},
+			},
+			Data: map[string][]byte{secret.ValueKey: []byte("nope")},
+		},
+	)
+
+	sm := secret.NewSecretManager(client, "my-tenant")
+
+	_, err := sm.Get(ctx, "foo")
+	require.Equal(t, model.ErrNotFound, err)
+
+	_, err = sm.Get(ctx, "bar")
+	require.Equal(t, model.ErrNotFound, err)
+}
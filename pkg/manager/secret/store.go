@@ -0,0 +1,83 @@
+package secret
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// TypeLabel marks a Kubernetes secret as holding a Relay secret or
+	// connection. Its value must be one of TypeSecret or TypeConnection.
+	TypeLabel = "relay.sh/secret-store-type"
+
+	TypeSecret     = "secret"
+	TypeConnection = "connection"
+
+	// NameAnnotation is the name of the Relay secret or connection held by a
+	// Kubernetes secret.
+	NameAnnotation = "relay.sh/secret-store-name"
+
+	// ConnectionTypeAnnotation is the type of the Relay connection held by a
+	// Kubernetes secret, like "aws" or "gcp".
+	ConnectionTypeAnnotation = "relay.sh/secret-store-connection-type"
+
+	// ValueKey is the data key that holds the value of a Relay secret.
+	ValueKey = "value"
+)
+
+// SecretName returns the name of the Kubernetes secret that holds the Relay
+// secret with the given name.
+func SecretName(name string) string {
+	return objectName(TypeSecret, name)
+}
+
+// ConnectionName returns the name of the Kubernetes secret that holds the
+// Relay connection with the given type and name.
+func ConnectionName(typ, name string) string {
+	return objectName(TypeConnection, typ, name)
+}
+
+// objectName hashes the identifying parts of a Relay secret or connection,
+// which may contain characters that aren't allowed in Kubernetes object names.
+func objectName(typ string, parts ...string) string {
+	// Neither Relay names nor connection types may contain a NUL byte, so the
+	// joined parts are unambiguous.
+	h := sha1.Sum([]byte(strings.Join(parts, "\x00")))
+	return fmt.Sprintf("relay-%s-%s", typ, hex.EncodeToString(h[:]))
+}
+
+type store struct {
+	client    kubernetes.Interface
+	namespace string
+}
+
+// get returns the Kubernetes secret with the given name, or nil if there is no
+// such secret or it is not labelled with the given type and annotations.
+func (s *store) get(ctx context.Context, name, typ string, annotations map[string]string) (*corev1.Secret, error) {
+	sec, err := s.client.CoreV1().Secrets(s.namespace).Get(name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if sec.GetLabels()[TypeLabel] != typ {
+		return nil, nil
+	}
+
+	for name, value := range annotations {
+		if sec.GetAnnotations()[name] != value {
+			return nil, nil
+		}
+	}
+
+	return sec, nil
+}
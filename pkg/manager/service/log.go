@@ -1,11 +1,16 @@
 package service
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/puppetlabs/relay-core/pkg/model"
 	"github.com/puppetlabs/relay-pls/pkg/plspb"
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -20,56 +25,153 @@ func (m *LogManager) PostLog(ctx context.Context, value interface{}) ([]byte, er
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
-	incoming, ok := value.(string)
-	if ok {
-		request := &plspb.LogCreateRequest{}
+	lr, ok := logRequest(value)
+	if !ok {
+		return nil, nil
+	}
 
-		err := proto.Unmarshal([]byte(incoming), request)
-		if err != nil {
+	request := &plspb.LogCreateRequest{}
+
+	switch lr.MediaType {
+	case model.LogMediaTypeProtobuf, model.LogMediaTypeJSON:
+		if err := unmarshalLogMessage(lr.MediaType, lr.Data, request); err != nil {
 			return nil, err
 		}
+	default:
+		return nil, fmt.Errorf("unsupported log request media type %q", lr.MediaType)
+	}
 
-		if request.Context == "" {
-			request.Context = m.logContext
-		}
+	if request.Context == "" {
+		request.Context = m.logContext
+	}
 
-		if m.logClient != nil {
-			response, err := m.logClient.Create(ctx, request)
-			if err != nil {
-				return nil, err
-			}
+	response := &plspb.LogCreateResponse{}
+	if m.logClient != nil {
+		var err error
 
-			return proto.Marshal(response)
+		response, err = m.logClient.Create(ctx, request)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	return nil, nil
+	return marshalLogMessage(lr.MediaType, response)
 }
 
 func (m *LogManager) PostLogMessage(ctx context.Context, logID string, value interface{}) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
+	lr, ok := logRequest(value)
+	if !ok {
+		return nil, nil
+	}
 
-	incoming, ok := value.(string)
-	if ok {
+	switch lr.MediaType {
+	case model.LogMediaTypeProtobuf, model.LogMediaTypeJSON:
 		request := &plspb.LogMessageAppendRequest{}
+		if err := unmarshalLogMessage(lr.MediaType, lr.Data, request); err != nil {
+			return nil, err
+		}
 
-		err := proto.Unmarshal([]byte(incoming), request)
+		response, err := m.appendLogMessage(logID, request)
 		if err != nil {
 			return nil, err
 		}
 
-		if m.logClient != nil {
-			response, err := m.logClient.MessageAppend(ctx, request)
-			if err != nil {
-				return nil, err
+		return marshalLogMessage(lr.MediaType, response)
+	case model.LogMediaTypeNDJSON:
+		return m.appendLogMessageBatch(logID, lr.Data)
+	default:
+		return nil, fmt.Errorf("unsupported log request media type %q", lr.MediaType)
+	}
+}
+
+func (m *LogManager) appendLogMessage(logID string, request *plspb.LogMessageAppendRequest) (*plspb.LogMessageAppendResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if request.LogId == "" {
+		request.LogId = logID
+	}
+
+	if m.logClient == nil {
+		return &plspb.LogMessageAppendResponse{}, nil
+	}
+
+	return m.logClient.MessageAppend(ctx, request)
+}
+
+// logMessageBatchError is written in place of the response for a message in a
+// batch that could not be appended.
+type logMessageBatchError struct {
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// appendLogMessageBatch appends each message in a newline-delimited JSON batch
+// in turn. The batch is rejected if any message is malformed. Otherwise, every
+// message is attempted and the result for each is reported on the
+// corresponding line of the response, so a client can tell which messages to
+// retry. If no message could be appended, the first error is returned instead.
+func (m *LogManager) appendLogMessageBatch(logID string, data []byte) ([]byte, error) {
+	var requests []*plspb.LogMessageAppendRequest
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 4*1024*1024)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		request := &plspb.LogMessageAppendRequest{}
+		if err := protojson.Unmarshal(line, request); err != nil {
+			return nil, err
+		}
+
+		requests = append(requests, request)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+
+	var firstErr error
+	var appended int
+
+	for _, request := range requests {
+		var b []byte
+
+		response, err := m.appendLogMessage(logID, request)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
 			}
 
-			return proto.Marshal(response)
+			var be logMessageBatchError
+			be.Error.Message = err.Error()
+
+			b, err = json.Marshal(be)
+		} else {
+			appended++
+
+			b, err = marshalLogMessage(model.LogMediaTypeNDJSON, response)
+		}
+		if err != nil {
+			return nil, err
 		}
+
+		buf.Write(b)
+		buf.WriteByte('\n')
+	}
+
+	if appended == 0 && firstErr != nil {
+		return nil, firstErr
 	}
 
-	return nil, nil
+	return buf.Bytes(), nil
 }
 
 type LogManagerOption func(lm *LogManager)
@@ -86,3 +188,35 @@ func NewLogManager(logClient plspb.LogClient, logContext string, opts ...LogMana
 
 	return lm
 }
+
+// logRequest normalizes the values accepted by the log manager. A bare string
+// is treated as a protobuf-encoded request for compatibility with existing
+// callers.
+func logRequest(value interface{}) (*model.LogRequest, bool) {
+	switch v := value.(type) {
+	case string:
+		return &model.LogRequest{MediaType: model.LogMediaTypeProtobuf, Data: []byte(v)}, true
+	case *model.LogRequest:
+		return v, v != nil
+	case model.LogRequest:
+		return &v, true
+	default:
+		return nil, false
+	}
+}
+
+func unmarshalLogMessage(mediaType string, data []byte, m proto.Message) error {
+	if mediaType == model.LogMediaTypeProtobuf {
+		return proto.Unmarshal(data, m)
+	}
+
+	return protojson.Unmarshal(data, m)
+}
+
+func marshalLogMessage(mediaType string, m proto.Message) ([]byte, error) {
+	if mediaType == model.LogMediaTypeProtobuf {
+		return proto.Marshal(m)
+	}
+
+	return protojson.Marshal(m)
+}
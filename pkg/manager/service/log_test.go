@@ -0,0 +1,80 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/puppetlabs/relay-core/pkg/manager/service"
+	"github.com/puppetlabs/relay-core/pkg/model"
+	"github.com/puppetlabs/relay-pls/pkg/plspb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type failingLogClient struct {
+	plspb.LogClient
+
+	appended []string
+}
+
+func (flc *failingLogClient) MessageAppend(ctx context.Context, in *plspb.LogMessageAppendRequest, opts ...grpc.CallOption) (*plspb.LogMessageAppendResponse, error) {
+	// Each message gets its own deadline rather than sharing one with the
+	// rest of the batch.
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) <= 0 {
+		return nil, errors.New("no time left")
+	}
+
+	if string(in.GetPayload()) == "fail" {
+		return nil, errors.New("service unavailable")
+	}
+
+	flc.appended = append(flc.appended, string(in.GetPayload()))
+	return &plspb.LogMessageAppendResponse{LogId: in.GetLogId(), LogMessageId: string(in.GetPayload())}, nil
+}
+
+func TestLogManagerPostLogMessageBatch(t *testing.T) {
+	ctx := context.Background()
+
+	lc := &failingLogClient{}
+	lm := service.NewLogManager(lc, "test")
+
+	b, err := lm.PostLogMessage(ctx, "my-log", &model.LogRequest{
+		MediaType: model.LogMediaTypeNDJSON,
+		Data: []byte(strings.Join([]string{
+			`{"payload": "b2s="}`,
+			`{"payload": "ZmFpbA=="}`,
+			`{"payload": "YWxzbyBvaw=="}`,
+		}, "\n")),
+	})
+	require.NoError(t, err)
+
+	// The message after the failure is still appended, and the response tells
+	// the client which one to retry.
+	assert.Equal(t, []string{"ok", "also ok"}, lc.appended)
+	lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	require.Len(t, lines, 3)
+	assert.JSONEq(t, `{"logId": "my-log", "logMessageId": "ok"}`, lines[0])
+	assert.JSONEq(t, `{"error": {"message": "service unavailable"}}`, lines[1])
+	assert.JSONEq(t, `{"logId": "my-log", "logMessageId": "also ok"}`, lines[2])
+
+	// A malformed message rejects the whole batch before anything is appended.
+	lc.appended = nil
+	_, err = lm.PostLogMessage(ctx, "my-log", &model.LogRequest{
+		MediaType: model.LogMediaTypeNDJSON,
+		Data:      []byte(`{"payload": "b2s="}` + "\n" + `{"payload": `),
+	})
+	require.Error(t, err)
+	assert.Empty(t, lc.appended)
+
+	// If nothing could be appended, the batch fails.
+	_, err = lm.PostLogMessage(ctx, "my-log", &model.LogRequest{
+		MediaType: model.LogMediaTypeNDJSON,
+		Data:      []byte(`{"payload": "ZmFpbA=="}`),
+	})
+	require.EqualError(t, err, "service unavailable")
+}
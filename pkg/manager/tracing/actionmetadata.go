@@ -0,0 +1,20 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+type ActionMetadataManager struct {
+	delegate model.ActionMetadataManager
+}
+
+var _ model.ActionMetadataManager = &ActionMetadataManager{}
+
+func (m *ActionMetadataManager) Get(ctx context.Context) (md *model.ActionMetadata, err error) {
+	ctx, span := start(ctx, "manager.actionmetadata.get")
+	defer func() { finish(ctx, span, err) }()
+
+	return m.delegate.Get(ctx)
+}
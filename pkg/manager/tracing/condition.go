@@ -0,0 +1,20 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+type ConditionManager struct {
+	delegate model.ConditionGetterManager
+}
+
+var _ model.ConditionGetterManager = &ConditionManager{}
+
+func (m *ConditionManager) Get(ctx context.Context) (c *model.Condition, err error) {
+	ctx, span := start(ctx, "manager.conditions.get")
+	defer func() { finish(ctx, span, err) }()
+
+	return m.delegate.Get(ctx)
+}
@@ -0,0 +1,24 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+	"go.opentelemetry.io/otel/label"
+)
+
+type ConnectionManager struct {
+	delegate model.ConnectionManager
+}
+
+var _ model.ConnectionManager = &ConnectionManager{}
+
+func (m *ConnectionManager) Get(ctx context.Context, typ, name string) (c *model.Connection, err error) {
+	ctx, span := start(ctx, "manager.connections.get",
+		label.String("relay.connection.type", typ),
+		label.String("relay.connection.name", name),
+	)
+	defer func() { finish(ctx, span, err) }()
+
+	return m.delegate.Get(ctx, typ, name)
+}
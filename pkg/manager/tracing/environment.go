@@ -0,0 +1,20 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+type EnvironmentManager struct {
+	delegate model.EnvironmentGetterManager
+}
+
+var _ model.EnvironmentGetterManager = &EnvironmentManager{}
+
+func (m *EnvironmentManager) Get(ctx context.Context) (e *model.Environment, err error) {
+	ctx, span := start(ctx, "manager.environment.get")
+	defer func() { finish(ctx, span, err) }()
+
+	return m.delegate.Get(ctx)
+}
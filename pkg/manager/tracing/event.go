@@ -0,0 +1,20 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+type EventManager struct {
+	delegate model.EventManager
+}
+
+var _ model.EventManager = &EventManager{}
+
+func (m *EventManager) Emit(ctx context.Context, data map[string]interface{}, key string) (ev *model.Event, err error) {
+	ctx, span := start(ctx, "manager.events.emit")
+	defer func() { finish(ctx, span, err) }()
+
+	return m.delegate.Emit(ctx, data, key)
+}
@@ -0,0 +1,30 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+	"go.opentelemetry.io/otel/label"
+)
+
+type LogManager struct {
+	delegate model.LogManager
+}
+
+var _ model.LogManager = &LogManager{}
+
+func (m *LogManager) PostLog(ctx context.Context, value interface{}) (b []byte, err error) {
+	ctx, span := start(ctx, "manager.logs.create")
+	defer func() { finish(ctx, span, err) }()
+
+	return m.delegate.PostLog(ctx, value)
+}
+
+func (m *LogManager) PostLogMessage(ctx context.Context, logID string, value interface{}) (b []byte, err error) {
+	ctx, span := start(ctx, "manager.logs.append",
+		label.String("relay.log.id", logID),
+	)
+	defer func() { finish(ctx, span, err) }()
+
+	return m.delegate.PostLogMessage(ctx, logID, value)
+}
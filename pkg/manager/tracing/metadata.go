@@ -0,0 +1,63 @@
+package tracing
+
+import (
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+// MetadataManagers wraps every manager provided by a delegate so that each
+// call to a manager is recorded as a span.
+type MetadataManagers struct {
+	delegate model.MetadataManagers
+}
+
+var _ model.MetadataManagers = &MetadataManagers{}
+
+func (mm *MetadataManagers) ActionMetadata() model.ActionMetadataManager {
+	return &ActionMetadataManager{delegate: mm.delegate.ActionMetadata()}
+}
+
+func (mm *MetadataManagers) Conditions() model.ConditionGetterManager {
+	return &ConditionManager{delegate: mm.delegate.Conditions()}
+}
+
+func (mm *MetadataManagers) Connections() model.ConnectionManager {
+	return &ConnectionManager{delegate: mm.delegate.Connections()}
+}
+
+func (mm *MetadataManagers) Events() model.EventManager {
+	return &EventManager{delegate: mm.delegate.Events()}
+}
+
+func (mm *MetadataManagers) Environment() model.EnvironmentGetterManager {
+	return &EnvironmentManager{delegate: mm.delegate.Environment()}
+}
+
+func (mm *MetadataManagers) Logs() model.LogManager {
+	return &LogManager{delegate: mm.delegate.Logs()}
+}
+
+func (mm *MetadataManagers) Parameters() model.ParameterGetterManager {
+	return &ParameterManager{delegate: mm.delegate.Parameters()}
+}
+
+func (mm *MetadataManagers) Secrets() model.SecretManager {
+	return &SecretManager{delegate: mm.delegate.Secrets()}
+}
+
+func (mm *MetadataManagers) Spec() model.SpecGetterManager {
+	return &SpecManager{delegate: mm.delegate.Spec()}
+}
+
+func (mm *MetadataManagers) State() model.StateGetterManager {
+	return &StateManager{delegate: mm.delegate.State()}
+}
+
+func (mm *MetadataManagers) StepOutputs() model.StepOutputManager {
+	return &StepOutputManager{delegate: mm.delegate.StepOutputs()}
+}
+
+func NewMetadataManagers(delegate model.MetadataManagers) *MetadataManagers {
+	return &MetadataManagers{
+		delegate: delegate,
+	}
+}
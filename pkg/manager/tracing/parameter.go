@@ -0,0 +1,23 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+	"go.opentelemetry.io/otel/label"
+)
+
+type ParameterManager struct {
+	delegate model.ParameterGetterManager
+}
+
+var _ model.ParameterGetterManager = &ParameterManager{}
+
+func (m *ParameterManager) Get(ctx context.Context, name string) (p *model.Parameter, err error) {
+	ctx, span := start(ctx, "manager.parameters.get",
+		label.String("relay.parameter.name", name),
+	)
+	defer func() { finish(ctx, span, err) }()
+
+	return m.delegate.Get(ctx, name)
+}
@@ -0,0 +1,33 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+	"go.opentelemetry.io/otel/label"
+)
+
+type SecretManager struct {
+	delegate model.SecretManager
+}
+
+var _ model.SecretManager = &SecretManager{}
+
+func (m *SecretManager) Get(ctx context.Context, name string) (s *model.Secret, err error) {
+	ctx, span := start(ctx, "manager.secrets.get",
+		label.String("relay.secret.name", name),
+	)
+	defer func() { finish(ctx, span, err) }()
+
+	return m.delegate.Get(ctx, name)
+}
+
+func (m *SecretManager) GetVersion(ctx context.Context, name string, version int) (s *model.Secret, err error) {
+	ctx, span := start(ctx, "manager.secrets.getversion",
+		label.String("relay.secret.name", name),
+		label.Int("relay.secret.version", version),
+	)
+	defer func() { finish(ctx, span, err) }()
+
+	return m.delegate.GetVersion(ctx, name, version)
+}
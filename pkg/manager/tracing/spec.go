@@ -0,0 +1,20 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+type SpecManager struct {
+	delegate model.SpecGetterManager
+}
+
+var _ model.SpecGetterManager = &SpecManager{}
+
+func (m *SpecManager) Get(ctx context.Context) (s *model.Spec, err error) {
+	ctx, span := start(ctx, "manager.spec.get")
+	defer func() { finish(ctx, span, err) }()
+
+	return m.delegate.Get(ctx)
+}
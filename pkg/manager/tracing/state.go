@@ -0,0 +1,23 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+	"go.opentelemetry.io/otel/label"
+)
+
+type StateManager struct {
+	delegate model.StateGetterManager
+}
+
+var _ model.StateGetterManager = &StateManager{}
+
+func (m *StateManager) Get(ctx context.Context, name string) (s *model.State, err error) {
+	ctx, span := start(ctx, "manager.state.get",
+		label.String("relay.state.name", name),
+	)
+	defer func() { finish(ctx, span, err) }()
+
+	return m.delegate.Get(ctx, name)
+}
@@ -0,0 +1,33 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/puppetlabs/relay-core/pkg/model"
+	"go.opentelemetry.io/otel/label"
+)
+
+type StepOutputManager struct {
+	delegate model.StepOutputManager
+}
+
+var _ model.StepOutputManager = &StepOutputManager{}
+
+func (m *StepOutputManager) Get(ctx context.Context, stepName, name string) (so *model.StepOutput, err error) {
+	ctx, span := start(ctx, "manager.outputs.get",
+		label.String("relay.output.from", stepName),
+		label.String("relay.output.name", name),
+	)
+	defer func() { finish(ctx, span, err) }()
+
+	return m.delegate.Get(ctx, stepName, name)
+}
+
+func (m *StepOutputManager) Set(ctx context.Context, name string, value interface{}) (so *model.StepOutput, err error) {
+	ctx, span := start(ctx, "manager.outputs.set",
+		label.String("relay.output.name", name),
+	)
+	defer func() { finish(ctx, span, err) }()
+
+	return m.delegate.Set(ctx, name, value)
+}
@@ -0,0 +1,53 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/puppetlabs/relay-core/pkg/manager/builder"
+	"github.com/puppetlabs/relay-core/pkg/manager/memory"
+	"github.com/puppetlabs/relay-core/pkg/manager/tracing"
+	"github.com/puppetlabs/relay-core/pkg/model"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace/tracetest"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/label"
+)
+
+func TestMetadataManagers(t *testing.T) {
+	ctx := context.Background()
+
+	sr := &tracetest.StandardSpanRecorder{}
+	global.SetTracerProvider(tracetest.NewTracerProvider(tracetest.WithSpanRecorder(sr)))
+
+	mgrs := tracing.NewMetadataManagers(
+		builder.NewMetadataBuilder().
+			SetSecrets(memory.NewSecretManager(map[string]string{"foo": "bar"})).
+			Build(),
+	)
+
+	sec, err := mgrs.Secrets().Get(ctx, "foo")
+	require.NoError(t, err)
+	require.Equal(t, "bar", sec.Value)
+
+	_, err = mgrs.Secrets().Get(ctx, "baz")
+	require.Equal(t, model.ErrNotFound, err)
+
+	// Managers that aren't configured reject requests.
+	_, err = mgrs.Spec().Get(ctx)
+	require.Equal(t, model.ErrRejected, err)
+
+	spans := sr.Completed()
+	require.Len(t, spans, 3)
+
+	require.Equal(t, "manager.secrets.get", spans[0].Name())
+	require.Equal(t, label.StringValue("foo"), spans[0].Attributes()["relay.secret.name"])
+	require.Equal(t, codes.Unset, spans[0].StatusCode())
+
+	require.Equal(t, "manager.secrets.get", spans[1].Name())
+	require.Equal(t, codes.Error, spans[1].StatusCode())
+
+	require.Equal(t, "manager.spec.get", spans[2].Name())
+	require.Equal(t, codes.Error, spans[2].StatusCode())
+}
@@ -2,49 +2,134 @@ package vault
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/puppetlabs/relay-core/pkg/manager/cache"
+	"github.com/puppetlabs/relay-core/pkg/manager/refresh"
 	"github.com/puppetlabs/relay-core/pkg/model"
 )
 
 type ConnectionManager struct {
-	client *KVV2Client
+	client    *KVClient
+	refresher refresh.Refresher
+	leases    refresh.LeaseRecorder
 }
 
 var _ model.ConnectionManager = &ConnectionManager{}
+var _ cache.LeasedConnectionManager = &ConnectionManager{}
 
 func (m *ConnectionManager) Get(ctx context.Context, typ, name string) (*model.Connection, error) {
-	connectionID, err := m.client.In(typ, name).ReadString(ctx)
+	conn, _, err := m.GetWithLease(ctx, typ, name)
+	return conn, err
+}
+
+// GetWithLease retrieves a connection along with the shortest lease duration
+// Vault reported for any of its attributes, if any. If the connection is
+// refreshed, the validity of the refreshed credentials also bounds the lease.
+func (m *ConnectionManager) GetWithLease(ctx context.Context, typ, name string) (*model.Connection, time.Duration, error) {
+	connectionID, lease, err := m.client.In(typ, name).ReadStringWithLease(ctx)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	keys, err := m.client.In(connectionID).List(ctx)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	attrs := make(map[string]interface{}, len(keys))
 	for _, key := range keys {
-		value, err := m.client.In(connectionID, key).ReadString(ctx)
+		value, attrLease, err := m.client.In(connectionID, key).ReadStringWithLease(ctx)
 		if err == model.ErrNotFound {
 			// Deleted from under us?
 			continue
 		} else if err != nil {
-			return nil, err
+			return nil, 0, err
+		}
+
+		if attrLease > 0 && (lease == 0 || attrLease < lease) {
+			lease = attrLease
 		}
 
 		attrs[key] = value
 	}
 
-	return &model.Connection{
+	conn := &model.Connection{
 		Type:       typ,
 		Name:       name,
 		Attributes: attrs,
-	}, nil
+	}
+
+	if m.refresher != nil {
+		store := &connectionStore{
+			client: m.client.In(connectionID),
+			leases: m.leases,
+		}
+
+		refreshed, refreshedLease, err := m.refresher.Refresh(ctx, conn, store)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if refreshedLease > 0 && (lease == 0 || refreshedLease < lease) {
+			lease = refreshedLease
+		}
+
+		conn = refreshed
+	}
+
+	return conn, lease, nil
+}
+
+// connectionStore writes the changes a refresher makes to a connection back to
+// Vault.
+type connectionStore struct {
+	client *KVClient
+	leases refresh.LeaseRecorder
 }
 
-func NewConnectionManager(client *KVV2Client) *ConnectionManager {
-	return &ConnectionManager{
+var _ refresh.Store = &connectionStore{}
+
+func (s *connectionStore) SetAttribute(ctx context.Context, name, value string) error {
+	return s.client.In(name).WriteString(ctx, value)
+}
+
+func (s *connectionStore) AddLease(ctx context.Context, lease *refresh.Lease) error {
+	if s.leases == nil {
+		return fmt.Errorf("vault: no lease recorder is configured")
+	}
+
+	return s.leases.AddLease(ctx, lease)
+}
+
+type ConnectionManagerOption func(m *ConnectionManager)
+
+// ConnectionManagerWithRefresher passes each connection read from Vault
+// through the given refresher so that callers receive current credentials.
+func ConnectionManagerWithRefresher(refresher refresh.Refresher) ConnectionManagerOption {
+	return func(m *ConnectionManager) {
+		m.refresher = refresher
+	}
+}
+
+// ConnectionManagerWithLeaseRecorder records the leases on credentials issued
+// by refreshers so that they can be revoked later. Without a lease recorder,
+// refreshing a connection fails if Vault issues a lease for its credentials.
+func ConnectionManagerWithLeaseRecorder(leases refresh.LeaseRecorder) ConnectionManagerOption {
+	return func(m *ConnectionManager) {
+		m.leases = leases
+	}
+}
+
+func NewConnectionManager(client *KVClient, opts ...ConnectionManagerOption) *ConnectionManager {
+	m := &ConnectionManager{
 		client: client,
 	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
 }
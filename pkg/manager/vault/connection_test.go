@@ -2,10 +2,16 @@ package vault_test
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	vaultapi "github.com/hashicorp/vault/api"
+	"github.com/puppetlabs/relay-core/pkg/manager/refresh"
 	"github.com/puppetlabs/relay-core/pkg/manager/vault"
 	"github.com/puppetlabs/relay-core/pkg/model"
 	"github.com/puppetlabs/relay-core/pkg/util/testutil"
@@ -40,7 +46,7 @@ func TestConnectionManager(t *testing.T) {
 		})
 		require.NoError(t, err)
 
-		cm := vault.NewConnectionManager(vault.NewKVV2Client(vcfg.Client, vcfg.SecretsPath).In("foo"))
+		cm := vault.NewConnectionManager(vault.NewKVClient(vcfg.Client, vcfg.SecretsPath).In("foo"))
 
 		conn, err := cm.Get(ctx, "some-type", "test")
 		require.NoError(t, err)
@@ -52,3 +58,158 @@ func TestConnectionManager(t *testing.T) {
 		require.Equal(t, model.ErrNotFound, err)
 	})
 }
+
+func TestConnectionManagerKVV1(t *testing.T) {
+	ctx := context.Background()
+
+	testutil.WithVault(t, func(vcfg *testutil.Vault) {
+		require.NoError(t, vcfg.Client.Sys().Mount("kv-v1", &vaultapi.MountInput{
+			Type:    "kv",
+			Options: map[string]string{"version": "1"},
+		}))
+
+		id := uuid.New().String()
+
+		// Write data. KV V1 engines report a TTL as the lease duration.
+		attrs := map[string]interface{}{
+			"foo": "bar",
+			"baz": "quux",
+		}
+		for k, v := range attrs {
+			_, err := vcfg.Client.Logical().Write(path.Join("kv-v1", "foo", id, k), map[string]interface{}{
+				"value": v,
+				"ttl":   "30s",
+			})
+			require.NoError(t, err)
+		}
+
+		// Write pointer.
+		_, err := vcfg.Client.Logical().Write("kv-v1/foo/some-type/test", map[string]interface{}{
+			"value": id,
+			"ttl":   "1m",
+		})
+		require.NoError(t, err)
+
+		cm := vault.NewConnectionManager(vault.NewKVClient(vcfg.Client, "kv-v1", vault.KVClientWithEngineVersion(vault.KVVersion1)).In("foo"))
+
+		conn, lease, err := cm.GetWithLease(ctx, "some-type", "test")
+		require.NoError(t, err)
+		require.Equal(t, attrs, conn.Attributes)
+		require.Equal(t, 30*time.Second, lease)
+
+		_, err = cm.Get(ctx, "some-other-type", "test")
+		require.Equal(t, model.ErrNotFound, err)
+	})
+}
+
+func TestConnectionManagerRefresh(t *testing.T) {
+	ctx := context.Background()
+
+	// A local token endpoint that exchanges any refresh token for an access
+	// token valid for ten minutes and rotates the refresh token.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+
+		w.Header().Set("content-type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  "access-for-" + r.PostForm.Get("refresh_token"),
+			"refresh_token": "rotated-" + r.PostForm.Get("refresh_token"),
+			"token_type":    "bearer",
+			"expires_in":    600,
+		}))
+	}))
+	defer srv.Close()
+
+	testutil.WithVault(t, func(vcfg *testutil.Vault) {
+		// We use a KV V1 engine with a TTL to stand in for a dynamic secrets
+		// engine that issues leases.
+		require.NoError(t, vcfg.Client.Sys().Mount("dynamic", &vaultapi.MountInput{
+			Type:    "kv",
+			Options: map[string]string{"version": "1"},
+		}))
+
+		_, err := vcfg.Client.Logical().Write("dynamic/creds/deploy", map[string]interface{}{
+			"username": "v-deploy-1234",
+			"password": "generated",
+			"ttl":      "5m",
+		})
+		require.NoError(t, err)
+
+		write := func(typ, name string, attrs map[string]interface{}) {
+			id := uuid.New().String()
+
+			for k, v := range attrs {
+				_, err := vcfg.Client.Logical().Write(path.Join(vcfg.SecretsPath, "data", "foo", id, k), map[string]interface{}{
+					"data": map[string]interface{}{
+						"value": v,
+					},
+				})
+				require.NoError(t, err)
+			}
+
+			_, err := vcfg.Client.Logical().Write(path.Join(vcfg.SecretsPath, "data", "foo", typ, name), map[string]interface{}{
+				"data": map[string]interface{}{
+					"value": id,
+				},
+			})
+			require.NoError(t, err)
+		}
+
+		write("oauth", "test", map[string]interface{}{
+			"clientID":     "client-id",
+			"clientSecret": "client-secret",
+			"refreshToken": "refresh-token",
+		})
+		write("database", "test", map[string]interface{}{
+			"path": "dynamic/creds/deploy",
+		})
+		write("database", "escape", map[string]interface{}{
+			"path": "dynamic/../" + vcfg.SecretsPath + "/data/foo/static/test",
+		})
+		write("static", "test", map[string]interface{}{
+			"token": "static-token",
+		})
+
+		cm := vault.NewConnectionManager(
+			vault.NewKVClient(vcfg.Client, vcfg.SecretsPath).In("foo"),
+			vault.ConnectionManagerWithRefresher(refresh.NewRegistry(
+				refresh.RegistryWithRefresher("oauth", refresh.NewOAuth2Refresher(srv.URL)),
+				refresh.RegistryWithRefresher("database", vault.NewDynamicSecretRefresher(vcfg.Client, "dynamic")),
+			)),
+		)
+
+		conn, lease, err := cm.GetWithLease(ctx, "oauth", "test")
+		require.NoError(t, err)
+		require.Equal(t, "access-for-refresh-token", conn.Attributes["accessToken"])
+		require.InDelta(t, float64(9*time.Minute), float64(lease), float64(5*time.Second))
+
+		// The rotated refresh token is written back to Vault and used for the
+		// next refresh.
+		conn, _, err = cm.GetWithLease(ctx, "oauth", "test")
+		require.NoError(t, err)
+		require.Equal(t, "access-for-rotated-refresh-token", conn.Attributes["accessToken"])
+		require.Equal(t, "rotated-rotated-refresh-token", conn.Attributes["refreshToken"])
+
+		conn, lease, err = cm.GetWithLease(ctx, "database", "test")
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{
+			"path":     "dynamic/creds/deploy",
+			"username": "v-deploy-1234",
+			"password": "generated",
+			"ttl":      "5m",
+		}, conn.Attributes)
+		require.Equal(t, 5*time.Minute, lease)
+
+		// Paths outside of the mount of the dynamic secrets engine are not
+		// read.
+		_, _, err = cm.GetWithLease(ctx, "database", "escape")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not under mount path")
+
+		conn, lease, err = cm.GetWithLease(ctx, "static", "test")
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"token": "static-token"}, conn.Attributes)
+		require.Equal(t, time.Duration(0), lease)
+	})
+}
@@ -0,0 +1,127 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	"github.com/puppetlabs/relay-core/pkg/manager/refresh"
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+// DefaultDynamicSecretPathAttribute is the default connection attribute that
+// holds the path to a dynamic secret.
+const DefaultDynamicSecretPathAttribute = "path"
+
+// DynamicSecretRefresher reads credentials for a connection from a Vault
+// dynamic secrets engine, like the AWS or database engines. The path to read
+// is taken from an attribute of the connection, and the data Vault returns is
+// added to the attributes of the refreshed connection. Connections without a
+// path are returned unchanged.
+//
+// Connections can only read from paths under the mount path of the refresher,
+// so that a connection can't be used to read arbitrary data with the
+// credentials of its tenant. If Vault issues a lease for the credentials, the
+// lease is added to the store given to Refresh so that it can be revoked when
+// the run that requested the credentials ends.
+type DynamicSecretRefresher struct {
+	client        *vaultapi.Client
+	mountPath     string
+	namespace     string
+	pathAttribute string
+}
+
+var _ refresh.Refresher = &DynamicSecretRefresher{}
+
+func (r *DynamicSecretRefresher) Refresh(ctx context.Context, conn *model.Connection, store refresh.Store) (*model.Connection, time.Duration, error) {
+	p, _ := conn.Attributes[r.pathAttribute].(string)
+	if p == "" {
+		return conn, 0, nil
+	}
+
+	if !r.allowed(p) {
+		return nil, 0, fmt.Errorf("vault: path %q of connection %s/%s is not under mount path %q", p, conn.Type, conn.Name, r.mountPath)
+	}
+
+	sec, err := do(ctx, r.client, newRequest(r.client, r.namespace, http.MethodGet, p))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if sec.LeaseID != "" {
+		lease := &refresh.Lease{
+			ID:        sec.LeaseID,
+			Namespace: r.namespace,
+		}
+
+		if store == nil {
+			return nil, 0, fmt.Errorf("vault: lease %s for connection %s/%s cannot be recorded for revocation", sec.LeaseID, conn.Type, conn.Name)
+		} else if err := store.AddLease(ctx, lease); err != nil {
+			return nil, 0, fmt.Errorf("vault: failed to record lease %s for connection %s/%s: %+v", sec.LeaseID, conn.Type, conn.Name, err)
+		}
+	}
+
+	attrs := make(map[string]interface{}, len(conn.Attributes)+len(sec.Data))
+	for k, v := range conn.Attributes {
+		attrs[k] = v
+	}
+	for k, v := range sec.Data {
+		attrs[k] = v
+	}
+
+	return &model.Connection{
+		Type:       conn.Type,
+		Name:       conn.Name,
+		Attributes: attrs,
+	}, time.Duration(sec.LeaseDuration) * time.Second, nil
+}
+
+// allowed determines whether the given path is strictly under the mount path
+// of this refresher. The path must already be in its canonical form so that
+// it can't escape the mount using relative components.
+func (r *DynamicSecretRefresher) allowed(p string) bool {
+	if p != path.Clean(p) || path.IsAbs(p) {
+		return false
+	}
+
+	return strings.HasPrefix(p, r.mountPath+"/")
+}
+
+type DynamicSecretRefresherOption func(r *DynamicSecretRefresher)
+
+// DynamicSecretRefresherWithNamespace sends requests to the given Vault
+// Enterprise namespace.
+func DynamicSecretRefresherWithNamespace(namespace string) DynamicSecretRefresherOption {
+	return func(r *DynamicSecretRefresher) {
+		r.namespace = namespace
+	}
+}
+
+// DynamicSecretRefresherWithPathAttribute reads the path to the dynamic secret
+// from the given connection attribute. The default is
+// DefaultDynamicSecretPathAttribute.
+func DynamicSecretRefresherWithPathAttribute(attr string) DynamicSecretRefresherOption {
+	return func(r *DynamicSecretRefresher) {
+		r.pathAttribute = attr
+	}
+}
+
+// NewDynamicSecretRefresher creates a refresher that reads credentials from
+// the dynamic secrets engine mounted at the given path.
+func NewDynamicSecretRefresher(client *vaultapi.Client, mountPath string, opts ...DynamicSecretRefresherOption) *DynamicSecretRefresher {
+	r := &DynamicSecretRefresher{
+		client:        client,
+		mountPath:     strings.Trim(mountPath, "/"),
+		pathAttribute: DefaultDynamicSecretPathAttribute,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
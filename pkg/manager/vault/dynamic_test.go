@@ -0,0 +1,175 @@
+package vault_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	"github.com/hashicorp/vault/sdk/helper/consts"
+	"github.com/puppetlabs/relay-core/pkg/manager/refresh"
+	"github.com/puppetlabs/relay-core/pkg/manager/vault"
+	"github.com/puppetlabs/relay-core/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// leaseRecorder is a refresh.Store that only records leases.
+type leaseRecorder struct {
+	leases []*refresh.Lease
+}
+
+func (lr *leaseRecorder) AddLease(ctx context.Context, lease *refresh.Lease) error {
+	lr.leases = append(lr.leases, lease)
+	return nil
+}
+
+func (lr *leaseRecorder) SetAttribute(ctx context.Context, name, value string) error {
+	return nil
+}
+
+// dynamicSecretsEngine is a stand-in for a Vault server with a dynamic secrets
+// engine mounted at aws/ that issues leases on every read and records the
+// leases that are revoked.
+type dynamicSecretsEngine struct {
+	t       *testing.T
+	reads   []string
+	revoked []string
+}
+
+func (dse *dynamicSecretsEngine) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	assert.Equal(dse.t, "tenant", r.Header.Get(consts.NamespaceHeaderName))
+
+	w.Header().Set("content-type", "application/json")
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/aws/creds/deploy":
+		dse.reads = append(dse.reads, r.URL.Path)
+
+		assert.NoError(dse.t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"lease_id":       "aws/creds/deploy/abcd",
+			"lease_duration": 900,
+			"renewable":      true,
+			"data": map[string]interface{}{
+				"access_key": "AKIA",
+				"secret_key": "secret",
+			},
+		}))
+	case r.Method == http.MethodPut && r.URL.Path == "/v1/sys/leases/revoke":
+		var body struct {
+			LeaseID string `json:"lease_id"`
+		}
+		assert.NoError(dse.t, json.NewDecoder(r.Body).Decode(&body))
+
+		dse.revoked = append(dse.revoked, body.LeaseID)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		assert.NoError(dse.t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"errors": []string{},
+		}))
+	}
+}
+
+func newDynamicSecretsEngineClient(t *testing.T, dse *dynamicSecretsEngine) (*vaultapi.Client, func()) {
+	srv := httptest.NewServer(dse)
+
+	client, err := vaultapi.NewClient(&vaultapi.Config{Address: srv.URL})
+	require.NoError(t, err)
+
+	return client, srv.Close
+}
+
+func TestDynamicSecretRefresher(t *testing.T) {
+	ctx := context.Background()
+
+	dse := &dynamicSecretsEngine{t: t}
+
+	client, done := newDynamicSecretsEngineClient(t, dse)
+	defer done()
+
+	r := vault.NewDynamicSecretRefresher(client, "aws/", vault.DynamicSecretRefresherWithNamespace("tenant"))
+
+	store := &leaseRecorder{}
+
+	conn, lease, err := r.Refresh(ctx, &model.Connection{
+		Type: "aws",
+		Name: "test",
+		Attributes: map[string]interface{}{
+			"path": "aws/creds/deploy",
+		},
+	}, store)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"path":       "aws/creds/deploy",
+		"access_key": "AKIA",
+		"secret_key": "secret",
+	}, conn.Attributes)
+	assert.Equal(t, 15*time.Minute, lease)
+	assert.Equal(t, []*refresh.Lease{{ID: "aws/creds/deploy/abcd", Namespace: "tenant"}}, store.leases)
+
+	// Without a store, the lease would never be revoked.
+	_, _, err = r.Refresh(ctx, &model.Connection{
+		Type: "aws",
+		Name: "test",
+		Attributes: map[string]interface{}{
+			"path": "aws/creds/deploy",
+		},
+	}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "aws/creds/deploy/abcd")
+}
+
+func TestDynamicSecretRefresherMountPath(t *testing.T) {
+	ctx := context.Background()
+
+	dse := &dynamicSecretsEngine{t: t}
+
+	client, done := newDynamicSecretsEngineClient(t, dse)
+	defer done()
+
+	r := vault.NewDynamicSecretRefresher(client, "aws", vault.DynamicSecretRefresherWithNamespace("tenant"))
+
+	for _, p := range []string{
+		"aws",
+		"aws/",
+		"awsfoo/creds/deploy",
+		"/aws/creds/deploy",
+		"aws/../sys/leases/lookup",
+		"aws/creds/../../secret/data/other",
+		"aws//creds/deploy",
+		"secret/data/other",
+	} {
+		t.Run(p, func(t *testing.T) {
+			_, _, err := r.Refresh(ctx, &model.Connection{
+				Type: "aws",
+				Name: "test",
+				Attributes: map[string]interface{}{
+					"path": p,
+				},
+			}, &leaseRecorder{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "not under mount path")
+		})
+	}
+
+	assert.Empty(t, dse.reads)
+}
+
+func TestRevokeLease(t *testing.T) {
+	ctx := context.Background()
+
+	dse := &dynamicSecretsEngine{t: t}
+
+	client, done := newDynamicSecretsEngineClient(t, dse)
+	defer done()
+
+	require.NoError(t, vault.RevokeLease(ctx, client, &refresh.Lease{
+		ID:        "aws/creds/deploy/abcd",
+		Namespace: "tenant",
+	}))
+	assert.Equal(t, []string{"aws/creds/deploy/abcd"}, dse.revoked)
+}
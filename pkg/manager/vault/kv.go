@@ -0,0 +1,301 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	"github.com/hashicorp/vault/sdk/helper/consts"
+	"github.com/puppetlabs/horsehead/v2/encoding/transfer"
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+// KVVersion is the version of a KV secrets engine.
+type KVVersion int
+
+const (
+	KVVersion1 KVVersion = 1
+	KVVersion2 KVVersion = 2
+)
+
+// ParseKVVersion converts the string representation of a KV engine version,
+// like "1" or "v2", to a KVVersion. An empty string is the default version,
+// KVVersion2.
+func ParseKVVersion(s string) (KVVersion, error) {
+	switch s {
+	case "", "2", "v2":
+		return KVVersion2, nil
+	case "1", "v1":
+		return KVVersion1, nil
+	default:
+		return 0, fmt.Errorf("vault: unknown KV engine version %q", s)
+	}
+}
+
+// KVClient accesses metadata and data from a KV V1 or KV V2 engine mount
+// uniformly.
+type KVClient struct {
+	client        *vaultapi.Client
+	namespace     string
+	enginePath    string
+	engineVersion KVVersion
+	path          string
+	version       int
+}
+
+// In returns a client for the given path relative to the path of this client.
+// The returned client reads the latest version of secrets.
+func (c *KVClient) In(sub ...string) *KVClient {
+	return &KVClient{
+		client:        c.client,
+		namespace:     c.namespace,
+		enginePath:    c.enginePath,
+		engineVersion: c.engineVersion,
+		path:          path.Join(c.path, path.Join(sub...)),
+	}
+}
+
+// AtVersion returns a client that reads the given version of the value at the
+// path of this client. KV V1 engines do not keep versions, so a client for a
+// KV V1 engine never finds a specific version.
+func (c *KVClient) AtVersion(version int) *KVClient {
+	return &KVClient{
+		client:        c.client,
+		namespace:     c.namespace,
+		enginePath:    c.enginePath,
+		engineVersion: c.engineVersion,
+		path:          c.path,
+		version:       version,
+	}
+}
+
+func (c *KVClient) Read(ctx context.Context) (interface{}, error) {
+	value, _, err := c.ReadWithLease(ctx)
+	return value, err
+}
+
+// ReadWithLease reads the value at this path and returns the lease duration
+// Vault reported for it. KV V2 engines do not usually issue leases, in which
+// case the lease duration is zero.
+func (c *KVClient) ReadWithLease(ctx context.Context) (interface{}, time.Duration, error) {
+	if c.version > 0 && c.engineVersion == KVVersion1 {
+		return nil, 0, model.ErrNotFound
+	}
+
+	r := c.newRequest(http.MethodGet, c.dataPath())
+	if c.version > 0 {
+		r.Params.Set("version", strconv.Itoa(c.version))
+	}
+
+	sec, err := c.do(ctx, r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data := sec.Data
+	if c.engineVersion != KVVersion1 {
+		// KV V2 engines wrap the data alongside its metadata. If the version
+		// has been deleted, the data is null.
+		var ok bool
+		data, ok = sec.Data["data"].(map[string]interface{})
+		if !ok {
+			return nil, 0, model.ErrNotFound
+		}
+	}
+
+	value, found := data["value"]
+	if !found {
+		return nil, 0, model.ErrNotFound
+	}
+
+	return value, time.Duration(sec.LeaseDuration) * time.Second, nil
+}
+
+func (c *KVClient) ReadString(ctx context.Context) (string, error) {
+	value, _, err := c.ReadStringWithLease(ctx)
+	return value, err
+}
+
+func (c *KVClient) ReadStringWithLease(ctx context.Context) (string, time.Duration, error) {
+	raw, lease, err := c.ReadWithLease(ctx)
+	if err != nil {
+		return "", 0, err
+	}
+
+	encoded, ok := raw.(string)
+	if !ok {
+		// TODO: Should this be a different error?
+		return "", 0, model.ErrNotFound
+	}
+
+	b, err := transfer.DecodeFromTransfer(encoded)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return string(b), lease, nil
+}
+
+// WriteString encodes the given value for transfer and writes it to this path
+// as a new version.
+func (c *KVClient) WriteString(ctx context.Context, value string) error {
+	encoded, err := transfer.EncodeForTransfer([]byte(value))
+	if err != nil {
+		return err
+	}
+
+	data := map[string]interface{}{"value": encoded}
+	if c.engineVersion != KVVersion1 {
+		data = map[string]interface{}{"data": data}
+	}
+
+	r := c.newRequest(http.MethodPut, c.dataPath())
+	if err := r.SetJSONBody(data); err != nil {
+		return err
+	}
+
+	// KV V1 engines do not respond with any data, so we can't use do() here.
+	resp, err := c.client.RawRequestWithContext(ctx, r)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	return err
+}
+
+func (c *KVClient) List(ctx context.Context) ([]string, error) {
+	r := c.newRequest(http.MethodGet, c.metadataPath())
+	r.Params.Set("list", "true")
+
+	ls, err := c.do(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	ki, ok := ls.Data["keys"].([]interface{})
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+
+	keys := make([]string, len(ki))
+	for i, k := range ki {
+		keys[i], ok = k.(string)
+		if !ok {
+			// TODO: Should this be a different error?
+			return nil, model.ErrNotFound
+		}
+	}
+
+	return keys, nil
+}
+
+func (c *KVClient) dataPath() string {
+	if c.engineVersion == KVVersion1 {
+		return path.Join(c.enginePath, c.path)
+	}
+
+	return path.Join(c.enginePath, "data", c.path)
+}
+
+func (c *KVClient) metadataPath() string {
+	if c.engineVersion == KVVersion1 {
+		return path.Join(c.enginePath, c.path)
+	}
+
+	return path.Join(c.enginePath, "metadata", c.path)
+}
+
+func (c *KVClient) newRequest(method, p string) *vaultapi.Request {
+	return newRequest(c.client, c.namespace, method, p)
+}
+
+func (c *KVClient) do(ctx context.Context, r *vaultapi.Request) (*vaultapi.Secret, error) {
+	return do(ctx, c.client, r)
+}
+
+func newRequest(client *vaultapi.Client, namespace, method, p string) *vaultapi.Request {
+	r := client.NewRequest(method, "/v1/"+p)
+	if namespace != "" {
+		// We set the namespace on each request instead of on the client so
+		// that clients can be shared between tenants.
+		if r.Headers == nil {
+			r.Headers = make(http.Header)
+		}
+
+		r.Headers.Set(consts.NamespaceHeaderName, namespace)
+	}
+
+	return r
+}
+
+// do sends the given request to Vault. Unlike the Vault client's logical
+// backend, it returns model.ErrNotFound instead of a nil secret if there is no
+// data at the requested path.
+func do(ctx context.Context, client *vaultapi.Client, r *vaultapi.Request) (*vaultapi.Secret, error) {
+	resp, err := client.RawRequestWithContext(ctx, r)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		// A KV V2 engine responds with the metadata of a deleted version
+		// along with a not found status, so we still need to consider the
+		// response.
+		sec, perr := vaultapi.ParseSecret(resp.Body)
+		if perr == io.EOF || (perr == nil && (sec == nil || len(sec.Data) == 0)) {
+			return nil, model.ErrNotFound
+		} else if perr != nil {
+			return nil, perr
+		}
+
+		return sec, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	sec, err := vaultapi.ParseSecret(resp.Body)
+	if err != nil {
+		return nil, err
+	} else if sec == nil {
+		return nil, model.ErrNotFound
+	}
+
+	return sec, nil
+}
+
+type KVClientOption func(c *KVClient)
+
+// KVClientWithEngineVersion sets the version of the KV engine mounted at the
+// engine path. By default, the engine is assumed to be KVVersion2.
+func KVClientWithEngineVersion(version KVVersion) KVClientOption {
+	return func(c *KVClient) {
+		c.engineVersion = version
+	}
+}
+
+// KVClientWithNamespace sends requests to the given Vault Enterprise
+// namespace.
+func KVClientWithNamespace(namespace string) KVClientOption {
+	return func(c *KVClient) {
+		c.namespace = namespace
+	}
+}
+
+func NewKVClient(delegate *vaultapi.Client, enginePath string, opts ...KVClientOption) *KVClient {
+	c := &KVClient{
+		client:        delegate,
+		enginePath:    enginePath,
+		engineVersion: KVVersion2,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
@@ -0,0 +1,27 @@
+package vault
+
+import (
+	"context"
+	"net/http"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	"github.com/puppetlabs/relay-core/pkg/manager/refresh"
+)
+
+// RevokeLease revokes the given lease immediately, invalidating the
+// credentials Vault issued with it. The client must be allowed to update
+// sys/leases/revoke in the namespace of the lease. Revoking a lease that has
+// already expired or been revoked succeeds.
+func RevokeLease(ctx context.Context, client *vaultapi.Client, lease *refresh.Lease) error {
+	r := newRequest(client, lease.Namespace, http.MethodPut, "sys/leases/revoke")
+	if err := r.SetJSONBody(map[string]interface{}{"lease_id": lease.ID}); err != nil {
+		return err
+	}
+
+	resp, err := client.RawRequestWithContext(ctx, r)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	return err
+}
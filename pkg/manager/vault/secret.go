@@ -2,18 +2,42 @@ package vault
 
 import (
 	"context"
+	"time"
 
+	"github.com/puppetlabs/relay-core/pkg/manager/cache"
 	"github.com/puppetlabs/relay-core/pkg/model"
 )
 
 type SecretManager struct {
-	client *KVV2Client
+	client *KVClient
 }
 
 var _ model.SecretManager = &SecretManager{}
+var _ cache.LeasedSecretManager = &SecretManager{}
 
 func (m *SecretManager) Get(ctx context.Context, name string) (*model.Secret, error) {
-	value, err := m.client.In(name).ReadString(ctx)
+	sec, _, err := m.GetWithLease(ctx, name)
+	return sec, err
+}
+
+// GetWithLease retrieves a secret along with the lease duration Vault reported
+// for it, if any.
+func (m *SecretManager) GetWithLease(ctx context.Context, name string) (*model.Secret, time.Duration, error) {
+	value, lease, err := m.client.In(name).ReadStringWithLease(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &model.Secret{
+		Name:  name,
+		Value: value,
+	}, lease, nil
+}
+
+// GetVersion retrieves a specific version of a secret. Secrets stored in a KV
+// V1 engine do not have versions, so they are never found.
+func (m *SecretManager) GetVersion(ctx context.Context, name string, version int) (*model.Secret, error) {
+	value, err := m.client.In(name).AtVersion(version).ReadString(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -24,7 +48,7 @@ func (m *SecretManager) Get(ctx context.Context, name string) (*model.Secret, er
 	}, nil
 }
 
-func NewSecretManager(client *KVV2Client) *SecretManager {
+func NewSecretManager(client *KVClient) *SecretManager {
 	return &SecretManager{
 		client: client,
 	}
@@ -5,6 +5,7 @@ import (
 	"path"
 	"testing"
 
+	vaultapi "github.com/hashicorp/vault/api"
 	"github.com/puppetlabs/relay-core/pkg/manager/vault"
 	"github.com/puppetlabs/relay-core/pkg/model"
 	"github.com/puppetlabs/relay-core/pkg/util/testutil"
@@ -22,7 +23,7 @@ func TestSecretManager(t *testing.T) {
 		})
 		require.NoError(t, err)
 
-		sm := vault.NewSecretManager(vault.NewKVV2Client(vcfg.Client, vcfg.SecretsPath).In("foo"))
+		sm := vault.NewSecretManager(vault.NewKVClient(vcfg.Client, vcfg.SecretsPath).In("foo"))
 
 		sec, err := sm.Get(ctx, "bar")
 		require.NoError(t, err)
@@ -32,3 +33,69 @@ func TestSecretManager(t *testing.T) {
 		require.Equal(t, model.ErrNotFound, err)
 	})
 }
+
+func TestSecretManagerVersion(t *testing.T) {
+	ctx := context.Background()
+
+	testutil.WithVault(t, func(vcfg *testutil.Vault) {
+		for _, value := range []string{"first", "second"} {
+			_, err := vcfg.Client.Logical().Write(path.Join(vcfg.SecretsPath, "data/foo/bar"), map[string]interface{}{
+				"data": map[string]interface{}{
+					"value": value,
+				},
+			})
+			require.NoError(t, err)
+		}
+
+		sm := vault.NewSecretManager(vault.NewKVClient(vcfg.Client, vcfg.SecretsPath).In("foo"))
+
+		sec, err := sm.Get(ctx, "bar")
+		require.NoError(t, err)
+		require.Equal(t, "second", sec.Value)
+
+		sec, err = sm.GetVersion(ctx, "bar", 1)
+		require.NoError(t, err)
+		require.Equal(t, "first", sec.Value)
+
+		_, err = sm.GetVersion(ctx, "bar", 3)
+		require.Equal(t, model.ErrNotFound, err)
+
+		// Deleted versions can't be read.
+		_, err = vcfg.Client.Logical().Write(path.Join(vcfg.SecretsPath, "delete/foo/bar"), map[string]interface{}{
+			"versions": []int{1},
+		})
+		require.NoError(t, err)
+
+		_, err = sm.GetVersion(ctx, "bar", 1)
+		require.Equal(t, model.ErrNotFound, err)
+	})
+}
+
+func TestSecretManagerKVV1(t *testing.T) {
+	ctx := context.Background()
+
+	testutil.WithVault(t, func(vcfg *testutil.Vault) {
+		require.NoError(t, vcfg.Client.Sys().Mount("kv-v1", &vaultapi.MountInput{
+			Type:    "kv",
+			Options: map[string]string{"version": "1"},
+		}))
+
+		_, err := vcfg.Client.Logical().Write("kv-v1/foo/bar", map[string]interface{}{
+			"value": "baz",
+		})
+		require.NoError(t, err)
+
+		sm := vault.NewSecretManager(vault.NewKVClient(vcfg.Client, "kv-v1", vault.KVClientWithEngineVersion(vault.KVVersion1)).In("foo"))
+
+		sec, err := sm.Get(ctx, "bar")
+		require.NoError(t, err)
+		require.Equal(t, "baz", sec.Value)
+
+		_, err = sm.Get(ctx, "nonexistent")
+		require.Equal(t, model.ErrNotFound, err)
+
+		// KV V1 engines don't keep versions.
+		_, err = sm.GetVersion(ctx, "bar", 1)
+		require.Equal(t, model.ErrNotFound, err)
+	})
+}
@@ -0,0 +1,3 @@
+//go:generate go run generate_tool.go
+
+package asset
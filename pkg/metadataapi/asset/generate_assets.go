@@ -0,0 +1,198 @@
+// Code generated by vfsgen; DO NOT EDIT.
+
+package asset
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	pathpkg "path"
+	"time"
+)
+
+// assets statically implements the virtual filesystem provided to vfsgen.
+var assets = func() http.FileSystem {
+	fs := vfsgen۰FS{
+		"/": &vfsgen۰DirInfo{
+			name:    "/",
+			modTime: time.Time{},
+		},
+		"/openapi": &vfsgen۰DirInfo{
+			name:    "openapi",
+			modTime: time.Time{},
+		},
+		"/openapi/v1": &vfsgen۰DirInfo{
+			name:    "v1",
+			modTime: time.Time{},
+		},
+		"/openapi/v1/openapi.json": &vfsgen۰CompressedFileInfo{
+			name:             "openapi.json",
+			modTime:          time.Time{},
+			uncompressedSize: 31481,
+
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x3d\x5d\x73\xdc\x36\x92\xef\xfc\x15\x28\xde\x3e\x8e\x46\x89\x93\xba\xba\x4b\x9e\x74\x89\xf7\xce\x5b\xf9\x2a\xdb\xc9\x3e\x6c\xb9\x5c\x10\xd9\x33\x83\x88\x03\x30\x00\x38\xf2\xac\x8b\xff\xfd\xaa\x41\x90\x03\x90\x04\xc8\xa1\xc6\xb6\xec\x52\xa4\x94\xf9\x01\x34\x1a\xfd\x85\x46\x77\x13\x7a\x9f\x10\x92\x8a\x12\x38\x2d\x59\xfa\x1d\x49\xbf\x59\x7f\xb5\xfe\x26\x5d\xe1\x53\xc6\x37\x22\xfd\x8e\x60\x0b\x42\x52\xcd\x74\x01\xd8\xe2\x25\x14\xf4\x48\x7e\x06\x4d\x73\xaa\x29\xb9\xf9\xed\x85\x69\x4e\x48\x9a\x83\xca\x24\x2b\x35\x13\x1c\x1b\xbe\xde\x01\xd9\x3b\xcd\x48\x29\xc5\x81\xe5\xa0\x48\x26\xf8\x86\x6d\x2b\x49\xb1\xe9\x8a\x28\xc8\x24\x68\xb5\x22\xa2\xd2\x65\x85\x17\x94\xe7\x44\xe8\x1d\x48\x22\x2b\x7e\xa5\xd9\x1e\x08\x62\x23\xf7\xa6\x07\xd1\x82\x34\x58\x28\x0d\xa5\x32\xad\xb5\x64\xdb\x2d\x48\xb5\x6e\x91\x39\x80\x54\x16\x91\xc3\xd7\x69\x42\x48\x8d\x2f\x52\x05\x59\x25\x99\x3e\xa6\xdf\x91\x7f\x99\x86\xcd\xf4\x08\x49\x6f\x81\x4a\x90\x37\x95\xde\xe1\xbb\x37\xe6\x71\x9d\x10\xf2\xc6\xf4\x2b\xa9\xde\xa9\x13\x35\xae\x77\x40\x0b\xbd\xfb\x77\xf7\x84\x90\x74\x0b\xda\xb9\xc5\xb1\xaa\xfd\x9e\x4a\x1c\x2a\xfd\x61\x07\xd9\x1d\xd1\x3b\x20\x4d\x47\x22\x36\x44\xf7\xe8\x63\x31\xc7\x5f\xe4\x48\x43\x9d\x17\x39\x76\xdf\x82\xfe\x3f\x3b\xa0\xd3\xc8\x9d\xcb\x1b\xe7\xb9\x04\x55\x0a\xae\xe0\x84\xaf\x7d\xf1\xec\xab\xaf\x7a\x8f\x66\x70\x8d\x29\x8b\xf3\xd1\x41\x10\x7f\xd3\x4c\x70\x0d\xdc\x9f\xb4\x7d\x45\xcb\xb2\x60\x99\x99\xc1\xf5\x9f\x4a\xf0\x91\x36\x38\x81\x6c\x07\x7b\x3a\xfa\x8e\x90\xf4\x6f\x12\x36\x88\xd1\x7f\x5c\x67\x62\x5f\x0a\x0e\x5c\xab\xeb\xa6\x8b\xba\x6e\xc9\x31\xe8\x58\x27\xb1\xfb\x3a\x19\xbb\x6e\xaf\x9a\x7f\x6b\x2b\x41\xd7\x56\x2d\xd6\xbd\x09\x44\x19\xfd\x12\xb4\x64\x70\x00\xa2\x77\x4c\x91\x5f\x4b\xe0\x28\xf9\x2e\x8d\xa3\x5c\xb6\x1d\x3e\x0a\x97\x47\x90\x9b\x10\xcb\x8f\xc1\x75\x7d\x2c\x8d\x99\x11\xb7\x7f\x42\xa6\x3f\x28\x7f\x33\xc1\x73\x86\xe4\x57\x73\xb9\xfb\xfc\x40\x8b\x8a\x6a\xe4\x2e\x90\xfb\x1d\x70\x72\x82\xd1\xd2\x2e\xab\xa4\x04\xae\x8d\x6d\x8a\x33\xfb\x87\xd3\xf8\x4e\xbb\x92\x4a\xba\x07\x0d\x52\x75\x36\xaa\xf9\x79\x9f\x4c\xab\xc7\xa9\xf3\xf5\x8f\x70\x5b\x6d\xd3\x51\x7a\x5c\x54\x8c\x1c\x0a\xdc\x83\x04\xb2\xa9\x8a\xe2\x48\xc0\x52\x2a\xff\xc8\xe2\x13\x35\x1a\x0e\xc1\x07\x7d\xeb\x24\x76\xef\xde\x59\xf9\xb1\x03\x7e\xfb\xd5\xd7\xe9\x77\xb3\x78\xd3\x51\xfa\xfa\x77\x4e\x2b\xbd\x13\x92\xfd\x1b\xf2\x34\x02\xf9\x9b\xb3\x21\x3f\x97\x52\xc8\x18\xc8\x6f\x2f\x0e\xf2\xd9\xb3\x73\xc5\x24\x13\x55\x91\x13\x2e\x34\xb9\x85\xc7\x29\x28\xaf\x25\xcd\x20\x37\xc4\x7c\xce\x0f\x50\x88\x12\x5c\x0a\x58\x3a\x2c\x95\x98\x67\xff\x7d\x36\x13\x5e\x0b\xf1\x33\xe5\xc7\x97\xf0\x57\x05\x4a\xab\x34\x08\x3c\x87\x0d\xad\x0a\x7d\xf6\x00\x43\x2e\x27\xfd\xab\x9e\xf5\x84\x03\xe2\xe8\x0c\x94\x96\x42\x45\x4c\xe7\x9e\x69\x42\x39\x31\xdd\xc8\x46\x8a\xbd\x67\x2d\xad\xff\x16\x31\x98\x08\xfd\x39\x76\x76\xdb\xc8\x86\x20\xff\x23\xf2\xa3\x37\xb2\x7d\xc5\x24\x60\x5f\x2d\x2b\x58\x25\x33\x64\x6b\x8e\x64\xc5\xe4\x2a\x2e\x55\x06\x7b\xcb\x42\x97\xd4\x2e\x91\x87\x77\x75\x32\xc2\xe9\x98\xd9\x9e\xa7\x8f\x0d\x1f\xee\xa9\x22\x34\xcb\xa0\xd4\x71\x4b\xf4\x39\xd9\xb8\x67\xcf\x2e\x0f\xf2\xcb\xd0\x58\x7e\x60\x52\xf0\xbd\x2f\xf9\xf3\xdc\x59\x5a\x14\x27\x53\x4d\x1c\x48\xe4\x40\x25\xa3\xb7\x05\x28\xb2\x11\xd2\xd3\x69\x9a\x4d\x3b\xbc\xcf\x1d\x9c\x9c\x86\xdd\x34\x97\x7a\x25\x53\xb8\xa2\xdc\x93\x3d\x2d\x1f\xd3\xaa\xf3\x12\x14\x9a\xee\x41\xbf\x3a\x89\xdd\xd7\x41\xc9\x7a\x72\x4d\xbe\x34\xb5\xbd\x7e\xcf\xe9\x1e\xea\xf3\xb5\x97\x28\xc6\xb7\xc5\x94\x5a\x3c\x54\x83\xff\xb0\x70\x3e\xc4\x76\xe6\x17\xba\x87\x71\xb2\xbd\xf9\x78\x76\xe3\x31\x59\x0b\xbb\x17\x65\x82\x3f\xd9\x8d\x27\xbb\x31\x62\x37\x0a\xb1\x9d\xef\x9e\xff\x20\x01\xe3\x1a\x94\x70\xb8\x27\x85\xd8\x12\xa5\x25\xd0\xfd\xf9\x16\x01\xdd\xf4\x9f\xc4\xf6\x02\x4e\x7a\x4f\x3b\x6f\x88\xc4\x98\xef\xba\x2c\xd4\xfa\x27\xb1\x6d\x10\xb6\xf4\x26\x7b\x50\x8a\x6e\x61\x45\x80\x67\x22\x87\x9c\x50\x85\xe1\x66\x2d\x6e\xab\x0d\x11\x92\xfc\xe3\xd5\xaf\xbf\x38\x28\x45\x54\xd7\x53\x5c\x91\x69\xd0\x57\x0d\x29\x06\x2d\xe3\xea\xdb\x05\xb2\x94\x96\x8c\xbb\xf4\x68\xff\x4b\x9b\x80\x36\x12\xed\x96\x71\x74\xb8\x7a\x4d\x4e\x1c\x1e\x88\xd1\x3c\x03\x13\xc5\x2f\x6a\x5c\xfa\x04\x9e\x40\x2d\x19\x7b\x53\xcf\xb3\xcb\x5f\x4f\xda\xe5\x00\xe7\x1b\x88\x2d\xeb\x09\xe3\x36\x74\x99\x33\x4a\x90\xf8\x6d\x40\xce\x8a\xdf\x42\xdb\x3d\x21\x02\xb3\xe3\x99\x41\x31\x98\x21\x08\x3e\x85\x47\x84\x61\x9e\x38\x4c\xe2\x3a\x57\x24\x1a\xca\xcf\xc0\x32\x09\xbd\xfd\x9c\x17\x9c\xa7\xfd\x65\x60\x7f\x89\x0b\xce\xf5\xfb\x42\x6c\x5f\xe4\xf5\xb5\x55\xcb\xf9\x2b\xd0\x4d\x59\x02\xcf\x09\xed\x14\x5a\x0b\x42\x9d\xa5\x68\x7a\xcd\xf9\xb9\xe9\xb8\xc4\xfb\x44\xb7\x1a\x91\x30\xc8\x3b\x00\xf0\x37\x65\xa8\x4c\x26\x03\xd8\x7f\x13\x59\xbe\x86\x66\x0c\xb7\xa5\x2c\x07\xae\xd9\x86\x81\x6c\xad\x13\x4e\x50\x82\xae\x24\x87\xbc\x49\x29\xb0\x26\x2c\x93\x49\x18\x0b\x8b\x06\x35\xb8\x6f\x69\xbc\xd7\x75\x32\x76\xfd\xe6\x43\x2f\xd2\x96\x23\x0d\x6b\xad\xa8\x76\xfc\x8d\x2c\xd5\x2b\x5c\xb3\x29\xb9\xa5\x3a\xdb\x21\xa1\x6c\x17\xb3\x6d\xe7\x70\x5f\x30\x0e\x57\x39\x14\x6c\xcf\x70\x2b\xf3\xb4\xb8\x9f\xbd\xb8\x8f\x31\x66\x39\x86\xef\xae\x78\xbe\x04\xcb\x69\x2a\xf6\xc4\xeb\x57\x0e\x46\x3e\xae\x5a\xd9\x09\xc9\x58\x09\x92\xa0\x94\x4c\xcc\x29\x19\x7b\x33\xd3\x6f\x19\x59\x05\x7a\xd8\xa2\xc2\xc7\xd4\xa1\xe7\xc0\x08\x79\x92\xf3\x27\x67\xe6\x83\x3b\x33\xa3\xcc\x78\x28\xb2\x11\x4d\xb8\x08\x71\x47\xf4\x01\xc5\x9c\x94\xd0\x09\x4f\x2b\x3b\x56\x52\x56\xed\xbd\xa2\x7b\x20\x42\xe6\x20\xd7\xe4\x39\xcd\x76\x46\x3f\x08\x53\x04\x98\xa9\xb4\xd1\xbb\x49\xdd\xb2\x02\x2b\xe4\x8a\xb0\xb6\x50\xc0\x34\xf0\x53\x7a\xd4\x34\x87\x1c\xeb\x78\x48\x93\xc7\x27\xf7\x4c\xef\xf0\x16\xd0\xab\x20\x77\x70\xc4\x84\xa0\xa6\x8c\x33\xbe\x3d\xad\xf9\x6b\xf2\x62\x43\xb8\xe8\x81\xf5\x40\x3a\x53\x23\x1b\xca\x0a\xd4\x15\xa5\x81\xe6\x33\x58\x97\x84\xee\x9e\xdc\xd1\x2f\xd0\x1d\xb5\xd5\x64\x23\x31\xd3\xb2\x0a\xbb\xa1\xaf\xc0\xa4\x29\x9b\xce\xad\xd5\x9d\x59\xd3\x51\x56\xfa\x57\xd3\x6f\x89\x0b\x3a\x3a\xff\x05\x01\xd0\x4b\xb8\x71\xb8\x72\x61\x78\xb1\x5b\x76\x1a\x72\xac\xcd\xe2\xdb\xa9\xdf\xad\xc8\x19\x28\x42\x25\x10\xa5\x85\x6c\xa2\x2e\x4a\xcb\x2a\xd3\x15\xde\x61\x05\xd1\xf7\x26\x73\xd4\xd4\xf2\x9d\x56\xb3\x7e\x27\x8a\x3e\x3e\xe3\xdb\x75\xea\x61\x35\xcb\x8d\x0b\x98\x5b\xc7\xd8\x9e\x28\x35\x10\x47\x34\xbb\xf0\x4e\x5f\x97\x05\x65\x13\x50\x92\x29\x83\xdd\x6b\x10\x1f\xf5\x8c\x55\xf9\x72\x38\x24\x63\xd7\x33\xdd\x9d\x11\x93\x38\x22\x34\x56\x6d\x70\xff\xd2\x70\x37\x4d\x02\x34\x78\xb2\xb2\x5f\x98\x95\x45\xdb\x88\x49\x9a\x7a\x71\x92\xca\xb5\xba\x68\x11\xa0\x6c\x7d\x97\xd6\xfe\xca\x6a\x22\x19\xb5\xdc\xfc\xb6\x11\x80\x76\x1a\x1f\x30\x08\x80\x43\xb5\x86\x15\x87\x23\x7a\x47\x35\x51\xa0\x1d\x53\xdb\x1f\x24\x68\x03\xa2\x16\xa0\x0e\x09\xc8\x1c\xa9\x58\xb0\xf6\x58\x31\x5a\x9a\x7c\x1b\x9f\xfa\xcc\xbd\xcd\x52\xb7\x3b\xba\x4f\xb0\xf2\x34\xe8\x57\x27\xb1\xfb\x20\xd9\x9f\x52\x6b\x5f\x46\x6a\xcd\x7e\xb1\xb0\xd8\xd2\xd9\x4f\x1e\x5a\x23\xa0\x81\x53\xae\x89\xb8\x37\xbb\xa1\xb3\xd2\x6d\x5b\xd0\xaf\x0c\xac\x25\x36\xef\x91\xa8\xbd\xea\x4f\xe0\x93\xab\xbd\x25\xe9\xa0\x5f\x9d\xc4\xee\xeb\xa0\x64\x3e\xa9\xfd\x97\xa1\xf6\x25\x64\x73\x95\xdd\xfb\x54\x40\x95\x90\xb5\xca\x7e\x96\x6a\xe3\x80\x0b\x14\xbb\x75\x66\xfe\x1a\xf7\x62\xfe\xaa\x40\x1e\x23\x6e\xcc\x86\x16\x6a\xc2\x8f\xb9\x21\x06\x08\x7e\x8a\xa5\xa0\x80\x4c\x13\x4a\x4a\x2a\xbb\x1d\xb3\x99\xb1\x16\x5d\x3d\x8d\x8d\xec\x68\xf3\x02\x13\x20\x36\x94\x83\xe9\x10\x69\x9b\x33\xd5\x35\xcf\xfd\x9d\xe0\x07\x77\x80\x5a\x82\x15\x94\x6f\x3f\x18\xcd\xd0\xc5\xc1\x01\x2a\x8c\x98\x59\x3a\x19\xb0\x64\xcb\x0e\xc0\xc9\xed\xb1\x79\x44\x3a\x36\xaf\xc9\x9f\x7f\x61\x72\x64\x2b\xe9\xfe\x44\x9c\x3e\xe1\xbe\x27\x3f\x3c\xff\x89\xc0\xbb\x52\x82\xc2\x6f\xde\x14\xc9\x28\x27\x12\x36\x18\xd5\x13\x04\x30\xd8\xa7\x45\x79\x55\xc0\x01\x0a\x13\x7b\x73\x99\x64\x2a\x21\xdb\x02\xa7\xe5\x64\xf7\xfb\x11\x92\x02\xaf\xf6\x3d\x29\xb5\x6f\x46\x9c\x68\xfc\x4d\xd1\x94\x4f\xbd\xbb\xd2\xb0\x2f\x0b\xaa\xfb\xfe\x39\xfe\xa4\x7f\xf6\xe5\x1d\x7f\xd2\x0c\x0a\x5f\x28\xbc\xe5\x6b\x60\x44\x9a\x44\xdf\x22\x29\x1a\x35\x38\x9f\xe0\x93\x9c\x4e\x8b\x0c\x87\x1f\xd3\xc2\xfa\x54\xaa\x76\xe9\x52\xb5\xa7\xb8\xc4\x78\x5c\x42\x69\xaa\xe1\x01\x0e\x3a\x76\xb7\x31\xd0\x25\x55\xb1\xaf\xb4\x6f\xa3\x3e\x3b\x9f\xfc\x34\xff\xc7\x64\x3f\x1a\xb2\x0e\xba\xd5\x49\xec\xbe\x0e\x8a\xe3\x93\x5f\xfe\x45\xf8\xe5\x07\x5a\xb0\x1c\x05\x63\x6e\xad\xd1\x1f\xb6\x03\xd1\x83\xd5\xb2\xef\xa6\x9b\x00\x1d\xdd\x52\xcc\x32\x9a\xe6\x8d\x28\x9a\xc2\x58\xa6\x15\x61\xfb\x5e\xc9\x51\x4f\x99\xec\x50\xf8\x71\x35\xa6\x2b\x2b\x69\x93\x1f\x12\x4a\x21\x71\x89\x16\x95\xbe\x12\x9b\xab\x5b\x3c\xc5\x00\xff\xe7\x70\x00\x69\x52\x9b\xcd\x37\xe4\x36\xdb\xb2\x8e\xd8\x1b\xac\xb9\x6d\xa7\xe4\x36\xeb\x68\xba\xd4\x0a\x58\xc2\x22\xf2\x6d\xd2\x07\x43\xfb\xa5\x14\x19\x28\xf5\xd1\xbf\xd9\xe4\x55\x51\xa0\x97\x6a\x23\xad\x83\x26\x75\x12\xbb\xaf\x83\x92\xfa\x64\x06\x3e\x6b\x33\xd0\x1d\xee\x71\x82\xd3\x8d\xd6\x1d\x9f\xf0\x0a\x25\xcb\xd3\x04\xff\xc4\x8f\xf7\xc9\x60\x67\xb3\xd3\xda\xcb\xf6\x1a\xcd\x37\x6f\x9a\x9e\xee\xbb\xe6\xc9\xdf\xbb\xda\x97\x7f\xfc\xf3\x75\xc4\x28\xa0\x6e\x69\x71\x07\x9c\x30\xa5\x2a\xc8\x71\x9b\x86\xb6\xa5\xa9\xae\x5f\x93\x7f\x62\x1d\x60\xff\x20\x06\xc2\x4e\x27\xa7\x34\x5d\x0a\x21\xee\x48\x55\x92\x52\xe4\x0a\x77\x8f\x2f\x7e\x23\x34\xcf\x71\x17\xb8\xc2\x2a\x8a\x1d\xd0\x1c\x24\xb1\x36\x04\x53\x14\xeb\xd4\xa7\xdc\x2a\x19\xfa\x26\x1d\x79\x4c\xf4\xcf\x23\x4c\xbb\x4b\xe6\x7e\x7e\xc4\xee\x90\x7b\x5b\xb7\x70\x5e\x64\x2a\x27\x62\x4d\x0d\xda\x46\x53\x3c\xe2\x02\x1d\x35\x10\xfd\xbd\xe8\x50\x50\x5a\x08\x69\x73\x2a\xc2\xe8\xac\x72\xf3\x6a\x95\x4c\x6c\xfc\x23\x9b\xfe\xfe\xc4\x5e\x6c\x88\xc6\x2f\xc9\xdb\xaa\x15\x5b\x3f\xc3\x78\x56\x54\x78\x0e\x0e\x6d\x5e\xe3\xbc\x77\xe2\xbe\xd9\xa8\x9b\x6d\x3b\x70\xec\xb4\xa9\xb8\x91\x07\xc2\xf8\x41\x64\xf6\xb0\x1c\x5c\x22\x9a\xa0\x01\x5a\xe2\x6e\xed\x5a\x93\x57\xc0\x15\xd3\xec\x60\xbd\xb6\xae\x86\xac\x19\x02\xf3\xed\x12\x72\x9a\x99\xb6\xf8\x7d\x3b\x86\x9a\x99\x22\x82\x17\x47\x42\x0f\x94\x19\xcb\xda\xd6\xf5\x28\x90\xb8\x0a\x01\xc7\x87\x8a\x30\xfd\x7d\x73\x1e\xcf\x3d\x53\xe0\xf2\xa8\x11\x2e\x64\x12\xe4\xeb\xb3\xf9\xb4\x4a\xa6\xe2\x05\xa9\xc1\xfe\xc4\x50\x42\xde\x0c\x99\xeb\xc9\x72\x67\x40\x9c\xb1\x53\xcf\x58\xbb\x38\x8d\x89\x62\x3b\x35\xbf\xa6\xa9\xd2\x3b\x8c\x55\x65\xbd\x9a\xdc\xc0\x92\x17\xab\x2a\x18\xa7\x4d\x54\x8e\xdd\xe9\xfa\xd7\x43\x09\x6f\x96\x8f\xd8\x24\x6f\xda\x32\x2c\x91\x19\x2f\x27\x6f\x17\xf4\x36\xfb\x60\x29\x30\x63\x9a\x93\xab\x7a\x64\xb2\x51\x47\x3f\x72\xf6\xc2\x5c\x42\xf4\x57\xa8\x18\x49\x5e\x77\xc6\x97\xec\x50\xad\xde\x65\x00\x58\x5f\x8a\x1e\x5e\x2b\x0f\xb7\x55\xbe\xf5\xf3\x2c\x8d\x81\xf5\x21\x93\xe6\xb3\xcb\xe3\xd5\xcd\x46\x83\xcf\x87\xd0\xc0\xbc\xda\xdf\x36\x05\xe1\x0a\xf0\x34\x15\x85\xb6\xfd\x9e\x32\x14\xbc\x8d\x30\x8a\xab\xe5\x31\xcc\x9c\x99\x42\xc5\xb8\x86\x2d\x78\x4b\xaa\x4b\x40\xf7\xba\xfe\x2c\x39\xef\xd9\x01\x0b\xce\x19\x3e\x6d\xcf\x8c\x72\x31\xea\x9f\x37\x34\x6e\xe7\x5d\xa3\x94\x96\x9e\x6a\xba\xbb\xee\x52\x62\x20\x40\x33\xcf\xf8\x9c\xfa\xf8\xcf\xa2\xea\x3e\x32\xbb\x76\x9c\xf4\x79\x53\xfb\xfc\x87\xd9\x9b\xc7\x84\xfa\xc6\xc6\x2f\x4c\x79\xc1\x9e\x1e\x4d\x6d\x26\x31\x46\xc9\xd6\x5b\x99\x32\x7b\xde\x7d\x34\x67\xf1\x70\x66\x24\x38\xfc\xba\xe9\x11\x60\xfe\x24\x56\xd3\xbd\x06\x64\x8f\x91\x1e\x7f\xd2\xbf\x19\x6c\xfb\xcb\x07\xfe\xa4\xe8\x29\xb9\x08\x78\xdc\x89\x73\xa8\x07\x7a\xf8\x72\x38\xd3\xfe\xf0\xb1\x98\x37\x9e\x2f\x47\x15\xfc\xe7\xb7\x3e\x7a\xfe\x82\x36\x42\xb5\xd3\xb4\xe6\x60\xd4\x6b\x50\x27\xa1\xbb\x3a\xe9\x8f\xde\x8d\x9a\xfe\xce\x25\x28\x51\x1c\xec\x46\xeb\x7d\x32\x18\x6d\xa8\x2a\x23\x46\xad\xf3\x63\x54\x53\xde\xe2\xad\xa6\xcd\x08\x58\x22\x58\xa1\xc4\xb5\x6e\x4c\x2f\xaa\x16\x56\x27\x9b\x7f\xef\x3d\x76\x50\xa4\x52\xd2\x41\xb6\x86\x69\xd8\x8f\xf2\x3d\x38\xb1\x69\x71\x6c\xfd\xc7\xde\xe3\x9e\xdc\x4d\x49\xde\xc9\x0b\x1d\xbe\x99\x64\xf3\x88\xc8\x78\xe7\x1f\x46\x41\xb6\x8b\xc2\x10\x02\x21\xe9\x9e\x71\xb6\x37\x12\xfd\xf5\xe8\xfb\x11\xb6\xdb\x61\x5b\x57\xde\xd6\x39\x18\x09\xb8\xa7\xdd\x5a\x8a\xb5\xe1\x0c\x2b\xbd\x9b\x92\x27\xcc\xe7\x0c\xbf\x3f\xf1\x45\x76\x78\x5f\x27\x01\x0a\xa0\x4f\xc6\x21\xeb\x9f\xec\xd6\x9b\xf9\xc7\x14\x11\xd3\x7b\x15\x60\xfa\x83\x45\xc7\xe2\x36\x7c\xb3\x50\x74\x1e\x24\x8a\x0b\x59\x66\xcb\x08\x07\xc3\x7e\x12\x76\xe1\xe9\x54\x69\x88\x30\x0f\x66\x97\x81\x3e\xf6\x66\x0e\x79\x43\x58\x2d\x85\x97\xc4\xee\xeb\x24\x30\xf2\x78\xc4\xe0\x13\x72\xec\x32\x9c\xf9\x14\x94\xa4\x5c\xdd\x3f\x1a\x32\x52\x75\xf7\x12\x36\xe9\x2a\x40\x9a\x07\x13\xd8\xc2\x1f\x7b\x37\x87\xc4\x21\xbc\x96\xc2\x4b\x62\xf7\x75\x12\x18\x39\x3d\x85\x63\x86\xd3\xfc\x24\x6c\xfb\xac\xa4\x3f\xe9\x3f\xed\x88\x9b\xda\xea\x00\x17\x87\x73\x9d\x4e\x04\x80\xce\x47\xeb\x51\xe2\xd7\x65\xdc\xa9\x8d\x21\x5a\x42\x53\x8c\x84\x41\x87\x02\x34\x60\x08\xab\x89\xe3\x19\x5f\x04\xfb\x41\xfb\x71\x1a\xfa\xae\x40\x2a\x6e\xa1\x41\x8e\xa5\x39\x5a\xb5\xde\x8d\x81\xe5\xa2\x14\x60\x53\x3a\xcc\xa3\xa6\x95\xeb\x66\x7b\x6f\x5a\xc4\xd2\x64\x84\x97\x61\x2e\xda\x41\xfc\x87\xe3\x64\x3a\x4d\x07\x2f\x60\x4d\x5e\x19\x0d\xb1\x9e\x3a\x86\x0a\xd1\x33\x33\xc9\x1f\xf2\xfb\xeb\xbf\x5f\xfd\x97\xc9\x58\xd9\x5d\xe2\x3a\x0d\x2a\x86\x37\xa7\x01\x22\xd1\x5d\xbf\xb7\xeb\x08\x0e\xd0\x91\x66\x00\xbc\x15\x93\x5b\x21\x0a\xa0\x3c\x8c\x63\x13\x50\xbc\x90\xda\x46\xa7\x84\x41\x56\xf8\x45\xe4\xde\x7c\x5c\x05\x88\x2b\xc3\xa9\xfb\x72\x7d\xb0\x8c\xb6\x0e\x39\x9d\x0c\x2e\x0b\x49\x62\xe1\x6e\x4f\xa6\x07\x95\x5a\xe9\x1d\xe3\x7e\x46\xb0\x9f\x28\x58\xac\x09\xde\x53\x28\x68\xa9\x20\x3f\x57\x3d\x0c\xbe\x41\xbe\x5b\xc3\xb6\x9a\xd4\x9d\xc2\x12\xab\xb5\x01\x5c\xe4\xdd\xd9\x33\x27\xbd\x42\xcb\x10\x96\x41\x43\xa8\x33\x51\x09\x84\x16\xc6\xb7\x16\x69\x38\x46\x72\x62\xf7\xf0\x5d\xc3\xfb\x24\x14\x9d\xf0\x27\x31\xba\x5c\x9c\x4f\x4f\xec\x80\x61\xa8\x16\x63\x97\xae\x4d\xde\xc4\x4d\x0d\x79\x52\x7b\x87\xdb\x48\x5b\x9b\x33\x40\xdd\x47\xd6\x06\x1c\xe4\x05\x10\x6e\x41\xe1\x1a\x81\xab\x4d\x1f\x89\xae\xc4\x33\x8c\xcd\x7c\x4b\x3d\xba\xa0\x9d\xc8\x23\x24\xf9\x57\x9b\xd5\x79\x83\xb9\x1b\xa6\x71\x39\x53\x6d\x2a\x28\x8c\x82\xa7\x74\x8f\xd4\x54\x9f\xa6\xb1\x1c\x06\x0c\xb2\x21\x63\x5c\x0f\x77\xb7\xb6\x66\x0a\xc0\x6a\x92\x93\xe6\x8f\x58\x68\x8a\x39\x5f\xa7\x2c\xda\x11\x75\x53\x87\xfb\xbf\x02\x43\x62\xc6\xc0\x84\x91\xca\x76\xac\xc8\x25\xf0\x30\x56\x8f\x67\x15\x1b\x9c\x52\xe8\x8e\x7f\xde\x62\x26\xe9\xfd\x5c\x07\x6f\xce\xfa\xf5\xc7\x70\x25\x1a\x97\xec\x79\xcb\x4b\x17\x8e\xaf\xc3\x20\x83\xfc\x1a\xcc\x1f\x7f\x53\x9a\x37\x27\xa6\xd3\xe2\xb7\xd0\xb0\x93\x5c\x1f\x56\xcd\x0c\xde\x87\xe4\x62\x88\x5f\xaf\x41\x9d\x84\xee\xea\xa4\x7f\x75\x92\x08\xe7\x10\x7e\x77\xcc\xfe\x58\x33\x38\xa8\xaa\x0c\xb3\x96\xde\x8c\x53\x7b\x74\xc4\xb9\xec\x6b\x61\xf9\x8f\xcf\xb1\x34\xed\xc0\x53\xa6\x22\x08\x60\x89\x67\xda\x53\x93\xdf\x24\x28\x2c\x5a\x33\x49\x7d\xb6\xe9\x0a\x0c\xbc\xd0\xeb\xfa\x91\x18\x06\xe7\x30\x74\x77\xec\x05\x82\xe0\xe7\x7d\xe6\x31\x7c\x34\xa9\xb2\x50\x21\xeb\x20\x4f\xef\xe0\x18\x1e\x64\xd6\xda\x71\xc3\x89\x30\x37\xb4\xf9\x74\xa3\x52\x4d\xed\x4f\x0e\x79\xd5\xe4\xde\xed\x59\xee\x7e\x09\x56\xd2\xbf\x3a\x51\xde\x7e\xd3\xea\xa2\xb5\x80\xe6\x9a\xaa\xbb\xb7\x43\xff\x1e\x27\xec\x3d\x40\x33\x7d\xb6\x31\x3d\x01\x9f\x22\xde\x83\x28\x1f\xec\xdc\x39\x68\x31\x3a\xda\x8f\x04\xdd\x41\x16\xd0\xf1\x32\x04\xbb\xcc\x6c\x93\xf9\xda\xef\x25\xa1\x9d\x7e\x51\x82\x69\xbf\x46\xf7\xb3\xa7\x57\x6c\xb2\xa6\x8c\xe1\x47\x4f\x97\xdf\x27\x83\xa1\x86\xf3\x0e\xe3\xbc\x91\x0c\x78\x5e\x3c\x00\x71\x0d\xd9\x8e\xb3\x8c\x16\x67\x81\x98\x9a\xa4\x07\x2c\x32\xb3\x10\x47\x73\xb1\xc7\x32\xa9\x53\x4b\x5c\xda\x9b\xf4\x9d\xff\x10\x25\xce\x7f\xd2\xfc\x51\xba\x33\x79\x6f\xc7\x9b\x22\x81\x3b\x10\xee\x04\xde\x51\xdc\xd0\x20\x8d\xe4\x9e\x86\x69\xdc\x62\xbe\x1c\xfc\x5e\xe4\x50\x84\x07\xc8\xfa\x21\xa1\xb3\x91\x7f\x6b\x46\x78\xcb\x85\x7e\xbb\x11\x15\xcf\xdf\x42\xbc\x3c\xb8\xfd\xd3\x7f\xf1\x21\x83\xdd\xdb\x6d\x5c\xf3\x57\xf7\x16\x02\xf1\x57\xc5\xb3\x2c\x55\x5f\x11\x83\x63\x50\xb9\xad\xf6\xbd\x3f\x6a\xd3\x43\xf3\x52\x7e\xc1\xb8\xcb\xb5\x70\x18\xaf\xd5\x2c\x72\xb8\x34\x20\x24\x8c\x66\x73\x42\xb5\x86\xfc\x83\xd1\x3c\xa3\x95\x82\x08\x25\x46\xbd\xde\x65\x0e\xeb\xd4\xcc\x93\xfe\x55\x87\x69\xea\xd7\xa4\xb9\x03\x2f\x58\xc1\x7a\xda\x36\xcf\x68\x05\xe2\x18\xe7\x4d\x38\x36\xc5\xb1\x3f\x79\xe5\x0e\x97\xd2\xa2\x98\x2a\x05\x9b\xc6\xa6\x03\xed\x74\xac\x57\x61\x88\x41\xea\xc6\xc9\x15\xd9\x4f\x4d\x4a\xd7\xc5\xf6\x55\x51\x51\x5d\xbc\xbf\xf2\xf9\x38\x76\x5f\x27\x63\xd7\xc3\x42\x2f\xe7\x40\xef\xb3\x76\x61\x61\x21\xc5\xac\x19\xbc\xf3\x01\x8d\x59\xf9\x55\x12\x23\xf6\x6b\x7b\x2a\xb2\x85\xf6\x3d\xb1\x5f\x90\xa8\xf6\x2b\x89\xde\x77\x98\x21\x51\x9a\x17\xa1\x1e\x25\x56\x9d\xf4\xe0\xa5\xc3\xd3\xcf\x5d\xc0\x8b\xa8\xd5\x9c\x35\x7d\x39\xfc\x7a\x87\x75\x5e\x88\xa7\xf3\xb0\x8c\x73\xf4\xc7\x1e\x03\x91\xbb\xce\x49\xd8\x36\x7b\x62\x15\x89\xf4\xbf\xbf\xef\x87\x59\x72\x46\x5f\x8f\x55\x3c\xf5\x71\x0a\x82\x28\xe9\xb1\x10\xf4\xec\x49\x39\x07\xdb\x1e\x35\x4c\x4c\x19\x85\xb8\xa9\xf8\xec\xce\x53\xb5\x01\x22\xd2\x0e\x1f\xc4\x0f\x43\xc5\x4a\xd3\x7d\xb9\x1c\x43\xfc\x12\xd0\xfc\xdd\x64\x6f\x94\xb3\xe4\xe7\xd3\x89\xf9\x09\x6c\x23\x7f\x16\xb1\x33\xa1\x0c\x27\x7b\xfa\x5a\x2c\xa9\x93\xff\x1f\x00\x6d\xab\xb7\x21\xf9\x7a\x00\x00"),
+		},
+	}
+	fs["/"].(*vfsgen۰DirInfo).entries = []os.FileInfo{
+		fs["/openapi"].(os.FileInfo),
+	}
+	fs["/openapi"].(*vfsgen۰DirInfo).entries = []os.FileInfo{
+		fs["/openapi/v1"].(os.FileInfo),
+	}
+	fs["/openapi/v1"].(*vfsgen۰DirInfo).entries = []os.FileInfo{
+		fs["/openapi/v1/openapi.json"].(os.FileInfo),
+	}
+
+	return fs
+}()
+
+type vfsgen۰FS map[string]interface{}
+
+func (fs vfsgen۰FS) Open(path string) (http.File, error) {
+	path = pathpkg.Clean("/" + path)
+	f, ok := fs[path]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+	}
+
+	switch f := f.(type) {
+	case *vfsgen۰CompressedFileInfo:
+		gr, err := gzip.NewReader(bytes.NewReader(f.compressedContent))
+		if err != nil {
+			// This should never happen because we generate the gzip bytes such that they are always valid.
+			panic("unexpected error reading own gzip compressed bytes: " + err.Error())
+		}
+		return &vfsgen۰CompressedFile{
+			vfsgen۰CompressedFileInfo: f,
+			gr:                        gr,
+		}, nil
+	case *vfsgen۰DirInfo:
+		return &vfsgen۰Dir{
+			vfsgen۰DirInfo: f,
+		}, nil
+	default:
+		// This should never happen because we generate only the above types.
+		panic(fmt.Sprintf("unexpected type %T", f))
+	}
+}
+
+// vfsgen۰CompressedFileInfo is a static definition of a gzip compressed file.
+type vfsgen۰CompressedFileInfo struct {
+	name              string
+	modTime           time.Time
+	compressedContent []byte
+	uncompressedSize  int64
+}
+
+func (f *vfsgen۰CompressedFileInfo) Readdir(count int) ([]os.FileInfo, error) {
+	return nil, fmt.Errorf("cannot Readdir from file %s", f.name)
+}
+func (f *vfsgen۰CompressedFileInfo) Stat() (os.FileInfo, error) { return f, nil }
+
+func (f *vfsgen۰CompressedFileInfo) GzipBytes() []byte {
+	return f.compressedContent
+}
+
+func (f *vfsgen۰CompressedFileInfo) Name() string       { return f.name }
+func (f *vfsgen۰CompressedFileInfo) Size() int64        { return f.uncompressedSize }
+func (f *vfsgen۰CompressedFileInfo) Mode() os.FileMode  { return 0444 }
+func (f *vfsgen۰CompressedFileInfo) ModTime() time.Time { return f.modTime }
+func (f *vfsgen۰CompressedFileInfo) IsDir() bool        { return false }
+func (f *vfsgen۰CompressedFileInfo) Sys() interface{}   { return nil }
+
+// vfsgen۰CompressedFile is an opened compressedFile instance.
+type vfsgen۰CompressedFile struct {
+	*vfsgen۰CompressedFileInfo
+	gr      *gzip.Reader
+	grPos   int64 // Actual gr uncompressed position.
+	seekPos int64 // Seek uncompressed position.
+}
+
+func (f *vfsgen۰CompressedFile) Read(p []byte) (n int, err error) {
+	if f.grPos > f.seekPos {
+		// Rewind to beginning.
+		err = f.gr.Reset(bytes.NewReader(f.compressedContent))
+		if err != nil {
+			return 0, err
+		}
+		f.grPos = 0
+	}
+	if f.grPos < f.seekPos {
+		// Fast-forward.
+		_, err = io.CopyN(ioutil.Discard, f.gr, f.seekPos-f.grPos)
+		if err != nil {
+			return 0, err
+		}
+		f.grPos = f.seekPos
+	}
+	n, err = f.gr.Read(p)
+	f.grPos += int64(n)
+	f.seekPos = f.grPos
+	return n, err
+}
+func (f *vfsgen۰CompressedFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+		f.seekPos = 0 + offset
+	case io.SeekCurrent:
+		f.seekPos += offset
+	case io.SeekEnd:
+		f.seekPos = f.uncompressedSize + offset
+	default:
+		panic(fmt.Errorf("invalid whence value: %v", whence))
+	}
+	return f.seekPos, nil
+}
+func (f *vfsgen۰CompressedFile) Close() error {
+	return f.gr.Close()
+}
+
+// vfsgen۰DirInfo is a static definition of a directory.
+type vfsgen۰DirInfo struct {
+	name    string
+	modTime time.Time
+	entries []os.FileInfo
+}
+
+func (d *vfsgen۰DirInfo) Read([]byte) (int, error) {
+	return 0, fmt.Errorf("cannot Read from directory %s", d.name)
+}
+func (d *vfsgen۰DirInfo) Close() error               { return nil }
+func (d *vfsgen۰DirInfo) Stat() (os.FileInfo, error) { return d, nil }
+
+func (d *vfsgen۰DirInfo) Name() string       { return d.name }
+func (d *vfsgen۰DirInfo) Size() int64        { return 0 }
+func (d *vfsgen۰DirInfo) Mode() os.FileMode  { return 0755 | os.ModeDir }
+func (d *vfsgen۰DirInfo) ModTime() time.Time { return d.modTime }
+func (d *vfsgen۰DirInfo) IsDir() bool        { return true }
+func (d *vfsgen۰DirInfo) Sys() interface{}   { return nil }
+
+// vfsgen۰Dir is an opened dir instance.
+type vfsgen۰Dir struct {
+	*vfsgen۰DirInfo
+	pos int // Position within entries for Seek and Readdir.
+}
+
+func (d *vfsgen۰Dir) Seek(offset int64, whence int) (int64, error) {
+	if offset == 0 && whence == io.SeekStart {
+		d.pos = 0
+		return 0, nil
+	}
+	return 0, fmt.Errorf("unsupported Seek in directory %s", d.name)
+}
+
+func (d *vfsgen۰Dir) Readdir(count int) ([]os.FileInfo, error) {
+	if d.pos >= len(d.entries) && count > 0 {
+		return nil, io.EOF
+	}
+	if count <= 0 || count > len(d.entries)-d.pos {
+		count = len(d.entries) - d.pos
+	}
+	e := d.entries[d.pos : d.pos+count]
+	d.pos += count
+	return e, nil
+}
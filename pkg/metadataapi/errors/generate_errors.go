@@ -240,6 +240,57 @@ func NewAPIObjectSerializationError() Error {
 	return NewAPIObjectSerializationErrorBuilder().Build()
 }
 
+// APIRateLimitErrorCode is the code for an instance of "rate_limit_error".
+const APIRateLimitErrorCode = "rma_api_rate_limit_error"
+
+// IsAPIRateLimitError tests whether a given error is an instance of "rate_limit_error".
+func IsAPIRateLimitError(err errawr.Error) bool {
+	return err != nil && err.Is(APIRateLimitErrorCode)
+}
+
+// IsAPIRateLimitError tests whether a given error is an instance of "rate_limit_error".
+func (External) IsAPIRateLimitError(err errawr.Error) bool {
+	return IsAPIRateLimitError(err)
+}
+
+// APIRateLimitErrorBuilder is a builder for "rate_limit_error" errors.
+type APIRateLimitErrorBuilder struct {
+	arguments impl.ErrorArguments
+}
+
+// Build creates the error for the code "rate_limit_error" from this builder.
+func (b *APIRateLimitErrorBuilder) Build() Error {
+	description := &impl.ErrorDescription{
+		Friendly:  "You have made too many requests to access {{action}}. Try again later.",
+		Technical: "You have made too many requests to access {{action}}. Try again later.",
+	}
+
+	return &impl.Error{
+		ErrorArguments:   b.arguments,
+		ErrorCode:        "rate_limit_error",
+		ErrorDescription: description,
+		ErrorDomain:      Domain,
+		ErrorMetadata: &impl.ErrorMetadata{HTTPErrorMetadata: &impl.HTTPErrorMetadata{
+			ErrorHeaders: impl.HTTPErrorMetadataHeaders{},
+			ErrorStatus:  429,
+		}},
+		ErrorSection:     APISection,
+		ErrorSensitivity: errawr.ErrorSensitivityNone,
+		ErrorTitle:       "Too many requests",
+		Version:          1,
+	}
+}
+
+// NewAPIRateLimitErrorBuilder creates a new error builder for the code "rate_limit_error".
+func NewAPIRateLimitErrorBuilder(action string) *APIRateLimitErrorBuilder {
+	return &APIRateLimitErrorBuilder{arguments: impl.ErrorArguments{"action": impl.NewErrorArgument(action, "the kind of resource that is being rate limited")}}
+}
+
+// NewAPIRateLimitError creates a new error with the code "rate_limit_error".
+func NewAPIRateLimitError(action string) Error {
+	return NewAPIRateLimitErrorBuilder(action).Build()
+}
+
 // APIUnknownRequestMediaTypeErrorCode is the code for an instance of "unknown_request_media_type_error".
 const APIUnknownRequestMediaTypeErrorCode = "rma_api_unknown_request_media_type_error"
 
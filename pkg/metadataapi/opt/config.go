@@ -6,11 +6,15 @@ import (
 	"net"
 	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	vaultapi "github.com/hashicorp/vault/api"
 	"github.com/puppetlabs/relay-core/pkg/authenticate"
+	"github.com/puppetlabs/relay-core/pkg/manager/audit"
 	"github.com/puppetlabs/relay-pls/pkg/plspb"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/exporters/otlp"
 	"google.golang.org/grpc"
 	yaml "gopkg.in/yaml.v3"
 	"k8s.io/client-go/kubernetes"
@@ -20,6 +24,7 @@ import (
 
 const (
 	DefaultListenPort      = 7000
+	DefaultMetricsBindAddr = "0.0.0.0:3050"
 	DefaultVaultURL        = "http://localhost:8200"
 	DefaultStepMetadataURL = "https://relay.sh/step-metadata.json"
 
@@ -31,6 +36,11 @@ type Config struct {
 	// Debug determines whether this server starts with debugging enabled.
 	Debug bool
 
+	// EvaluationTrace determines whether clients may request a trace of the
+	// evaluation of expressions. Traces redact sensitive values, but still
+	// describe every reference a step makes, so they are disabled by default.
+	EvaluationTrace bool
+
 	// Environment is the execution environment for this instance. Used for
 	// reporting errors.
 	Environment string
@@ -78,6 +88,21 @@ type Config struct {
 	// VaultAuthRole is the role to use when logging in as a tenant.
 	VaultAuthRole string
 
+	// JWTKeySetFile is the path to a JSON Web Key Set containing the public
+	// keys the operator may use to sign tokens. The file is reloaded
+	// periodically to pick up rotated keys.
+	JWTKeySetFile string
+
+	// JWTKeySetURL is the HTTP(S) URL to a JSON Web Key Set containing the
+	// public keys the operator may use to sign tokens.
+	JWTKeySetURL string
+
+	// JWTKeySetSecret is the namespace and name of a Kubernetes secret,
+	// separated by a slash, containing the public keys the operator may use to
+	// sign tokens. The secret may contain a JSON Web Key Set, PEM-encoded
+	// public keys, or both.
+	JWTKeySetSecret string
+
 	// KubernetesURL is the the HTTP(S) URL to the Kubernetes cluster master.
 	KubernetesURL string
 
@@ -98,9 +123,88 @@ type Config struct {
 	// from sample steps.
 	SampleHS256SigningKey string
 
+	// SampleDataDirectory is an optional directory to store the secrets,
+	// connections, parameters, step outputs, and state of sample runs in.
+	// Values from the sample configuration files are written to it unless
+	// they are already present, and values set by steps persist there.
+	SampleDataDirectory string
+
 	// SentryDSN is an optional identifier to automatically log API errors to
 	// Sentry.
 	SentryDSN string
+
+	// AuditLogPath is the path to a file to append a record of every secret
+	// and connection access to. If set to "-", audit records are written to
+	// standard output. If empty, access is not audited.
+	AuditLogPath string
+
+	// SecretCacheTTL is the maximum amount of time to remember a secret or
+	// connection for the steps of a run. If zero, the default, secrets and
+	// connections are requested from the secret store every time they are
+	// used.
+	SecretCacheTTL time.Duration
+
+	// SecretCacheNegativeTTL is the amount of time to remember that a secret
+	// or connection does not exist. If zero, the default, missing secrets and
+	// connections are not remembered.
+	SecretCacheNegativeTTL time.Duration
+
+	// ConnectionTypesFile is the path to a YAML file that declares how to
+	// refresh the credentials of connection types stored in Vault, for
+	// example using an OAuth 2 refresh token or a Vault dynamic secrets
+	// engine. If empty, connections are passed to steps as stored.
+	ConnectionTypesFile string
+
+	// MetricsEnabled determines whether to start a server that exposes
+	// metrics for collection.
+	MetricsEnabled bool
+
+	// MetricsBindAddr is the address to bind the metrics server to.
+	MetricsBindAddr string
+
+	// TracingOTLPAddr is the host and port of an OpenTelemetry collector to
+	// export traces to over OTLP. If empty, traces are not exported.
+	TracingOTLPAddr string
+
+	// TracingOTLPInsecure disables transport security when connecting to the
+	// OpenTelemetry collector.
+	TracingOTLPInsecure bool
+
+	// RateLimitSecretsPerSecond is the sustained number of requests per second
+	// a single action may make to retrieve secrets. If zero, secret requests
+	// are not rate limited.
+	RateLimitSecretsPerSecond float64
+
+	// RateLimitSecretsBurst is the maximum number of secret requests a single
+	// action may make at once.
+	RateLimitSecretsBurst int
+
+	// RateLimitOutputsPerSecond is the sustained number of requests per second
+	// a single action may make to retrieve or set outputs. If zero, output
+	// requests are not rate limited.
+	RateLimitOutputsPerSecond float64
+
+	// RateLimitOutputsBurst is the maximum number of output requests a single
+	// action may make at once.
+	RateLimitOutputsBurst int
+
+	// RateLimitLogsPerSecond is the sustained number of requests per second a
+	// single action may make to create logs or append log messages. If zero,
+	// log requests are not rate limited.
+	RateLimitLogsPerSecond float64
+
+	// RateLimitLogsBurst is the maximum number of log requests a single action
+	// may make at once.
+	RateLimitLogsBurst int
+
+	// RateLimitDefaultPerSecond is the sustained number of requests per second
+	// a single action may make to any other endpoint, like the spec or
+	// conditions. If zero, these requests are not rate limited.
+	RateLimitDefaultPerSecond float64
+
+	// RateLimitDefaultBurst is the maximum number of requests to any other
+	// endpoint a single action may make at once.
+	RateLimitDefaultBurst int
 }
 
 func (c *Config) kubernetesInClusterHost() (string, bool) {
@@ -179,6 +283,29 @@ func (c *Config) KubernetesClient() (*authenticate.KubernetesInterface, error) {
 	return authenticate.NewKubernetesInterfaceForConfig(cfg)
 }
 
+func (c *Config) JWTKeySets(client kubernetes.Interface) ([]authenticate.KeySet, error) {
+	var kss []authenticate.KeySet
+
+	if c.JWTKeySetFile != "" {
+		kss = append(kss, authenticate.NewJWKSFileKeySet(c.JWTKeySetFile))
+	}
+
+	if c.JWTKeySetURL != "" {
+		kss = append(kss, authenticate.NewJWKSURLKeySet(nil, c.JWTKeySetURL))
+	}
+
+	if c.JWTKeySetSecret != "" {
+		parts := strings.SplitN(c.JWTKeySetSecret, "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("opt: JWT key set secret %q must be in the form namespace/name", c.JWTKeySetSecret)
+		}
+
+		kss = append(kss, authenticate.NewKubernetesSecretKeySet(client, parts[0], parts[1]))
+	}
+
+	return kss, nil
+}
+
 func (c *Config) LogServiceClient() (plspb.LogClient, error) {
 	if c.LogServiceURL == "" {
 		return nil, nil
@@ -207,6 +334,59 @@ func (c *Config) VaultTransitClient() (*vaultapi.Client, error) {
 	return client, nil
 }
 
+func (c *Config) AuditSink() (audit.Sink, error) {
+	switch c.AuditLogPath {
+	case "":
+		return nil, nil
+	case "-":
+		return audit.NewStdoutSink(), nil
+	default:
+		sink, err := audit.NewFileSink(c.AuditLogPath)
+		if err != nil {
+			return nil, err
+		}
+
+		return sink, nil
+	}
+}
+
+func (c *Config) ConnectionTypes() (ConnectionTypesConfig, error) {
+	if c.ConnectionTypesFile == "" {
+		return nil, nil
+	}
+
+	b, err := ioutil.ReadFile(c.ConnectionTypesFile)
+	if err != nil {
+		return nil, fmt.Errorf("opt: cannot read connection types file %q: %+v", c.ConnectionTypesFile, err)
+	}
+
+	var ctc ConnectionTypesConfig
+	if err := yaml.Unmarshal(b, &ctc); err != nil {
+		return nil, fmt.Errorf("opt: cannot parse connection types file %q: %+v", c.ConnectionTypesFile, err)
+	}
+
+	if err := ctc.Validate(); err != nil {
+		return nil, err
+	}
+
+	return ctc, nil
+}
+
+func (c *Config) TracingExporter() (*otlp.Exporter, error) {
+	if c.TracingOTLPAddr == "" {
+		return nil, nil
+	}
+
+	opts := []otlp.ExporterOption{
+		otlp.WithAddress(c.TracingOTLPAddr),
+	}
+	if c.TracingOTLPInsecure {
+		opts = append(opts, otlp.WithInsecure())
+	}
+
+	return otlp.NewExporter(opts...)
+}
+
 func (c *Config) SampleConfig() (*SampleConfig, error) {
 	if len(c.SampleConfigFiles) == 0 {
 		return nil, nil
@@ -258,11 +438,15 @@ func NewConfig() *Config {
 
 	viper.SetDefault("step_metadata_url", DefaultStepMetadataURL)
 
+	viper.SetDefault("metrics_bind_addr", DefaultMetricsBindAddr)
+
 	return &Config{
 		Debug:       viper.GetBool("debug"),
 		Environment: viper.GetString("environment"),
 		ListenPort:  viper.GetInt("listen_port"),
 
+		EvaluationTrace: viper.GetBool("evaluation_trace"),
+
 		TLSKeyFile:         viper.GetString("tls_key_file"),
 		TLSCertificateFile: viper.GetString("tls_certificate_file"),
 
@@ -279,13 +463,40 @@ func NewConfig() *Config {
 		VaultAuthPath: viper.GetString("vault_auth_path"),
 		VaultAuthRole: viper.GetString("vault_auth_role"),
 
+		JWTKeySetFile:   viper.GetString("jwt_key_set_file"),
+		JWTKeySetURL:    viper.GetString("jwt_key_set_url"),
+		JWTKeySetSecret: viper.GetString("jwt_key_set_secret"),
+
 		KubernetesURL:                 viper.GetString("kubernetes_url"),
 		KubernetesCAData:              viper.GetString("kubernetes_ca_data"),
 		KubernetesServiceAccountToken: viper.GetString("kubernetes_service_account_token"),
 
 		SampleConfigFiles:     viper.GetStringSlice("sample_config_files"),
 		SampleHS256SigningKey: viper.GetString("sample_hs256_signing_key"),
+		SampleDataDirectory:   viper.GetString("sample_data_directory"),
 
 		SentryDSN: viper.GetString("sentry_dsn"),
+
+		AuditLogPath: viper.GetString("audit_log_path"),
+
+		SecretCacheTTL:         viper.GetDuration("secret_cache_ttl"),
+		SecretCacheNegativeTTL: viper.GetDuration("secret_cache_negative_ttl"),
+
+		ConnectionTypesFile: viper.GetString("connection_types_file"),
+
+		MetricsEnabled:  viper.GetBool("metrics_enabled"),
+		MetricsBindAddr: viper.GetString("metrics_bind_addr"),
+
+		TracingOTLPAddr:     viper.GetString("tracing_otlp_addr"),
+		TracingOTLPInsecure: viper.GetBool("tracing_otlp_insecure"),
+
+		RateLimitSecretsPerSecond: viper.GetFloat64("rate_limit_secrets_per_second"),
+		RateLimitSecretsBurst:     viper.GetInt("rate_limit_secrets_burst"),
+		RateLimitOutputsPerSecond: viper.GetFloat64("rate_limit_outputs_per_second"),
+		RateLimitOutputsBurst:     viper.GetInt("rate_limit_outputs_burst"),
+		RateLimitLogsPerSecond:    viper.GetFloat64("rate_limit_logs_per_second"),
+		RateLimitLogsBurst:        viper.GetInt("rate_limit_logs_burst"),
+		RateLimitDefaultPerSecond: viper.GetFloat64("rate_limit_default_per_second"),
+		RateLimitDefaultBurst:     viper.GetInt("rate_limit_default_burst"),
 	}
 }
@@ -0,0 +1,146 @@
+package opt
+
+import (
+	"fmt"
+	"strings"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	"github.com/puppetlabs/relay-core/pkg/manager/refresh"
+	"github.com/puppetlabs/relay-core/pkg/manager/vault"
+	"golang.org/x/oauth2"
+)
+
+// ConnectionTypeRefreshOAuth2Config refreshes connections using the OAuth 2
+// refresh token grant.
+type ConnectionTypeRefreshOAuth2Config struct {
+	// TokenURL is the URL of the token endpoint of the authorization server.
+	TokenURL string `yaml:"tokenURL"`
+
+	// ClientID and ClientSecret are the client credentials to present to the
+	// token endpoint. If not set, the credentials stored with each connection
+	// are used.
+	ClientID     string `yaml:"clientID"`
+	ClientSecret string `yaml:"clientSecret"`
+
+	// Scopes are the scopes to request for new access tokens.
+	Scopes []string `yaml:"scopes"`
+
+	// AuthStyle is how client credentials are presented to the token
+	// endpoint, either "header" or "params". If not set, the style is
+	// detected automatically.
+	AuthStyle string `yaml:"authStyle"`
+}
+
+func (c *ConnectionTypeRefreshOAuth2Config) refresher() (*refresh.OAuth2Refresher, error) {
+	if c.TokenURL == "" {
+		return nil, fmt.Errorf("a token URL is required")
+	}
+
+	opts := []refresh.OAuth2RefresherOption{
+		refresh.OAuth2RefresherWithScopes(c.Scopes),
+	}
+
+	if c.ClientID != "" {
+		opts = append(opts, refresh.OAuth2RefresherWithClientCredentials(c.ClientID, c.ClientSecret))
+	}
+
+	switch c.AuthStyle {
+	case "":
+	case "header":
+		opts = append(opts, refresh.OAuth2RefresherWithAuthStyle(oauth2.AuthStyleInHeader))
+	case "params":
+		opts = append(opts, refresh.OAuth2RefresherWithAuthStyle(oauth2.AuthStyleInParams))
+	default:
+		return nil, fmt.Errorf("unknown authentication style %q", c.AuthStyle)
+	}
+
+	return refresh.NewOAuth2Refresher(c.TokenURL, opts...), nil
+}
+
+// ConnectionTypeRefreshVaultConfig refreshes connections by reading from a
+// Vault dynamic secrets engine.
+type ConnectionTypeRefreshVaultConfig struct {
+	// MountPath is the path the dynamic secrets engine is mounted at. The path
+	// of every connection of this type must be under it. Required.
+	MountPath string `yaml:"mountPath"`
+
+	// PathAttribute is the connection attribute holding the path to the
+	// dynamic secret. Defaults to "path".
+	PathAttribute string `yaml:"pathAttribute"`
+}
+
+// ConnectionTypeRefreshConfig declares how to refresh the credentials of a
+// connection type. Exactly one strategy must be set.
+type ConnectionTypeRefreshConfig struct {
+	OAuth2 *ConnectionTypeRefreshOAuth2Config `yaml:"oauth2"`
+	Vault  *ConnectionTypeRefreshVaultConfig  `yaml:"vault"`
+}
+
+type ConnectionTypeConfig struct {
+	Refresh *ConnectionTypeRefreshConfig `yaml:"refresh"`
+}
+
+// ConnectionTypesConfig is the registry of connection types, keyed by the name
+// of the type.
+type ConnectionTypesConfig map[string]*ConnectionTypeConfig
+
+// Validate checks that each connection type declares a usable refresh
+// strategy.
+func (ctc ConnectionTypesConfig) Validate() error {
+	for typ, ct := range ctc {
+		if ct == nil || ct.Refresh == nil {
+			continue
+		}
+
+		switch {
+		case ct.Refresh.OAuth2 != nil && ct.Refresh.Vault != nil:
+			return fmt.Errorf("opt: connection type %q: only one refresh strategy may be set", typ)
+		case ct.Refresh.OAuth2 != nil:
+			if _, err := ct.Refresh.OAuth2.refresher(); err != nil {
+				return fmt.Errorf("opt: connection type %q: OAuth 2 refresh: %+v", typ, err)
+			}
+		case ct.Refresh.Vault != nil:
+			if strings.Trim(ct.Refresh.Vault.MountPath, "/") == "" {
+				return fmt.Errorf("opt: connection type %q: Vault refresh: a mount path is required", typ)
+			}
+		default:
+			return fmt.Errorf("opt: connection type %q: a refresh strategy is required", typ)
+		}
+	}
+
+	return nil
+}
+
+// Refresher creates a refresher for the connections of a tenant given a Vault
+// client authenticated as the tenant and the Vault namespace of the tenant.
+// The configuration must be valid.
+func (ctc ConnectionTypesConfig) Refresher(client *vaultapi.Client, namespace string) refresh.Refresher {
+	var opts []refresh.RegistryOption
+
+	for typ, ct := range ctc {
+		if ct == nil || ct.Refresh == nil {
+			continue
+		}
+
+		switch {
+		case ct.Refresh.OAuth2 != nil:
+			r, err := ct.Refresh.OAuth2.refresher()
+			if err != nil {
+				continue
+			}
+
+			opts = append(opts, refresh.RegistryWithRefresher(typ, r))
+		case ct.Refresh.Vault != nil:
+			vopts := []vault.DynamicSecretRefresherOption{
+				vault.DynamicSecretRefresherWithNamespace(namespace),
+			}
+			if ct.Refresh.Vault.PathAttribute != "" {
+				vopts = append(vopts, vault.DynamicSecretRefresherWithPathAttribute(ct.Refresh.Vault.PathAttribute))
+			}
+
+			opts = append(opts, refresh.RegistryWithRefresher(typ, vault.NewDynamicSecretRefresher(client, ct.Refresh.Vault.MountPath, vopts...)))
+		}
+	}
+
+	return refresh.NewRegistry(opts...)
+}
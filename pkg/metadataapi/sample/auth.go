@@ -6,6 +6,7 @@ import (
 
 	"github.com/puppetlabs/relay-core/pkg/authenticate"
 	"github.com/puppetlabs/relay-core/pkg/manager/builder"
+	"github.com/puppetlabs/relay-core/pkg/manager/filesystem"
 	mlog "github.com/puppetlabs/relay-core/pkg/manager/log"
 	"github.com/puppetlabs/relay-core/pkg/manager/memory"
 	"github.com/puppetlabs/relay-core/pkg/manager/service"
@@ -16,9 +17,10 @@ import (
 )
 
 type Authenticator struct {
-	sc   *opt.SampleConfig
-	key  interface{}
-	mgrs map[model.Hash]func(mgrs *builder.MetadataBuilder)
+	sc      *opt.SampleConfig
+	key     interface{}
+	dataDir string
+	mgrs    map[model.Hash]func(mgrs *builder.MetadataBuilder)
 }
 
 var _ middleware.Authenticator = &Authenticator{}
@@ -27,6 +29,8 @@ func (a *Authenticator) Authenticate(r *http.Request) (*middleware.Credential, e
 	mgrs := builder.NewMetadataBuilder()
 	mgrs.SetEvents(mlog.EventManager)
 
+	var target *authenticate.Claims
+
 	auth := authenticate.NewAuthenticator(
 		authenticate.NewHTTPAuthorizationHeaderIntermediary(r),
 		authenticate.NewKeyResolver(
@@ -36,8 +40,15 @@ func (a *Authenticator) Authenticate(r *http.Request) (*middleware.Credential, e
 			}),
 		),
 		authenticate.AuthenticatorWithInjector(authenticate.InjectorFunc(func(ctx context.Context, claims *authenticate.Claims) error {
-			mgrs.SetConnections(memory.NewConnectionManager(a.sc.Connections))
-			mgrs.SetSecrets(memory.NewSecretManager(a.sc.Secrets))
+			target = claims
+
+			if a.dataDir != "" {
+				mgrs.SetConnections(filesystem.NewConnectionManager(a.dataDir))
+				mgrs.SetSecrets(filesystem.NewSecretManager(a.dataDir))
+			} else {
+				mgrs.SetConnections(memory.NewConnectionManager(a.sc.Connections))
+				mgrs.SetSecrets(memory.NewSecretManager(a.sc.Secrets))
+			}
 
 			// TODO: Add support for triggers!
 
@@ -62,23 +73,45 @@ func (a *Authenticator) Authenticate(r *http.Request) (*middleware.Credential, e
 	}
 
 	return &middleware.Credential{
+		Claims:   target,
 		Managers: mgrs.Build(),
 	}, nil
 }
 
-func NewAuthenticator(sc *opt.SampleConfig, key interface{}) *Authenticator {
+type AuthenticatorOption func(a *Authenticator)
+
+// AuthenticatorWithDataDirectory stores secrets, connections, parameters,
+// step outputs, and state in the given directory instead of in memory. The
+// directory should be seeded with the sample configuration using
+// SeedDataDirectory.
+func AuthenticatorWithDataDirectory(dir string) AuthenticatorOption {
+	return func(a *Authenticator) {
+		a.dataDir = dir
+	}
+}
+
+func NewAuthenticator(sc *opt.SampleConfig, key interface{}, opts ...AuthenticatorOption) *Authenticator {
 	a := &Authenticator{
 		sc:   sc,
 		key:  key,
 		mgrs: make(map[model.Hash]func(mgrs *builder.MetadataBuilder)),
 	}
 
+	for _, opt := range opts {
+		opt(a)
+	}
+
 	// Pre-build managers so that changes persist across HTTP requests.
 	for id, sc := range sc.Runs {
 		run := model.Run{ID: id}
 		som := memory.NewStepOutputMap()
 
-		parameterManager := memory.NewParameterManager(memory.ParameterManagerWithInitialParameters(sc.Parameters))
+		var parameterManager model.ParameterManager
+		if a.dataDir != "" {
+			parameterManager = filesystem.NewParameterManager(run, a.dataDir)
+		} else {
+			parameterManager = memory.NewParameterManager(memory.ParameterManagerWithInitialParameters(sc.Parameters))
+		}
 
 		for name, sc := range sc.Steps {
 			step := &model.Step{
@@ -110,23 +143,33 @@ func NewAuthenticator(sc *opt.SampleConfig, key interface{}) *Authenticator {
 
 			specManager := memory.NewSpecManager(specOpts...)
 
-			var stateOpts []memory.StateManagerOption
-			if sc.State != nil {
-				stateOpts = append(stateOpts, memory.StateManagerWithInitialState(sc.State))
-			}
+			var stateManager model.StateManager
+			if a.dataDir != "" {
+				stateManager = filesystem.NewStateManager(step, a.dataDir)
+			} else {
+				var stateOpts []memory.StateManagerOption
+				if sc.State != nil {
+					stateOpts = append(stateOpts, memory.StateManagerWithInitialState(sc.State))
+				}
 
-			stateManager := memory.NewStateManager(stateOpts...)
+				stateManager = memory.NewStateManager(stateOpts...)
+			}
 
 			am := &model.ActionMetadata{
 				Image: sc.Image,
 			}
 			actionMetadataManager := memory.NewActionMetadataManager(am)
 
-			for name, value := range sc.Outputs {
-				som.Set(step, name, value)
-			}
+			var stepOutputManager model.StepOutputManager
+			if a.dataDir != "" {
+				stepOutputManager = filesystem.NewStepOutputManager(step, a.dataDir)
+			} else {
+				for name, value := range sc.Outputs {
+					som.Set(step, name, value)
+				}
 
-			stepOutputManager := memory.NewStepOutputManager(step, som)
+				stepOutputManager = memory.NewStepOutputManager(step, som)
+			}
 
 			a.mgrs[step.Hash()] = func(mgrs *builder.MetadataBuilder) {
 				mgrs.SetConditions(conditionManager)
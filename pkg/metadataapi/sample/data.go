@@ -0,0 +1,104 @@
+package sample
+
+import (
+	"context"
+
+	"github.com/puppetlabs/relay-core/pkg/manager/filesystem"
+	"github.com/puppetlabs/relay-core/pkg/metadataapi/opt"
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+// SeedDataDirectory writes the values from the sample configuration to the
+// given data directory. Values that are already present in the directory, for
+// example because a step set them in a previous invocation, are left as-is so
+// that steps can be re-run against the data they produced.
+func SeedDataDirectory(ctx context.Context, sc *opt.SampleConfig, dir string) error {
+	sm := filesystem.NewSecretManager(dir)
+	for name, value := range sc.Secrets {
+		if err := seed(func() error {
+			_, err := sm.Get(ctx, name)
+			return err
+		}, func() error {
+			_, err := sm.Set(ctx, name, value)
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+
+	cm := filesystem.NewConnectionManager(dir)
+	for key, attrs := range sc.Connections {
+		if err := seed(func() error {
+			_, err := cm.Get(ctx, key.Type, key.Name)
+			return err
+		}, func() error {
+			_, err := cm.Set(ctx, key.Type, key.Name, attrs)
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+
+	for id, rc := range sc.Runs {
+		run := model.Run{ID: id}
+
+		pm := filesystem.NewParameterManager(run, dir)
+		for name, value := range rc.Parameters {
+			if err := seed(func() error {
+				_, err := pm.Get(ctx, name)
+				return err
+			}, func() error {
+				_, err := pm.Set(ctx, name, value)
+				return err
+			}); err != nil {
+				return err
+			}
+		}
+
+		for name, sc := range rc.Steps {
+			step := &model.Step{
+				Run:  run,
+				Name: name,
+			}
+
+			som := filesystem.NewStepOutputManager(step, dir)
+			for name, value := range sc.Outputs {
+				if err := seed(func() error {
+					_, err := som.Get(ctx, step.Name, name)
+					return err
+				}, func() error {
+					_, err := som.Set(ctx, name, value)
+					return err
+				}); err != nil {
+					return err
+				}
+			}
+
+			stm := filesystem.NewStateManager(step, dir)
+			for name, value := range sc.State {
+				if err := seed(func() error {
+					_, err := stm.Get(ctx, name)
+					return err
+				}, func() error {
+					_, err := stm.Set(ctx, name, value)
+					return err
+				}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func seed(get, set func() error) error {
+	switch err := get(); err {
+	case nil:
+		return nil
+	case model.ErrNotFound:
+		return set()
+	default:
+		return err
+	}
+}
@@ -0,0 +1,48 @@
+package sample
+
+import (
+	"fmt"
+
+	"github.com/puppetlabs/relay-core/pkg/expr/evaluate"
+	"github.com/puppetlabs/relay-core/pkg/manager/memory"
+	"github.com/puppetlabs/relay-core/pkg/manager/resolve"
+	"github.com/puppetlabs/relay-core/pkg/metadataapi/opt"
+	"github.com/puppetlabs/relay-core/pkg/model"
+)
+
+// EvaluatorOptions configures an evaluator to resolve references from the
+// sample configuration the same way the metadata API would for the given step.
+func EvaluatorOptions(sc *opt.SampleConfig, runID, stepName string) ([]evaluate.Option, error) {
+	rc, found := sc.Runs[runID]
+	if !found {
+		return nil, fmt.Errorf("sample: run %q is not configured", runID)
+	}
+
+	stc, found := rc.Steps[stepName]
+	if !found {
+		return nil, fmt.Errorf("sample: step %q of run %q is not configured", stepName, runID)
+	}
+
+	run := model.Run{ID: runID}
+
+	som := memory.NewStepOutputMap()
+	for name, sc := range rc.Steps {
+		step := &model.Step{Run: run, Name: name}
+		for key, value := range sc.Outputs {
+			som.Set(step, key, value)
+		}
+	}
+
+	var stateOpts []memory.StateManagerOption
+	if stc.State != nil {
+		stateOpts = append(stateOpts, memory.StateManagerWithInitialState(stc.State))
+	}
+
+	return []evaluate.Option{
+		evaluate.WithConnectionTypeResolver(resolve.NewConnectionTypeResolver(memory.NewConnectionManager(sc.Connections))),
+		evaluate.WithParameterTypeResolver(resolve.NewParameterTypeResolver(memory.NewParameterManager(memory.ParameterManagerWithInitialParameters(rc.Parameters)))),
+		evaluate.WithOutputTypeResolver(resolve.NewOutputTypeResolver(memory.NewStepOutputManager(&model.Step{Run: run, Name: stepName}, som))),
+		evaluate.WithSecretTypeResolver(resolve.NewSecretTypeResolver(memory.NewSecretManager(sc.Secrets))),
+		evaluate.WithAnswerTypeResolver(resolve.NewAnswerTypeResolver(memory.NewStateManager(stateOpts...))),
+	}, nil
+}
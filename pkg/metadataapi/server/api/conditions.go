@@ -13,8 +13,9 @@ import (
 )
 
 type GetConditionsResponseEnvelope struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
+	Success bool                           `json:"success"`
+	Message string                         `json:"message"`
+	Trace   []*model.JSONTraceNodeEnvelope `json:"trace,omitempty"`
 }
 
 func (s *Server) GetConditions(w http.ResponseWriter, r *http.Request) {
@@ -29,16 +30,23 @@ func (s *Server) GetConditions(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	trace, err := s.evaluationTrace(r)
+	if err != nil {
+		utilapi.WriteError(ctx, w, errors.NewAPIMalformedRequestError().WithCause(err))
+		return
+	}
+
 	ev := evaluate.NewEvaluator(
 		evaluate.WithParameterTypeResolver(resolve.NewParameterTypeResolver(managers.Parameters())),
 		evaluate.WithSecretTypeResolver(resolve.NewSecretTypeResolver(managers.Secrets())),
 		evaluate.WithOutputTypeResolver(resolve.NewOutputTypeResolver(managers.StepOutputs())),
 		evaluate.WithAnswerTypeResolver(resolve.NewAnswerTypeResolver(managers.State())),
+		evaluate.WithTrace(trace),
 	)
 
-	rv, rerr := ev.EvaluateAll(ctx, cond.Tree)
+	rv, rerr := ev.EvaluateAll(s.evaluationContext(ctx), cond.Tree)
 	if rerr != nil {
-		utilapi.WriteError(ctx, w, errors.NewExpressionEvaluationError(rerr.Error()).Bug())
+		writeErrorWithTrace(ctx, w, errors.NewExpressionEvaluationError(rerr.Error()).Bug(), trace)
 		return
 	}
 
@@ -53,7 +61,7 @@ check:
 			result, ok := cond.(bool)
 			if !ok {
 				if rv.Complete() {
-					utilapi.WriteError(ctx, w, errors.NewConditionTypeError(fmt.Sprintf("%T", cond)))
+					writeErrorWithTrace(ctx, w, errors.NewConditionTypeError(fmt.Sprintf("%T", cond)), trace)
 					return
 				}
 				continue
@@ -65,11 +73,18 @@ check:
 			}
 		}
 	default:
-		utilapi.WriteError(ctx, w, errors.NewConditionTypeError(fmt.Sprintf("%T", vt)))
-		return
+		// An incomplete condition, like an invocation that could not be
+		// evaluated yet, is reported as unresolvable below.
+		if rv.Complete() {
+			writeErrorWithTrace(ctx, w, errors.NewConditionTypeError(fmt.Sprintf("%T", vt)), trace)
+			return
+		}
 	}
 
 	var resp GetConditionsResponseEnvelope
+	if trace != nil {
+		resp.Trace = model.NewJSONTraceEnvelope(trace.Nodes())
+	}
 
 	if failed {
 		resp.Success = false
@@ -92,7 +107,7 @@ check:
 			causes[i] = cause.Error()
 		}
 
-		utilapi.WriteError(ctx, w, errors.NewExpressionUnresolvableError(causes))
+		writeErrorWithTrace(ctx, w, errors.NewExpressionUnresolvableError(causes), trace)
 		return
 	}
 
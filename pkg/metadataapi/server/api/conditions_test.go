@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/puppetlabs/errawr-go/v2/pkg/errawr"
+	"github.com/puppetlabs/relay-core/pkg/expr/model"
 	"github.com/puppetlabs/relay-core/pkg/expr/parse"
 	"github.com/puppetlabs/relay-core/pkg/expr/serialize"
 	exprtestutil "github.com/puppetlabs/relay-core/pkg/expr/testutil"
@@ -110,6 +111,50 @@ func TestGetConditions(t *testing.T) {
 			},
 			ExpectedSuccess: false,
 		},
+		{
+			Name: "Logical or with unresolvable dead branch",
+			Conditions: exprtestutil.JSONInvocation("or", []interface{}{
+				exprtestutil.JSONInvocation("equals", []interface{}{
+					exprtestutil.JSONOutput("previous-task", "output1"),
+					"foobar",
+				}),
+				exprtestutil.JSONInvocation("equals", []interface{}{
+					exprtestutil.JSONParameter("override"),
+					true,
+				}),
+			}),
+			ExpectedSuccess: true,
+		},
+		{
+			Name: "Logical or with unresolvable live branch",
+			Conditions: exprtestutil.JSONInvocation("or", []interface{}{
+				exprtestutil.JSONInvocation("equals", []interface{}{
+					exprtestutil.JSONOutput("previous-task", "output1"),
+					"fubar",
+				}),
+				exprtestutil.JSONInvocation("equals", []interface{}{
+					exprtestutil.JSONParameter("override"),
+					true,
+				}),
+			}),
+			ExpectedError: errors.NewExpressionUnresolvableError([]string{
+				`model: parameter "override" could not be found`,
+			}),
+		},
+		{
+			Name: "Conditional with unresolvable dead branch",
+			Conditions: []interface{}{
+				exprtestutil.JSONInvocation("if", []interface{}{
+					exprtestutil.JSONInvocation("equals", []interface{}{
+						exprtestutil.JSONOutput("previous-task", "output1"),
+						"foobar",
+					}),
+					true,
+					exprtestutil.JSONSecret("missing"),
+				}),
+			},
+			ExpectedSuccess: true,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.Name, func(t *testing.T) {
@@ -166,3 +211,71 @@ func TestGetConditions(t *testing.T) {
 		})
 	}
 }
+
+func TestGetConditionsTrace(t *testing.T) {
+	ctx := context.Background()
+
+	tokenGenerator, err := sample.NewHS256TokenGenerator(nil)
+	require.NoError(t, err)
+
+	sc := &opt.SampleConfig{
+		Runs: map[string]*opt.SampleConfigRun{
+			"test": &opt.SampleConfigRun{
+				Steps: map[string]*opt.SampleConfigStep{
+					"current-task": &opt.SampleConfigStep{
+						Conditions: serialize.YAMLTree{
+							Tree: []interface{}{
+								exprtestutil.JSONInvocation("equals", []interface{}{
+									exprtestutil.JSONParameter("param1"),
+									"foobar",
+								}),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tokenMap := tokenGenerator.GenerateAll(ctx, sc)
+
+	currentTaskToken, found := tokenMap.ForStep("test", "current-task")
+	require.True(t, found)
+
+	req, err := http.NewRequest(http.MethodGet, "/conditions?debug=trace", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+currentTaskToken)
+
+	// Tracing must be enabled by the server.
+	h := api.NewHandler(sample.NewAuthenticator(sc, tokenGenerator.Key()))
+
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusUnprocessableEntity, resp.Result().StatusCode)
+
+	h = api.NewHandler(sample.NewAuthenticator(sc, tokenGenerator.Key()), api.WithEvaluationTrace(true))
+
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+
+	// Unresolvable conditions are the same error they would be without a
+	// trace, but the response also includes the trace.
+	expected := errors.NewExpressionUnresolvableError([]string{`model: parameter "param1" could not be found`})
+	require.Equal(t, http.StatusUnprocessableEntity, resp.Result().StatusCode)
+
+	var env api.TracedErrorEnvelope
+	require.NoError(t, json.NewDecoder(resp.Result().Body).Decode(&env))
+	require.NotNil(t, env.ErrorEnvelope)
+	require.Equal(t, expected.Error(), env.Error.AsError().Error())
+
+	require.Len(t, env.Trace, 1)
+	require.Equal(t, "0", env.Trace[0].Path)
+	require.Equal(t, model.TraceNodeKindInvocation, env.Trace[0].Kind)
+	require.Equal(t, "equals", env.Trace[0].Name)
+	require.False(t, env.Trace[0].Complete)
+
+	require.Len(t, env.Trace[0].Children, 1)
+	require.Equal(t, "0.$fn.equals.0", env.Trace[0].Children[0].Path)
+	require.Equal(t, "Parameter", env.Trace[0].Children[0].Name)
+	require.Equal(t, []*model.JSONUnresolvableParameterEnvelope{{Name: "param1"}}, env.Trace[0].Children[0].Unresolvable.Parameters)
+}
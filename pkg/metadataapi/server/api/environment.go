@@ -36,7 +36,7 @@ func (s *Server) GetEnvironmentVariable(w http.ResponseWriter, r *http.Request)
 		evaluate.WithSecretTypeResolver(resolve.NewSecretTypeResolver(managers.Secrets())),
 	).ScopeTo(value)
 
-	rv, rerr := eval.EvaluateAll(ctx)
+	rv, rerr := eval.EvaluateAll(s.evaluationContext(ctx))
 	if rerr != nil {
 		utilapi.WriteError(ctx, w, errors.NewExpressionEvaluationError(rerr.Error()))
 		return
@@ -56,6 +56,8 @@ func (s *Server) GetEnvironment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ectx := s.evaluationContext(ctx)
+
 	complete := true
 	evs := make(map[string]interface{})
 	for name, value := range environment.Value {
@@ -65,7 +67,7 @@ func (s *Server) GetEnvironment(w http.ResponseWriter, r *http.Request) {
 			evaluate.WithSecretTypeResolver(resolve.NewSecretTypeResolver(managers.Secrets())),
 		).ScopeTo(value)
 
-		rv, rerr := eval.EvaluateAll(ctx)
+		rv, rerr := eval.EvaluateAll(ectx)
 		if rerr != nil {
 			utilapi.WriteError(ctx, w, errors.NewExpressionEvaluationError(rerr.Error()))
 			return
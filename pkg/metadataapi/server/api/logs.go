@@ -3,11 +3,13 @@ package api
 import (
 	"bytes"
 	"context"
+	"mime"
 	"net/http"
 
 	utilapi "github.com/puppetlabs/horsehead/v2/httputil/api"
 	"github.com/puppetlabs/relay-core/pkg/metadataapi/errors"
 	"github.com/puppetlabs/relay-core/pkg/metadataapi/server/middleware"
+	"github.com/puppetlabs/relay-core/pkg/model"
 )
 
 func (s *Server) PostLogMessage(w http.ResponseWriter, r *http.Request) {
@@ -17,21 +19,26 @@ func (s *Server) PostLogMessage(w http.ResponseWriter, r *http.Request) {
 
 	id, _ := middleware.Var(r, "logId")
 
-	switch r.Header.Get("content-type") {
-	case "application/octet-stream":
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("content-type"))
+
+	switch mediaType {
+	case model.LogMediaTypeProtobuf, model.LogMediaTypeJSON, model.LogMediaTypeNDJSON:
 		buf := &bytes.Buffer{}
 		if _, err := buf.ReadFrom(r.Body); err != nil {
 			utilapi.WriteError(ctx, w, errors.NewAPIMalformedRequestError().WithCause(err))
 			return
 		}
 
-		response, err := managers.Logs().PostLogMessage(ctx, id, buf.String())
+		response, err := managers.Logs().PostLogMessage(ctx, id, &model.LogRequest{
+			MediaType: mediaType,
+			Data:      buf.Bytes(),
+		})
 		if err != nil {
 			utilapi.WriteError(ctx, w, ModelWriteError(err))
 			return
 		}
 
-		WriteObjectWithStatus(ctx, w, http.StatusAccepted, response)
+		WriteObjectWithStatus(ctx, w, mediaType, http.StatusAccepted, response)
 		return
 	default:
 		utilapi.WriteError(ctx, w, errors.NewAPIUnknownRequestMediaTypeError(r.Header.Get("content-type")))
@@ -44,21 +51,26 @@ func (s *Server) PostLog(w http.ResponseWriter, r *http.Request) {
 
 	managers := middleware.Managers(r)
 
-	switch r.Header.Get("content-type") {
-	case "application/octet-stream":
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("content-type"))
+
+	switch mediaType {
+	case model.LogMediaTypeProtobuf, model.LogMediaTypeJSON:
 		buf := &bytes.Buffer{}
 		if _, err := buf.ReadFrom(r.Body); err != nil {
 			utilapi.WriteError(ctx, w, errors.NewAPIMalformedRequestError().WithCause(err))
 			return
 		}
 
-		response, err := managers.Logs().PostLog(ctx, buf.String())
+		response, err := managers.Logs().PostLog(ctx, &model.LogRequest{
+			MediaType: mediaType,
+			Data:      buf.Bytes(),
+		})
 		if err != nil {
 			utilapi.WriteError(ctx, w, ModelWriteError(err))
 			return
 		}
 
-		WriteObjectWithStatus(ctx, w, http.StatusCreated, response)
+		WriteObjectWithStatus(ctx, w, mediaType, http.StatusCreated, response)
 		return
 	default:
 		utilapi.WriteError(ctx, w, errors.NewAPIUnknownRequestMediaTypeError(r.Header.Get("content-type")))
@@ -66,8 +78,8 @@ func (s *Server) PostLog(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func WriteObjectWithStatus(ctx context.Context, w http.ResponseWriter, status int, b []byte) {
-	w.Header().Set("content-type", "application/octet-stream")
+func WriteObjectWithStatus(ctx context.Context, w http.ResponseWriter, mediaType string, status int, b []byte) {
+	w.Header().Set("content-type", mediaType)
 
 	w.WriteHeader(status)
 
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -17,6 +18,7 @@ import (
 	"github.com/puppetlabs/relay-core/pkg/metadataapi/server/api"
 	"github.com/puppetlabs/relay-pls/pkg/plspb"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -119,3 +121,76 @@ func TestPostLog(t *testing.T) {
 		require.NoError(t, err)
 	}
 }
+
+func TestPostLogJSON(t *testing.T) {
+	ctx := context.Background()
+
+	tokenGenerator, err := sample.NewHS256TokenGenerator(nil)
+	require.NoError(t, err)
+
+	sc := &opt.SampleConfig{
+		Runs: map[string]*opt.SampleConfigRun{
+			"test": &opt.SampleConfigRun{
+				Steps: map[string]*opt.SampleConfigStep{
+					"current-task": &opt.SampleConfigStep{},
+				},
+			},
+		},
+	}
+
+	tokenMap := tokenGenerator.GenerateAll(ctx, sc)
+
+	currentTaskToken, found := tokenMap.ForStep("test", "current-task")
+	require.True(t, found)
+
+	h := api.NewHandler(sample.NewAuthenticator(sc, tokenGenerator.Key()))
+
+	req, err := http.NewRequest(http.MethodPost, "/logs", strings.NewReader(`{"context": "current-task", "name": "stdout"}`))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+currentTaskToken)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+	require.Equal(t, "application/json", resp.Result().Header.Get("content-type"))
+	require.NoError(t, protojson.Unmarshal(resp.Body.Bytes(), &plspb.LogCreateResponse{}))
+
+	id := uuid.New().String()
+	u := &url.URL{Path: fmt.Sprintf("/logs/%s/messages", id)}
+
+	req, err = http.NewRequest(http.MethodPost, u.String(), strings.NewReader(`{"payload": "aGVsbG8=", "timestamp": "2020-12-01T00:00:00Z"}`))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+currentTaskToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusAccepted, resp.Result().StatusCode)
+	require.Equal(t, "application/json", resp.Result().Header.Get("content-type"))
+	require.NoError(t, protojson.Unmarshal(resp.Body.Bytes(), &plspb.LogMessageAppendResponse{}))
+
+	batch := strings.Join([]string{
+		`{"payload": "b25l"}`,
+		`{"payload": "dHdv"}`,
+		``,
+		`{"payload": "dGhyZWU="}`,
+	}, "\n")
+
+	req, err = http.NewRequest(http.MethodPost, u.String(), strings.NewReader(batch))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+currentTaskToken)
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusAccepted, resp.Result().StatusCode)
+	require.Equal(t, "application/x-ndjson", resp.Result().Header.Get("content-type"))
+
+	lines := strings.Split(strings.TrimSpace(resp.Body.String()), "\n")
+	require.Len(t, lines, 3)
+	for _, line := range lines {
+		require.NoError(t, protojson.Unmarshal([]byte(line), &plspb.LogMessageAppendResponse{}))
+	}
+
+}
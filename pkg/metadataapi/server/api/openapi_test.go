@@ -0,0 +1,51 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/puppetlabs/relay-core/pkg/metadataapi/asset"
+	"github.com/puppetlabs/relay-core/pkg/metadataapi/server/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPIDescribesAllRoutes(t *testing.T) {
+	var doc struct {
+		Paths map[string]map[string]interface{} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(asset.MustAssetString(asset.OpenAPIV1)), &doc))
+
+	r := mux.NewRouter()
+	api.NewServer(nil).Route(r)
+
+	var routes int
+	require.NoError(t, r.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+
+		methods, err := route.GetMethods()
+		require.NoError(t, err, "route %s has no methods", path)
+
+		for _, method := range methods {
+			if method == http.MethodHead || method == http.MethodOptions {
+				continue
+			}
+
+			ops, found := doc.Paths[path]
+			require.True(t, found, "route %s is not described in the OpenAPI document", path)
+
+			_, found = ops[strings.ToLower(method)]
+			require.True(t, found, "route %s %s is not described in the OpenAPI document", method, path)
+
+			routes++
+		}
+
+		return nil
+	}))
+	require.NotZero(t, routes)
+}
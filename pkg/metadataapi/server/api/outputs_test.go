@@ -3,8 +3,10 @@ package api_test
 import (
 	"context"
 	"encoding/json"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
 
@@ -65,3 +67,71 @@ func TestPutGetOutput(t *testing.T) {
 	require.Equal(t, "test-task", out.TaskName)
 	require.Equal(t, "bar\x90", out.Value.Data)
 }
+
+func TestPutGetOutputWithDataDirectory(t *testing.T) {
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "relay-sample-data-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	tokenGenerator, err := sample.NewHS256TokenGenerator(nil)
+	require.NoError(t, err)
+
+	sc := &opt.SampleConfig{
+		Runs: map[string]*opt.SampleConfigRun{
+			"test": &opt.SampleConfigRun{
+				Steps: map[string]*opt.SampleConfigStep{
+					"first": &opt.SampleConfigStep{
+						Outputs: map[string]interface{}{
+							"foo": "seeded",
+							"baz": "seeded",
+						},
+					},
+					"second": &opt.SampleConfigStep{},
+				},
+			},
+		},
+	}
+
+	tokenMap := tokenGenerator.GenerateAll(ctx, sc)
+
+	firstToken, found := tokenMap.ForStep("test", "first")
+	require.True(t, found)
+
+	secondToken, found := tokenMap.ForStep("test", "second")
+	require.True(t, found)
+
+	require.NoError(t, sample.SeedDataDirectory(ctx, sc, dir))
+
+	h := api.NewHandler(sample.NewAuthenticator(sc, tokenGenerator.Key(), sample.AuthenticatorWithDataDirectory(dir)))
+
+	// Overwrite one of the seeded outputs.
+	req, err := http.NewRequest(http.MethodPut, "/outputs/foo", strings.NewReader("bar\x90"))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+firstToken)
+
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+
+	// Seeding again must not clobber what the step wrote.
+	require.NoError(t, sample.SeedDataDirectory(ctx, sc, dir))
+
+	// A new instance reads the outputs back from the directory.
+	h = api.NewHandler(sample.NewAuthenticator(sc, tokenGenerator.Key(), sample.AuthenticatorWithDataDirectory(dir)))
+
+	for name, expected := range map[string]string{"foo": "bar\x90", "baz": "seeded"} {
+		req, err = http.NewRequest(http.MethodGet, "/outputs/first/"+name, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+secondToken)
+
+		resp = httptest.NewRecorder()
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+
+		var out api.GetOutputResponseEnvelope
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		require.Equal(t, expected, out.Value.Data, name)
+	}
+}
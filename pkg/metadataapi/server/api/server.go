@@ -1,9 +1,16 @@
 package api
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/puppetlabs/errawr-go/v2/pkg/errawr"
+	utilapi "github.com/puppetlabs/horsehead/v2/httputil/api"
+	"github.com/puppetlabs/relay-core/pkg/expr/fnlib"
+	"github.com/puppetlabs/relay-core/pkg/expr/model"
 	"github.com/puppetlabs/relay-core/pkg/metadataapi/server/middleware"
 	"github.com/puppetlabs/relay-core/pkg/workflow/validation"
 )
@@ -16,14 +23,122 @@ func WithSchemaRegistry(reg validation.SchemaRegistry) ServerOption {
 	}
 }
 
+func WithRateLimiter(rl *middleware.RateLimiter) ServerOption {
+	return func(s *Server) {
+		s.rateLimiter = rl
+	}
+}
+
+// WithClock uses the given function to determine the current time when
+// evaluating expressions instead of time.Now.
+func WithClock(now func() time.Time) ServerOption {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// WithEvaluationTrace allows clients to request a trace of the evaluation of
+// expressions using the debug query parameter. Requests for a trace are
+// rejected otherwise.
+func WithEvaluationTrace(enabled bool) ServerOption {
+	return func(s *Server) {
+		s.evaluationTraceEnabled = enabled
+	}
+}
+
 type Server struct {
-	auth           middleware.Authenticator
-	schemaRegistry validation.SchemaRegistry
+	auth                   middleware.Authenticator
+	schemaRegistry         validation.SchemaRegistry
+	rateLimiter            *middleware.RateLimiter
+	now                    func() time.Time
+	evaluationTraceEnabled bool
+}
+
+// evaluationContext returns a context for evaluating the expressions of a
+// single request. Every expression evaluated using the context sees the same
+// current time.
+func (s *Server) evaluationContext(ctx context.Context) context.Context {
+	return fnlib.NewContextWithTime(ctx, s.now())
+}
+
+// evaluationTrace returns a trace to record the evaluation of a request's
+// expressions in, if the client asked for one using the debug query parameter
+// and this server allows it.
+func (s *Server) evaluationTrace(r *http.Request) (*model.Trace, error) {
+	switch debug := r.URL.Query().Get("debug"); debug {
+	case "":
+		return nil, nil
+	case "trace":
+		if !s.evaluationTraceEnabled {
+			return nil, fmt.Errorf("debug mode %q is not enabled", debug)
+		}
+
+		return model.NewTrace(), nil
+	default:
+		return nil, fmt.Errorf("unknown debug mode %q", debug)
+	}
+}
+
+// TracedErrorEnvelope is an error response that also carries the trace of the
+// evaluation that led to the error.
+type TracedErrorEnvelope struct {
+	*utilapi.ErrorEnvelope
+	Trace []*model.JSONTraceNodeEnvelope `json:"trace,omitempty"`
+}
+
+// writeErrorWithTrace writes the given error exactly as utilapi.WriteError
+// would, but includes the given trace, if any, in the response.
+func writeErrorWithTrace(ctx context.Context, w http.ResponseWriter, err errawr.Error, trace *model.Trace) {
+	if trace == nil {
+		utilapi.WriteError(ctx, w, err)
+		return
+	}
+
+	status := http.StatusInternalServerError
+	if hm, ok := err.Metadata().HTTP(); ok {
+		status = hm.Status()
+	}
+
+	env := &TracedErrorEnvelope{
+		Trace: model.NewJSONTraceEnvelope(trace.Nodes()),
+	}
+	if sensitivity, ok := utilapi.ErrorSensitivityFromContext(ctx); ok {
+		env.ErrorEnvelope = utilapi.NewErrorEnvelopeWithSensitivity(err, sensitivity)
+	} else {
+		env.ErrorEnvelope = utilapi.NewErrorEnvelope(err)
+	}
+
+	utilapi.WriteObjectWithStatus(ctx, w, status, env)
+}
+
+// rateLimitBudgets assigns the requests to each route, by path template, to a
+// rate limit budget. Requests to any other route use the default budget.
+var rateLimitBudgets = map[string]middleware.RateLimitBudget{
+	"/logs":                      middleware.RateLimitBudgetLogs,
+	"/logs/{logId}/messages":     middleware.RateLimitBudgetLogs,
+	"/outputs/{name}":            middleware.RateLimitBudgetOutputs,
+	"/outputs/{stepName}/{name}": middleware.RateLimitBudgetOutputs,
+	"/secrets/{name}":            middleware.RateLimitBudgetSecrets,
+}
+
+func rateLimitBudget(r *http.Request) middleware.RateLimitBudget {
+	if route := mux.CurrentRoute(r); route != nil {
+		if tpl, err := route.GetPathTemplate(); err == nil {
+			if budget, found := rateLimitBudgets[tpl]; found {
+				return budget
+			}
+		}
+	}
+
+	return middleware.RateLimitBudgetDefault
 }
 
 func (s *Server) Route(r *mux.Router) {
 	r.UseEncodedPath()
 	r.Use(middleware.WithAuthentication(s.auth))
+	if s.rateLimiter != nil {
+		r.Use(s.rateLimiter.RouteMiddleware(rateLimitBudget))
+	}
 
 	// Conditions
 	r.HandleFunc("/conditions", s.GetConditions).Methods(http.MethodGet)
@@ -59,6 +174,7 @@ func (s *Server) Route(r *mux.Router) {
 func NewServer(auth middleware.Authenticator, opts ...ServerOption) *Server {
 	svr := &Server{
 		auth: auth,
+		now:  time.Now,
 	}
 
 	for _, opt := range opts {
@@ -23,14 +23,18 @@ func (s *Server) GetSpec(w http.ResponseWriter, r *http.Request) {
 	}
 
 	lang := evaluate.LanguagePath
-	switch r.URL.Query().Get("lang") {
-	case "jsonpath-template":
-		lang = evaluate.LanguageJSONPathTemplate
-	case "jsonpath":
-		lang = evaluate.LanguageJSONPath
-	case "", "path":
-	default:
-		utilapi.WriteError(ctx, w, errors.NewExpressionUnsupportedLanguageError(r.URL.Query().Get("lang")))
+	if name := r.URL.Query().Get("lang"); name != "" {
+		var ok bool
+		if lang, ok = evaluate.ParseLanguage(name); !ok {
+			utilapi.WriteError(ctx, w, errors.NewExpressionUnsupportedLanguageError(name))
+			return
+		}
+	}
+
+	trace, err := s.evaluationTrace(r)
+	if err != nil {
+		utilapi.WriteError(ctx, w, errors.NewAPIMalformedRequestError().WithCause(err))
+		return
 	}
 
 	ev := evaluate.NewEvaluator(
@@ -39,19 +43,25 @@ func (s *Server) GetSpec(w http.ResponseWriter, r *http.Request) {
 		evaluate.WithParameterTypeResolver(resolve.NewParameterTypeResolver(managers.Parameters())),
 		evaluate.WithOutputTypeResolver(resolve.NewOutputTypeResolver(managers.StepOutputs())),
 		evaluate.WithSecretTypeResolver(resolve.NewSecretTypeResolver(managers.Secrets())),
+		evaluate.WithTrace(trace),
 	).ScopeTo(spec.Tree)
 
 	var rv *model.Result
 	var rerr error
 	if query := r.URL.Query().Get("q"); query != "" {
-		rv, rerr = ev.EvaluateQuery(ctx, query)
+		rv, rerr = ev.EvaluateQuery(s.evaluationContext(ctx), query)
 	} else {
-		rv, rerr = ev.EvaluateAll(ctx)
+		rv, rerr = ev.EvaluateAll(s.evaluationContext(ctx))
 	}
 	if rerr != nil {
 		utilapi.WriteError(ctx, w, errors.NewExpressionEvaluationError(rerr.Error()))
 		return
 	}
 
-	utilapi.WriteObjectOK(ctx, w, model.NewJSONResultEnvelope(rv))
+	env := model.NewJSONResultEnvelope(rv)
+	if trace != nil {
+		env.Trace = model.NewJSONTraceEnvelope(trace.Nodes())
+	}
+
+	utilapi.WriteObjectOK(ctx, w, env)
 }
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/puppetlabs/relay-core/pkg/expr/model"
 	"github.com/puppetlabs/relay-core/pkg/expr/serialize"
@@ -15,6 +16,7 @@ import (
 	"github.com/puppetlabs/relay-core/pkg/metadataapi/opt"
 	"github.com/puppetlabs/relay-core/pkg/metadataapi/sample"
 	"github.com/puppetlabs/relay-core/pkg/metadataapi/server/api"
+	"github.com/puppetlabs/relay-core/pkg/metadataapi/server/middleware"
 	"github.com/stretchr/testify/require"
 )
 
@@ -87,7 +89,7 @@ func TestGetSpec(t *testing.T) {
 	currentTaskToken, found := tokenMap.ForStep("test", "current-task")
 	require.True(t, found)
 
-	h := api.NewHandler(sample.NewAuthenticator(sc, tokenGenerator.Key()))
+	h := api.NewHandler(sample.NewAuthenticator(sc, tokenGenerator.Key()), api.WithEvaluationTrace(true))
 
 	// Request the whole spec.
 	req, err := http.NewRequest(http.MethodGet, "/spec", nil)
@@ -120,6 +122,7 @@ func TestGetSpec(t *testing.T) {
 		"superNormal": "test-normal-value",
 	}, r.Value.Data)
 	require.True(t, r.Complete)
+	require.True(t, r.Sensitive)
 
 	// Request a specific expression from the spec.
 	req.URL.RawQuery = url.Values{"q": []string{"structuredOutput.a"}}.Encode()
@@ -132,6 +135,7 @@ func TestGetSpec(t *testing.T) {
 	require.NoError(t, json.NewDecoder(resp.Result().Body).Decode(&r))
 	require.Equal(t, "value", r.Value.Data)
 	require.True(t, r.Complete)
+	require.False(t, r.Sensitive)
 
 	// Request a specific expression from the spec using the JSON path query language
 	req.URL.RawQuery = url.Values{
@@ -147,4 +151,208 @@ func TestGetSpec(t *testing.T) {
 	require.NoError(t, json.NewDecoder(resp.Body).Decode(&r))
 	require.Equal(t, "value", r.Value.Data)
 	require.True(t, r.Complete)
+
+	// Request a specific expression from the spec using jq
+	req.URL.RawQuery = url.Values{
+		"q":    []string{".structuredOutput.a"},
+		"lang": []string{"jq"},
+	}.Encode()
+
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+
+	r = model.JSONResultEnvelope{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&r))
+	require.Equal(t, "value", r.Value.Data)
+	require.True(t, r.Complete)
+
+	// Request a boolean check against the spec using CEL
+	req.URL.RawQuery = url.Values{
+		"q":    []string{`structuredOutput.a == "value"`},
+		"lang": []string{"cel"},
+	}.Encode()
+
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+
+	r = model.JSONResultEnvelope{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&r))
+	require.Equal(t, true, r.Value.Data)
+	require.True(t, r.Complete)
+
+	// Request a trace of the evaluation of an expression
+	req.URL.RawQuery = url.Values{
+		"q":     []string{"superSecret"},
+		"debug": []string{"trace"},
+	}.Encode()
+
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+
+	r = model.JSONResultEnvelope{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&r))
+	require.Equal(t, "test-secret-value", r.Value.Data)
+	require.Len(t, r.Trace, 1)
+	require.Equal(t, model.TraceNodeKindQuery, r.Trace[0].Kind)
+	require.Equal(t, "superSecret", r.Trace[0].Name)
+	require.Equal(t, model.RedactedValue, r.Trace[0].Value.Data)
+	require.Len(t, r.Trace[0].Children, 1)
+	require.Equal(t, model.TraceNodeKindType, r.Trace[0].Children[0].Kind)
+	require.Equal(t, "Secret", r.Trace[0].Children[0].Name)
+	require.Equal(t, model.RedactedValue, r.Trace[0].Children[0].Value.Data)
+	require.True(t, r.Trace[0].Children[0].Sensitive)
+
+	// Request an unknown debug mode
+	req.URL.RawQuery = url.Values{
+		"debug": []string{"nope"},
+	}.Encode()
+
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusUnprocessableEntity, resp.Result().StatusCode)
+
+	// Request a trace from a server that does not enable tracing
+	req.URL.RawQuery = url.Values{
+		"q":     []string{"superSecret"},
+		"debug": []string{"trace"},
+	}.Encode()
+
+	resp = httptest.NewRecorder()
+	api.NewHandler(sample.NewAuthenticator(sc, tokenGenerator.Key())).ServeHTTP(resp, req)
+	require.Equal(t, http.StatusUnprocessableEntity, resp.Result().StatusCode)
+}
+
+func TestGetSpecWithTimeFunctions(t *testing.T) {
+	ctx := context.Background()
+
+	tokenGenerator, err := sample.NewHS256TokenGenerator(nil)
+	require.NoError(t, err)
+
+	sc := &opt.SampleConfig{
+		Runs: map[string]*opt.SampleConfigRun{
+			"test": &opt.SampleConfigRun{
+				Steps: map[string]*opt.SampleConfigStep{
+					"current-task": &opt.SampleConfigStep{
+						Spec: opt.SampleConfigSpec{
+							"startedAt": serialize.YAMLTree{
+								Tree: sdktestutil.JSONInvocation("now", []interface{}{}),
+							},
+							"tag": serialize.YAMLTree{
+								Tree: sdktestutil.JSONInvocation("formatTime", []interface{}{
+									sdktestutil.JSONInvocation("now", []interface{}{}),
+									"v2006.01.02-150405",
+								}),
+							},
+							"remindAt": serialize.YAMLTree{
+								Tree: sdktestutil.JSONInvocation("formatTime", []interface{}{
+									sdktestutil.JSONInvocation("addTime", []interface{}{
+										sdktestutil.JSONInvocation("truncateTime", []interface{}{
+											sdktestutil.JSONInvocation("now", []interface{}{}),
+											"day",
+										}),
+										"48h",
+									}),
+								}),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tokenMap := tokenGenerator.GenerateAll(ctx, sc)
+
+	currentTaskToken, found := tokenMap.ForStep("test", "current-task")
+	require.True(t, found)
+
+	now := time.Date(2020, time.October, 7, 15, 4, 5, 0, time.UTC)
+	h := api.NewHandler(
+		sample.NewAuthenticator(sc, tokenGenerator.Key()),
+		api.WithClock(func() time.Time { return now }),
+	)
+
+	req, err := http.NewRequest(http.MethodGet, "/spec", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+currentTaskToken)
+
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+
+	var r model.JSONResultEnvelope
+	require.NoError(t, json.NewDecoder(resp.Result().Body).Decode(&r))
+	require.Equal(t, map[string]interface{}{
+		"startedAt": "2020-10-07T15:04:05Z",
+		"tag":       "v2020.10.07-150405",
+		"remindAt":  "2020-10-09T00:00:00Z",
+	}, r.Value.Data)
+	require.True(t, r.Complete)
+}
+
+func TestGetSpecRateLimit(t *testing.T) {
+	ctx := context.Background()
+
+	tokenGenerator, err := sample.NewHS256TokenGenerator(nil)
+	require.NoError(t, err)
+
+	sc := &opt.SampleConfig{
+		Secrets: map[string]string{
+			"test-secret-key": "test-secret-value",
+		},
+		Runs: map[string]*opt.SampleConfigRun{
+			"test": &opt.SampleConfigRun{
+				Steps: map[string]*opt.SampleConfigStep{
+					"current-task": &opt.SampleConfigStep{
+						Spec: opt.SampleConfigSpec{
+							"superNormal": serialize.YAMLTree{
+								Tree: "test-normal-value",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tokenMap := tokenGenerator.GenerateAll(ctx, sc)
+
+	accessToken, found := tokenMap.ForStep("test", "current-task")
+	require.True(t, found)
+
+	now := time.Date(2020, time.November, 1, 0, 0, 0, 0, time.UTC)
+	rl := middleware.NewRateLimiter(
+		middleware.RateLimiterWithClock(func() time.Time { return now }),
+		middleware.RateLimiterWithLimit(middleware.RateLimitBudgetDefault, middleware.RateLimit{
+			PerSecond: 1,
+			Burst:     1,
+		}),
+	)
+
+	h := api.NewHandler(sample.NewAuthenticator(sc, tokenGenerator.Key()), api.WithRateLimiter(rl))
+
+	request := func(path string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, req)
+		return resp.Result()
+	}
+
+	require.Equal(t, http.StatusOK, request("/spec").StatusCode)
+
+	resp := request("/spec")
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	require.Equal(t, "1", resp.Header.Get("Retry-After"))
+
+	// Secrets have their own budget, which is not limited here.
+	require.Equal(t, http.StatusOK, request("/secrets/test-secret-key").StatusCode)
+
+	now = now.Add(time.Second)
+	require.Equal(t, http.StatusOK, request("/spec").StatusCode)
 }
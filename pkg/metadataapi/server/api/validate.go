@@ -37,7 +37,7 @@ func (s *Server) PostValidate(w http.ResponseWriter, r *http.Request) {
 			evaluate.WithSecretTypeResolver(resolve.NewSecretTypeResolver(managers.Secrets())),
 		).ScopeTo(spec.Tree)
 
-		rv, err := ev.EvaluateAll(ctx)
+		rv, err := ev.EvaluateAll(s.evaluationContext(ctx))
 		if err != nil {
 			utilapi.WriteError(ctx, w, errors.NewExpressionEvaluationError(err.Error()))
 
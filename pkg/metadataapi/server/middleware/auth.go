@@ -5,17 +5,24 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"path"
 
 	"github.com/gorilla/mux"
 	vaultapi "github.com/hashicorp/vault/api"
 	"github.com/puppetlabs/horsehead/v2/instrumentation/alerts/trackers"
 	"github.com/puppetlabs/relay-core/pkg/authenticate"
 	"github.com/puppetlabs/relay-core/pkg/manager/api"
+	"github.com/puppetlabs/relay-core/pkg/manager/audit"
 	"github.com/puppetlabs/relay-core/pkg/manager/builder"
+	"github.com/puppetlabs/relay-core/pkg/manager/cache"
 	"github.com/puppetlabs/relay-core/pkg/manager/configmap"
 	"github.com/puppetlabs/relay-core/pkg/manager/memory"
+	"github.com/puppetlabs/relay-core/pkg/manager/permission"
+	"github.com/puppetlabs/relay-core/pkg/manager/refresh"
 	"github.com/puppetlabs/relay-core/pkg/manager/reject"
+	"github.com/puppetlabs/relay-core/pkg/manager/secret"
 	"github.com/puppetlabs/relay-core/pkg/manager/service"
+	"github.com/puppetlabs/relay-core/pkg/manager/tracing"
 	"github.com/puppetlabs/relay-core/pkg/manager/vault"
 	"github.com/puppetlabs/relay-core/pkg/model"
 	"github.com/puppetlabs/relay-pls/pkg/plspb"
@@ -24,6 +31,7 @@ import (
 
 // Credential represents a valid authentication request.
 type Credential struct {
+	Claims   *authenticate.Claims
 	Managers model.MetadataManagers
 	Tags     []trackers.Tag
 }
@@ -37,6 +45,11 @@ type Authenticator interface {
 
 type KubernetesAuthenticatorClientFactoryFunc func(token string) (kubernetes.Interface, error)
 
+// KubernetesAuthenticatorConnectionRefresherFactoryFunc creates a refresher
+// for the connections of a tenant that stores its connections in Vault. The
+// Vault client is authenticated as the tenant.
+type KubernetesAuthenticatorConnectionRefresherFactoryFunc func(client *vaultapi.Client, namespace string) refresh.Refresher
+
 type KubernetesAuthenticator struct {
 	// The factory to produce a scoped token from a request.
 	factory KubernetesAuthenticatorClientFactoryFunc
@@ -45,6 +58,10 @@ type KubernetesAuthenticator struct {
 	// request headers.
 	kubernetesClient *authenticate.KubernetesInterface
 
+	// Optional cache of pods to avoid querying the Kubernetes API server on
+	// every request.
+	kubernetesPodCache *authenticate.KubernetesPodCache
+
 	// Log Service
 	logServiceClient plspb.LogClient
 
@@ -58,8 +75,20 @@ type KubernetesAuthenticator struct {
 	vaultJWTAuthPath string
 	vaultJWTAuthRole string
 
+	// Provides current credentials for connections stored in Vault.
+	connectionRefresherFactory KubernetesAuthenticatorConnectionRefresherFactoryFunc
+
 	// Static keys to use for JWT verification.
 	keys []interface{}
+
+	// Key sets, which may change over time, to use for JWT verification.
+	keySets []authenticate.KeySet
+
+	// Records access to secrets and connections.
+	auditSink audit.Sink
+
+	// Remembers secrets and connections across requests from the same run.
+	secretCache *cache.Cache
 }
 
 var _ Authenticator = &KubernetesAuthenticator{}
@@ -71,16 +100,33 @@ func (ka *KubernetesAuthenticator) intermediary(r *http.Request, mgrs *builder.M
 		return authenticate.NewHTTPAuthorizationHeaderIntermediary(r)
 	}
 
-	// Extract IP from request to hand to Kubernetes for pod
-	// authentication.
-	host, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		host = r.RemoteAddr
+	var kopts []authenticate.KubernetesIntermediaryOption
+	if ka.kubernetesPodCache != nil {
+		kopts = append(kopts, authenticate.KubernetesIntermediaryWithPodCache(ka.kubernetesPodCache))
 	}
 
-	// Go's HTTP server should always give us a valid RemoteAddr, and if it
-	// fails the intermediary will bail on an empty IP anyway.
-	ki := authenticate.NewKubernetesIntermediary(ka.kubernetesClient, net.ParseIP(host))
+	// We can't know which tenant a request is from until we've found its pod,
+	// so the request decides how we look it up. The token on the pod records
+	// the method its tenant is configured to use, and the intermediary rejects
+	// the request if the two don't match.
+	var ki *authenticate.KubernetesIntermediary
+	if token, ok := authenticate.HTTPBearerToken(r); ok {
+		// Pods of tenants that use service account token authentication
+		// present their projected token instead of relying on their IP
+		// address.
+		ki = authenticate.NewKubernetesServiceAccountTokenIntermediary(ka.kubernetesClient, token, kopts...)
+	} else {
+		// Extract IP from request to hand to Kubernetes for pod
+		// authentication.
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+
+		// Go's HTTP server should always give us a valid RemoteAddr, and if it
+		// fails the intermediary will bail on an empty IP anyway.
+		ki = authenticate.NewKubernetesIntermediary(ka.kubernetesClient, net.ParseIP(host), kopts...)
+	}
 
 	if ka.vaultClient == nil {
 		// Done. We assume the token will be set directly on the pod
@@ -117,10 +163,38 @@ func (ka *KubernetesAuthenticator) resolver(mgrs *builder.MetadataBuilder) authe
 					return nil
 				}
 
-				base := vault.NewKVV2Client(md.VaultClient, claims.RelayVaultEnginePath)
+				engineVersion, err := vault.ParseKVVersion(claims.RelayVaultEngineVersion)
+				if err != nil {
+					return err
+				}
+
+				base := vault.NewKVClient(
+					md.VaultClient,
+					claims.RelayVaultEnginePath,
+					vault.KVClientWithEngineVersion(engineVersion),
+					vault.KVClientWithNamespace(claims.RelayVaultNamespace),
+				)
 
 				if claims.RelayVaultConnectionPath != "" {
-					mgrs.SetConnections(vault.NewConnectionManager(base.In(claims.RelayVaultConnectionPath)))
+					var copts []vault.ConnectionManagerOption
+					if ka.connectionRefresherFactory != nil {
+						client, err := ka.factory(claims.KubernetesServiceAccountToken)
+						if err != nil {
+							return err
+						}
+
+						// Leases on refreshed credentials are recorded with
+						// the run so that the operator can revoke them when
+						// the run ends.
+						mutableMap := configmap.NewClientConfigMap(client, claims.KubernetesNamespaceName, claims.RelayKubernetesMutableConfigMapName)
+
+						copts = append(copts,
+							vault.ConnectionManagerWithRefresher(ka.connectionRefresherFactory(md.VaultClient, claims.RelayVaultNamespace)),
+							vault.ConnectionManagerWithLeaseRecorder(configmap.NewLeaseManager(mutableMap)),
+						)
+					}
+
+					mgrs.SetConnections(vault.NewConnectionManager(base.In(claims.RelayVaultConnectionPath), copts...))
 				}
 
 				if claims.RelayVaultSecretPath != "" {
@@ -136,11 +210,17 @@ func (ka *KubernetesAuthenticator) resolver(mgrs *builder.MetadataBuilder) authe
 		delegates = append(delegates, authenticate.NewKeyResolver(key))
 	}
 
+	for _, keySet := range ka.keySets {
+		delegates = append(delegates, authenticate.NewKeySetResolver(keySet))
+	}
+
 	return authenticate.NewAnyResolver(delegates)
 }
 
-func (ka *KubernetesAuthenticator) injector(mgrs *builder.MetadataBuilder, tags *[]trackers.Tag) authenticate.Injector {
+func (ka *KubernetesAuthenticator) injector(mgrs *builder.MetadataBuilder, target **authenticate.Claims, tags *[]trackers.Tag) authenticate.Injector {
 	return authenticate.InjectorFunc(func(ctx context.Context, claims *authenticate.Claims) error {
+		*target = claims
+
 		client, err := ka.factory(claims.KubernetesServiceAccountToken)
 		if err != nil {
 			return err
@@ -155,13 +235,24 @@ func (ka *KubernetesAuthenticator) injector(mgrs *builder.MetadataBuilder, tags
 			// Only a step can work with parameters and outputs. Other actions
 			// will get the default rejection manager.
 			mgrs.SetParameters(configmap.NewParameterManager(immutableMap))
-			mgrs.SetStepOutputs(configmap.NewStepOutputManager(step, mutableMap))
+
+			var stepOutputs model.StepOutputManager = configmap.NewStepOutputManager(step, mutableMap)
+			if perms := claims.RelayPermissions; perms != nil {
+				stepOutputs = permission.NewStepOutputManager(stepOutputs, perms)
+			}
+
+			mgrs.SetStepOutputs(stepOutputs)
 		})
 
 		if claims.RelayEventAPIURL != nil {
 			mgrs.SetEvents(api.NewEventManager(action, claims.RelayEventAPIURL.URL.String(), claims.RelayEventAPIToken))
 		}
 
+		if namespace := claims.RelayKubernetesSecretStoreNamespace; namespace != "" {
+			mgrs.SetConnections(secret.NewConnectionManager(client, namespace))
+			mgrs.SetSecrets(secret.NewSecretManager(client, namespace))
+		}
+
 		mgrs.SetConditions(configmap.NewConditionManager(action, immutableMap))
 		mgrs.SetEnvironment(configmap.NewEnvironmentManager(action, immutableMap))
 		mgrs.SetSpec(configmap.NewSpecManager(action, immutableMap))
@@ -181,6 +272,47 @@ func (ka *KubernetesAuthenticator) injector(mgrs *builder.MetadataBuilder, tags
 			mgrs.SetLogs(reject.LogManager)
 		}
 
+		if ka.secretCache != nil {
+			// The cache is the innermost decorator so that every request is
+			// still subject to permission checks and auditing.
+			scope := secretCacheScope(claims)
+
+			mgrs.DecorateSecrets(func(m model.SecretManager) model.SecretManager {
+				return ka.secretCache.SecretManager(scope, m)
+			})
+			mgrs.DecorateConnections(func(m model.ConnectionManager) model.ConnectionManager {
+				return ka.secretCache.ConnectionManager(scope, m)
+			})
+		}
+
+		if perms := claims.RelayPermissions; perms != nil {
+			// Permissions are checked before access is audited so that
+			// rejected requests are recorded too.
+			mgrs.DecorateSecrets(func(m model.SecretManager) model.SecretManager {
+				return permission.NewSecretManager(m, perms)
+			})
+			mgrs.DecorateConnections(func(m model.ConnectionManager) model.ConnectionManager {
+				return permission.NewConnectionManager(m, perms)
+			})
+		}
+
+		if ka.auditSink != nil {
+			actor := audit.Actor{
+				DomainID:   claims.RelayDomainID,
+				TenantID:   claims.RelayTenantID,
+				RunID:      claims.RelayRunID,
+				ActionType: action.Type().Singular,
+				ActionName: claims.RelayName,
+			}
+
+			mgrs.DecorateSecrets(func(m model.SecretManager) model.SecretManager {
+				return audit.NewSecretManager(m, ka.auditSink, actor)
+			})
+			mgrs.DecorateConnections(func(m model.ConnectionManager) model.ConnectionManager {
+				return audit.NewConnectionManager(m, ka.auditSink, actor)
+			})
+		}
+
 		ts := []trackers.Tag{
 			{Key: "relay.domain.id", Value: claims.RelayDomainID},
 			{Key: "relay.tenant.id", Value: claims.RelayTenantID},
@@ -198,14 +330,27 @@ func (ka *KubernetesAuthenticator) injector(mgrs *builder.MetadataBuilder, tags
 	})
 }
 
+// secretCacheScope determines which actions may share cached secrets and
+// connections. All the steps of a run use the same secret store, so they share
+// a scope. Triggers are not part of a run, so each trigger has its own scope.
+func secretCacheScope(claims *authenticate.Claims) string {
+	action := claims.Action()
+	if action.Type().Singular == model.ActionTypeStep.Singular {
+		return path.Join(claims.RelayTenantID, "runs", claims.RelayRunID)
+	}
+
+	return path.Join(claims.RelayTenantID, action.Type().Plural, action.Hash().HexEncoding())
+}
+
 func (ka *KubernetesAuthenticator) Authenticate(r *http.Request) (*Credential, error) {
 	mgrs := builder.NewMetadataBuilder()
+	var claims *authenticate.Claims
 	var tags []trackers.Tag
 
 	auth := authenticate.NewAuthenticator(
 		ka.intermediary(r, mgrs),
 		ka.resolver(mgrs),
-		authenticate.AuthenticatorWithInjector(ka.injector(mgrs, &tags)),
+		authenticate.AuthenticatorWithInjector(ka.injector(mgrs, &claims, &tags)),
 	)
 
 	if ok, err := auth.Authenticate(r.Context()); err != nil {
@@ -215,6 +360,7 @@ func (ka *KubernetesAuthenticator) Authenticate(r *http.Request) (*Credential, e
 	}
 
 	return &Credential{
+		Claims:   claims,
 		Managers: mgrs.Build(),
 		Tags:     tags,
 	}, nil
@@ -228,6 +374,12 @@ func KubernetesAuthenticatorWithKubernetesIntermediary(client *authenticate.Kube
 	}
 }
 
+func KubernetesAuthenticatorWithKubernetesPodCache(podCache *authenticate.KubernetesPodCache) KubernetesAuthenticatorOption {
+	return func(ka *KubernetesAuthenticator) {
+		ka.kubernetesPodCache = podCache
+	}
+}
+
 func KubernetesAuthenticatorWithLogServiceIntermediary(client plspb.LogClient) KubernetesAuthenticatorOption {
 	return func(ka *KubernetesAuthenticator) {
 		ka.logServiceClient = client
@@ -256,6 +408,34 @@ func KubernetesAuthenticatorWithKeyResolver(key interface{}) KubernetesAuthentic
 	}
 }
 
+func KubernetesAuthenticatorWithKeySetResolver(keySet authenticate.KeySet) KubernetesAuthenticatorOption {
+	return func(ka *KubernetesAuthenticator) {
+		ka.keySets = append(ka.keySets, keySet)
+	}
+}
+
+func KubernetesAuthenticatorWithAuditSink(sink audit.Sink) KubernetesAuthenticatorOption {
+	return func(ka *KubernetesAuthenticator) {
+		ka.auditSink = sink
+	}
+}
+
+// KubernetesAuthenticatorWithSecretCache caches the secrets and connections
+// requested by the steps of a run in the given cache.
+func KubernetesAuthenticatorWithSecretCache(c *cache.Cache) KubernetesAuthenticatorOption {
+	return func(ka *KubernetesAuthenticator) {
+		ka.secretCache = c
+	}
+}
+
+// KubernetesAuthenticatorWithConnectionRefresher refreshes the credentials of
+// connections stored in Vault using refreshers created by the given factory.
+func KubernetesAuthenticatorWithConnectionRefresher(factory KubernetesAuthenticatorConnectionRefresherFactoryFunc) KubernetesAuthenticatorOption {
+	return func(ka *KubernetesAuthenticator) {
+		ka.connectionRefresherFactory = factory
+	}
+}
+
 func NewKubernetesAuthenticator(factory KubernetesAuthenticatorClientFactoryFunc, opts ...KubernetesAuthenticatorOption) *KubernetesAuthenticator {
 	ka := &KubernetesAuthenticator{
 		factory: factory,
@@ -283,7 +463,9 @@ func WithAuthentication(a Authenticator) mux.MiddlewareFunc {
 					r = r.WithContext(trackers.NewContextWithCapturer(r.Context(), capturer))
 				}
 
-				WithManagers(cred.Managers)(next).ServeHTTP(w, r)
+				managers := tracing.NewMetadataManagers(cred.Managers)
+
+				WithClaims(cred.Claims)(WithManagers(managers)(next)).ServeHTTP(w, r)
 			}
 		})
 	}
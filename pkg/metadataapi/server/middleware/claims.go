@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/puppetlabs/relay-core/pkg/authenticate"
+)
+
+type claimsContextKey int
+
+const (
+	claimsClaimsContextKey claimsContextKey = iota
+)
+
+// Claims returns the claims of the authenticated action making the request, if
+// any.
+func Claims(r *http.Request) (*authenticate.Claims, bool) {
+	claims, ok := r.Context().Value(claimsClaimsContextKey).(*authenticate.Claims)
+	return claims, ok && claims != nil
+}
+
+func WithClaims(claims *authenticate.Claims) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = r.WithContext(context.WithValue(r.Context(), claimsClaimsContextKey, claims))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/puppetlabs/horsehead/v2/instrumentation/metrics"
+	"github.com/puppetlabs/horsehead/v2/instrumentation/metrics/collectors"
+)
+
+const (
+	metricHTTPRequestDuration = "http_request_duration_seconds"
+)
+
+// RouteTemplate returns the path template of the route that matched the
+// request, or an empty string if no route matched.
+func RouteTemplate(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return ""
+	}
+
+	tpl, err := route.GetPathTemplate()
+	if err != nil {
+		return ""
+	}
+
+	return tpl
+}
+
+// WithRequestMetrics records the duration of each request labeled by the
+// matched route template, the HTTP method, and the response status code.
+func WithRequestMetrics(m *metrics.Metrics) mux.MiddlewareFunc {
+	m.MustRegisterDurationMiddleware(metricHTTPRequestDuration, collectors.DurationMiddlewareOptions{
+		Description: "the time taken to serve requests to the metadata API",
+		Labels:      []string{"route", "method", "code"},
+	})
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			m.MustDurationMiddleware(metricHTTPRequestDuration, metrics.NewLabel("route", RouteTemplate(r))).
+				Wrap(next).
+				ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/puppetlabs/horsehead/v2/instrumentation/metrics"
+	"github.com/puppetlabs/horsehead/v2/instrumentation/metrics/delegates"
+	"github.com/puppetlabs/relay-core/pkg/metadataapi/server/middleware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestMetrics(t *testing.T) {
+	mets, err := metrics.NewNamespace("metadata_api_test", metrics.Options{
+		DelegateType:  delegates.PrometheusDelegate,
+		ErrorBehavior: metrics.ErrorBehaviorPanic,
+	})
+	require.NoError(t, err)
+
+	r := mux.NewRouter()
+	r.Use(middleware.WithRequestMetrics(mets))
+	r.HandleFunc("/secrets/{name}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	req, err := http.NewRequest(http.MethodGet, "/secrets/foo", nil)
+	require.NoError(t, err)
+
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusNotFound, resp.Result().StatusCode)
+
+	req, err = http.NewRequest(http.MethodGet, "/metrics", nil)
+	require.NoError(t, err)
+
+	resp = httptest.NewRecorder()
+	mets.Handler().ServeHTTP(resp, req)
+	require.Contains(t, resp.Body.String(), `metadata_api_test_http_request_duration_seconds_count{code="404",method="get",route="/secrets/{name}"} 1`)
+}
@@ -0,0 +1,226 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	utilapi "github.com/puppetlabs/horsehead/v2/httputil/api"
+	"github.com/puppetlabs/horsehead/v2/instrumentation/metrics"
+	"github.com/puppetlabs/horsehead/v2/instrumentation/metrics/collectors"
+	"github.com/puppetlabs/relay-core/pkg/metadataapi/errors"
+	"golang.org/x/time/rate"
+)
+
+const (
+	metricRateLimitThrottledRequests = "rate_limit_throttled_requests"
+
+	// rateLimitIdleExpiration is the amount of time after which we discard the
+	// limiter for an action that has stopped making requests.
+	rateLimitIdleExpiration = 15 * time.Minute
+)
+
+// RateLimitBudget identifies a class of requests that share a request budget.
+type RateLimitBudget string
+
+const (
+	RateLimitBudgetSecrets RateLimitBudget = "secrets"
+	RateLimitBudgetOutputs RateLimitBudget = "outputs"
+	RateLimitBudgetLogs    RateLimitBudget = "logs"
+
+	// RateLimitBudgetDefault is shared by requests that do not belong to any
+	// other budget.
+	RateLimitBudgetDefault RateLimitBudget = "default"
+)
+
+// RateLimit configures the number of requests an action can make in a given
+// budget.
+type RateLimit struct {
+	// PerSecond is the sustained number of requests allowed per second.
+	PerSecond float64
+
+	// Burst is the maximum number of requests allowed at once.
+	Burst int
+}
+
+type rateLimitKey struct {
+	budget RateLimitBudget
+	runID  string
+	name   string
+}
+
+type rateLimitEntry struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// RateLimiter enforces per-action request limits. Actions are identified by
+// the run ID and name in their authentication claims.
+type RateLimiter struct {
+	limits  map[RateLimitBudget]RateLimit
+	metrics *metrics.Metrics
+	now     func() time.Time
+
+	mut       sync.Mutex
+	entries   map[rateLimitKey]*rateLimitEntry
+	lastSweep time.Time
+}
+
+func (rl *RateLimiter) reserve(key rateLimitKey, limit RateLimit) (time.Duration, bool) {
+	rl.mut.Lock()
+	defer rl.mut.Unlock()
+
+	now := rl.now()
+
+	if now.Sub(rl.lastSweep) > rateLimitIdleExpiration {
+		for k, entry := range rl.entries {
+			if now.Sub(entry.lastSeen) > rateLimitIdleExpiration {
+				delete(rl.entries, k)
+			}
+		}
+
+		rl.lastSweep = now
+	}
+
+	entry, found := rl.entries[key]
+	if !found {
+		entry = &rateLimitEntry{
+			limiter: rate.NewLimiter(rate.Limit(limit.PerSecond), limit.Burst),
+		}
+		rl.entries[key] = entry
+	}
+
+	entry.lastSeen = now
+
+	r := entry.limiter.ReserveN(now, 1)
+	if !r.OK() {
+		return 0, false
+	}
+
+	delay := r.DelayFrom(now)
+	if delay > 0 {
+		// We won't actually wait for the reservation, so give the token back.
+		r.CancelAt(now)
+		return delay, false
+	}
+
+	return 0, true
+}
+
+func (rl *RateLimiter) throttled(budget RateLimitBudget) {
+	if rl.metrics == nil {
+		return
+	}
+
+	rl.metrics.MustCounter(metricRateLimitThrottledRequests, metrics.NewLabel("budget", string(budget))).Inc()
+}
+
+// Middleware returns a middleware that enforces the limit for the given budget
+// on requests. It must run after authentication. If no limit is configured
+// for the budget, requests are passed through unmodified.
+func (rl *RateLimiter) Middleware(budget RateLimitBudget) mux.MiddlewareFunc {
+	return rl.RouteMiddleware(func(r *http.Request) RateLimitBudget { return budget })
+}
+
+// RouteMiddleware returns a middleware that enforces the limit for the budget
+// the given function selects for each request. It must run after
+// authentication. Requests in a budget without a configured limit are passed
+// through unmodified.
+func (rl *RateLimiter) RouteMiddleware(fn func(r *http.Request) RateLimitBudget) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			budget := fn(r)
+
+			limit, found := rl.limits[budget]
+			if !found {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			claims, ok := Claims(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := rateLimitKey{
+				budget: budget,
+				runID:  claims.RelayRunID,
+				name:   claims.RelayName,
+			}
+
+			if delay, ok := rl.reserve(key, limit); !ok {
+				rl.throttled(budget)
+
+				if delay > 0 {
+					w.Header().Set("Retry-After", strconv.FormatInt(int64(math.Ceil(delay.Seconds())), 10))
+				}
+
+				utilapi.WriteError(r.Context(), w, errors.NewAPIRateLimitError(string(budget)))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+type RateLimiterOption func(rl *RateLimiter)
+
+// RateLimiterWithLimit sets the limit for a budget. Budgets without a limit,
+// or with a limit that does not allow any requests per second, are not rate
+// limited.
+func RateLimiterWithLimit(budget RateLimitBudget, limit RateLimit) RateLimiterOption {
+	return func(rl *RateLimiter) {
+		if limit.PerSecond <= 0 {
+			delete(rl.limits, budget)
+			return
+		}
+
+		if limit.Burst < 1 {
+			limit.Burst = 1
+		}
+
+		rl.limits[budget] = limit
+	}
+}
+
+// RateLimiterWithMetrics reports the number of throttled requests to the given
+// metrics collector.
+func RateLimiterWithMetrics(mets *metrics.Metrics) RateLimiterOption {
+	return func(rl *RateLimiter) {
+		rl.metrics = mets
+	}
+}
+
+// RateLimiterWithClock overrides the function used to determine the current
+// time.
+func RateLimiterWithClock(now func() time.Time) RateLimiterOption {
+	return func(rl *RateLimiter) {
+		rl.now = now
+	}
+}
+
+func NewRateLimiter(opts ...RateLimiterOption) *RateLimiter {
+	rl := &RateLimiter{
+		limits:  make(map[RateLimitBudget]RateLimit),
+		now:     time.Now,
+		entries: make(map[rateLimitKey]*rateLimitEntry),
+	}
+
+	for _, opt := range opts {
+		opt(rl)
+	}
+
+	if rl.metrics != nil {
+		rl.metrics.MustRegisterCounter(metricRateLimitThrottledRequests, collectors.CounterOptions{
+			Description: "number of requests rejected because an action exceeded its rate limit",
+			Labels:      []string{"budget"},
+		})
+	}
+
+	return rl
+}
@@ -0,0 +1,62 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/puppetlabs/relay-core/pkg/authenticate"
+	"github.com/puppetlabs/relay-core/pkg/metadataapi/server/middleware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Date(2020, time.November, 1, 0, 0, 0, 0, time.UTC)
+
+	rl := middleware.NewRateLimiter(
+		middleware.RateLimiterWithClock(func() time.Time { return now }),
+		middleware.RateLimiterWithLimit(middleware.RateLimitBudgetSecrets, middleware.RateLimit{
+			PerSecond: 1,
+			Burst:     2,
+		}),
+	)
+
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	request := func(budget middleware.RateLimitBudget, runID, name string) *http.Response {
+		claims := &authenticate.Claims{
+			RelayRunID: runID,
+			RelayName:  name,
+		}
+
+		req, err := http.NewRequest(http.MethodGet, "/", nil)
+		require.NoError(t, err)
+
+		resp := httptest.NewRecorder()
+		middleware.WithClaims(claims)(rl.Middleware(budget)(h)).ServeHTTP(resp, req)
+		return resp.Result()
+	}
+
+	// Burst is allowed.
+	require.Equal(t, http.StatusOK, request(middleware.RateLimitBudgetSecrets, "run-1", "step-1").StatusCode)
+	require.Equal(t, http.StatusOK, request(middleware.RateLimitBudgetSecrets, "run-1", "step-1").StatusCode)
+
+	// Then the budget is exhausted.
+	resp := request(middleware.RateLimitBudgetSecrets, "run-1", "step-1")
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	require.Equal(t, "1", resp.Header.Get("Retry-After"))
+
+	// Other steps and budgets are not affected.
+	require.Equal(t, http.StatusOK, request(middleware.RateLimitBudgetSecrets, "run-1", "step-2").StatusCode)
+	require.Equal(t, http.StatusOK, request(middleware.RateLimitBudgetSecrets, "run-2", "step-1").StatusCode)
+	require.Equal(t, http.StatusOK, request(middleware.RateLimitBudgetOutputs, "run-1", "step-1").StatusCode)
+
+	// Rejected requests do not consume the budget, so the next token is
+	// available after one second.
+	now = now.Add(time.Second)
+	require.Equal(t, http.StatusOK, request(middleware.RateLimitBudgetSecrets, "run-1", "step-1").StatusCode)
+	require.Equal(t, http.StatusTooManyRequests, request(middleware.RateLimitBudgetSecrets, "run-1", "step-1").StatusCode)
+}
@@ -0,0 +1,24 @@
+package server
+
+import (
+	"net/http"
+
+	utilapi "github.com/puppetlabs/horsehead/v2/httputil/api"
+	"github.com/puppetlabs/relay-core/pkg/metadataapi/asset"
+	"github.com/puppetlabs/relay-core/pkg/metadataapi/errors"
+)
+
+func (*Server) GetOpenAPI(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	doc, err := asset.AssetString(asset.OpenAPIV1)
+	if err != nil {
+		utilapi.WriteError(ctx, w, errors.NewAPIObjectSerializationError().WithCause(err).Bug())
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	_, _ = w.Write([]byte(doc))
+}
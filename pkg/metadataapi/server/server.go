@@ -2,11 +2,13 @@ package server
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/puppetlabs/errawr-go/v2/pkg/errawr"
 	utilapi "github.com/puppetlabs/horsehead/v2/httputil/api"
 	"github.com/puppetlabs/horsehead/v2/instrumentation/alerts/trackers"
+	"github.com/puppetlabs/horsehead/v2/instrumentation/metrics"
 	"github.com/puppetlabs/relay-core/pkg/metadataapi/server/api"
 	"github.com/puppetlabs/relay-core/pkg/metadataapi/server/middleware"
 	"github.com/puppetlabs/relay-core/pkg/workflow/validation"
@@ -18,16 +20,30 @@ type Server struct {
 	capturer         trackers.Capturer
 	trustedProxyHops int
 	schemaRegistry   validation.SchemaRegistry
+	rateLimiter      *middleware.RateLimiter
+	metrics          *metrics.Metrics
+	now              func() time.Time
+	evaluationTrace  bool
 }
 
 func (s *Server) Route(r *mux.Router) {
 	r.Use(middleware.WithErrorSensitivity(s.errorSensitivity))
+	r.Use(middleware.WithTracing())
+	if s.metrics != nil {
+		r.Use(middleware.WithRequestMetrics(s.metrics))
+	}
 
 	r.HandleFunc("/healthz", s.GetHealthz).Methods("GET")
+	r.HandleFunc("/openapi.json", s.GetOpenAPI).Methods("GET")
 
 	// This has a different set of middleware so bind it under a subrouter.
-	api.NewServer(s.auth, api.WithSchemaRegistry(s.schemaRegistry)).
-		Route(r.NewRoute().Subrouter())
+	api.NewServer(
+		s.auth,
+		api.WithSchemaRegistry(s.schemaRegistry),
+		api.WithRateLimiter(s.rateLimiter),
+		api.WithClock(s.now),
+		api.WithEvaluationTrace(s.evaluationTrace),
+	).Route(r.NewRoute().Subrouter())
 }
 
 type Option func(s *Server)
@@ -56,10 +72,39 @@ func WithSchemaRegistry(r validation.SchemaRegistry) Option {
 	}
 }
 
+func WithRateLimiter(rl *middleware.RateLimiter) Option {
+	return func(s *Server) {
+		s.rateLimiter = rl
+	}
+}
+
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(s *Server) {
+		s.metrics = m
+	}
+}
+
+// WithClock uses the given function to determine the current time when
+// evaluating expressions instead of time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// WithEvaluationTrace allows clients to request a trace of the evaluation of
+// expressions using the debug query parameter.
+func WithEvaluationTrace(enabled bool) Option {
+	return func(s *Server) {
+		s.evaluationTrace = enabled
+	}
+}
+
 func new(auth middleware.Authenticator, opts ...Option) *Server {
 	s := &Server{
 		auth:             auth,
 		errorSensitivity: errawr.ErrorSensitivityNone,
+		now:              time.Now,
 	}
 
 	for _, opt := range opts {
@@ -4,6 +4,29 @@ import (
 	"context"
 )
 
+const (
+	// LogMediaTypeProtobuf is the media type of a single protobuf-encoded log
+	// service request or response.
+	LogMediaTypeProtobuf = "application/octet-stream"
+
+	// LogMediaTypeJSON is the media type of a single log service request or
+	// response encoded using the canonical protobuf JSON mapping.
+	LogMediaTypeJSON = "application/json"
+
+	// LogMediaTypeNDJSON is the media type of a batch of log service requests
+	// or responses, one JSON-encoded message per line.
+	LogMediaTypeNDJSON = "application/x-ndjson"
+)
+
+// LogRequest is an encoded request to the log service.
+type LogRequest struct {
+	// MediaType is the encoding of Data, one of the LogMediaType constants.
+	MediaType string
+
+	// Data is the encoded request.
+	Data []byte
+}
+
 type LogManager interface {
 	PostLog(ctx context.Context, value interface{}) ([]byte, error)
 	PostLogMessage(ctx context.Context, logID string, value interface{}) ([]byte, error)
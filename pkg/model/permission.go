@@ -0,0 +1,71 @@
+package model
+
+// ActionPermissionConnection grants access to a single connection.
+type ActionPermissionConnection struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// ActionPermissionOutput grants access to a single output of another step.
+type ActionPermissionOutput struct {
+	From string `json:"from"`
+	Name string `json:"name"`
+}
+
+// ActionPermissions restrict the data an action may retrieve. They are
+// computed from the references in the action's configuration when it is
+// created, so an action can only access what it was configured to use.
+type ActionPermissions struct {
+	Secrets     []string                     `json:"secrets,omitempty"`
+	Connections []ActionPermissionConnection `json:"connections,omitempty"`
+	Outputs     []ActionPermissionOutput     `json:"outputs,omitempty"`
+}
+
+// AllowsSecret returns true if the action may retrieve the secret with the
+// given name. A nil receiver allows access to any secret.
+func (ap *ActionPermissions) AllowsSecret(name string) bool {
+	if ap == nil {
+		return true
+	}
+
+	for _, candidate := range ap.Secrets {
+		if candidate == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AllowsConnection returns true if the action may retrieve the connection
+// with the given type and name. A nil receiver allows access to any
+// connection.
+func (ap *ActionPermissions) AllowsConnection(typ, name string) bool {
+	if ap == nil {
+		return true
+	}
+
+	for _, candidate := range ap.Connections {
+		if candidate.Type == typ && candidate.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AllowsOutput returns true if the action may retrieve the output with the
+// given name from the given step. A nil receiver allows access to any output.
+func (ap *ActionPermissions) AllowsOutput(from, name string) bool {
+	if ap == nil {
+		return true
+	}
+
+	for _, candidate := range ap.Outputs {
+		if candidate.From == from && candidate.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
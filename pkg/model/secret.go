@@ -8,4 +8,9 @@ type Secret struct {
 
 type SecretManager interface {
 	Get(ctx context.Context, name string) (*Secret, error)
+
+	// GetVersion retrieves a specific version of a secret. Versions are
+	// numbered starting from 1. Managers that do not keep previous versions
+	// of secrets return ErrNotFound.
+	GetVersion(ctx context.Context, name string, version int) (*Secret, error)
 }
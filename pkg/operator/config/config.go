@@ -5,6 +5,7 @@ import (
 
 	"github.com/puppetlabs/horsehead/v2/instrumentation/alerts"
 	"github.com/puppetlabs/horsehead/v2/instrumentation/alerts/trackers"
+	relayv1beta1 "github.com/puppetlabs/relay-core/pkg/apis/relay.sh/v1beta1"
 	"k8s.io/client-go/tools/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -12,19 +13,20 @@ import (
 // WorkflowControllerConfig is the configuration object used to
 // configure the Workflow controller.
 type WorkflowControllerConfig struct {
-	Environment             string
-	Standalone              bool
-	Namespace               string
-	ImagePullSecret         string
-	MaxConcurrentReconciles int
-	MetadataAPIURL          *url.URL
-	VaultTransitPath        string
-	VaultTransitKey         string
-	WebhookServerPort       int
-	WebhookServerKeyDir     string
-	DynamicRBACBinding      bool
-	ToolInjectionImage      string
-	AlertsDelegate          alerts.DelegateFunc
+	Environment               string
+	Standalone                bool
+	Namespace                 string
+	ImagePullSecret           string
+	MaxConcurrentReconciles   int
+	MetadataAPIURL            *url.URL
+	VaultTransitPath          string
+	VaultTransitKey           string
+	WebhookServerPort         int
+	WebhookServerKeyDir       string
+	DynamicRBACBinding        bool
+	ToolInjectionImage        string
+	DefaultSecretStoreBackend relayv1beta1.TenantSecretStoreBackend
+	AlertsDelegate            alerts.DelegateFunc
 }
 
 func (c *WorkflowControllerConfig) Capturer() trackers.Capturer {
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	nebulav1 "github.com/puppetlabs/relay-core/pkg/apis/nebula.puppet.com/v1"
+	"github.com/puppetlabs/relay-core/pkg/authenticate"
 	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
 	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	corev1 "k8s.io/api/core/v1"
@@ -67,7 +68,9 @@ func NewCondition(key client.ObjectKey) *Condition {
 }
 
 func ConfigureCondition(ctx context.Context, c *Condition, wrd *WorkflowRunDeps, ws *nebulav1.WorkflowStep) error {
-	if err := wrd.AnnotateStepToken(ctx, &c.Object.ObjectMeta, ws); err != nil {
+	// Condition pods can't mount a projected service account token, so they
+	// always authenticate by IP address.
+	if err := wrd.AnnotateStepToken(ctx, &c.Object.ObjectMeta, ws, authenticate.KubernetesAuthenticationMethodPodIP); err != nil {
 		return err
 	}
 
@@ -2,7 +2,10 @@ package obj
 
 import (
 	"context"
+	"sort"
 
+	"github.com/puppetlabs/relay-core/pkg/manager/secret"
+	"github.com/puppetlabs/relay-core/pkg/model"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -15,6 +18,7 @@ type Role struct {
 
 var _ Persister = &Role{}
 var _ Loader = &Role{}
+var _ Deleter = &Role{}
 var _ Ownable = &Role{}
 var _ LabelAnnotatableFrom = &Role{}
 
@@ -26,6 +30,10 @@ func (r *Role) Load(ctx context.Context, cl client.Client) (bool, error) {
 	return GetIgnoreNotFound(ctx, cl, r.Key, r.Object)
 }
 
+func (r *Role) Delete(ctx context.Context, cl client.Client) (bool, error) {
+	return DeleteIgnoreNotFound(ctx, cl, r.Object)
+}
+
 func (r *Role) Owned(ctx context.Context, owner Owner) error {
 	return Own(r.Object, owner)
 }
@@ -41,6 +49,42 @@ func NewRole(key client.ObjectKey) *Role {
 	}
 }
 
+// ConfigureMetadataAPIRoleForKubernetesSecretStore allows the metadata API to
+// read the Kubernetes secrets that hold a tenant's secrets and connections. If
+// perms is nil, any secret in the namespace may be read. Otherwise, only the
+// secrets and connections the permissions allow may be read. It must be
+// called after ConfigureMetadataAPIRole.
+func ConfigureMetadataAPIRoleForKubernetesSecretStore(role *Role, perms *model.ActionPermissions) {
+	rule := rbacv1.PolicyRule{
+		APIGroups: []string{""},
+		Resources: []string{"secrets"},
+		Verbs:     []string{"get"},
+	}
+
+	if perms != nil {
+		names := make(map[string]struct{})
+		for _, name := range perms.Secrets {
+			names[secret.SecretName(name)] = struct{}{}
+		}
+		for _, conn := range perms.Connections {
+			names[secret.ConnectionName(conn.Type, conn.Name)] = struct{}{}
+		}
+
+		if len(names) == 0 {
+			// A rule without resource names would allow access to every
+			// secret.
+			return
+		}
+
+		for name := range names {
+			rule.ResourceNames = append(rule.ResourceNames, name)
+		}
+		sort.Strings(rule.ResourceNames)
+	}
+
+	role.Object.Rules = append(role.Object.Rules, rule)
+}
+
 func ConfigureMetadataAPIRole(role *Role, immutableConfigMap, mutableConfigMap *ConfigMap) {
 	role.Object.Rules = []rbacv1.PolicyRule{
 		{
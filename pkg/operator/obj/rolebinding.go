@@ -14,6 +14,7 @@ type RoleBinding struct {
 
 var _ Persister = &RoleBinding{}
 var _ Loader = &RoleBinding{}
+var _ Deleter = &RoleBinding{}
 var _ Ownable = &RoleBinding{}
 
 func (rb *RoleBinding) Persist(ctx context.Context, cl client.Client) error {
@@ -24,6 +25,10 @@ func (rb *RoleBinding) Load(ctx context.Context, cl client.Client) (bool, error)
 	return GetIgnoreNotFound(ctx, cl, rb.Key, rb.Object)
 }
 
+func (rb *RoleBinding) Delete(ctx context.Context, cl client.Client) (bool, error) {
+	return DeleteIgnoreNotFound(ctx, cl, rb.Object)
+}
+
 func (rb *RoleBinding) Owned(ctx context.Context, owner Owner) error {
 	return Own(rb.Object, owner)
 }
@@ -5,6 +5,7 @@ import (
 	"path"
 
 	nebulav1 "github.com/puppetlabs/relay-core/pkg/apis/nebula.puppet.com/v1"
+	"github.com/puppetlabs/relay-core/pkg/authenticate"
 	"github.com/puppetlabs/relay-core/pkg/entrypoint"
 	"github.com/puppetlabs/relay-core/pkg/model"
 	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
@@ -12,6 +13,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	MetadataAPIServiceAccountTokenVolumeName        = "metadata-api-token"
+	MetadataAPIServiceAccountTokenMountPath         = "/var/run/secrets/relay.sh/metadata-api"
+	MetadataAPIServiceAccountTokenExpirationSeconds = 3600
+)
+
 type Task struct {
 	Key    client.ObjectKey
 	Object *tektonv1beta1.Task
@@ -137,7 +144,13 @@ func ConfigureTask(ctx context.Context, t *Task, wrd *WorkflowRunDeps, ws *nebul
 		}
 	}
 
-	if err := wrd.AnnotateStepToken(ctx, &t.Object.ObjectMeta, ws); err != nil {
+	method := authenticate.KubernetesAuthenticationMethodPodIP
+	if wrd.UseServiceAccountTokenAuthentication() {
+		method = authenticate.KubernetesAuthenticationMethodServiceAccountToken
+		ConfigureMetadataAPIServiceAccountToken(&t.Object.Spec.Volumes, &container)
+	}
+
+	if err := wrd.AnnotateStepToken(ctx, &t.Object.ObjectMeta, ws, method); err != nil {
 		return err
 	}
 
@@ -157,6 +170,49 @@ func ConfigureTask(ctx context.Context, t *Task, wrd *WorkflowRunDeps, ws *nebul
 	return nil
 }
 
+// ConfigureMetadataAPIServiceAccountToken projects a service account token
+// bound to the pod into the given container. The container can present the
+// token to the metadata API to authenticate.
+func ConfigureMetadataAPIServiceAccountToken(volumes *[]corev1.Volume, container *corev1.Container) {
+	found := false
+	for _, volume := range *volumes {
+		if volume.Name == MetadataAPIServiceAccountTokenVolumeName {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		*volumes = append(*volumes, corev1.Volume{
+			Name: MetadataAPIServiceAccountTokenVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Projected: &corev1.ProjectedVolumeSource{
+					Sources: []corev1.VolumeProjection{
+						{
+							ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+								Audience:          authenticate.MetadataAPIAudienceV1,
+								ExpirationSeconds: func(i int64) *int64 { return &i }(MetadataAPIServiceAccountTokenExpirationSeconds),
+								Path:              "token",
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      MetadataAPIServiceAccountTokenVolumeName,
+		ReadOnly:  true,
+		MountPath: MetadataAPIServiceAccountTokenMountPath,
+	})
+
+	container.Env = append(container.Env, corev1.EnvVar{
+		Name:  "METADATA_API_TOKEN_FILE",
+		Value: path.Join(MetadataAPIServiceAccountTokenMountPath, "token"),
+	})
+}
+
 type Tasks struct {
 	Deps *WorkflowRunDeps
 	List []*Task
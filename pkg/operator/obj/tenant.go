@@ -94,6 +94,23 @@ func (t *Tenant) Ready() bool {
 	return false
 }
 
+// SecretStoreBackend returns the backend that stores the secrets and
+// connections of this tenant, or the given default if the tenant does not
+// specify one.
+func (t *Tenant) SecretStoreBackend(def relayv1beta1.TenantSecretStoreBackend) relayv1beta1.TenantSecretStoreBackend {
+	if backend := t.Object.Spec.SecretStore.Backend; backend != "" {
+		return backend
+	}
+
+	return def
+}
+
+// SecretStoreVault returns the configuration for accessing the secrets and
+// connections of this tenant in Vault.
+func (t *Tenant) SecretStoreVault() relayv1beta1.TenantSecretStoreVault {
+	return t.Object.Spec.SecretStore.Vault
+}
+
 func NewTenant(key client.ObjectKey) *Tenant {
 	return &Tenant{
 		Key:    key,
@@ -36,6 +36,10 @@ type WebhookTriggerDeps struct {
 	Tenant         *Tenant
 	TenantDeps     *TenantDeps
 
+	// DefaultSecretStoreBackend is the backend used for secrets and
+	// connections if the tenant does not specify one.
+	DefaultSecretStoreBackend relayv1beta1.TenantSecretStoreBackend
+
 	// StaleOwnerConfigMap is a reference to a now-outdated stub object that
 	// needs to be cleaned up. It is set if the tenant is deleted or if the
 	// tenant namespace changes.
@@ -256,10 +260,20 @@ func (wtd *WebhookTriggerDeps) AnnotateTriggerToken(ctx context.Context, target
 	claims.RelayKubernetesImmutableConfigMapName = wtd.ImmutableConfigMap.Key.Name
 	claims.RelayKubernetesMutableConfigMapName = wtd.MutableConfigMap.Key.Name
 
-	claims.RelayVaultEnginePath = annotations[model.RelayVaultEngineMountAnnotation]
-	claims.RelayVaultSecretPath = annotations[model.RelayVaultSecretPathAnnotation]
-	claims.RelayVaultConnectionPath = annotations[model.RelayVaultConnectionPathAnnotation]
-	idh.Set("vault", claims.RelayVaultEnginePath, claims.RelayVaultSecretPath, claims.RelayVaultConnectionPath)
+	if wtd.UseKubernetesSecretStore() {
+		claims.RelayKubernetesSecretStoreNamespace = wtd.TenantDeps.Namespace.Name
+		idh.Set("secret-store", claims.RelayKubernetesSecretStoreNamespace)
+	} else {
+		claims.RelayVaultEnginePath = annotations[model.RelayVaultEngineMountAnnotation]
+		claims.RelayVaultSecretPath = annotations[model.RelayVaultSecretPathAnnotation]
+		claims.RelayVaultConnectionPath = annotations[model.RelayVaultConnectionPathAnnotation]
+
+		vcfg := wtd.Tenant.SecretStoreVault()
+		claims.RelayVaultNamespace = vcfg.Namespace
+		claims.RelayVaultEngineVersion = string(vcfg.KVVersion)
+
+		idh.Set("vault", claims.RelayVaultEnginePath, claims.RelayVaultSecretPath, claims.RelayVaultConnectionPath, claims.RelayVaultNamespace, claims.RelayVaultEngineVersion)
+	}
 
 	if sink := wtd.TenantDeps.APITriggerEventSink; sink != nil {
 		if u, _ := url.Parse(sink.URL()); u != nil {
@@ -285,10 +299,25 @@ func (wtd *WebhookTriggerDeps) AnnotateTriggerToken(ctx context.Context, target
 	return nil
 }
 
-func NewWebhookTriggerDeps(wt *WebhookTrigger, issuer authenticate.Issuer, metadataAPIURL *url.URL) *WebhookTriggerDeps {
+// UseKubernetesSecretStore determines whether the secrets and connections of
+// this webhook trigger are read from Kubernetes secrets in the tenant
+// namespace instead of Vault.
+func (wtd *WebhookTriggerDeps) UseKubernetesSecretStore() bool {
+	return wtd.Tenant.SecretStoreBackend(wtd.DefaultSecretStoreBackend) == relayv1beta1.TenantSecretStoreBackendKubernetes
+}
+
+type WebhookTriggerDepsOption func(wtd *WebhookTriggerDeps)
+
+func WebhookTriggerDepsWithDefaultSecretStoreBackend(backend relayv1beta1.TenantSecretStoreBackend) WebhookTriggerDepsOption {
+	return func(wtd *WebhookTriggerDeps) {
+		wtd.DefaultSecretStoreBackend = backend
+	}
+}
+
+func NewWebhookTriggerDeps(wt *WebhookTrigger, issuer authenticate.Issuer, metadataAPIURL *url.URL, opts ...WebhookTriggerDepsOption) *WebhookTriggerDeps {
 	key := wt.Key
 
-	return &WebhookTriggerDeps{
+	wtd := &WebhookTriggerDeps{
 		WebhookTrigger: wt,
 		Issuer:         issuer,
 
@@ -296,6 +325,12 @@ func NewWebhookTriggerDeps(wt *WebhookTrigger, issuer authenticate.Issuer, metad
 
 		MetadataAPIURL: metadataAPIURL,
 	}
+
+	for _, opt := range opts {
+		opt(wtd)
+	}
+
+	return wtd
 }
 
 func ConfigureWebhookTriggerDeps(ctx context.Context, wtd *WebhookTriggerDeps) error {
@@ -321,6 +356,11 @@ func ConfigureWebhookTriggerDeps(ctx context.Context, wtd *WebhookTriggerDeps) e
 
 	ConfigureMetadataAPIServiceAccount(wtd.MetadataAPIServiceAccount)
 	ConfigureMetadataAPIRole(wtd.MetadataAPIRole, wtd.ImmutableConfigMap, wtd.MutableConfigMap)
+	if wtd.UseKubernetesSecretStore() {
+		// Webhook triggers aren't restricted to particular secrets or
+		// connections.
+		ConfigureMetadataAPIRoleForKubernetesSecretStore(wtd.MetadataAPIRole, nil)
+	}
 	ConfigureMetadataAPIRoleBinding(wtd.MetadataAPIRoleBinding, wtd.MetadataAPIServiceAccount, wtd.MetadataAPIRole)
 
 	ConfigureUntrustedServiceAccount(wtd.KnativeServiceAccount)
@@ -328,8 +368,8 @@ func ConfigureWebhookTriggerDeps(ctx context.Context, wtd *WebhookTriggerDeps) e
 	return nil
 }
 
-func ApplyWebhookTriggerDeps(ctx context.Context, cl client.Client, wt *WebhookTrigger, issuer authenticate.Issuer, metadataAPIURL *url.URL) (*WebhookTriggerDeps, error) {
-	deps := NewWebhookTriggerDeps(wt, issuer, metadataAPIURL)
+func ApplyWebhookTriggerDeps(ctx context.Context, cl client.Client, wt *WebhookTrigger, issuer authenticate.Issuer, metadataAPIURL *url.URL, opts ...WebhookTriggerDepsOption) (*WebhookTriggerDeps, error) {
+	deps := NewWebhookTriggerDeps(wt, issuer, metadataAPIURL, opts...)
 
 	if loaded, err := deps.Load(ctx, cl); err != nil {
 		return nil, err
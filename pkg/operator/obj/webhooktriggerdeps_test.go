@@ -2,6 +2,7 @@ package obj_test
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	relayv1beta1 "github.com/puppetlabs/relay-core/pkg/apis/relay.sh/v1beta1"
@@ -85,5 +86,52 @@ func TestWebhookTriggerDepsConfigureAnnotate(t *testing.T) {
 		tok3 := md.GetAnnotations()[authenticate.KubernetesTokenAnnotation]
 		require.NotEmpty(t, tok3)
 		require.NotEqual(t, tok1, tok3)
+
+		// Move the secrets of the tenant to a KV V1 engine in a Vault
+		// namespace; reissue.
+		tenant := obj.NewTenant(client.ObjectKey{
+			Namespace: namespace.Name,
+			Name:      "my-test-tenant",
+		})
+
+		ok, err = tenant.Load(ctx, cl)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		tenant.Object.Spec.SecretStore.Vault = relayv1beta1.TenantSecretStoreVault{
+			Namespace: "my-test-namespace",
+			KVVersion: relayv1beta1.TenantSecretStoreVaultKVVersion1,
+		}
+		require.NoError(t, tenant.Persist(ctx, cl))
+
+		deps, err = obj.ApplyWebhookTriggerDeps(ctx, cl, trigger, TestIssuer, TestMetadataAPIURL)
+		require.NoError(t, err)
+
+		require.NoError(t, deps.AnnotateTriggerToken(ctx, &md))
+
+		tokVault := md.GetAnnotations()[authenticate.KubernetesTokenAnnotation]
+		require.NotEqual(t, tok3, tokVault)
+
+		var claims authenticate.Claims
+		require.NoError(t, json.Unmarshal([]byte(tokVault), &claims))
+		require.Equal(t, "my-test-namespace", claims.RelayVaultNamespace)
+		require.Equal(t, "v1", claims.RelayVaultEngineVersion)
+
+		// Move the secrets of the tenant to Kubernetes; reissue.
+		tenant.Object.Spec.SecretStore.Backend = relayv1beta1.TenantSecretStoreBackendKubernetes
+		require.NoError(t, tenant.Persist(ctx, cl))
+
+		deps, err = obj.ApplyWebhookTriggerDeps(ctx, cl, trigger, TestIssuer, TestMetadataAPIURL)
+		require.NoError(t, err)
+
+		require.NoError(t, deps.AnnotateTriggerToken(ctx, &md))
+
+		tok4 := md.GetAnnotations()[authenticate.KubernetesTokenAnnotation]
+		require.NotEqual(t, tokVault, tok4)
+
+		claims = authenticate.Claims{}
+		require.NoError(t, json.Unmarshal([]byte(tok4), &claims))
+		require.Equal(t, deps.TenantDeps.Namespace.Name, claims.RelayKubernetesSecretStoreNamespace)
+		require.Empty(t, claims.RelayVaultEnginePath)
 	})
 }
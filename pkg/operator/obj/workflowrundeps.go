@@ -7,7 +7,11 @@ import (
 	"time"
 
 	nebulav1 "github.com/puppetlabs/relay-core/pkg/apis/nebula.puppet.com/v1"
+	relayv1beta1 "github.com/puppetlabs/relay-core/pkg/apis/relay.sh/v1beta1"
 	"github.com/puppetlabs/relay-core/pkg/authenticate"
+	"github.com/puppetlabs/relay-core/pkg/errmark"
+	"github.com/puppetlabs/relay-core/pkg/expr/evaluate"
+	exprmodel "github.com/puppetlabs/relay-core/pkg/expr/model"
 	"github.com/puppetlabs/relay-core/pkg/model"
 	"gopkg.in/square/go-jose.v2/jwt"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -21,6 +25,14 @@ type WorkflowRunDeps struct {
 
 	Namespace *Namespace
 
+	// Tenant is the tenant the workflow run belongs to, if any.
+	Tenant     *Tenant
+	TenantDeps *TenantDeps
+
+	// DefaultSecretStoreBackend is the backend used for secrets and
+	// connections if the tenant does not specify one.
+	DefaultSecretStoreBackend relayv1beta1.TenantSecretStoreBackend
+
 	// TODO: This belongs at the Tenant as it should apply to the whole
 	// namespace.
 	LimitRange *LimitRange
@@ -35,6 +47,12 @@ type WorkflowRunDeps struct {
 	MetadataAPIRole           *Role
 	MetadataAPIRoleBinding    *RoleBinding
 
+	// SecretStoreRole and SecretStoreRoleBinding allow the metadata API to read
+	// the tenant's Kubernetes secrets when they are not in the namespace of
+	// the workflow run.
+	SecretStoreRole        *Role
+	SecretStoreRoleBinding *RoleBinding
+
 	PipelineServiceAccount  *ServiceAccount
 	UntrustedServiceAccount *ServiceAccount
 }
@@ -55,6 +73,16 @@ func (wrd *WorkflowRunDeps) Persist(ctx context.Context, cl client.Client) error
 		wrd.UntrustedServiceAccount,
 	}
 
+	if wrd.WorkflowRun.Object.Status.CompletionTime != nil {
+		// The run no longer needs to read secrets, so don't grant access to
+		// them again once it has been removed.
+		if _, err := wrd.DeleteSecretStoreAccess(ctx, cl); err != nil {
+			return err
+		}
+	} else {
+		ps = append(ps, IgnoreNilPersister{wrd.SecretStoreRole}, IgnoreNilPersister{wrd.SecretStoreRoleBinding})
+	}
+
 	for _, p := range ps {
 		if err := p.Persist(ctx, cl); err != nil {
 			return err
@@ -65,8 +93,34 @@ func (wrd *WorkflowRunDeps) Persist(ctx context.Context, cl client.Client) error
 }
 
 func (wrd *WorkflowRunDeps) Load(ctx context.Context, cl client.Client) (bool, error) {
-	return Loaders{
-		RequiredLoader{wrd.Namespace},
+	if _, err := (RequiredLoader{wrd.Namespace}).Load(ctx, cl); err != nil {
+		return false, err
+	}
+
+	// Load the tenant first so that we know which namespace its secrets are
+	// stored in.
+	all := true
+	if wrd.Tenant != nil {
+		if ok, err := wrd.Tenant.Load(ctx, cl); err != nil {
+			return false, err
+		} else if ok {
+			wrd.TenantDeps = NewTenantDeps(wrd.Tenant)
+		} else {
+			all = false
+		}
+	}
+
+	var loaders Loaders
+	if ns := wrd.SecretStoreNamespace(); wrd.UseKubernetesSecretStore() && ns != wrd.Namespace.Name {
+		key := SuffixObjectKey(client.ObjectKey{Namespace: ns, Name: wrd.WorkflowRun.Key.Name}, "secret-store")
+
+		wrd.SecretStoreRole = NewRole(key)
+		wrd.SecretStoreRoleBinding = NewRoleBinding(key)
+
+		loaders = append(loaders, wrd.TenantDeps.Namespace, wrd.SecretStoreRole, wrd.SecretStoreRoleBinding)
+	}
+
+	ok, err := append(loaders,
 		IgnoreNilLoader{wrd.LimitRange},
 		IgnoreNilLoader{wrd.NetworkPolicy},
 		wrd.ImmutableConfigMap,
@@ -76,10 +130,47 @@ func (wrd *WorkflowRunDeps) Load(ctx context.Context, cl client.Client) (bool, e
 		wrd.MetadataAPIRoleBinding,
 		wrd.PipelineServiceAccount,
 		wrd.UntrustedServiceAccount,
-	}.Load(ctx, cl)
+	).Load(ctx, cl)
+	if err != nil {
+		return false, err
+	}
+
+	return all && ok, nil
 }
 
-func (wrd *WorkflowRunDeps) AnnotateStepToken(ctx context.Context, target *metav1.ObjectMeta, ws *nebulav1.WorkflowStep) error {
+// DeleteSecretStoreAccess removes the role and role binding that allow the
+// metadata API to read the tenant's secrets from another namespace. They can't
+// be owned by the workflow run, so they aren't garbage collected with it.
+func (wrd *WorkflowRunDeps) DeleteSecretStoreAccess(ctx context.Context, cl client.Client) (bool, error) {
+	owner := Owner{Object: wrd.WorkflowRun.Object, GVK: WorkflowRunKind}
+
+	if rb := wrd.SecretStoreRoleBinding; rb != nil && rb.Object.GetUID() != "" {
+		if ok, err := IsDependencyOf(rb.Object.ObjectMeta, owner); err != nil {
+			return false, err
+		} else if ok {
+			if _, err := rb.Delete(ctx, cl); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if r := wrd.SecretStoreRole; r != nil && r.Object.GetUID() != "" {
+		if ok, err := IsDependencyOf(r.Object.ObjectMeta, owner); err != nil {
+			return false, err
+		} else if ok {
+			if _, err := r.Delete(ctx, cl); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// AnnotateStepToken issues a token for the given step and annotates the target
+// with it. The pods created from the target must identify themselves to the
+// metadata API using the given authentication method.
+func (wrd *WorkflowRunDeps) AnnotateStepToken(ctx context.Context, target *metav1.ObjectMeta, ws *nebulav1.WorkflowStep, method authenticate.KubernetesAuthenticationMethod) error {
 	if _, found := target.Annotations[authenticate.KubernetesTokenAnnotation]; found {
 		// We only add this once and exactly once per run per target.
 		return nil
@@ -88,6 +179,11 @@ func (wrd *WorkflowRunDeps) AnnotateStepToken(ctx context.Context, target *metav
 	ms := ModelStep(wrd.WorkflowRun, ws)
 	now := time.Now()
 
+	perms, err := ModelStepPermissions(ctx, ws)
+	if err != nil {
+		return err
+	}
+
 	sat, err := wrd.MetadataAPIServiceAccount.DefaultTokenSecret.Token()
 	if err != nil {
 		return err
@@ -105,9 +201,10 @@ func (wrd *WorkflowRunDeps) AnnotateStepToken(ctx context.Context, target *metav
 			IssuedAt:  jwt.NewNumericDate(now),
 		},
 
-		KubernetesNamespaceName:       wrd.Namespace.Name,
-		KubernetesNamespaceUID:        string(wrd.Namespace.Object.GetUID()),
-		KubernetesServiceAccountToken: sat,
+		KubernetesNamespaceName:        wrd.Namespace.Name,
+		KubernetesNamespaceUID:         string(wrd.Namespace.Object.GetUID()),
+		KubernetesServiceAccountToken:  sat,
+		KubernetesAuthenticationMethod: method,
 
 		RelayDomainID: annotations[model.RelayDomainIDAnnotation],
 		RelayTenantID: annotations[model.RelayTenantIDAnnotation],
@@ -117,9 +214,19 @@ func (wrd *WorkflowRunDeps) AnnotateStepToken(ctx context.Context, target *metav
 		RelayKubernetesImmutableConfigMapName: wrd.ImmutableConfigMap.Key.Name,
 		RelayKubernetesMutableConfigMapName:   wrd.MutableConfigMap.Key.Name,
 
-		RelayVaultEnginePath:     annotations[model.RelayVaultEngineMountAnnotation],
-		RelayVaultSecretPath:     annotations[model.RelayVaultSecretPathAnnotation],
-		RelayVaultConnectionPath: annotations[model.RelayVaultConnectionPathAnnotation],
+		RelayPermissions: perms,
+	}
+
+	if wrd.UseKubernetesSecretStore() {
+		claims.RelayKubernetesSecretStoreNamespace = wrd.SecretStoreNamespace()
+	} else {
+		claims.RelayVaultEnginePath = annotations[model.RelayVaultEngineMountAnnotation]
+		claims.RelayVaultSecretPath = annotations[model.RelayVaultSecretPathAnnotation]
+		claims.RelayVaultConnectionPath = annotations[model.RelayVaultConnectionPathAnnotation]
+
+		vcfg := wrd.SecretStoreVault()
+		claims.RelayVaultNamespace = vcfg.Namespace
+		claims.RelayVaultEngineVersion = string(vcfg.KVVersion)
 	}
 
 	tok, err := wrd.Issuer.Issue(ctx, claims)
@@ -133,8 +240,125 @@ func (wrd *WorkflowRunDeps) AnnotateStepToken(ctx context.Context, target *metav
 	return nil
 }
 
+// ModelStepPermissions computes the least-privilege permissions for a step
+// from the secrets, connections, and outputs referenced in its spec,
+// environment, and conditions. If the step requests dynamic access, no
+// permissions are returned and the step is unrestricted.
+func ModelStepPermissions(ctx context.Context, ws *nebulav1.WorkflowStep) (*model.ActionPermissions, error) {
+	if ws.DynamicAccess {
+		return nil, nil
+	}
+
+	// Evaluating inertly reports references in every branch of a condition,
+	// since we can't know which one the step will take.
+	ev := evaluate.NewEvaluator(evaluate.WithInert(true))
+
+	var values []interface{}
+	if len(ws.Spec) > 0 {
+		values = append(values, ws.Spec.Value())
+	}
+	for _, value := range ws.Env.Value() {
+		values = append(values, value)
+	}
+	if when := ws.When.Value(); when != nil {
+		values = append(values, when)
+	}
+
+	var ur exprmodel.Unresolvable
+	for _, value := range values {
+		r, err := ev.EvaluateAll(ctx, value)
+		if err != nil {
+			return nil, errmark.MarkUser(err)
+		}
+
+		ur.Extends(r.Unresolvable)
+	}
+
+	perms := &model.ActionPermissions{}
+	for _, s := range ur.Secrets {
+		perms.Secrets = append(perms.Secrets, s.Name)
+	}
+	for _, c := range ur.Connections {
+		perms.Connections = append(perms.Connections, model.ActionPermissionConnection{Type: c.Type, Name: c.Name})
+	}
+	for _, o := range ur.Outputs {
+		perms.Outputs = append(perms.Outputs, model.ActionPermissionOutput{From: o.From, Name: o.Name})
+	}
+
+	return perms, nil
+}
+
+// ModelWorkflowRunPermissions combines the permissions of every step in a
+// workflow run. If any step requests dynamic access, no permissions are
+// returned and the run is unrestricted.
+func ModelWorkflowRunPermissions(ctx context.Context, wr *WorkflowRun) (*model.ActionPermissions, error) {
+	perms := &model.ActionPermissions{}
+
+	for _, ws := range wr.Object.Spec.Workflow.Steps {
+		sp, err := ModelStepPermissions(ctx, ws)
+		if err != nil {
+			return nil, err
+		} else if sp == nil {
+			return nil, nil
+		}
+
+		perms.Secrets = append(perms.Secrets, sp.Secrets...)
+		perms.Connections = append(perms.Connections, sp.Connections...)
+		perms.Outputs = append(perms.Outputs, sp.Outputs...)
+	}
+
+	return perms, nil
+}
+
+// UseServiceAccountTokenAuthentication determines whether the pods of this
+// workflow run should authenticate to the metadata API using a projected
+// service account token instead of their IP address.
+func (wrd *WorkflowRunDeps) UseServiceAccountTokenAuthentication() bool {
+	return wrd.Tenant != nil && wrd.Tenant.Object.Spec.Authentication.Method == relayv1beta1.TenantAuthenticationMethodServiceAccountToken
+}
+
+// UseKubernetesSecretStore determines whether the secrets and connections of
+// this workflow run are read from Kubernetes secrets in its tenant namespace
+// instead of Vault.
+func (wrd *WorkflowRunDeps) UseKubernetesSecretStore() bool {
+	backend := wrd.DefaultSecretStoreBackend
+	if wrd.Tenant != nil {
+		backend = wrd.Tenant.SecretStoreBackend(backend)
+	}
+
+	return backend == relayv1beta1.TenantSecretStoreBackendKubernetes
+}
+
+// SecretStoreNamespace returns the namespace that holds the Kubernetes secrets
+// of this workflow run. This is the tenant namespace, which is shared with the
+// webhook triggers of the tenant, or the namespace of the workflow run if it
+// has no tenant.
+func (wrd *WorkflowRunDeps) SecretStoreNamespace() string {
+	if wrd.TenantDeps == nil {
+		return wrd.Namespace.Name
+	}
+
+	return wrd.TenantDeps.Namespace.Name
+}
+
+// SecretStoreVault returns the configuration for accessing secrets and
+// connections in Vault for this workflow run.
+func (wrd *WorkflowRunDeps) SecretStoreVault() relayv1beta1.TenantSecretStoreVault {
+	if wrd.Tenant == nil {
+		return relayv1beta1.TenantSecretStoreVault{}
+	}
+
+	return wrd.Tenant.SecretStoreVault()
+}
+
 type WorkflowRunDepsOption func(wrd *WorkflowRunDeps)
 
+func WorkflowRunDepsWithDefaultSecretStoreBackend(backend relayv1beta1.TenantSecretStoreBackend) WorkflowRunDepsOption {
+	return func(wrd *WorkflowRunDeps) {
+		wrd.DefaultSecretStoreBackend = backend
+	}
+}
+
 func WorkflowRunDepsWithStandaloneMode(standalone bool) WorkflowRunDepsOption {
 	return func(wrd *WorkflowRunDeps) {
 		if standalone {
@@ -169,6 +393,10 @@ func NewWorkflowRunDeps(wr *WorkflowRun, issuer authenticate.Issuer, metadataAPI
 		UntrustedServiceAccount: NewServiceAccount(SuffixObjectKey(key, "untrusted")),
 	}
 
+	if ref := wr.Object.Spec.TenantRef; ref != nil {
+		wrd.Tenant = NewTenant(client.ObjectKey{Namespace: key.Namespace, Name: ref.Name})
+	}
+
 	for _, opt := range opts {
 		opt(wrd)
 	}
@@ -233,6 +461,32 @@ func ConfigureWorkflowRunDeps(ctx context.Context, wrd *WorkflowRunDeps) error {
 
 	ConfigureMetadataAPIServiceAccount(wrd.MetadataAPIServiceAccount)
 	ConfigureMetadataAPIRole(wrd.MetadataAPIRole, wrd.ImmutableConfigMap, wrd.MutableConfigMap)
+	if wrd.UseKubernetesSecretStore() {
+		perms, err := ModelWorkflowRunPermissions(ctx, wrd.WorkflowRun)
+		if err != nil {
+			return err
+		}
+
+		if wrd.SecretStoreRole == nil {
+			ConfigureMetadataAPIRoleForKubernetesSecretStore(wrd.MetadataAPIRole, perms)
+		} else {
+			wrd.SecretStoreRole.LabelAnnotateFrom(ctx, wrd.WorkflowRun.Object.ObjectMeta)
+
+			// Owner references can't cross namespaces, so we track the
+			// workflow run with an annotation instead.
+			owner := Owner{Object: wrd.WorkflowRun.Object, GVK: WorkflowRunKind}
+			if err := SetDependencyOf(&wrd.SecretStoreRole.Object.ObjectMeta, owner); err != nil {
+				return err
+			}
+			if err := SetDependencyOf(&wrd.SecretStoreRoleBinding.Object.ObjectMeta, owner); err != nil {
+				return err
+			}
+
+			wrd.SecretStoreRole.Object.Rules = nil
+			ConfigureMetadataAPIRoleForKubernetesSecretStore(wrd.SecretStoreRole, perms)
+			ConfigureMetadataAPIRoleBinding(wrd.SecretStoreRoleBinding, wrd.MetadataAPIServiceAccount, wrd.SecretStoreRole)
+		}
+	}
 	ConfigureMetadataAPIRoleBinding(wrd.MetadataAPIRoleBinding, wrd.MetadataAPIServiceAccount, wrd.MetadataAPIRole)
 	ConfigureUntrustedServiceAccount(wrd.PipelineServiceAccount)
 	ConfigureUntrustedServiceAccount(wrd.UntrustedServiceAccount)
@@ -7,13 +7,19 @@ import (
 	"testing"
 
 	nebulav1 "github.com/puppetlabs/relay-core/pkg/apis/nebula.puppet.com/v1"
+	relayv1beta1 "github.com/puppetlabs/relay-core/pkg/apis/relay.sh/v1beta1"
 	"github.com/puppetlabs/relay-core/pkg/authenticate"
+	"github.com/puppetlabs/relay-core/pkg/manager/secret"
+	"github.com/puppetlabs/relay-core/pkg/model"
 	"github.com/puppetlabs/relay-core/pkg/operator/obj"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/square/go-jose.v2/jwt"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -35,6 +41,13 @@ func TestWorkflowRunDepsConfigureAnnotate(t *testing.T) {
 					Steps: []*nebulav1.WorkflowStep{
 						{
 							Name: "my-test-step",
+							Spec: relayv1beta1.NewUnstructuredObject(map[string]interface{}{
+								"password": map[string]interface{}{"$type": "Secret", "name": "password"},
+								"aws":      map[string]interface{}{"$type": "Connection", "type": "aws", "name": "my-aws"},
+							}),
+							Env: relayv1beta1.NewUnstructuredObject(map[string]interface{}{
+								"PREVIOUS": map[string]interface{}{"$type": "Output", "from": "my-previous-step", "name": "result"},
+							}),
 						},
 					},
 				},
@@ -56,7 +69,7 @@ func TestWorkflowRunDepsConfigureAnnotate(t *testing.T) {
 		ws := run.Object.Spec.Workflow.Steps[0]
 
 		var md metav1.ObjectMeta
-		require.NoError(t, deps.AnnotateStepToken(ctx, &md, ws))
+		require.NoError(t, deps.AnnotateStepToken(ctx, &md, ws, authenticate.KubernetesAuthenticationMethodPodIP))
 
 		tok := md.GetAnnotations()[authenticate.KubernetesTokenAnnotation]
 		require.NotEmpty(t, tok)
@@ -77,9 +90,183 @@ func TestWorkflowRunDepsConfigureAnnotate(t *testing.T) {
 		assert.Equal(t, namespace.Name, claims.KubernetesNamespaceName)
 		assert.Equal(t, string(namespace.Object.GetUID()), claims.KubernetesNamespaceUID)
 		assert.Equal(t, sat, claims.KubernetesServiceAccountToken)
+		assert.Equal(t, authenticate.KubernetesAuthenticationMethodPodIP, claims.KubernetesAuthenticationMethod)
 		assert.Equal(t, run.Object.Spec.Name, claims.RelayRunID)
 		assert.Equal(t, ws.Name, claims.RelayName)
 		assert.Equal(t, deps.ImmutableConfigMap.Key.Name, claims.RelayKubernetesImmutableConfigMapName)
 		assert.Equal(t, deps.MutableConfigMap.Key.Name, claims.RelayKubernetesMutableConfigMapName)
+		assert.Equal(t, &model.ActionPermissions{
+			Secrets:     []string{"password"},
+			Connections: []model.ActionPermissionConnection{{Type: "aws", Name: "my-aws"}},
+			Outputs:     []model.ActionPermissionOutput{{From: "my-previous-step", Name: "result"}},
+		}, claims.RelayPermissions)
 	})
 }
+
+func TestWorkflowRunDepsKubernetesSecretStoreSharedWithWebhookTrigger(t *testing.T) {
+	ctx := context.Background()
+
+	WithTestNamespace(t, ctx, func(namespace *obj.Namespace) {
+		cl := Client(t)
+
+		require.NoError(t, cl.Create(ctx, &relayv1beta1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-test-tenant",
+				Namespace: namespace.Name,
+			},
+			Spec: relayv1beta1.TenantSpec{
+				NamespaceTemplate: relayv1beta1.NamespaceTemplate{
+					Metadata: metav1.ObjectMeta{
+						Name: namespace.Name + "-tenant",
+					},
+				},
+				SecretStore: relayv1beta1.TenantSecretStore{
+					Backend: relayv1beta1.TenantSecretStoreBackendKubernetes,
+				},
+			},
+		}))
+
+		tenant := obj.NewTenant(client.ObjectKey{
+			Namespace: namespace.Name,
+			Name:      "my-test-tenant",
+		})
+
+		ok, err := tenant.Load(ctx, cl)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		td, err := obj.ApplyTenantDeps(ctx, cl, tenant)
+		require.NoError(t, err)
+		defer td.Delete(ctx, cl)
+
+		require.NoError(t, cl.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   td.Namespace.Name,
+				Name:        secret.SecretName("password"),
+				Labels:      map[string]string{secret.TypeLabel: secret.TypeSecret},
+				Annotations: map[string]string{secret.NameAnnotation: "password"},
+			},
+			Data: map[string][]byte{secret.ValueKey: []byte("hunter2")},
+		}))
+
+		require.NoError(t, cl.Create(ctx, &nebulav1.WorkflowRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-test-run",
+				Namespace: namespace.Name,
+			},
+			Spec: nebulav1.WorkflowRunSpec{
+				Name: "my-workflow-run-1234",
+				TenantRef: &corev1.LocalObjectReference{
+					Name: "my-test-tenant",
+				},
+				Workflow: nebulav1.Workflow{
+					Name: "my-workflow",
+					Steps: []*nebulav1.WorkflowStep{
+						{
+							Name: "my-test-step",
+							Spec: relayv1beta1.NewUnstructuredObject(map[string]interface{}{
+								"password": map[string]interface{}{"$type": "Secret", "name": "password"},
+							}),
+						},
+					},
+				},
+			},
+		}))
+
+		require.NoError(t, cl.Create(ctx, &relayv1beta1.WebhookTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-test-trigger",
+				Namespace: namespace.Name,
+			},
+			Spec: relayv1beta1.WebhookTriggerSpec{
+				TenantRef: corev1.LocalObjectReference{
+					Name: "my-test-tenant",
+				},
+				Name:  "hello-world",
+				Image: "alpine:latest",
+			},
+		}))
+
+		run := obj.NewWorkflowRun(client.ObjectKey{
+			Namespace: namespace.Name,
+			Name:      "my-test-run",
+		})
+
+		ok, err = run.Load(ctx, cl)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		runDeps, err := obj.ApplyWorkflowRunDeps(ctx, cl, run, TestIssuer, TestMetadataAPIURL)
+		require.NoError(t, err)
+
+		var runMD metav1.ObjectMeta
+		require.NoError(t, runDeps.AnnotateStepToken(ctx, &runMD, run.Object.Spec.Workflow.Steps[0], authenticate.KubernetesAuthenticationMethodPodIP))
+
+		trigger := obj.NewWebhookTrigger(client.ObjectKey{
+			Namespace: namespace.Name,
+			Name:      "my-test-trigger",
+		})
+
+		ok, err = trigger.Load(ctx, cl)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		triggerDeps, err := obj.ApplyWebhookTriggerDeps(ctx, cl, trigger, TestIssuer, TestMetadataAPIURL)
+		require.NoError(t, err)
+
+		var triggerMD metav1.ObjectMeta
+		require.NoError(t, triggerDeps.AnnotateTriggerToken(ctx, &triggerMD))
+
+		for _, md := range []metav1.ObjectMeta{runMD, triggerMD} {
+			var claims authenticate.Claims
+			require.NoError(t, json.Unmarshal([]byte(md.GetAnnotations()[authenticate.KubernetesTokenAnnotation]), &claims))
+			require.Equal(t, td.Namespace.Name, claims.RelayKubernetesSecretStoreNamespace)
+
+			// Read the secret the same way the metadata API does, as the
+			// service account in the token.
+			cfg := rest.AnonymousClientConfig(e2e.RESTConfig)
+			cfg.BearerToken = claims.KubernetesServiceAccountToken
+
+			kc, err := kubernetes.NewForConfig(cfg)
+			require.NoError(t, err)
+
+			sec, err := secret.NewSecretManager(kc, claims.RelayKubernetesSecretStoreNamespace).Get(ctx, "password")
+			require.NoError(t, err)
+			assert.Equal(t, "hunter2", sec.Value)
+		}
+	})
+}
+
+func TestModelStepPermissionsConditionalBranches(t *testing.T) {
+	ctx := context.Background()
+
+	// Both branches must be permitted, since which one the step takes isn't
+	// known until it runs.
+	perms, err := obj.ModelStepPermissions(ctx, &nebulav1.WorkflowStep{
+		Name: "my-test-step",
+		Spec: relayv1beta1.NewUnstructuredObject(map[string]interface{}{
+			"password": map[string]interface{}{
+				"$fn.if": []interface{}{
+					map[string]interface{}{"$fn.equals": []interface{}{
+						map[string]interface{}{"$type": "Output", "from": "my-previous-step", "name": "env"},
+						"production",
+					}},
+					map[string]interface{}{"$type": "Secret", "name": "production-password"},
+					map[string]interface{}{"$type": "Secret", "name": "staging-password"},
+				},
+			},
+			"aws": map[string]interface{}{
+				"$fn.or": []interface{}{
+					true,
+					map[string]interface{}{"$type": "Connection", "type": "aws", "name": "my-aws"},
+				},
+			},
+		}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &model.ActionPermissions{
+		Secrets:     []string{"production-password", "staging-password"},
+		Connections: []model.ActionPermissionConnection{{Type: "aws", Name: "my-aws"}},
+		Outputs:     []model.ActionPermissionOutput{{From: "my-previous-step", Name: "env"}},
+	}, perms)
+}
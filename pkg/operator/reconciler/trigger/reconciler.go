@@ -66,7 +66,12 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (result ctrl.Result, err error)
 		return ctrl.Result{}, nil
 	}
 
-	deps := obj.NewWebhookTriggerDeps(wt, r.issuer, r.Config.MetadataAPIURL)
+	deps := obj.NewWebhookTriggerDeps(
+		wt,
+		r.issuer,
+		r.Config.MetadataAPIURL,
+		obj.WebhookTriggerDepsWithDefaultSecretStoreBackend(r.Config.DefaultSecretStoreBackend),
+	)
 	loaded, err := deps.Load(ctx, r.Client)
 	if err != nil {
 		return ctrl.Result{}, errmark.MapLast(err, func(err error) error {
@@ -8,6 +8,8 @@ import (
 	"github.com/puppetlabs/horsehead/v2/storage"
 	"github.com/puppetlabs/relay-core/pkg/authenticate"
 	"github.com/puppetlabs/relay-core/pkg/errmark"
+	"github.com/puppetlabs/relay-core/pkg/manager/configmap"
+	"github.com/puppetlabs/relay-core/pkg/manager/vault"
 	"github.com/puppetlabs/relay-core/pkg/operator/dependency"
 	"github.com/puppetlabs/relay-core/pkg/operator/obj"
 	corev1 "k8s.io/api/core/v1"
@@ -80,17 +82,19 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (result ctrl.Result, err error)
 		return ctrl.Result{}, nil
 	}
 
+	var deps *obj.WorkflowRunDeps
 	var pr *obj.PipelineRun
 	err = r.metrics.trackDurationWithOutcome(metricWorkflowRunStartUpDuration, func() error {
 		// Configure and save all the infrastructure bits needed to create a
 		// Pipeline.
-		deps, err := obj.ApplyWorkflowRunDeps(
+		deps, err = obj.ApplyWorkflowRunDeps(
 			ctx,
 			r.Client,
 			wr,
 			r.issuer,
 			r.Config.MetadataAPIURL,
 			obj.WorkflowRunDepsWithStandaloneMode(r.standalone),
+			obj.WorkflowRunDepsWithDefaultSecretStoreBackend(r.Config.DefaultSecretStoreBackend),
 		)
 
 		if err != nil {
@@ -139,9 +143,45 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (result ctrl.Result, err error)
 		})
 	}
 
+	if wr.Object.Status.CompletionTime != nil {
+		if err := r.revokeLeases(ctx, deps); err != nil {
+			return ctrl.Result{}, errmark.MapLast(err, func(err error) error {
+				return fmt.Errorf("failed to revoke leases: %+v", err)
+			})
+		}
+
+		if _, err := deps.DeleteSecretStoreAccess(ctx, r.Client); err != nil {
+			return ctrl.Result{}, errmark.MapLast(err, func(err error) error {
+				return fmt.Errorf("failed to delete secret store access: %+v", err)
+			})
+		}
+	}
+
 	return ctrl.Result{}, nil
 }
 
+// revokeLeases revokes the leases on the credentials the metadata API issued
+// to the steps of a completed run. Leases are forgotten once they have been
+// revoked, so this is a no-op for subsequent reconciliations.
+func (r *Reconciler) revokeLeases(ctx context.Context, deps *obj.WorkflowRunDeps) error {
+	lm := configmap.NewLeaseManager(configmap.NewControllerRuntimeConfigMap(r.Client, deps.MutableConfigMap.Key))
+
+	leases, err := lm.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, lease := range leases {
+		klog.Infof("WorkflowRun %s is complete, revoking lease %s", deps.WorkflowRun.Key, lease.ID)
+
+		if err := vault.RevokeLease(ctx, r.VaultClient, lease); err != nil {
+			return err
+		}
+	}
+
+	return lm.Remove(ctx, leases...)
+}
+
 func (r *Reconciler) uploadLogs(ctx context.Context, wr *obj.WorkflowRun, plr *obj.PipelineRun) {
 	podNames := make(map[string]string)
 
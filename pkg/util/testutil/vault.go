@@ -77,7 +77,7 @@ path "${ .SecretsPath }/metadata/connections/{{identity.entity.aliases.${ .Acces
 }
 
 path "${ .SecretsPath }/data/connections/{{identity.entity.aliases.${ .Accessor }.metadata.domain_id}}/*" {
-	capabilities = ["read"]
+	capabilities = ["read", "update"]
 }
 `))
 
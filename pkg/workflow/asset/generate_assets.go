@@ -32,9 +32,9 @@ var assets = func() http.FileSystem {
 		"/schemas/v1/Workflow.json": &vfsgen۰CompressedFileInfo{
 			name:             "Workflow.json",
 			modTime:          time.Time{},
-			uncompressedSize: 7032,
+			uncompressedSize: 7288,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc4\x58\x4d\x6f\xdb\x38\x13\xbe\xfb\x57\x0c\xd4\x02\xef\xa5\x79\xbd\x3d\x2d\x90\x5b\xda\x6e\x81\x02\x5d\xb4\x68\xda\xed\xa1\xe8\x81\x96\x46\x16\x6b\x8a\x64\x49\xca\x89\x11\xf8\xbf\x2f\x48\x4a\xb2\x28\x51\x8a\x25\x27\xd8\x1c\x92\x48\xe2\xcc\x3c\x7c\xe6\x93\x7c\x58\x01\x24\x2f\x75\x5a\x60\x49\x92\x6b\x48\x0a\x63\xe4\xf5\x7a\xfd\x4b\x0b\x7e\xe5\xdf\xfe\x5f\xa8\xed\x3a\x53\x24\x37\x57\x7f\xfc\xb9\xf6\xef\x5e\x24\xaf\xac\x9c\xa1\x86\xa1\x95\xfa\x2e\xd4\x2e\x67\xe2\xce\xbf\xce\x50\xa7\x8a\x4a\x43\x05\xb7\x1f\x6f\xe0\xae\xfe\x0c\x19\xe6\x94\x53\xf7\xc1\x2b\x38\x48\x27\x2f\x36\xbf\x30\x35\xfe\x9d\x54\x42\xa2\x32\x14\x75\x72\x0d\x16\x1e\x40\x42\x24\xfd\x07\x95\xf6\x0a\xfd\xbb\x8e\xb4\x36\x8a\xf2\xad\x93\x76\xef\x7b\xf6\xbf\x16\x78\x42\xe0\xf1\xc3\xbe\xd6\xd6\xca\x20\xaf\xca\xe4\x1a\x7e\xd4\xcf\x00\xc9\xfe\x75\x52\x3f\xfc\x74\x7f\x8f\x7e\x6d\xb2\xa3\x3c\x7b\x22\x14\x4e\x74\x02\x42\x4b\x6b\x14\x08\x27\x25\x2e\x00\x72\xc3\x41\xb8\x07\xc2\xc0\xaa\x80\x5c\x28\x30\x1d\x74\x49\x60\x45\x57\x65\x49\xd4\x61\x89\x21\x28\xe8\xb6\xb8\x62\xb8\x47\x06\xb2\x50\x44\x23\xf8\x25\x1b\xca\xb7\x70\x57\x10\x13\xd8\x85\x4c\xa0\x0e\x8d\x87\x1a\xe7\x03\x28\x85\xb2\x36\x0d\xa1\x0c\x33\xe8\x7c\x06\x91\x4f\xec\xb9\x10\x25\x4a\xb2\xbd\x94\xdd\x6f\x5f\x3e\x82\x11\x40\xe0\x0e\x37\x9a\x9a\x08\xd3\xad\x96\x5c\xa8\x92\x18\xab\xa0\x52\xb4\xe7\x00\x51\xa9\xf4\x89\xa0\x74\x8d\xff\x4f\x03\xa9\x4c\x21\x14\x35\xc4\xd0\x3d\x82\x37\x04\xa9\xc8\x10\x98\x48\x89\x09\x12\x64\x02\xa1\x21\x5b\x1d\xc3\x47\x94\x22\x87\xb3\xe0\x31\xaa\x8d\x75\x49\xae\x10\xaf\xac\x25\x60\x64\x83\x4c\x5b\xcc\x05\x32\x09\x12\x85\x64\x08\x39\xe5\xd9\x08\x83\xd4\x60\xd9\x45\x31\xe4\xa9\xfe\x70\x0c\xb0\xdb\x8d\xb2\x28\xfa\x4e\x4d\x8a\xc1\xff\x82\x95\x26\x1b\x8b\xa9\xc5\x9c\x11\x43\x40\x4b\x4c\x69\x4e\x53\x4f\x37\xd5\x23\x21\x26\x89\x22\x25\x1a\x54\x67\xd9\x26\x59\x46\x3d\x55\x9f\x87\xd5\xd1\xad\x78\xa9\x30\xb7\x92\x2f\xd6\xa7\x1a\xab\xd7\x9f\x1b\x2b\xf1\xcd\x6b\x83\x72\x81\xe7\x3e\xd6\xde\x3a\x95\x33\xa7\xa7\x5d\x5e\x52\xfe\xa1\xf6\xc6\xeb\x29\xff\xc4\x31\xdf\x1a\x94\x71\xb8\x46\xd1\xed\x76\x84\xb1\x99\x88\x5b\x55\xf3\xf1\x7d\xf5\xa2\x3d\x88\xab\x1a\x66\xd2\x59\x7a\xea\x5f\xb7\xae\xde\xbf\xa7\xc8\xa2\xad\xe3\x91\x50\xbb\x69\x2b\x97\x50\xae\x70\x1d\x24\x66\x40\xb9\x4f\xc9\x20\x4b\x23\xcd\xb3\x6b\xe9\xf4\x66\xbc\x8c\xc4\x11\xec\x09\xab\xd0\x37\xac\x76\xdd\xb1\xa1\xa0\x35\xaf\xf0\x77\x45\x15\xda\x4d\xfe\xf0\xfa\xc3\x96\xf5\xd7\xbd\x54\xa8\xfb\x7d\x7c\x58\x18\xb0\x5d\x07\x68\x2d\x13\x83\x19\x6c\x0e\x2e\xf5\x37\x24\xdd\x21\xcf\xc2\x74\x3a\x05\xfa\x12\x7a\xdb\xb0\x68\xb3\xb2\x3f\xa9\x4c\x92\x9b\x61\x4e\x2a\x66\xfa\xfc\xf6\xcc\xbc\xf3\xab\x3a\x36\x1c\xa7\x1d\x3a\x5f\xad\x46\x64\x97\xba\xed\x53\x53\x5f\xbb\xfb\x3a\xad\x18\x3a\x32\xe0\xd4\x25\xe2\x45\x74\xda\xba\x30\x8b\xc9\xde\x48\x33\x77\xbf\xdf\x38\xfd\x5d\x61\xcf\xbe\xd3\x39\x42\xb2\x44\x9e\xe9\x4f\x03\x8a\x7b\x6a\x6f\xfd\x36\xec\x62\xe4\xa9\x05\x1d\x80\x10\x1c\x3f\xe5\xc1\xe0\x66\x7f\x1e\x82\xa7\xd1\x6e\x34\x40\x35\x29\x1b\x56\xb9\xf6\xf3\xb0\x76\x9d\x65\xf5\xe4\xf8\xe1\xd3\xcf\x33\xb3\xbc\xe7\xbb\x66\x50\x6d\xd7\x0e\xc9\x79\x18\x2b\xac\x6f\x05\x37\x84\x72\x54\x2e\xf0\xba\x9c\x8c\x8a\xdc\x48\xa9\xc4\x9e\xb0\x5a\x22\x3a\x26\xb7\x6a\xff\xa6\xf7\x34\xa8\x3b\x63\x51\x48\xcb\x70\xf6\x8b\x11\x39\x15\x86\xef\x44\xba\x43\x05\x4e\x8d\x9b\xf9\x5c\x1c\xe2\x3d\xa6\x55\x2f\xef\x3a\xc1\x98\x8a\xb2\x24\xc1\xc9\x62\xae\xd9\xb7\x5e\x83\x1d\x3c\xa8\xd6\x63\x95\x85\xa8\x6d\x3f\x54\x26\xc2\x6b\xcc\x06\x51\xdb\xaa\x44\x6e\x7a\xa9\x10\x0f\xc4\x89\x20\x3c\x46\x31\x52\x2e\x2b\xf3\x9e\xb2\x4b\x9c\x60\xcf\x5b\x0a\x99\x1f\x6d\x25\x31\x85\x1b\xc5\x39\xe4\x94\xa1\xfd\xb7\xd2\xa7\x79\xdc\xd9\x03\x2f\x1e\x67\xcd\xad\x58\x4e\xdb\x87\x8e\x01\x6b\xdc\xc7\x02\x3e\x1d\x79\x67\xe6\xa8\x8f\xec\xe9\x34\xe9\x97\xfd\x39\x23\x45\x2a\xb8\x76\x67\x84\xb4\xd1\x36\x35\x2d\x10\xc6\x66\xd7\x06\x9f\xc4\x23\xa9\x1e\x94\x83\x4b\xb7\x40\x6a\x65\x97\xcc\x3b\xcd\xac\x78\x51\x17\xad\x67\xd5\xff\xb2\x91\x36\x10\xc6\x7b\xe9\xe0\x94\x0a\x8f\x4e\xd0\xb7\x5e\x24\xaa\x6e\x43\x79\x66\x41\xce\xd1\xf7\xa6\x96\x89\x2a\xbc\x2b\x70\xd0\xe6\xe3\xda\x3a\x33\xea\xb2\x1e\x38\x64\x25\x1a\xac\x21\x0b\x4f\x12\x21\xf5\x09\x3e\x16\x28\x33\xfa\xb0\x3d\xac\x64\x15\xc3\x10\xe0\x59\xfd\xf8\x73\xa5\x8b\x05\x62\xdf\x71\x53\x08\xb1\xeb\x4b\x46\x69\x8b\xa3\x5b\x42\x9f\xae\x35\x9d\x43\xdf\xa2\x0a\xd2\x18\x18\xc9\x98\xe6\xeb\x65\x3d\x2e\xb7\x91\x88\x3c\x3d\xb8\xbe\xcf\xf7\x62\x87\xfe\xde\xa1\xd9\x55\x7d\x96\x72\x27\x46\xd7\xed\x52\x25\x38\xe8\x03\x37\xe4\x7e\x7e\x84\x07\x57\x96\x30\xdc\x63\xe8\xac\x61\x3c\x2c\x71\x94\xac\x74\xf1\x6c\x4e\xb2\xca\xc7\x1d\xe4\x2e\xc5\xa3\xee\xe9\xa1\x8e\x22\xef\xdc\x70\x35\x37\xbe\x02\xd0\x9e\xdb\x53\x04\xeb\x84\x6a\x53\x52\x63\xcf\xb6\xb8\x47\x6e\xdc\x05\x52\xa8\xf1\xd1\x8b\x1f\x98\xb8\x48\xe9\x5c\x3a\x5c\x34\x35\x74\x4f\xfd\xa1\x7f\xa3\x89\xbb\xa8\x94\x79\x45\xcf\xe6\xe5\x5a\xff\x73\x4f\x23\x73\x07\x83\x37\x83\x36\x77\x3e\x65\x0d\x55\x75\xab\x9c\xc5\xd5\x0e\x0f\x97\xdc\x06\x75\xe2\x7a\x87\x87\x16\x81\x9d\xaa\xdd\xe0\xc0\x0e\x40\x33\xe4\x86\xe6\x07\x3b\x72\xbb\xe0\x8e\xe7\x58\xf4\x1a\x74\x8a\x87\x18\x9e\xf6\xf2\xa7\x41\xa2\x9b\x01\xdf\x76\xfd\x86\x28\x7f\x83\xd4\xc2\x79\xb2\x34\x8b\xcd\x0b\xd1\x2c\x5b\x35\xbf\x8f\xab\xe3\xea\xdf\x00\x00\x00\xff\xff\x68\x92\xbe\x65\x78\x1b\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc4\x59\x4b\x8f\xdb\x36\x10\xbe\xeb\x57\x0c\x94\x00\xbd\xec\x76\x9b\x53\x81\xbd\xb9\x49\x03\x04\x48\x91\x20\x8f\xe6\x10\xe4\x40\x4b\x23\x8b\x31\x45\x2a\x24\x65\xaf\x11\xf8\xbf\x17\x24\xf5\xa0\x28\xca\x6b\x6b\x1d\x14\x7b\x88\x25\x71\x66\xbe\xf9\xe6\xc1\x21\xf3\x33\x01\x48\x9f\xab\xac\xc4\x8a\xa4\xf7\x90\x96\x5a\xd7\xf7\x77\x77\xdf\x95\xe0\xb7\xee\xed\xef\x42\x6e\xee\x72\x49\x0a\x7d\xfb\xc7\x9f\x77\xee\xdd\xb3\xf4\xc6\xc8\x69\xaa\x19\x1a\xa9\x2f\x42\x6e\x0b\x26\xf6\xee\x75\x8e\x2a\x93\xb4\xd6\x54\x70\xf3\x71\x05\xfb\xf6\x33\xe4\x58\x50\x4e\xed\x07\xbb\x52\x1f\x6a\x2b\x2f\xd6\xdf\x31\xd3\x4e\xba\x96\xa2\x46\xa9\x29\xaa\xf4\x1e\x0c\x3c\x80\x94\xd4\xf4\x5f\x94\xca\xc8\x75\xef\x3c\x69\xa5\x25\xe5\x1b\x2b\x0d\x30\xb5\xff\xa9\xc4\x01\x81\xc3\x0f\xbb\x56\x5b\x2f\x83\xbc\xa9\xd2\x7b\xf8\xda\x3e\x03\xa4\xbb\x17\x69\xfb\xf0\xcd\xfe\x7b\x74\x6b\xd3\x2d\xe5\xf9\x95\x50\x58\xd1\x13\x10\x7a\x5a\xa3\x40\x38\xa9\x70\x01\x90\x15\x07\x61\x51\x11\x06\x46\x05\x14\x42\x82\xf6\xd0\xa5\x23\x2b\xaa\xa9\x2a\x22\x0f\x4b\x0c\x41\x49\x37\xe5\x2d\xc3\x1d\x32\xa8\x4b\x49\x14\x82\x5b\xb2\xa6\x7c\x03\xfb\x92\xe8\x91\x5d\xc8\x05\xaa\xb1\xf1\xb1\xc6\xcb\x01\x54\x42\x1a\x9b\x9a\x50\x86\x79\x6b\xdc\x6a\x03\x51\x9c\xf0\xb9\x14\x15\xd6\x64\xf3\x54\x76\x3f\x7f\x78\x0b\x5a\x00\x81\x3d\xae\x15\xd5\x11\xa6\x7b\x2d\x85\x90\x15\xd1\x46\x41\x23\xe9\x18\x8c\x12\x8d\xcc\xae\x04\xc5\x37\xfe\x9b\x02\xd2\xe8\x52\x48\xaa\x89\xa6\x3b\x04\x67\x08\x32\x91\x23\x30\x91\x91\xbe\x4c\x1f\x43\xa8\xc9\x46\xc5\xf0\x11\x29\xc9\xe1\x2c\x78\x8c\x2a\x6d\x42\x52\x48\xc4\x5b\xc3\x05\x30\xb2\x46\xa6\x0c\x7d\x25\xb2\x1a\x6a\x14\x35\x43\x28\x28\xcf\x67\x18\xa4\x1a\x2b\x1f\xc5\x94\xa7\xf6\xc3\x71\x84\xdd\x38\xca\xa2\xe8\xbd\x9e\x14\x83\xff\x01\x1b\x45\xd6\x0c\x3d\xcc\x39\xd1\x04\x54\x8d\x19\x2d\x68\x66\xa0\xeb\x92\xaa\x01\xea\xc8\x6e\x4d\x24\xa9\x50\xa3\x3c\xcb\x36\xc9\x73\xdb\x35\x09\x7b\x3f\xed\x8e\xe6\x2f\x7d\x2e\xb1\x30\xb0\x9e\xdd\x0d\x3d\x56\xdd\xbd\xef\xac\xc4\x9d\x57\x1a\xeb\x05\x91\x7b\xdb\x46\xab\xf3\x0c\x9c\x9e\x7e\x79\x45\xf9\x9b\x36\x1a\x2f\x4e\xc5\x27\x8e\xf9\xa3\xc6\x3a\x0e\x57\x4b\xba\xd9\xa0\xbc\x06\xe2\x5e\xd5\xe5\xf8\x3e\x39\xd1\x00\x62\xd2\xc2\x4c\xbd\xa5\xbd\xb6\xf4\xa3\xdd\x75\x5e\x53\x64\xf9\x39\xe1\x0e\xd0\xaf\xfa\xce\x25\xa4\xa9\x12\xd3\x7f\x73\xa0\xdc\x24\x5d\x50\xa5\x91\xcd\xd3\xb7\x34\xbc\x99\x96\x47\xa7\x23\x8e\x60\x47\x58\x83\xd6\x72\xe7\x78\xe7\x7a\x1f\x1f\x80\x54\xe2\x8f\x86\x4a\x34\x4e\x7e\x75\xfa\xc7\x5b\xd6\xdf\x0f\xb5\x44\x15\xee\xe3\xa1\x31\x0e\xd8\xaf\x03\x34\x96\x89\xc6\x1c\xd6\x07\x5b\xfa\x6b\x92\x6d\x91\xe7\xe3\x72\x1a\x12\xdd\xd3\x7b\x36\xbd\x7d\x5a\xf4\x55\x19\x4e\x2a\x27\xc9\xcd\xb1\x20\x0d\xd3\xa3\x97\x53\x33\xaf\xdc\x2a\xcf\x86\xf1\xcc\xa7\xf3\x26\x99\x91\x5d\x1a\xb6\x77\x5d\x7f\xf5\xfd\x1a\x56\x4c\x03\x39\xe2\xd4\x16\xe2\x60\x7c\x01\x9d\xa6\x2f\x5c\xc4\x64\x30\xd2\x5c\xea\xef\x67\x4e\x7f\x34\xc3\xe6\x60\xfb\x92\x9d\x71\xe6\x48\xae\x91\xe7\xea\xdd\x84\xe2\x40\xad\x21\x02\xdc\x62\xe4\x99\x01\x3d\x02\x21\x38\xbe\x2b\x46\x83\x9b\xf9\xf3\x15\xc6\xfc\x18\x7d\x3e\xde\x9c\x27\x3b\xee\x72\x00\xb3\xbd\x6b\x22\x19\xb3\x3a\x04\x7e\xfa\xf4\x2d\x89\x60\x4b\xf3\x03\x27\x15\xcd\x56\x59\x86\x2a\x34\xd8\x9b\x5a\x0b\xc1\x90\x0c\xd1\x8e\x51\xba\x62\x4c\xec\x95\xad\x67\x1b\x24\x2d\x40\xa2\x96\x14\x77\x08\x84\x1f\x40\x61\x26\x51\xdf\x40\x26\x38\xc7\xcc\x48\xdd\x80\x69\x7e\x8d\xae\x1b\x0d\x64\x47\x28\xb3\x9b\x6f\x3b\xd3\xc8\x86\x03\xe5\x4a\x23\xc9\x4d\x83\x14\x9c\x99\x5e\x21\x14\x82\xc4\x02\x25\xf2\xcc\x36\x4c\xa0\x5a\xc5\xf2\x31\xa8\xe2\x82\x30\x85\x49\xc8\x4a\xbc\xcb\x05\xb9\xdb\x3e\x7e\xbb\x49\x66\x93\xe3\xe7\xdc\xc6\xf2\x52\x70\x4d\x28\x47\x69\xf2\x2d\xf5\x79\x9f\x15\x59\xd5\xb5\x14\x3b\xc2\x5a\x89\xe8\x31\xa1\x57\xfb\x0f\x7d\xa0\x7e\xaa\xcf\x56\x21\xad\xc6\xb3\x6f\x2c\x91\x4e\x05\xf7\x95\xc8\xb6\x28\xc1\xaa\xb1\x33\xaf\x0d\x31\x3e\x60\xd6\x04\x7d\x67\xd0\x92\x66\xa2\xaa\x08\xcf\x9f\x60\xf6\xa5\xd3\x60\x06\x2f\xaa\xd4\x5c\x67\x25\x72\xa3\xe6\x8c\x4c\xcb\x6b\xce\x06\x91\x9b\xa6\x42\xae\x83\x56\x10\x2f\xc4\xd0\x89\x24\x56\x72\x3e\x46\xca\xeb\x46\xbf\xa6\xec\x29\x41\x30\xa7\x5e\x89\xcc\x8d\xf6\x35\xd1\xa5\xe1\x85\x70\x28\xa8\x2b\x9b\x46\x0d\xe7\x11\x6b\x0f\x9c\x78\x9c\x35\xbb\x62\x39\x6d\x6f\x3c\x03\xc6\xb8\xcb\x05\xbc\x1e\x79\x49\x00\x79\xa6\x46\x6d\x4a\xa6\xa7\xcb\x24\xdc\xf6\xe6\xaa\xa4\xc5\x35\xbc\xb1\x49\xcc\x95\x99\x03\xcc\x0f\x57\xcb\x69\x12\xa2\x1d\x30\x12\xc6\x2e\xee\x0d\xae\x88\xe1\x18\xf5\x61\xd4\x0e\x9e\xea\x02\x69\x95\xa5\x67\xb2\x1c\x9b\xf7\xba\x59\x79\xb0\xb1\x60\x8a\x68\x67\xf5\xff\x73\x90\xe8\x20\xcc\xcf\x12\x93\x53\xfa\xe3\x27\x88\x8f\x4e\x24\xaa\x6e\x4d\x79\x6e\x40\x5e\xa2\xef\xaf\x56\x26\xaa\x70\x5f\x22\x3f\x4f\x9b\x37\xa3\x9f\x19\xf9\x80\xf6\x29\x2b\xd1\x64\x1d\xb3\x70\x95\x0c\x69\x6f\x30\x06\x6f\xd2\x25\xfb\xb0\x39\xac\xe5\x0d\xc3\x31\xc0\xc1\xf7\x13\xa2\xef\x1b\x55\x2e\x10\xfb\x82\xeb\x52\x88\x6d\x28\x19\xa5\x2d\x8e\x6e\x09\x7d\xaa\xd5\x74\x0e\x7d\x8b\x3a\x48\x67\x20\x9e\xe2\xfd\xd7\x40\xfc\x82\x32\x35\x7b\x5c\x61\x7a\x10\xf2\xec\x60\xf6\x15\xca\x77\x62\x8b\xee\xde\xa5\xf3\xaa\x3d\x4b\xba\x01\xd0\xec\x76\x99\x14\x1c\xd4\x81\x6b\xf2\xe0\x21\x4b\x02\x84\x33\x19\x3e\xba\xb2\xf5\xbd\x88\x06\x6b\x9a\x0f\x4b\x02\x55\x37\xaa\xfc\x65\x41\x32\xca\x3d\x1a\x02\xd7\x2a\x12\x0a\xce\xa0\x7e\xec\x86\xaf\xbb\xf1\x16\x80\xe6\xde\x22\x43\x30\x41\x68\xd6\x15\xd5\xe6\x6c\x8f\x3b\xe4\xda\x5e\xa0\x8d\x35\x3e\x7a\xf1\x75\xa2\x91\xf9\x97\x2e\xde\xfa\x63\x12\xfe\x3a\x2b\xe6\xd1\xf8\x46\x0b\x77\x80\x77\x7e\x88\xf7\x4e\xd1\x2f\x8b\x72\xab\xdf\x0b\xf4\xc4\xf7\x6b\x4c\x23\x51\x22\x4f\x0c\x06\xdd\x96\xb5\x84\xb2\x8e\xaa\x76\xab\xbc\x88\xab\x2d\xfa\xff\xa1\x71\x69\xdb\xf1\xf3\x7a\x8b\x87\x1e\x81\x16\xd0\xd8\x1b\x08\x76\x00\x9a\x23\xd7\xb4\x38\x00\xe1\x2e\xb9\x3d\xea\x07\xdd\x69\x7f\x31\xa3\xe6\xf0\x04\x3c\xc4\xf0\xf4\x97\x5f\x1d\x12\xd5\x0d\xf8\x66\xd7\xef\x88\x72\x37\x68\x3d\x9c\xab\x95\x59\x6c\x5e\x18\xf2\xcb\xcb\xb4\xe1\xa2\x34\x39\x26\xff\x0d\x00\xd4\x6f\xe6\xdb\x78\x1c\x00\x00"),
 		},
 	}
 	fs["/"].(*vfsgen۰DirInfo).entries = []os.FileInfo{
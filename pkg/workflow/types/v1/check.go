@@ -0,0 +1,80 @@
+package v1
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/puppetlabs/relay-core/pkg/expr/check"
+	"github.com/puppetlabs/relay-core/pkg/expr/fn"
+)
+
+// CheckExpressions statically checks the spec and conditions of each step in
+// a workflow. Function invocations must match the signatures of the functions
+// they call, parameter references must name declared parameters, and output
+// references must name declared steps. Conditions must evaluate to a boolean
+// or an array of booleans.
+//
+// If any problems are found, the returned error is a
+// *WorkflowExpressionsInvalidError.
+func CheckExpressions(wd *WorkflowData) error {
+	parameters := make([]string, 0, len(wd.Parameters))
+	for name := range wd.Parameters {
+		parameters = append(parameters, name)
+	}
+
+	steps := make([]string, len(wd.Steps))
+	for i, step := range wd.Steps {
+		steps[i] = step.Name
+	}
+
+	c := check.NewChecker(
+		check.WithParameters(parameters),
+		check.WithSteps(steps),
+	)
+
+	var errs []*WorkflowStepExpressionError
+	for _, step := range wd.Steps {
+		report := func(path []string, cause error) {
+			errs = append(errs, &WorkflowStepExpressionError{
+				Name:  step.Name,
+				Path:  strings.Join(path, "."),
+				Cause: cause,
+			})
+		}
+
+		if sv, ok := step.Variant.(*ContainerWorkflowStep); ok {
+			keys := make([]string, 0, len(sv.Spec))
+			for key := range sv.Spec {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				for _, err := range c.Check(sv.Spec[key].Tree) {
+					report(append([]string{"spec", key}, err.Path...), err.Cause)
+				}
+			}
+		}
+
+		// Conditions are either a single boolean or an array of booleans that
+		// must all be true.
+		if conds, ok := step.When.Tree.([]interface{}); ok {
+			for i, cond := range conds {
+				for _, err := range c.CheckType(cond, fn.TypeBoolean) {
+					report(append([]string{"when", strconv.Itoa(i)}, err.Path...), err.Cause)
+				}
+			}
+		} else if step.When.Tree != nil {
+			for _, err := range c.CheckType(step.When.Tree, fn.TypeBoolean|fn.TypeArray) {
+				report(append([]string{"when"}, err.Path...), err.Cause)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return &WorkflowExpressionsInvalidError{Errors: errs}
+	}
+
+	return nil
+}
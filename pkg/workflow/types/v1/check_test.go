@@ -0,0 +1,60 @@
+package v1
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/puppetlabs/relay-core/pkg/expr/check"
+	"github.com/puppetlabs/relay-core/pkg/expr/evaluate"
+	"github.com/puppetlabs/relay-core/pkg/expr/fn"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeFixture(t *testing.T, name string) *WorkflowData {
+	f, err := os.Open(name)
+	require.NoError(t, err)
+
+	wd, err := NewDocumentStreamingDecoder(f, &YAMLDecoder{}).DecodeStream(context.Background())
+	require.NoError(t, err)
+
+	return wd
+}
+
+func TestCheckExpressions(t *testing.T) {
+	require.NoError(t, CheckExpressions(decodeFixture(t, "testdata/expressions/valid.yaml")))
+
+	err := CheckExpressions(decodeFixture(t, "testdata/expressions/invalid.yaml"))
+	require.Equal(t, &WorkflowExpressionsInvalidError{
+		Errors: []*WorkflowStepExpressionError{
+			{
+				Name:  "build",
+				Path:  "spec.tag.$fn.concat.2",
+				Cause: &evaluate.InvalidTypeError{Type: "Parameter", Cause: &check.UndeclaredParameterError{Name: "version"}},
+			},
+			{
+				Name:  "build",
+				Path:  "spec.upper.$fn.upper",
+				Cause: &evaluate.InvalidInvocationError{Name: "upper", Cause: &fn.ArityError{Wanted: []int{1}, Got: 2}},
+			},
+			{
+				Name:  "deploy",
+				Path:  "spec.image",
+				Cause: &evaluate.InvalidTypeError{Type: "Output", Cause: &check.UndeclaredStepError{Name: "compile"}},
+			},
+			{
+				Name: "deploy",
+				Path: "spec.regions.$fn.join.0",
+				Cause: &evaluate.InvocationError{
+					Name:  "join",
+					Cause: &fn.PositionalArgError{Arg: 1, Cause: &check.TypeError{Wanted: fn.TypeArray, Got: fn.TypeString}},
+				},
+			},
+			{
+				Name:  "deploy",
+				Path:  "when.1",
+				Cause: &check.TypeError{Wanted: fn.TypeBoolean, Got: fn.TypeString},
+			},
+		},
+	}, err)
+}
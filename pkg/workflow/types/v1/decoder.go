@@ -121,16 +121,18 @@ func yamlWorkflowDataToWorkflowData(ywd *YAMLWorkflowData) (*WorkflowData, error
 			}
 
 			env.Steps = append(env.Steps, &WorkflowStep{
-				Name:      step.Name,
-				DependsOn: step.DependsOn,
-				When:      serialize.JSONTree{Tree: when},
-				Variant:   &ApprovalWorkflowStep{},
+				Name:          step.Name,
+				DependsOn:     step.DependsOn,
+				When:          serialize.JSONTree{Tree: when},
+				DynamicAccess: step.DynamicAccess,
+				Variant:       &ApprovalWorkflowStep{},
 			})
 		default:
 			env.Steps = append(env.Steps, &WorkflowStep{
-				Name:      step.Name,
-				DependsOn: step.DependsOn,
-				When:      serialize.JSONTree(step.When),
+				Name:          step.Name,
+				DependsOn:     step.DependsOn,
+				When:          serialize.JSONTree(step.When),
+				DynamicAccess: step.DynamicAccess,
 				Variant: &ContainerWorkflowStep{
 					ContainerMixin: ContainerMixin{
 						Image:     step.Image,
@@ -0,0 +1,206 @@
+package v1
+
+import (
+	"context"
+	"sort"
+
+	nebulav1 "github.com/puppetlabs/relay-core/pkg/apis/nebula.puppet.com/v1"
+	"github.com/puppetlabs/relay-core/pkg/expr/evaluate"
+	exprmodel "github.com/puppetlabs/relay-core/pkg/expr/model"
+)
+
+// DependencyInferenceMode determines what the run engine mapper does with a
+// step that refers to the outputs of a step it does not depend on.
+type DependencyInferenceMode string
+
+const (
+	// DependencyInferenceModeAdd adds the missing dependencies to the step.
+	DependencyInferenceModeAdd DependencyInferenceMode = "add"
+
+	// DependencyInferenceModeWarn leaves the dependencies of the step as they
+	// are and only reports the missing dependencies in the mapping.
+	DependencyInferenceModeWarn DependencyInferenceMode = "warn"
+
+	// DependencyInferenceModeStrict rejects the workflow with a
+	// *WorkflowStepDependenciesMissingError.
+	DependencyInferenceModeStrict DependencyInferenceMode = "strict"
+)
+
+// stepOutputReferences returns the names of the steps whose outputs are
+// referred to by the spec, environment, or conditions of the given step.
+func stepOutputReferences(ctx context.Context, ws *nebulav1.WorkflowStep) ([]string, error) {
+	ev := evaluate.NewEvaluator(evaluate.WithInert(true))
+
+	values := make(map[string]interface{})
+	if len(ws.Spec) > 0 {
+		values["spec"] = ws.Spec.Value()
+	}
+	for name, value := range ws.Env.Value() {
+		values["env."+name] = value
+	}
+	if when := ws.When.Value(); when != nil {
+		values["when"] = when
+	}
+
+	paths := make([]string, 0, len(values))
+	for path := range values {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var ur exprmodel.Unresolvable
+	for _, path := range paths {
+		r, err := ev.EvaluateAll(ctx, values[path])
+		if err != nil {
+			return nil, &WorkflowStepExpressionError{Name: ws.Name, Path: path, Cause: err}
+		}
+
+		ur.Extends(r.Unresolvable)
+	}
+
+	var from []string
+	seen := make(map[string]bool)
+	for _, o := range ur.Outputs {
+		if !seen[o.From] {
+			from = append(from, o.From)
+			seen[o.From] = true
+		}
+	}
+
+	return from, nil
+}
+
+// findStepDependencyCycle returns a path through the given dependencies that
+// starts and ends at the same step, or nil if there is no such path.
+func findStepDependencyCycle(steps []*nebulav1.WorkflowStep, deps map[string][]string) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(steps))
+	var stack []string
+
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		state[name] = visiting
+		stack = append(stack, name)
+
+		for _, dep := range deps[name] {
+			switch state[dep] {
+			case unvisited:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			case visiting:
+				for i := range stack {
+					if stack[i] == dep {
+						return append(append([]string{}, stack[i:]...), dep)
+					}
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+		return nil
+	}
+
+	for _, step := range steps {
+		if state[step.Name] != unvisited {
+			continue
+		}
+
+		if cycle := visit(step.Name); cycle != nil {
+			return cycle
+		}
+	}
+
+	return nil
+}
+
+// inferStepDependencies makes sure every step depends, directly or
+// indirectly, on each step whose outputs it refers to. Otherwise the step may
+// run before the outputs it needs have been set. The dependencies that were
+// missing are returned for each step that lacked any.
+func inferStepDependencies(ctx context.Context, steps []*nebulav1.WorkflowStep, mode DependencyInferenceMode) ([]*WorkflowStepDependenciesMissingError, error) {
+	declared := make(map[string]bool, len(steps))
+	for _, step := range steps {
+		declared[step.Name] = true
+	}
+
+	// Both explicit and implied dependencies, ignoring any steps that aren't
+	// declared. Those are a problem for the run, but not for the order of
+	// steps.
+	explicit := make(map[string][]string, len(steps))
+	all := make(map[string][]string, len(steps))
+	refs := make(map[string][]string, len(steps))
+	for _, step := range steps {
+		for _, dep := range step.DependsOn {
+			if declared[dep] {
+				explicit[step.Name] = append(explicit[step.Name], dep)
+			}
+		}
+
+		from, err := stepOutputReferences(ctx, step)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range from {
+			if declared[name] {
+				refs[step.Name] = append(refs[step.Name], name)
+			}
+		}
+
+		all[step.Name] = append(append([]string{}, explicit[step.Name]...), refs[step.Name]...)
+	}
+
+	if cycle := findStepDependencyCycle(steps, all); cycle != nil {
+		return nil, &WorkflowStepDependencyCycleError{Cycle: cycle}
+	}
+
+	var inferred []*WorkflowStepDependenciesMissingError
+	for _, step := range steps {
+		// Find everything this step already waits for.
+		ancestors := make(map[string]bool)
+		pending := append([]string{}, explicit[step.Name]...)
+		for len(pending) > 0 {
+			name := pending[0]
+			pending = pending[1:]
+
+			if ancestors[name] {
+				continue
+			}
+
+			ancestors[name] = true
+			pending = append(pending, explicit[name]...)
+		}
+
+		var missing []string
+		for _, name := range refs[step.Name] {
+			if !ancestors[name] {
+				missing = append(missing, name)
+			}
+		}
+
+		if len(missing) == 0 {
+			continue
+		}
+
+		err := &WorkflowStepDependenciesMissingError{Name: step.Name, DependsOn: missing}
+
+		switch mode {
+		case DependencyInferenceModeStrict:
+			return nil, err
+		case DependencyInferenceModeWarn:
+		default:
+			step.DependsOn = append(append([]string{}, step.DependsOn...), missing...)
+		}
+
+		inferred = append(inferred, err)
+	}
+
+	return inferred, nil
+}
@@ -1,6 +1,8 @@
 package v1
 
 import (
+	"context"
+
 	nebulav1 "github.com/puppetlabs/relay-core/pkg/apis/nebula.puppet.com/v1"
 	"github.com/puppetlabs/relay-core/pkg/apis/relay.sh/v1beta1"
 	corev1 "k8s.io/api/core/v1"
@@ -26,6 +28,12 @@ const (
 type RunKubernetesObjectMapping struct {
 	Namespace   *corev1.Namespace
 	WorkflowRun *nebulav1.WorkflowRun
+
+	// MissingDependencies are the dependencies of steps on the steps whose
+	// outputs they refer to that were not declared in the workflow. Depending
+	// on the DependencyInferenceMode of the mapper, they have either been
+	// added to the steps of the WorkflowRun or only reported here.
+	MissingDependencies []*WorkflowStepDependenciesMissingError
 }
 
 type TenantKubernetesObjectMapping struct {
@@ -41,7 +49,7 @@ type WebhookTriggerKubernetesObjectMapping struct {
 // object manifest. The results have not been applied or created on the
 // kubernetes server.
 type RunKubernetesEngineMapper interface {
-	ToRuntimeObjectsManifest(*WorkflowData) (*RunKubernetesObjectMapping, error)
+	ToRuntimeObjectsManifest(context.Context, *WorkflowData) (*RunKubernetesObjectMapping, error)
 }
 
 type TenantKubernetesEngineMapper interface {
@@ -3,6 +3,7 @@ package v1
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 type WorkflowFileFormatError struct {
@@ -28,3 +29,47 @@ func (e *WorkflowStepInvalidError) Error() string {
 
 var MissingTenantIDError = errors.New("tenantID cannot be blank")
 var MissingWorkflowIDError = errors.New("workflowID cannot be blank")
+
+type WorkflowStepExpressionError struct {
+	Name  string
+	Path  string
+	Cause error
+}
+
+func (e *WorkflowStepExpressionError) Unwrap() error {
+	return e.Cause
+}
+
+func (e *WorkflowStepExpressionError) Error() string {
+	return fmt.Sprintf("workflow step %s has an invalid expression at %s: %+v", e.Name, e.Path, e.Cause)
+}
+
+type WorkflowExpressionsInvalidError struct {
+	Errors []*WorkflowStepExpressionError
+}
+
+func (e *WorkflowExpressionsInvalidError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = fmt.Sprintf("* %s", err.Error())
+	}
+
+	return fmt.Sprintf("workflow expressions are invalid:\n%s", strings.Join(msgs, "\n"))
+}
+
+type WorkflowStepDependenciesMissingError struct {
+	Name      string
+	DependsOn []string
+}
+
+func (e *WorkflowStepDependenciesMissingError) Error() string {
+	return fmt.Sprintf("workflow step %s refers to the outputs of steps it does not depend on: %s", e.Name, strings.Join(e.DependsOn, ", "))
+}
+
+type WorkflowStepDependencyCycleError struct {
+	Cycle []string
+}
+
+func (e *WorkflowStepDependencyCycleError) Error() string {
+	return fmt.Sprintf("workflow steps depend on each other in a cycle: %s", strings.Join(e.Cycle, " -> "))
+}
@@ -75,6 +75,7 @@ type YAMLWorkflowStep struct {
 	YAMLContainerMixin `yaml:",inline"`
 	DependsOn          stringutil.StringArray `yaml:"dependsOn" json:"depends_on,omitempty"`
 	When               serialize.YAMLTree     `yaml:"when" json:"when,omitempty"`
+	DynamicAccess      bool                   `yaml:"dynamicAccess" json:"dynamic_access,omitempty"`
 }
 
 type YAMLWorkflowTriggerBinding struct {
@@ -258,7 +259,12 @@ type WorkflowStep struct {
 	Name      string             `yaml:"name" json:"name"`
 	DependsOn []string           `yaml:"dependsOn" json:"depends_on"`
 	When      serialize.JSONTree `yaml:"when" json:"when,omitempty"`
-	Variant   WorkflowStepVariant
+
+	// DynamicAccess allows the step to retrieve any secret, connection, or
+	// output available to the run instead of only those it references.
+	DynamicAccess bool `yaml:"dynamicAccess" json:"dynamic_access,omitempty"`
+
+	Variant WorkflowStepVariant
 }
 
 type WorkflowTriggerSource struct {
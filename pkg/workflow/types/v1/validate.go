@@ -1,8 +1,23 @@
 package v1
 
-import "github.com/puppetlabs/relay-core/pkg/util/typeutil"
+import (
+	"context"
+
+	"github.com/puppetlabs/relay-core/pkg/util/typeutil"
+)
 
 // ValidateYAML validates a yaml document according to the schema specification
+// and statically checks the expressions of its steps. If the expressions are
+// invalid, the returned error is a *WorkflowExpressionsInvalidError.
 func ValidateYAML(y string) error {
-	return typeutil.ValidateYAMLString(WorkflowSchema, y)
+	if err := typeutil.ValidateYAMLString(WorkflowSchema, y); err != nil {
+		return err
+	}
+
+	wd, err := (&YAMLDecoder{}).Decode(context.Background(), []byte(y))
+	if err != nil {
+		return err
+	}
+
+	return CheckExpressions(wd)
 }
@@ -7,6 +7,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/puppetlabs/relay-core/pkg/expr/check"
+	"github.com/puppetlabs/relay-core/pkg/expr/evaluate"
 	"github.com/puppetlabs/relay-core/pkg/util/typeutil"
 	v1 "github.com/puppetlabs/relay-core/pkg/workflow/types/v1"
 	"github.com/stretchr/testify/require"
@@ -120,3 +122,41 @@ func TestFixtureValidationForTriggers(t *testing.T) {
 		})
 	}
 }
+
+func TestFixtureValidationForExpressions(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/expressions/valid.yaml")
+	require.NoError(t, err)
+	require.NoError(t, v1.ValidateYAML(string(b)))
+
+	b, err = ioutil.ReadFile("testdata/expressions/invalid.yaml")
+	require.NoError(t, err)
+
+	err = v1.ValidateYAML(string(b))
+	require.Error(t, err)
+
+	ierr, ok := err.(*v1.WorkflowExpressionsInvalidError)
+	require.True(t, ok, "unexpected error type %T", err)
+	require.Len(t, ierr.Errors, 5)
+	require.Equal(t, "build", ierr.Errors[0].Name)
+	require.Equal(t, "spec.tag.$fn.concat.2", ierr.Errors[0].Path)
+
+	// A single bad reference in an otherwise valid workflow is enough to
+	// reject it.
+	err = v1.ValidateYAML(`
+apiVersion: v1
+steps:
+- name: deploy
+  image: relaysh/core:latest
+  spec:
+    region: !Parameter region
+`)
+	require.Equal(t, &v1.WorkflowExpressionsInvalidError{
+		Errors: []*v1.WorkflowStepExpressionError{
+			{
+				Name:  "deploy",
+				Path:  "spec.region",
+				Cause: &evaluate.InvalidTypeError{Type: "Parameter", Cause: &check.UndeclaredParameterError{Name: "region"}},
+			},
+		},
+	}, err)
+}
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"context"
 	"path"
 
 	nebulav1 "github.com/puppetlabs/relay-core/pkg/apis/nebula.puppet.com/v1"
@@ -46,6 +47,15 @@ func WithRunParametersRunOption(params WorkflowRunParameters) DefaultRunEngineMa
 	}
 }
 
+// WithDependencyInferenceModeRunOption sets what the mapper does with steps
+// that refer to the outputs of steps they don't depend on. By default, the
+// missing dependencies are added.
+func WithDependencyInferenceModeRunOption(mode DependencyInferenceMode) DefaultRunEngineMapperOption {
+	return func(m *DefaultRunEngineMapper) {
+		m.dependencyInferenceMode = mode
+	}
+}
+
 func WithVaultEngineMountRunOption(mount string) DefaultRunEngineMapperOption {
 	return func(m *DefaultRunEngineMapper) {
 		m.vaultEngineMount = mount
@@ -61,14 +71,25 @@ type DefaultRunEngineMapper struct {
 	runParameters    WorkflowRunParameters
 	domainID         string
 	vaultEngineMount string
+
+	dependencyInferenceMode DependencyInferenceMode
 }
 
 // ToRuntimeObjectsManifest returns a RunKubernetesObjectMapping that contains
 // uncreated objects that map to relay-core CRDs and other kubernetes resources
 // required to support a run.
-func (m *DefaultRunEngineMapper) ToRuntimeObjectsManifest(wd *WorkflowData) (*RunKubernetesObjectMapping, error) {
+func (m *DefaultRunEngineMapper) ToRuntimeObjectsManifest(ctx context.Context, wd *WorkflowData) (*RunKubernetesObjectMapping, error) {
 	manifest := RunKubernetesObjectMapping{}
 
+	steps := mapSteps(wd)
+
+	missing, err := inferStepDependencies(ctx, steps, m.dependencyInferenceMode)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest.MissingDependencies = missing
+
 	if m.namespace != defaultNamespace {
 		manifest.Namespace = mapNamespace(m.namespace)
 	}
@@ -107,7 +128,7 @@ func (m *DefaultRunEngineMapper) ToRuntimeObjectsManifest(wd *WorkflowData) (*Ru
 			Workflow: nebulav1.Workflow{
 				Name:       m.name,
 				Parameters: v1beta1.NewUnstructuredObject(wp),
-				Steps:      mapSteps(wd),
+				Steps:      steps,
 			},
 		},
 	}
@@ -122,6 +143,8 @@ func NewDefaultRunEngineMapper(opts ...DefaultRunEngineMapperOption) *DefaultRun
 		name:      defaultWorkflowName,
 		runName:   defaultWorkflowRunName,
 		namespace: defaultNamespace,
+
+		dependencyInferenceMode: DependencyInferenceModeAdd,
 	}
 
 	for _, opt := range opts {
@@ -153,9 +176,10 @@ func mapSteps(wd *WorkflowData) []*nebulav1.WorkflowStep {
 
 	for _, value := range wd.Steps {
 		workflowStep := nebulav1.WorkflowStep{
-			Name:      value.Name,
-			DependsOn: value.DependsOn,
-			When:      v1beta1.AsUnstructured(value.When.Tree),
+			Name:          value.Name,
+			DependsOn:     value.DependsOn,
+			When:          v1beta1.AsUnstructured(value.When.Tree),
+			DynamicAccess: value.DynamicAccess,
 		}
 
 		switch variant := value.Variant.(type) {
@@ -5,6 +5,10 @@ import (
 	"os"
 	"testing"
 
+	"github.com/puppetlabs/relay-core/pkg/expr/serialize"
+	"github.com/puppetlabs/relay-core/pkg/expr/testutil"
+	"github.com/puppetlabs/relay-core/pkg/model"
+	"github.com/puppetlabs/relay-core/pkg/operator/obj"
 	"github.com/stretchr/testify/require"
 )
 
@@ -25,7 +29,7 @@ func TestWorkflowRunEngineMapping(t *testing.T) {
 		WithWorkflowRunNameRunOption("valid-workflow-run-name"),
 	)
 
-	manifest, err := mapper.ToRuntimeObjectsManifest(wd)
+	manifest, err := mapper.ToRuntimeObjectsManifest(ctx, wd)
 	require.NoError(t, err)
 
 	require.NotNil(t, manifest.Namespace)
@@ -38,3 +42,169 @@ func TestWorkflowRunEngineMapping(t *testing.T) {
 	require.Len(t, manifest.WorkflowRun.Spec.Workflow.Steps, 1)
 	require.Len(t, manifest.WorkflowRun.Spec.Workflow.Parameters, 1)
 }
+
+func TestWorkflowRunEngineMappingDynamicAccess(t *testing.T) {
+	ctx := context.Background()
+
+	manifest, err := NewDefaultRunEngineMapper().ToRuntimeObjectsManifest(ctx, decodeFixture(t, "testdata/dynamic_access.yaml"))
+	require.NoError(t, err)
+
+	steps := manifest.WorkflowRun.Spec.Workflow.Steps
+	require.Len(t, steps, 2)
+
+	// A step is scoped to the secrets it references unless it requests
+	// dynamic access, in which case it has no scopes at all.
+	require.False(t, steps[0].DynamicAccess)
+	perms, err := obj.ModelStepPermissions(ctx, steps[0])
+	require.NoError(t, err)
+	require.Equal(t, &model.ActionPermissions{Secrets: []string{"password"}}, perms)
+
+	require.True(t, steps[1].DynamicAccess)
+	perms, err = obj.ModelStepPermissions(ctx, steps[1])
+	require.NoError(t, err)
+	require.Nil(t, perms)
+}
+
+func TestWorkflowRunEngineMappingDependencyInference(t *testing.T) {
+	step := func(name string, dependsOn []string, when interface{}, spec map[string]interface{}) *WorkflowStep {
+		em := make(ExpressionMap, len(spec))
+		for k, v := range spec {
+			em[k] = serialize.JSONTree{Tree: v}
+		}
+
+		return &WorkflowStep{
+			Name:      name,
+			DependsOn: dependsOn,
+			When:      serialize.JSONTree{Tree: when},
+			Variant: &ContainerWorkflowStep{
+				ContainerMixin: ContainerMixin{Image: "alpine:latest", Spec: em},
+			},
+		}
+	}
+
+	tests := []struct {
+		Name              string
+		Steps             []*WorkflowStep
+		Mode              DependencyInferenceMode
+		ExpectedDependsOn map[string][]string
+		ExpectedMissing   []*WorkflowStepDependenciesMissingError
+		ExpectedError     error
+	}{
+		{
+			Name: "missing dependencies are added",
+			Steps: []*WorkflowStep{
+				step("build", nil, nil, nil),
+				step("test", nil, nil, nil),
+				step("deploy", []string{"test"}, []interface{}{
+					testutil.JSONInvocation("equals", []interface{}{testutil.JSONOutput("test", "result"), "passed"}),
+				}, map[string]interface{}{
+					"image": testutil.JSONInvocation("if", []interface{}{
+						testutil.JSONParameter("latest"),
+						"latest",
+						testutil.JSONOutput("build", "image"),
+					}),
+				}),
+			},
+			ExpectedDependsOn: map[string][]string{
+				"deploy": {"test", "build"},
+			},
+			ExpectedMissing: []*WorkflowStepDependenciesMissingError{
+				{Name: "deploy", DependsOn: []string{"build"}},
+			},
+		},
+		{
+			Name: "indirect dependencies are sufficient",
+			Steps: []*WorkflowStep{
+				step("build", nil, nil, nil),
+				step("test", []string{"build"}, nil, nil),
+				step("deploy", []string{"test"}, nil, map[string]interface{}{
+					"image": testutil.JSONOutput("build", "image"),
+				}),
+			},
+			ExpectedDependsOn: map[string][]string{
+				"test":   {"build"},
+				"deploy": {"test"},
+			},
+		},
+		{
+			Name: "undeclared steps are ignored",
+			Steps: []*WorkflowStep{
+				step("deploy", nil, nil, map[string]interface{}{
+					"image": testutil.JSONOutput("build", "image"),
+				}),
+			},
+		},
+		{
+			Name: "warn",
+			Steps: []*WorkflowStep{
+				step("build", nil, nil, nil),
+				step("test", nil, nil, map[string]interface{}{
+					"image": testutil.JSONOutput("build", "image"),
+				}),
+				step("deploy", []string{"test"}, nil, map[string]interface{}{
+					"image":  testutil.JSONOutput("build", "image"),
+					"result": testutil.JSONOutput("test", "result"),
+				}),
+			},
+			Mode: DependencyInferenceModeWarn,
+			ExpectedDependsOn: map[string][]string{
+				"deploy": {"test"},
+			},
+			ExpectedMissing: []*WorkflowStepDependenciesMissingError{
+				{Name: "test", DependsOn: []string{"build"}},
+				{Name: "deploy", DependsOn: []string{"build"}},
+			},
+		},
+		{
+			Name: "strict",
+			Steps: []*WorkflowStep{
+				step("build", nil, nil, nil),
+				step("deploy", nil, nil, map[string]interface{}{
+					"image": testutil.JSONOutput("build", "image"),
+				}),
+			},
+			Mode:          DependencyInferenceModeStrict,
+			ExpectedError: &WorkflowStepDependenciesMissingError{Name: "deploy", DependsOn: []string{"build"}},
+		},
+		{
+			Name: "cycle",
+			Steps: []*WorkflowStep{
+				step("build", []string{"deploy"}, nil, nil),
+				step("test", []string{"build"}, nil, nil),
+				step("deploy", nil, nil, map[string]interface{}{
+					"image": testutil.JSONOutput("test", "image"),
+				}),
+			},
+			ExpectedError: &WorkflowStepDependencyCycleError{Cycle: []string{"build", "deploy", "test", "build"}},
+		},
+		{
+			Name: "self reference",
+			Steps: []*WorkflowStep{
+				step("build", nil, nil, map[string]interface{}{
+					"tag": testutil.JSONOutput("build", "tag"),
+				}),
+			},
+			ExpectedError: &WorkflowStepDependencyCycleError{Cycle: []string{"build", "build"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var opts []DefaultRunEngineMapperOption
+			if test.Mode != "" {
+				opts = append(opts, WithDependencyInferenceModeRunOption(test.Mode))
+			}
+
+			manifest, err := NewDefaultRunEngineMapper(opts...).ToRuntimeObjectsManifest(context.Background(), &WorkflowData{Steps: test.Steps})
+			if test.ExpectedError != nil {
+				require.Equal(t, test.ExpectedError, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for _, step := range manifest.WorkflowRun.Spec.Workflow.Steps {
+				require.Equal(t, test.ExpectedDependsOn[step.Name], step.DependsOn, "step %s", step.Name)
+			}
+			require.Equal(t, test.ExpectedMissing, manifest.MissingDependencies)
+		})
+	}
+}